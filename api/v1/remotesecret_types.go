@@ -0,0 +1,726 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RemoteSecretSpec defines the desired state of RemoteSecret
+type RemoteSecretSpec struct {
+	// Secret defines the properties of the secret and the linked service accounts that should be
+	// created in the target namespaces.
+	Secret LinkableSecretSpec `json:"secret"`
+	// Targets is the list of the target namespaces that the secret and service accounts should be deployed to.
+	// +optional
+	Targets []RemoteSecretTarget `json:"targets,omitempty"`
+	// Suspend stops the reconciliation of the remote secret. While suspended, the secrets and service accounts in the targets
+	// are neither updated nor deleted and the data from the upload secrets is not written to the storage. The upload secrets
+	// are left in place and processed once the remote secret is resumed. The deletion of the remote secret itself is not
+	// affected by this.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// Adoption specifies how the secrets that already exist in the targets under the name specified for the secret are dealt with.
+	// +optional
+	Adoption SecretAdoption `json:"adoption,omitempty"`
+	// ReplicationTargets is the list of the remote secrets, typically in other clusters, to which the data of this remote secret
+	// should be replicated. The data is transferred using an upload secret that is processed by the remote secret controller
+	// in the cluster of the replica.
+	// +optional
+	ReplicationTargets []ReplicationTarget `json:"replicationTargets,omitempty"`
+	// ExternalSource makes the data of the remote secret periodically read from the external secret store configured in the operator
+	// instead of being uploaded. The data cannot be uploaded or copied to the remote secret with an external source.
+	// +optional
+	ExternalSource *ExternalSource `json:"externalSource,omitempty"`
+}
+
+// ExternalSource specifies where in the external secret store the data of the remote secret is read from.
+type ExternalSource struct {
+	// Data maps the keys in the data of the remote secret to the secrets in the external secret store.
+	// +optional
+	Data []ExternalSourceData `json:"data,omitempty"`
+	// DataFrom are the secrets in the external secret store whose keys and values are all put into the data of the remote secret.
+	// The keys of the later secrets take precedence and the keys in the data take precedence over all of them.
+	// +optional
+	DataFrom []ExternalSourceRef `json:"dataFrom,omitempty"`
+	// RefreshInterval is how often the data is read from the external secret store. Defaults to 1 hour.
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+}
+
+// ExternalSourceData puts a secret from the external secret store under a key in the data of the remote secret.
+type ExternalSourceData struct {
+	// SecretKey is the key in the data of the remote secret.
+	SecretKey string `json:"secretKey"`
+	// RemoteRef is the secret in the external secret store.
+	RemoteRef ExternalSourceRef `json:"remoteRef"`
+}
+
+// ExternalSourceRef references a secret in the external secret store.
+type ExternalSourceRef struct {
+	// Key is the key of the secret in the external secret store. It is relative to the prefix configured in the operator.
+	Key string `json:"key"`
+	// Property is the property of the secret to read. If not specified, the whole secret is read.
+	// +optional
+	Property string `json:"property,omitempty"`
+	// Version is the version of the secret to read. If not specified, the current version is read.
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+type ReplicationTarget struct {
+	// Namespace is the namespace of the replica remote secret.
+	Namespace string `json:"namespace"`
+	// RemoteSecretName is the name of the replica remote secret. If not specified, the name of this remote secret is used.
+	// If the replica doesn't exist, it is created by the remote secret controller in its cluster.
+	// +kubebuilder:validation:Optional
+	RemoteSecretName string `json:"remoteSecretName,omitempty"`
+	// ApiUrl specifies the URL of the API server of the Kubernetes cluster of the replica. If left empty, the local cluster is assumed.
+	// +kubebuilder:validation:Optional
+	ApiUrl string `json:"apiUrl,omitempty"`
+	// ClusterCredentialsSecret is the name of the secret in the same namespace as the RemoteSecret that contains the token
+	// to use to authenticate with the Kubernetes cluster of the replica. This is ignored if `apiUrl` is empty.
+	// +kubebuilder:validation:Optional
+	ClusterCredentialsSecret string `json:"clusterCredentialsSecret,omitempty"`
+	// Continuous makes the data replicated again on every change of the data of this remote secret. Otherwise, the data
+	// is replicated only once.
+	// +kubebuilder:validation:Optional
+	Continuous bool `json:"continuous,omitempty"`
+}
+
+// AdoptionPolicy specifies whether a secret that already exists in a target can be taken over by the remote secret.
+type AdoptionPolicy string
+
+const (
+	// AdoptionPolicyNever makes the deployment to the target fail if the secret exists and is not managed by the remote secret.
+	AdoptionPolicyNever AdoptionPolicy = "Never"
+	// AdoptionPolicyIfUnmanaged adopts the existing secret unless it is managed by another remote secret. This is the default.
+	AdoptionPolicyIfUnmanaged AdoptionPolicy = "IfUnmanaged"
+	// AdoptionPolicyAlways adopts the existing secret even if it is managed by another remote secret.
+	AdoptionPolicyAlways AdoptionPolicy = "Always"
+)
+
+type SecretAdoption struct {
+	// Policy specifies whether the secrets that already exist in the targets can be adopted by the remote secret. The adopted
+	// secrets are marked as managed by the remote secret and their data, labels and annotations are updated to match the remote
+	// secret. If not specified, IfUnmanaged is assumed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Never;IfUnmanaged;Always
+	Policy AdoptionPolicy `json:"policy,omitempty"`
+	// ImportData makes the data of the first adoptable secret (in the order of the targets) the initial data of the remote secret
+	// while the remote secret doesn't have any data. The user creating or updating the remote secret needs to be able to get
+	// the secrets in the targets in the local cluster.
+	// +kubebuilder:validation:Optional
+	ImportData bool `json:"importData,omitempty"`
+}
+
+type RemoteSecretTarget struct {
+	// Secret contains the overriden definitions of the secret specific to this target.
+	// +kubebuilder:validation:Optional
+	Secret *SecretOverride `json:"secret,omitempty"`
+	// Namespace is the name of the target namespace to which to deploy.
+	Namespace string `json:"namespace"`
+	// ApiUrl specifies the URL of the API server of a remote Kubernetes cluster that this target points to. If left empty,
+	// the local cluster is assumed.
+	// +kubebuilder:validation:Optional
+	ApiUrl string `json:"apiUrl,omitempty"`
+	// ClusterCredentialsSecret is the name of the secret in the same namespace as the RemoteSecret that contains the token
+	// to use to authenticate with the remote Kubernetes cluster. This is ignored if `apiUrl` is empty.
+	// +kubebuilder:validation:Optional
+	ClusterCredentialsSecret string `json:"clusterCredentialsSecret,omitempty"`
+	// DeletionPolicy specifies what happens with the secret and the service accounts in this target when the remote secret
+	// is deleted. If not specified, the default deletion policy of the controller is used, which is `Delete` unless configured
+	// otherwise.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// DeletionPolicy specifies what happens with the objects deployed to a target when the remote secret is deleted.
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the secret and the managed service accounts from the target and unlinks the secret
+	// from the referenced service accounts.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyOrphan leaves the secret and the service accounts in the target. Only the labels and annotations marking
+	// them as belonging to the remote secret are removed.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+type SecretOverride struct {
+	// Labels is the new set of labels to be put on the secret instead of the labels defined in the spec. I.e. this completely replaces
+	// the labels from the secret spec. Note that this is a pointer to a map so that we can distinguish between an undefined, nil, value
+	// and an empty map (clearing any labels defined in the spec).
+	// +kubebuilder:validation:Optional
+	Labels *map[string]string `json:"labels,omitempty"`
+	// Annotations is the new set of annotations to be put on the secret instead of the annotations defined in the spec. I.e. this
+	// completely replaces the annotations from the secret spec. Note that this is a pointer to a map so that we can distinguish between
+	// an undefined, nil, value and an empty map (clearing any annotations defined in the spec).
+	// +kubebuilder:validation:Optional
+	Annotations *map[string]string `json:"annotations,omitempty"`
+	// Name is the name of the secret when deployed to the target. This overrides the name from the secret spec.
+	// +kubebuilder:validation:Optional
+	Name string `json:"name,omitempty"`
+	// GenerateName is the GenerateName of the secret when deployed to the target. This overrides the generateName from the secret spec.
+	// +kubebuilder:validation:Optional
+	GenerateName string `json:"generateName,omitempty"`
+}
+
+// RemoteSecretStatus defines the observed state of RemoteSecret
+type RemoteSecretStatus struct {
+	// Conditions is the list of conditions describing the state of the deployment
+	// to the targets.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Targets is the list of the deployment statuses for individual targets in the spec.
+	// +optional
+	Targets []TargetStatus `json:"targets,omitempty"`
+	// ObservedGeneration is the generation of the remote secret that was last processed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// SecretStatus describes the shape of the secret which is currently stored in SecretStorage.
+	// +optional
+	SecretStatus SecretStatus `json:"secret,omitempty"`
+	// DryRunPlan describes the changes that would be made in the targets if the remote secret was not marked
+	// for a dry run using the DryRunAnnotation. It is only present while the remote secret has the annotation.
+	// +optional
+	DryRunPlan *DryRunPlan `json:"dryRunPlan,omitempty"`
+	// ReplicationTargets is the list of the replication statuses for the individual replication targets in the spec.
+	// +optional
+	ReplicationTargets []ReplicationTargetStatus `json:"replicationTargets,omitempty"`
+	// LastDataUpdate describes the last attempt to update the data of the remote secret.
+	// +optional
+	LastDataUpdate *DataUpdateStatus `json:"lastDataUpdate,omitempty"`
+}
+
+// DataUpdateSource identifies how the data of the remote secret was updated.
+type DataUpdateSource string
+
+const (
+	// DataUpdateSourceWebhook means that the data was updated using the data or stringData fields of the remote secret.
+	DataUpdateSourceWebhook DataUpdateSource = "Webhook"
+	// DataUpdateSourceUploadSecret means that the data was updated using an upload secret.
+	DataUpdateSourceUploadSecret DataUpdateSource = "UploadSecret"
+	// DataUpdateSourceDataFrom means that the data was copied from the sources in the dataFrom of the remote secret.
+	DataUpdateSourceDataFrom DataUpdateSource = "DataFrom"
+	// DataUpdateSourceDataAPI means that the data was updated using the data subresource of the data API.
+	DataUpdateSourceDataAPI DataUpdateSource = "DataAPI"
+)
+
+// DataUpdateResult is the result of the update of the data.
+type DataUpdateResult string
+
+const (
+	DataUpdateResultSucceeded DataUpdateResult = "Succeeded"
+	DataUpdateResultFailed    DataUpdateResult = "Failed"
+)
+
+// DataUpdateStatus describes an attempt to update the data of the remote secret.
+type DataUpdateStatus struct {
+	// Source identifies how the data was updated.
+	Source DataUpdateSource `json:"source"`
+	// SourceName identifies the object the data was updated from, e.g. the name of the upload secret.
+	// +optional
+	SourceName string `json:"sourceName,omitempty"`
+	// Time is the time of the update.
+	Time metav1.Time `json:"time"`
+	// Result tells whether the data was updated.
+	Result DataUpdateResult `json:"result"`
+	// Reason is the machine-readable reason of the failure. It is the same as the reason in the metric of the rejected uploads.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message describes the failure.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Keys are the keys that were (or were supposed to be) written by the update.
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+	// DeletedKeys are the keys that were (or were supposed to be) deleted by the update.
+	// +optional
+	DeletedKeys []string `json:"deletedKeys,omitempty"`
+}
+
+type ReplicationTargetStatus struct {
+	// Namespace is the namespace of the replica remote secret.
+	Namespace string `json:"namespace"`
+	// RemoteSecretName is the name of the replica remote secret.
+	RemoteSecretName string `json:"remoteSecretName"`
+	// ApiUrl is the URL of the Kubernetes cluster of the replica.
+	// +optional
+	ApiUrl string `json:"apiUrl,omitempty"`
+	// TransferSecretName is the name of the upload secret transferring the data to the replica that has not been processed yet.
+	// +optional
+	TransferSecretName string `json:"transferSecretName,omitempty"`
+	// TransferStartTime is the time when the transfer secret was created.
+	// +optional
+	TransferStartTime *metav1.Time `json:"transferStartTime,omitempty"`
+	// TransferDataHash identifies the data being transferred by the transfer secret. It has the same form as the DataFingerprint
+	// of the targets.
+	// +optional
+	TransferDataHash string `json:"transferDataHash,omitempty"`
+	// ReplicatedDataHash identifies the data that was last successfully replicated. It has the same form as the DataFingerprint
+	// of the targets.
+	// +optional
+	ReplicatedDataHash string `json:"replicatedDataHash,omitempty"`
+	// LastReplicationTime is the time when the data was last successfully replicated.
+	// +optional
+	LastReplicationTime *metav1.Time `json:"lastReplicationTime,omitempty"`
+	// Conditions describe the state of the replication to the target. The Replicated condition is false with the Error reason
+	// and the error in the message if the replication failed.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+type SecretStatus struct {
+	Keys []string `json:"keys,omitempty"`
+	// KeyDetails describe the individual keys of the stored secret data.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	KeyDetails []SecretKeyStatus `json:"keyDetails,omitempty"`
+	// FingerprintKeyID identifies the key that the operator used to compute the fingerprints in the KeyDetails. Only
+	// the fingerprints computed using the same key can be compared.
+	// +optional
+	FingerprintKeyID string `json:"fingerprintKeyId,omitempty"`
+	// Size is the size of the stored secret data in bytes, i.e. the sum of the lengths of the keys and the values. It is used
+	// to compute the usage of the quota of the namespace.
+	// +optional
+	Size int64 `json:"size,omitempty"`
+	// Version is the version of the stored secret data in the secret storage. It can be used in the
+	// appstudio.redhat.com/remotesecret-expected-data-version annotation to make sure that the data is not changed
+	// concurrently. It is empty if the secret storage doesn't version the data.
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// SecretKeyStatus describes a single key of the stored secret data.
+type SecretKeyStatus struct {
+	// Name is the name of the key.
+	Name string `json:"name"`
+	// Fingerprint identifies the value of the key. It is an HMAC of the value computed using a key held by the operator,
+	// so it changes when the value changes but doesn't reveal the value.
+	Fingerprint string `json:"fingerprint"`
+	// Size is the length of the value in bytes.
+	Size int64 `json:"size"`
+	// LastModified is the time when the controller first observed the current value of the key.
+	// +optional
+	LastModified *metav1.Time `json:"lastModified,omitempty"`
+}
+
+type TargetStatus struct {
+	// Namespace is the namespace of the target where the secret and the service accounts have been deployed to.
+	Namespace string `json:"namespace"`
+	// ApiUrl is the URL of the remote Kubernetes cluster to which the target points to.
+	// +optional
+	ApiUrl string `json:"apiUrl,omitempty"`
+	// ClusterCredentialsSecret is the name of the secret in the same namespace as the RemoteSecret that contains the token
+	// to use to authenticate with the remote Kubernetes cluster. This is ignored if `apiUrl` is empty.
+	// +optional
+	ClusterCredentialsSecret string `json:"clusterCredentialsSecret,omitempty"`
+	// ExpectedSecret defines how the name of the Secret to be deployed should look like. The value comes either from
+	// LinkableSecretSpec definition in RemoteSecret spec, or from SecretOverride in the target. The value
+	// as such is not important for users, but it is required for a correct matching of targets from spec to status.
+	// +optional
+	ExpectedSecret *TargetSecretKey `json:"expectedSecret,omitempty"`
+	// DeployedSecret contains the status information about the linked secret deployed in the target
+	// +optional
+	DeployedSecret *DeployedSecretStatus `json:"deployedSecret,omitempty"`
+	// ServiceAccountNames is the names of the service accounts that have been deployed to the target namespace
+	// +optional
+	ServiceAccountNames []string `json:"serviceAccountNames,omitempty"`
+	// Conditions describe the state of the deployment to the target. The Deployed condition is false with the Error reason
+	// and the error in the message if the deployment of either the secret or the service accounts failed. The Ready condition
+	// is true if the secret and the service accounts are deployed and up to date. Its reason identifies the cause of the
+	// failure otherwise.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// LastSyncTime is the time when the secret and the service accounts were last successfully deployed to the target.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// ObservedGeneration is the generation of the remote secret that was last successfully deployed to the target.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// DataFingerprint identifies the version of the data that was last successfully deployed to the target. It is a hash
+	// of the data keyed with the fingerprint key of the operator and salted with the UID of the remote secret, so it can be
+	// compared between the targets but doesn't reveal the data. If the operator has no fingerprint key, the internal key
+	// of the operator is used instead.
+	// +optional
+	DataFingerprint string `json:"dataFingerprint,omitempty"`
+}
+
+type DeployedSecretStatus struct {
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Name        string            `json:"name"`
+}
+
+// DryRunPlan is the list of changes that the controller would make in the targets of the remote secret.
+type DryRunPlan struct {
+	// Targets contains the planned changes for the individual targets.
+	// +optional
+	Targets []TargetPlan `json:"targets,omitempty"`
+}
+
+// PlannedAction describes what would happen with an object in the target.
+type PlannedAction string
+
+const (
+	PlannedActionCreate  PlannedAction = "Create"
+	PlannedActionUpdate  PlannedAction = "Update"
+	PlannedActionReplace PlannedAction = "Replace"
+	PlannedActionDelete  PlannedAction = "Delete"
+	PlannedActionLink    PlannedAction = "Link"
+	PlannedActionUnlink  PlannedAction = "Unlink"
+	PlannedActionNone    PlannedAction = "None"
+)
+
+type TargetPlan struct {
+	// Namespace is the namespace of the target.
+	Namespace string `json:"namespace"`
+	// ApiUrl is the URL of the remote Kubernetes cluster to which the target points to.
+	// +optional
+	ApiUrl string `json:"apiUrl,omitempty"`
+	// Action is what would happen with the secret in the target.
+	// +optional
+	Action PlannedAction `json:"action,omitempty"`
+	// SecretName is the name of the secret in the target. It is empty if the secret would be created with a generated name.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+	// ReplacedSecretName is the name of the secret that would be deleted in favor of the secret with the new name.
+	// +optional
+	ReplacedSecretName string `json:"replacedSecretName,omitempty"`
+	// Changes lists the changes that would be made to the secret. Note that the values of the secret data are never
+	// included, only the keys.
+	// +optional
+	Changes []string `json:"changes,omitempty"`
+	// ServiceAccounts lists what would happen with the service accounts linked to the secret.
+	// +optional
+	ServiceAccounts []ServiceAccountPlan `json:"serviceAccounts,omitempty"`
+	// Error is the error that the deployment to the target would fail with or that prevented the computation of the plan.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+type ServiceAccountPlan struct {
+	// Name is the name of the service account. It is empty if the service account would be created with a generated name.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Action is what would happen with the service account.
+	Action PlannedAction `json:"action"`
+	// LinkType is the type of the link to the secret.
+	// +optional
+	LinkType ServiceAccountLinkType `json:"linkType,omitempty"`
+}
+
+type TargetSecretKey struct {
+	// Name is the exact name of the Secret to be deployed to target.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// GenerateName is the name prefix for Secret to be deployed to the target.
+	// +optional
+	GenerateName string `json:"generateName,omitempty"`
+}
+
+// TargetConditionType lists the types of conditions we track in the statuses of the individual targets
+type TargetConditionType string
+
+const (
+	TargetConditionTypeDeployed TargetConditionType = "Deployed"
+	TargetConditionTypeReady    TargetConditionType = "Ready"
+)
+
+// ReplicationTargetConditionType lists the types of conditions we track in the statuses of the individual replication targets
+type ReplicationTargetConditionType string
+
+const (
+	ReplicationTargetConditionTypeReplicated ReplicationTargetConditionType = "Replicated"
+)
+
+// TargetReason is the machine-readable reason of the conditions of the individual targets
+type TargetReason string
+
+const (
+	TargetReasonDeployed                      TargetReason = "Deployed"
+	TargetReasonError                         TargetReason = "Error"
+	TargetReasonDuplicateTarget               TargetReason = "DuplicateTarget"
+	TargetReasonInconsistent                  TargetReason = "Inconsistent"
+	TargetReasonSecretNameCollision           TargetReason = "SecretNameCollision"
+	TargetReasonSecretUpdate                  TargetReason = "SecretUpdate"
+	TargetReasonServiceAccountUnavailable     TargetReason = "ServiceAccountUnavailable"
+	TargetReasonServiceAccountUpdate          TargetReason = "ServiceAccountUpdate"
+	TargetReasonAuthServiceAccountUnavailable TargetReason = "AuthServiceAccountUnavailable"
+	TargetReasonClusterCredentialsUnavailable TargetReason = "ClusterCredentialsUnavailable"
+	TargetReasonClusterUnreachable            TargetReason = "ClusterUnreachable"
+	TargetReasonPolicyViolation               TargetReason = "PolicyViolation"
+)
+
+// RemoteSecretReason is the reconciliation status of the RemoteSecret object
+type RemoteSecretReason string
+
+// RemoteSecretConditionType lists the types of conditions we track in the remote secret status
+type RemoteSecretConditionType string
+
+const (
+	RemoteSecretConditionTypeDeployed     RemoteSecretConditionType = "Deployed"
+	RemoteSecretConditionTypeDataObtained RemoteSecretConditionType = "DataObtained"
+	RemoteSecretConditionTypeSuspended    RemoteSecretConditionType = "Suspended"
+	RemoteSecretConditionTypeReplicated   RemoteSecretConditionType = "Replicated"
+	// RemoteSecretConditionTypeReady aggregates the other conditions. It is true if the data is obtained and deployed to all
+	// the targets and replicas.
+	RemoteSecretConditionTypeReady RemoteSecretConditionType = "Ready"
+	// RemoteSecretConditionTypeDataValid describes whether the data of the remote secret is valid for the type of the secret
+	// and the rules of its keys. The data that is not valid is not deployed to the targets.
+	RemoteSecretConditionTypeDataValid RemoteSecretConditionType = "DataValid"
+
+	RemoteSecretReasonAwaitingTokenData  RemoteSecretReason = "AwaitingData"
+	RemoteSecretReasonDataFound          RemoteSecretReason = "DataFound"
+	RemoteSecretReasonInjected           RemoteSecretReason = "Injected"
+	RemoteSecretReasonPartiallyInjected  RemoteSecretReason = "PartiallyInjected"
+	RemoteSecretReasonError              RemoteSecretReason = "Error"
+	RemoteSecretReasonNoTargets          RemoteSecretReason = "NoTargets"
+	RemoteSecretReasonSuspended          RemoteSecretReason = "Suspended"
+	RemoteSecretReasonResumed            RemoteSecretReason = "Resumed"
+	RemoteSecretReasonReplicated         RemoteSecretReason = "Replicated"
+	RemoteSecretReasonReplicationPending RemoteSecretReason = "ReplicationPending"
+	RemoteSecretReasonDeploymentPending  RemoteSecretReason = "DeploymentPending"
+	RemoteSecretReasonReady              RemoteSecretReason = "Ready"
+	RemoteSecretReasonDataValid          RemoteSecretReason = "DataValid"
+	RemoteSecretReasonDataInvalid        RemoteSecretReason = "DataInvalid"
+	RemoteSecretReasonDataWarnings       RemoteSecretReason = "DataValidWithWarnings"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+
+// RemoteSecret is the Schema for the RemoteSecret API
+type RemoteSecret struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RemoteSecretSpec   `json:"spec,omitempty"`
+	Status RemoteSecretStatus `json:"status,omitempty"`
+	// Transient optional field for data to upload during create/update RemoteSecret
+	// It is processed by Mutating Webhook and must not be persisted,
+	// to make sure (in a case if something happened with Webhook) it is constrained
+	//+kubebuilder:validation:MaxProperties=0
+	UploadData map[string][]byte `json:"data,omitempty"`
+	// Similar to how one can specify the data in an ordinary Kubernetes secret using either
+	// the "data" or "stringData" fields, so one can do that when supplying the data to
+	// the remote secret. See the data field for more details about the behavior of these fields
+	// in remote secrets.
+	// Both in create and update, the contents of the stringData is merged into the data field first.
+	// This is the same behavior as with ordinary Kubernetes secret's stringData.
+	//+kubebuilder:validation:MaxProperties=0
+	StringUploadData map[string]string `json:"stringData,omitempty"`
+	// DataFrom is an optional field that can be used to copy data from another remote secret during the creation
+	// of the remote secret. This field can be specified only during creation of a remote secret (only one of data
+	// or dataFrom can be specified at the same time) or during an update of a remote secret that does not yet have
+	// data associated with it (its DataObtained condition is in the AwaitingData state).
+	// The data can be composed from several source remote secrets, optionally selecting and renaming their keys.
+	// If dataFrom has the follow flag set, the data is not copied but the remote secret continuously follows the data
+	// of the source remote secrets. The field is then kept in the remote secret and can be specified at any time.
+	DataFrom RemoteSecretDataFrom `json:"dataFrom,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RemoteSecretList contains a list of RemoteSecret
+type RemoteSecretList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RemoteSecret `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RemoteSecret{}, &RemoteSecretList{})
+}
+
+type LinkableSecretSpec struct {
+	// Name is the name of the secret to be created. If it is not defined a random name based on the name of the binding
+	// is used.
+	// +optional
+	Name         string `json:"name,omitempty"`
+	GenerateName string `json:"generateName,omitempty"`
+	// Labels contains the labels that the created secret should be labeled with.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations is the keys and values that the created secret should be annotated with.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Type is the type of the secret to be created in targets. If left empty, the default type used in the cluster is assumed (typically Opaque).
+	// The Type has to match type of the UploadSecret. This constraint ensures that the requirements on keys,
+	// put forth by Kubernetes (https://kubernetes.io/docs/concepts/configuration/secret/#secret-types), are met and
+	// secret can be properly created in targets.
+	Type corev1.SecretType `json:"type,omitempty"`
+	// RequiredKeys are the keys which need to be present in the UploadSecret to successfully upload the SecretData.
+	// Furthermore, the UploadSecret needs to contain the keys which are inferred from the Type
+	// (and UploadSecret's type, since these have to match) and may contain any additional keys.
+	// Each of the keys can be made optional and can specify the rules that its value needs to satisfy. The rules
+	// are checked on all the data uploaded to or copied into the remote secret.
+	RequiredKeys []SecretKey `json:"keys,omitempty"`
+	// LinkedTo specifies the objects that the secret is linked to. Currently, only service accounts are supported.
+	LinkedTo []SecretLink `json:"linkedTo,omitempty"`
+}
+
+// SecretKey specifies a key of the secret data and, optionally, the rules that the value of the key must satisfy.
+type SecretKey struct {
+	// Name is the name of the key in the secret data.
+	Name string `json:"name,omitempty"`
+	// Optional marks the key as not required to be present in the secret data. The rules below are still checked
+	// if the key is present.
+	// +optional
+	Optional bool `json:"optional,omitempty"`
+	// DefaultValue is the value that is stored under the key if the key is missing in the uploaded data. Specifying
+	// a default value implies that the key is optional. The default value must satisfy the rules of the key.
+	// +optional
+	DefaultValue *string `json:"defaultValue,omitempty"`
+	// Pattern is a regular expression (in the RE2 syntax) that the value of the key must match. Note that the pattern
+	// is not implicitly anchored.
+	// +optional
+	Pattern string `json:"pattern,omitempty"`
+	// MinLength is the minimum length of the value of the key in bytes.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MinLength *int32 `json:"minLength,omitempty"`
+	// MaxLength is the maximum length of the value of the key in bytes.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxLength *int32 `json:"maxLength,omitempty"`
+	// Format is the expected format of the value of the key.
+	// +optional
+	Format SecretKeyFormat `json:"format,omitempty"`
+}
+
+// SecretKeyFormat is the format that the value of a secret key can be required to have.
+// +kubebuilder:validation:Enum=pem-certificate;pem-private-key;json;url;base64
+type SecretKeyFormat string
+
+const (
+	// SecretKeyFormatPemCertificate requires the value to contain one or more PEM-encoded X.509 certificates.
+	SecretKeyFormatPemCertificate SecretKeyFormat = "pem-certificate"
+	// SecretKeyFormatPemPrivateKey requires the value to be a PEM-encoded private key.
+	SecretKeyFormatPemPrivateKey SecretKeyFormat = "pem-private-key"
+	// SecretKeyFormatJson requires the value to be a valid JSON document.
+	SecretKeyFormatJson SecretKeyFormat = "json"
+	// SecretKeyFormatUrl requires the value to be an absolute URL.
+	SecretKeyFormatUrl SecretKeyFormat = "url"
+	// SecretKeyFormatBase64 requires the value to be base64 encoded (using the standard encoding with padding).
+	SecretKeyFormatBase64 SecretKeyFormat = "base64"
+)
+
+type SecretLink struct {
+	// ServiceAccounts lists the service accounts that the secret is linked to.
+	ServiceAccount ServiceAccountLink `json:"serviceAccount,omitempty"`
+}
+
+type ServiceAccountLink struct {
+	// As specifies how the secret generated by the binding is linked to the service account.
+	// This can be either `secret` meaning that the secret is listed as one of the mountable secrets
+	// in the `secrets` of the service account, `imagePullSecret` which makes the secret listed as
+	// one of the image pull secrets associated with the service account. If not specified, it defaults
+	// to `secret`.
+	// +optional
+	// +kubebuilder:default:=secret
+	As ServiceAccountLinkType `json:"as,omitempty"`
+	// Reference specifies a pre-existing service account that the secret should be linked to. It is an error
+	// if the service account doesn't exist when the operator tries to add a link to a secret with the injected
+	// token.
+	Reference corev1.LocalObjectReference `json:"reference,omitempty"`
+	// Managed specifies the service account that is bound to the lifetime of the binding. This service account
+	// must not exist and is created and deleted along with the injected secret.
+	Managed ManagedServiceAccountSpec `json:"managed,omitempty"`
+}
+
+type ManagedServiceAccountSpec struct {
+	// Name is the name of the service account to create/link. Either this or GenerateName
+	// must be specified.
+	// +optional
+	Name string `json:"name"`
+	// GenerateName is the generate name to be used when creating the service account. It only
+	// really makes sense for the Managed service accounts that are cleaned up with the binding.
+	// +optional
+	GenerateName string `json:"generateName"`
+	// Labels contains the labels that the created service account should be labeled with.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations is the keys and values that the created service account should be annotated with.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+type ServiceAccountLinkType string
+
+const (
+	ServiceAccountLinkTypeSecret          ServiceAccountLinkType = "secret"
+	ServiceAccountLinkTypeImagePullSecret ServiceAccountLinkType = "imagePullSecret"
+)
+
+type RemoteSecretDataFrom struct {
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	// Sources are the remote secrets to compose the data from. The data of the sources is merged in the order of the list,
+	// so the keys of the later sources take precedence over the same keys of the earlier sources. If the name is also
+	// specified, the remote secret it refers to is the first source and all its keys are used.
+	// +optional
+	Sources []RemoteSecretDataSource `json:"sources,omitempty"`
+	// Follow makes the remote secret derive its data from the source remote secrets for as long as this is set instead of
+	// copying the data once. The changes of the data of the sources are propagated to the targets of this remote secret.
+	// The data cannot be uploaded to a remote secret that follows other remote secrets and the sources cannot follow
+	// other remote secrets themselves.
+	// +optional
+	Follow bool `json:"follow,omitempty"`
+}
+
+// RemoteSecretDataSource is a remote secret or a secret the data is taken from.
+type RemoteSecretDataSource struct {
+	// Kind is the kind of the source. The data is taken from a remote secret by default. If the kind is Secret,
+	// the data is imported from an existing Kubernetes secret.
+	// +optional
+	// +kubebuilder:validation:Enum=RemoteSecret;Secret
+	Kind RemoteSecretDataSourceKind `json:"kind,omitempty"`
+	// Name is the name of the source.
+	Name string `json:"name"`
+	// Namespace is the namespace of the source. Defaults to the namespace of the remote secret.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Keys are the keys to take from the data of the source. If empty, all the keys are taken.
+	// +optional
+	Keys []RemoteSecretDataSourceKey `json:"keys,omitempty"`
+	// DeleteAfterImport makes the source secret deleted once its data is successfully imported. It can only be set for
+	// the sources of the Secret kind.
+	// +optional
+	DeleteAfterImport bool `json:"deleteAfterImport,omitempty"`
+}
+
+// RemoteSecretDataSourceKind is the kind of the object the data is taken from.
+type RemoteSecretDataSourceKind string
+
+const (
+	RemoteSecretDataSourceKindRemoteSecret RemoteSecretDataSourceKind = "RemoteSecret"
+	RemoteSecretDataSourceKindSecret       RemoteSecretDataSourceKind = "Secret"
+)
+
+// RemoteSecretDataSourceKey selects a key from the data of the source remote secret.
+type RemoteSecretDataSourceKey struct {
+	// Name is the name of the key in the data of the source remote secret.
+	Name string `json:"name"`
+	// As is the name of the key in the data of the remote secret. Defaults to the name of the key in the source.
+	// +optional
+	As string `json:"as,omitempty"`
+}
@@ -0,0 +1,779 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataUpdateStatus) DeepCopyInto(out *DataUpdateStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeletedKeys != nil {
+		in, out := &in.DeletedKeys, &out.DeletedKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataUpdateStatus.
+func (in *DataUpdateStatus) DeepCopy() *DataUpdateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataUpdateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeployedSecretStatus) DeepCopyInto(out *DeployedSecretStatus) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployedSecretStatus.
+func (in *DeployedSecretStatus) DeepCopy() *DeployedSecretStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployedSecretStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunPlan) DeepCopyInto(out *DryRunPlan) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]TargetPlan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunPlan.
+func (in *DryRunPlan) DeepCopy() *DryRunPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSource) DeepCopyInto(out *ExternalSource) {
+	*out = *in
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make([]ExternalSourceData, len(*in))
+		copy(*out, *in)
+	}
+	if in.DataFrom != nil {
+		in, out := &in.DataFrom, &out.DataFrom
+		*out = make([]ExternalSourceRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSource.
+func (in *ExternalSource) DeepCopy() *ExternalSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSourceData) DeepCopyInto(out *ExternalSourceData) {
+	*out = *in
+	out.RemoteRef = in.RemoteRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSourceData.
+func (in *ExternalSourceData) DeepCopy() *ExternalSourceData {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSourceData)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSourceRef) DeepCopyInto(out *ExternalSourceRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSourceRef.
+func (in *ExternalSourceRef) DeepCopy() *ExternalSourceRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSourceRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LinkableSecretSpec) DeepCopyInto(out *LinkableSecretSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RequiredKeys != nil {
+		in, out := &in.RequiredKeys, &out.RequiredKeys
+		*out = make([]SecretKey, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LinkedTo != nil {
+		in, out := &in.LinkedTo, &out.LinkedTo
+		*out = make([]SecretLink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LinkableSecretSpec.
+func (in *LinkableSecretSpec) DeepCopy() *LinkableSecretSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LinkableSecretSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedServiceAccountSpec) DeepCopyInto(out *ManagedServiceAccountSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedServiceAccountSpec.
+func (in *ManagedServiceAccountSpec) DeepCopy() *ManagedServiceAccountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedServiceAccountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteSecret) DeepCopyInto(out *RemoteSecret) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	if in.UploadData != nil {
+		in, out := &in.UploadData, &out.UploadData
+		*out = make(map[string][]byte, len(*in))
+		for key, val := range *in {
+			var outVal []byte
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]byte, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.StringUploadData != nil {
+		in, out := &in.StringUploadData, &out.StringUploadData
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.DataFrom.DeepCopyInto(&out.DataFrom)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecret.
+func (in *RemoteSecret) DeepCopy() *RemoteSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RemoteSecret) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteSecretDataFrom) DeepCopyInto(out *RemoteSecretDataFrom) {
+	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]RemoteSecretDataSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecretDataFrom.
+func (in *RemoteSecretDataFrom) DeepCopy() *RemoteSecretDataFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteSecretDataFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteSecretDataSource) DeepCopyInto(out *RemoteSecretDataSource) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]RemoteSecretDataSourceKey, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecretDataSource.
+func (in *RemoteSecretDataSource) DeepCopy() *RemoteSecretDataSource {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteSecretDataSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteSecretDataSourceKey) DeepCopyInto(out *RemoteSecretDataSourceKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecretDataSourceKey.
+func (in *RemoteSecretDataSourceKey) DeepCopy() *RemoteSecretDataSourceKey {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteSecretDataSourceKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteSecretList) DeepCopyInto(out *RemoteSecretList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RemoteSecret, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecretList.
+func (in *RemoteSecretList) DeepCopy() *RemoteSecretList {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteSecretList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RemoteSecretList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteSecretSpec) DeepCopyInto(out *RemoteSecretSpec) {
+	*out = *in
+	in.Secret.DeepCopyInto(&out.Secret)
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]RemoteSecretTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Adoption = in.Adoption
+	if in.ReplicationTargets != nil {
+		in, out := &in.ReplicationTargets, &out.ReplicationTargets
+		*out = make([]ReplicationTarget, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExternalSource != nil {
+		in, out := &in.ExternalSource, &out.ExternalSource
+		*out = new(ExternalSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecretSpec.
+func (in *RemoteSecretSpec) DeepCopy() *RemoteSecretSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteSecretSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteSecretStatus) DeepCopyInto(out *RemoteSecretStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]TargetStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.SecretStatus.DeepCopyInto(&out.SecretStatus)
+	if in.DryRunPlan != nil {
+		in, out := &in.DryRunPlan, &out.DryRunPlan
+		*out = new(DryRunPlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplicationTargets != nil {
+		in, out := &in.ReplicationTargets, &out.ReplicationTargets
+		*out = make([]ReplicationTargetStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastDataUpdate != nil {
+		in, out := &in.LastDataUpdate, &out.LastDataUpdate
+		*out = new(DataUpdateStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecretStatus.
+func (in *RemoteSecretStatus) DeepCopy() *RemoteSecretStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteSecretStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteSecretTarget) DeepCopyInto(out *RemoteSecretTarget) {
+	*out = *in
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(SecretOverride)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecretTarget.
+func (in *RemoteSecretTarget) DeepCopy() *RemoteSecretTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteSecretTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationTarget) DeepCopyInto(out *ReplicationTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationTarget.
+func (in *ReplicationTarget) DeepCopy() *ReplicationTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationTargetStatus) DeepCopyInto(out *ReplicationTargetStatus) {
+	*out = *in
+	if in.TransferStartTime != nil {
+		in, out := &in.TransferStartTime, &out.TransferStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastReplicationTime != nil {
+		in, out := &in.LastReplicationTime, &out.LastReplicationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationTargetStatus.
+func (in *ReplicationTargetStatus) DeepCopy() *ReplicationTargetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationTargetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretAdoption) DeepCopyInto(out *SecretAdoption) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretAdoption.
+func (in *SecretAdoption) DeepCopy() *SecretAdoption {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretAdoption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKey) DeepCopyInto(out *SecretKey) {
+	*out = *in
+	if in.DefaultValue != nil {
+		in, out := &in.DefaultValue, &out.DefaultValue
+		*out = new(string)
+		**out = **in
+	}
+	if in.MinLength != nil {
+		in, out := &in.MinLength, &out.MinLength
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxLength != nil {
+		in, out := &in.MaxLength, &out.MaxLength
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKey.
+func (in *SecretKey) DeepCopy() *SecretKey {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeyStatus) DeepCopyInto(out *SecretKeyStatus) {
+	*out = *in
+	if in.LastModified != nil {
+		in, out := &in.LastModified, &out.LastModified
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyStatus.
+func (in *SecretKeyStatus) DeepCopy() *SecretKeyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretLink) DeepCopyInto(out *SecretLink) {
+	*out = *in
+	in.ServiceAccount.DeepCopyInto(&out.ServiceAccount)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretLink.
+func (in *SecretLink) DeepCopy() *SecretLink {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretLink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretOverride) DeepCopyInto(out *SecretOverride) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = new(map[string]string)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make(map[string]string, len(*in))
+			for key, val := range *in {
+				(*out)[key] = val
+			}
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = new(map[string]string)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make(map[string]string, len(*in))
+			for key, val := range *in {
+				(*out)[key] = val
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretOverride.
+func (in *SecretOverride) DeepCopy() *SecretOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStatus) DeepCopyInto(out *SecretStatus) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.KeyDetails != nil {
+		in, out := &in.KeyDetails, &out.KeyDetails
+		*out = make([]SecretKeyStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStatus.
+func (in *SecretStatus) DeepCopy() *SecretStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountLink) DeepCopyInto(out *ServiceAccountLink) {
+	*out = *in
+	out.Reference = in.Reference
+	in.Managed.DeepCopyInto(&out.Managed)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountLink.
+func (in *ServiceAccountLink) DeepCopy() *ServiceAccountLink {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountLink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountPlan) DeepCopyInto(out *ServiceAccountPlan) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountPlan.
+func (in *ServiceAccountPlan) DeepCopy() *ServiceAccountPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetPlan) DeepCopyInto(out *TargetPlan) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceAccounts != nil {
+		in, out := &in.ServiceAccounts, &out.ServiceAccounts
+		*out = make([]ServiceAccountPlan, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetPlan.
+func (in *TargetPlan) DeepCopy() *TargetPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetSecretKey) DeepCopyInto(out *TargetSecretKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetSecretKey.
+func (in *TargetSecretKey) DeepCopy() *TargetSecretKey {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetSecretKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetStatus) DeepCopyInto(out *TargetStatus) {
+	*out = *in
+	if in.ExpectedSecret != nil {
+		in, out := &in.ExpectedSecret, &out.ExpectedSecret
+		*out = new(TargetSecretKey)
+		**out = **in
+	}
+	if in.DeployedSecret != nil {
+		in, out := &in.DeployedSecret, &out.DeployedSecret
+		*out = new(DeployedSecretStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceAccountNames != nil {
+		in, out := &in.ServiceAccountNames, &out.ServiceAccountNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetStatus.
+func (in *TargetStatus) DeepCopy() *TargetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
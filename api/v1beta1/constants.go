@@ -25,17 +25,77 @@ const (
 	ManagingRemoteSecretNameAnnotation = "appstudio.redhat.com/managing-remote-secret" //#nosec G101 -- false positive
 	LinkedRemoteSecretsAnnotation      = "appstudio.redhat.com/linked-remote-secrets"  //#nosec G101 -- false positive
 
-	// ObjectClusterUrlAnnotation is put on the events that are created when we fail to clean up the deployed secrets during the RemoteSecret
-	// finalization. It specifies the API URL of the cluster where the secrets were deployed to (because there is no other good place to put
-	// this information on the Event object).
+	// ObjectClusterUrlAnnotation is put on the events about the targets of a RemoteSecret in remote clusters. It specifies the API URL
+	// of the cluster where the secrets were deployed to (because there is no other good place to put this information on the Event object).
 	ObjectClusterUrlAnnotation = "appstudio.redhat.com/object-cluster-url"
 
 	// RemoteSecretPartialUpdateAnnotation if present on the upload secret, this marks the upload secret as performing a partial update of the already existing secret data
 	// of the remote secret that the upload secret refers to using the RemoteSecretNameAnnotation annotation. The value of this annotation is not important but should be documented
 	// as "true". The data of the upload secret is used to update the secret data (i.e. the keys from the upload secret overwrite the keys in the secret data (adding new keys if not
 	// present in the secret data)).
+	// If present on the remote secret, the data and stringData of the remote secret partially update the stored data in the same way, unless
+	// there is no stored data yet.
 	RemoteSecretPartialUpdateAnnotation = "appstudio.redhat.com/remotesecret-partial-update"
 	// RemoteSecretDeletedKeysAnnotation should be placed on an upload secret if the user want to remove some keys from the secret data of an already existing remote secret. It
-	// contains the comma-separated list of keys that should be removed.
+	// contains the comma-separated list of keys that should be removed. It can also be put on a remote secret together with the
+	// RemoteSecretPartialUpdateAnnotation, in which case it is removed from the remote secret once the keys are deleted.
 	RemoteSecretDeletedKeysAnnotation = "appstudio.redhat.com/remotesecret-deleted-keys"
+
+	// DryRunAnnotation if set to "true" on a remote secret, makes the controller only compute the changes it would make in the targets
+	// and report them in the DryRunPlan of the remote secret status instead of actually deploying to the targets.
+	DryRunAnnotation = "appstudio.redhat.com/remotesecret-dry-run"
+
+	// DataFingerprintAnnotation is put on the secrets deployed to the targets. It contains the fingerprint of the deployed data, the same
+	// as the DataFingerprint in the status of the target, so that it is possible to tell which version of the data each target has.
+	DataFingerprintAnnotation = "appstudio.redhat.com/remotesecret-data-fingerprint"
+
+	// LastDataUpdateAnnotation is used by the webhook to pass the description of the data update to the controller, which moves it to
+	// the LastDataUpdate in the status of the remote secret. The webhook cannot set the status itself, because the status of a newly created
+	// object is ignored. Any value set by the user is ignored.
+	LastDataUpdateAnnotation = "appstudio.redhat.com/remotesecret-last-data-update"
+
+	// UploadFailedAtAnnotation is put on the failed upload secrets that are kept for debugging. It contains the time of the failure
+	// in the RFC3339 format. Removing the annotation makes the upload secret be processed again.
+	UploadFailedAtAnnotation = "appstudio.redhat.com/remotesecret-upload-failed-at"
+	// UploadErrorAnnotation is put on the failed upload secrets that are kept for debugging. It contains the error message.
+	UploadErrorAnnotation = "appstudio.redhat.com/remotesecret-upload-error"
+
+	// ExpectedDataVersionAnnotation can be put on the upload secrets and on the remote secrets uploading the data using
+	// the webhook. The data is then only updated if the stored data has the version in the annotation, i.e. it was not
+	// changed since the version was read from the status of the remote secret. The empty value means that there must be
+	// no stored data.
+	ExpectedDataVersionAnnotation = "appstudio.redhat.com/remotesecret-expected-data-version"
+
+	// ClusterCredentialsAllowedRemoteSecretsAnnotation can be put on a secret with a kubeconfig to restrict the remote secrets that can use it
+	// in the clusterCredentialsSecret of their targets. It contains the comma-separated list of the names of the remote secrets from the same namespace.
+	// If the annotation is not present, any remote secret in the namespace can use the kubeconfig.
+	ClusterCredentialsAllowedRemoteSecretsAnnotation = "appstudio.redhat.com/allowed-remote-secrets" //#nosec G101 -- false positive
+
+	// ImportAuthorizedSecretsAnnotation is maintained by the webhook on the remote secrets that import their data from the targets.
+	// It contains the comma-separated list of the secrets in the local cluster (as namespace/name) that the user was allowed to read.
+	// The controller doesn't import the data from any other secret in the local cluster. Any value set by the user is ignored.
+	ImportAuthorizedSecretsAnnotation = "appstudio.redhat.com/remotesecret-import-authorized-secrets" //#nosec G101 -- false positive
+
+	// DeleteImportedSecretsAnnotation is set by the webhook on the remote secrets that copied the data from the secrets with
+	// deleteAfterImport. It contains the comma-separated list of the secrets (as namespace/name) that the controller deletes once
+	// the remote secret with the copied data is persisted. Any value set by the user is ignored.
+	DeleteImportedSecretsAnnotation = "appstudio.redhat.com/remotesecret-delete-imported-secrets" //#nosec G101 -- false positive
+
+	// DataAPIUpdateAnnotation is set by the data API server on the remote secrets whose data it changed. It contains the time
+	// of the change in the RFC3339 format with nanoseconds. The data API writes the data directly to the storage, so changing
+	// the annotation is what makes the controller (possibly running in another replica) deploy the new data.
+	DataAPIUpdateAnnotation = "appstudio.redhat.com/remotesecret-data-api-update"
+)
+
+// The reasons of the events recorded by the operator on the remote secrets and the upload secrets.
+const (
+	RemoteSecretEventReasonDataUploaded           = "DataUploaded"
+	RemoteSecretEventReasonDataUploadFailed       = "DataUploadFailed"
+	RemoteSecretEventReasonDataCopied             = "DataCopied"
+	RemoteSecretEventReasonDataDeleted            = "DataDeleted"
+	RemoteSecretEventReasonTargetDeployed         = "TargetDeployed"
+	RemoteSecretEventReasonTargetDeploymentFailed = "TargetDeploymentFailed"
+	RemoteSecretEventReasonTargetRemoved          = "TargetRemoved"
+	RemoteSecretEventReasonTargetCleanupFailed    = "TargetCleanupFailed"
+	RemoteSecretEventReasonSourceDeletionFailed   = "SourceDeletionFailed"
 )
@@ -0,0 +1,737 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	v1 "github.com/redhat-appstudio/remote-secret/api/v1"
+)
+
+// ConversionDataAnnotation holds the parts of the remote secret that cannot be represented in the API version the remote
+// secret was converted to. Converting the remote secret back to the original API version restores them from the annotation
+// so that the conversion is lossless.
+const ConversionDataAnnotation = "appstudio.redhat.com/remotesecret-conversion-data"
+
+var _ conversion.Convertible = (*RemoteSecret)(nil)
+
+var unsupportedConversionError = errors.New("unsupported conversion of the remote secret")
+
+// conversionData is the content of the ConversionDataAnnotation. The targets are identified by their index in the status.
+type conversionData struct {
+	// TargetSecretNames contains the deprecated v1beta1 secret names of the targets that don't match the names of the deployed secrets.
+	TargetSecretNames map[int]string `json:"targetSecretNames,omitempty"`
+	// TargetsWithoutDeployedCondition contains the v1 targets with a deployed secret but without the Deployed condition, which
+	// would otherwise be derived when converting back to v1.
+	TargetsWithoutDeployedCondition []int `json:"targetsWithoutDeployedCondition,omitempty"`
+	// ReplicationTargetConditions contains the v1 conditions of the replication targets that cannot be derived from v1beta1.
+	ReplicationTargetConditions map[int][]metav1.Condition `json:"replicationTargetConditions,omitempty"`
+}
+
+// ConvertTo converts this remote secret to the hub version, v1.
+func (rs *RemoteSecret) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1.RemoteSecret)
+	if !ok {
+		return fmt.Errorf("%w: %T", unsupportedConversionError, dstRaw)
+	}
+	src := rs.DeepCopy()
+
+	data := popConversionData(&src.ObjectMeta)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = convertSpecToV1(&src.Spec)
+	dst.Status = v1.RemoteSecretStatus{
+		Conditions:         src.Status.Conditions,
+		ObservedGeneration: src.Status.ObservedGeneration,
+		SecretStatus:       convertSecretStatusToV1(&src.Status.SecretStatus),
+		DryRunPlan:         convertDryRunPlanToV1(src.Status.DryRunPlan),
+		ReplicationTargets: convertReplicationTargetStatusesToV1(src.Status.ReplicationTargets, data, src.CreationTimestamp),
+		LastDataUpdate:     convertDataUpdateStatusToV1(src.Status.LastDataUpdate),
+	}
+	dst.UploadData = src.UploadData
+	dst.StringUploadData = src.StringUploadData
+	dst.DataFrom = convertDataFromToV1(&src.DataFrom)
+
+	lost := conversionData{}
+	withoutDeployedCondition := sets.New(data.TargetsWithoutDeployedCondition...)
+	if len(src.Status.Targets) > 0 {
+		dst.Status.Targets = make([]v1.TargetStatus, len(src.Status.Targets))
+	}
+	for i := range src.Status.Targets {
+		t := &src.Status.Targets[i]
+		dst.Status.Targets[i] = v1.TargetStatus{
+			Namespace:                t.Namespace,
+			ApiUrl:                   t.ApiUrl,
+			ClusterCredentialsSecret: t.ClusterCredentialsSecret,
+			ExpectedSecret:           (*v1.TargetSecretKey)(t.ExpectedSecret),
+			DeployedSecret:           (*v1.DeployedSecretStatus)(t.DeployedSecret),
+			ServiceAccountNames:      t.ServiceAccountNames,
+			Conditions:               t.Conditions,
+			LastSyncTime:             t.LastSyncTime,
+			ObservedGeneration:       t.ObservedGeneration,
+			DataFingerprint:          t.DataFingerprint,
+		}
+
+		// the Deployed condition is only kept if the error hasn't been changed using v1beta1 since the last conversion. Otherwise,
+		// it is derived from the error and put in front of the other conditions, where ConvertFrom expects it. The missing Deployed
+		// condition is only kept if it was missing in v1, too.
+		keep := errorFromTargetConditions(t.Conditions) == t.Error
+		if meta.FindStatusCondition(t.Conditions, string(v1.TargetConditionTypeDeployed)) == nil {
+			keep = keep && withoutDeployedCondition.Has(i)
+		}
+		if !keep {
+			conditions := targetConditionsFromError(t.Error, t.DeployedSecret != nil, src.CreationTimestamp)
+			for _, c := range t.Conditions {
+				if c.Type != string(v1.TargetConditionTypeDeployed) {
+					conditions = append(conditions, c)
+				}
+			}
+			dst.Status.Targets[i].Conditions = conditions
+		}
+
+		if t.SecretName != deployedSecretName(t.DeployedSecret) { //nolint:staticcheck // SA1019 - the deprecated field needs to be preserved
+			if lost.TargetSecretNames == nil {
+				lost.TargetSecretNames = map[int]string{}
+			}
+			lost.TargetSecretNames[i] = t.SecretName //nolint:staticcheck // SA1019 - the deprecated field needs to be preserved
+		}
+	}
+
+	return setConversionData(&dst.ObjectMeta, lost)
+}
+
+// ConvertFrom converts the hub version, v1, of the remote secret to this version.
+func (rs *RemoteSecret) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1.RemoteSecret)
+	if !ok {
+		return fmt.Errorf("%w: %T", unsupportedConversionError, srcRaw)
+	}
+	src = src.DeepCopy()
+
+	data := popConversionData(&src.ObjectMeta)
+	lost := conversionData{}
+
+	rs.ObjectMeta = src.ObjectMeta
+	rs.Spec = convertSpecFromV1(&src.Spec)
+	rs.Status = RemoteSecretStatus{
+		Conditions:         src.Status.Conditions,
+		ObservedGeneration: src.Status.ObservedGeneration,
+		SecretStatus:       convertSecretStatusFromV1(&src.Status.SecretStatus),
+		DryRunPlan:         convertDryRunPlanFromV1(src.Status.DryRunPlan),
+		ReplicationTargets: convertReplicationTargetStatusesFromV1(src.Status.ReplicationTargets, &lost, src.CreationTimestamp),
+		LastDataUpdate:     convertDataUpdateStatusFromV1(src.Status.LastDataUpdate),
+	}
+	rs.UploadData = src.UploadData
+	rs.StringUploadData = src.StringUploadData
+	rs.DataFrom = convertDataFromFromV1(&src.DataFrom)
+
+	if len(src.Status.Targets) > 0 {
+		rs.Status.Targets = make([]TargetStatus, len(src.Status.Targets))
+	}
+	for i := range src.Status.Targets {
+		t := &src.Status.Targets[i]
+		rs.Status.Targets[i] = TargetStatus{
+			Namespace:                t.Namespace,
+			ApiUrl:                   t.ApiUrl,
+			ClusterCredentialsSecret: t.ClusterCredentialsSecret,
+			ExpectedSecret:           (*TargetSecretKey)(t.ExpectedSecret),
+			DeployedSecret:           (*DeployedSecretStatus)(t.DeployedSecret),
+			ServiceAccountNames:      t.ServiceAccountNames,
+			Error:                    errorFromTargetConditions(t.Conditions),
+			Conditions:               t.Conditions,
+			LastSyncTime:             t.LastSyncTime,
+			ObservedGeneration:       t.ObservedGeneration,
+			DataFingerprint:          t.DataFingerprint,
+		}
+
+		if secretName, ok := data.TargetSecretNames[i]; ok {
+			rs.Status.Targets[i].SecretName = secretName //nolint:staticcheck // SA1019 - the deprecated field needs to be set
+		} else {
+			rs.Status.Targets[i].SecretName = deployedSecretName(rs.Status.Targets[i].DeployedSecret) //nolint:staticcheck // SA1019 - the deprecated field needs to be set
+		}
+
+		// the Deployed condition is not stored in v1beta1 if ConvertTo can derive it again from the error. If there is none, ConvertTo
+		// must not derive it.
+		derived := targetConditionsFromError(rs.Status.Targets[i].Error, t.DeployedSecret != nil, src.CreationTimestamp)
+		if len(derived) > 0 && len(t.Conditions) > 0 && equality.Semantic.DeepEqual(derived[0], t.Conditions[0]) {
+			rs.Status.Targets[i].Conditions = t.Conditions[1:]
+			if len(rs.Status.Targets[i].Conditions) == 0 {
+				rs.Status.Targets[i].Conditions = nil
+			}
+		} else if len(derived) > 0 && meta.FindStatusCondition(t.Conditions, string(v1.TargetConditionTypeDeployed)) == nil {
+			lost.TargetsWithoutDeployedCondition = append(lost.TargetsWithoutDeployedCondition, i)
+		}
+	}
+
+	return setConversionData(&rs.ObjectMeta, lost)
+}
+
+// targetConditionsFromError derives the v1 conditions of a target from the v1beta1 error of the target. The creation timestamp of
+// the remote secret is used as the last transition time, so that the same conditions are derived every time.
+func targetConditionsFromError(err string, deployed bool, creationTimestamp metav1.Time) []metav1.Condition {
+	var conditions []metav1.Condition
+	if err != "" {
+		meta.SetStatusCondition(&conditions, metav1.Condition{
+			Type:    string(v1.TargetConditionTypeDeployed),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(v1.RemoteSecretReasonError),
+			Message: err,
+		})
+	} else if deployed {
+		meta.SetStatusCondition(&conditions, metav1.Condition{
+			Type:   string(v1.TargetConditionTypeDeployed),
+			Status: metav1.ConditionTrue,
+			Reason: string(v1.RemoteSecretReasonInjected),
+		})
+	}
+
+	for i := range conditions {
+		conditions[i].LastTransitionTime = creationTimestamp
+	}
+
+	return conditions
+}
+
+// replicationTargetConditionsFromStatus derives the v1 conditions of a replication target from its v1beta1 status. Like with the
+// targets, the creation timestamp of the remote secret is used as the last transition time.
+func replicationTargetConditionsFromStatus(st *ReplicationTargetStatus, creationTimestamp metav1.Time) []metav1.Condition {
+	var cond metav1.Condition
+	switch {
+	case st.Error != "":
+		cond = metav1.Condition{
+			Status:  metav1.ConditionFalse,
+			Reason:  string(v1.RemoteSecretReasonError),
+			Message: st.Error,
+		}
+	case st.TransferSecretName != "":
+		cond = metav1.Condition{
+			Status: metav1.ConditionFalse,
+			Reason: string(v1.RemoteSecretReasonReplicationPending),
+		}
+	case st.ReplicatedDataHash != "":
+		cond = metav1.Condition{
+			Status: metav1.ConditionTrue,
+			Reason: string(v1.RemoteSecretReasonReplicated),
+		}
+	default:
+		return nil
+	}
+
+	cond.Type = string(v1.ReplicationTargetConditionTypeReplicated)
+	cond.LastTransitionTime = creationTimestamp
+	return []metav1.Condition{cond}
+}
+
+// errorFromReplicationTargetConditions returns the v1beta1 error of a replication target given its v1 conditions.
+func errorFromReplicationTargetConditions(conditions []metav1.Condition) string {
+	cond := meta.FindStatusCondition(conditions, string(v1.ReplicationTargetConditionTypeReplicated))
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != string(v1.RemoteSecretReasonError) {
+		return ""
+	}
+	return cond.Message
+}
+
+// errorFromTargetConditions returns the v1beta1 error of a target given its v1 conditions.
+func errorFromTargetConditions(conditions []metav1.Condition) string {
+	cond := meta.FindStatusCondition(conditions, string(v1.TargetConditionTypeDeployed))
+	if cond == nil || cond.Status == metav1.ConditionTrue {
+		return ""
+	}
+	return cond.Message
+}
+
+func deployedSecretName(deployedSecret *DeployedSecretStatus) string {
+	if deployedSecret == nil {
+		return ""
+	}
+	return deployedSecret.Name
+}
+
+// popConversionData removes the ConversionDataAnnotation from the object and returns its parsed content.
+func popConversionData(obj *metav1.ObjectMeta) conversionData {
+	data := conversionData{}
+
+	raw, ok := obj.Annotations[ConversionDataAnnotation]
+	if !ok {
+		return data
+	}
+
+	delete(obj.Annotations, ConversionDataAnnotation)
+	if len(obj.Annotations) == 0 {
+		obj.Annotations = nil
+	}
+
+	// the annotation can be modified by the users. Failing the conversion would make the remote secret unreadable, so we rather
+	// just ignore the malformed data and derive the missing parts again.
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		return conversionData{}
+	}
+
+	return data
+}
+
+// setConversionData stores the data in the ConversionDataAnnotation unless the data is empty.
+func setConversionData(obj *metav1.ObjectMeta, data conversionData) error {
+	if len(data.TargetSecretNames) == 0 && len(data.TargetsWithoutDeployedCondition) == 0 && len(data.ReplicationTargetConditions) == 0 {
+		return nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to serialize the conversion data: %w", err)
+	}
+
+	if obj.Annotations == nil {
+		obj.Annotations = map[string]string{}
+	}
+	obj.Annotations[ConversionDataAnnotation] = string(raw)
+
+	return nil
+}
+
+func convertSpecToV1(src *RemoteSecretSpec) v1.RemoteSecretSpec {
+	dst := v1.RemoteSecretSpec{
+		Secret: v1.LinkableSecretSpec{
+			Name:         src.Secret.Name,
+			GenerateName: src.Secret.GenerateName,
+			Labels:       src.Secret.Labels,
+			Annotations:  src.Secret.Annotations,
+			Type:         src.Secret.Type,
+		},
+		Suspend: src.Suspend,
+		Adoption: v1.SecretAdoption{
+			Policy:     v1.AdoptionPolicy(src.Adoption.Policy),
+			ImportData: src.Adoption.ImportData,
+		},
+	}
+
+	if src.Secret.RequiredKeys != nil {
+		dst.Secret.RequiredKeys = make([]v1.SecretKey, len(src.Secret.RequiredKeys))
+		for i, k := range src.Secret.RequiredKeys {
+			dst.Secret.RequiredKeys[i] = v1.SecretKey{
+				Name:         k.Name,
+				Optional:     k.Optional,
+				DefaultValue: k.DefaultValue,
+				Pattern:      k.Pattern,
+				MinLength:    k.MinLength,
+				MaxLength:    k.MaxLength,
+				Format:       v1.SecretKeyFormat(k.Format),
+			}
+		}
+	}
+
+	if src.Secret.LinkedTo != nil {
+		dst.Secret.LinkedTo = make([]v1.SecretLink, len(src.Secret.LinkedTo))
+		for i, l := range src.Secret.LinkedTo {
+			dst.Secret.LinkedTo[i] = v1.SecretLink{
+				ServiceAccount: v1.ServiceAccountLink{
+					As:        v1.ServiceAccountLinkType(l.ServiceAccount.As),
+					Reference: l.ServiceAccount.Reference,
+					Managed:   v1.ManagedServiceAccountSpec(l.ServiceAccount.Managed),
+				},
+			}
+		}
+	}
+
+	if src.Targets != nil {
+		dst.Targets = make([]v1.RemoteSecretTarget, len(src.Targets))
+		for i, t := range src.Targets {
+			dst.Targets[i] = v1.RemoteSecretTarget{
+				Secret:                   (*v1.SecretOverride)(t.Secret),
+				Namespace:                t.Namespace,
+				ApiUrl:                   t.ApiUrl,
+				ClusterCredentialsSecret: t.ClusterCredentialsSecret,
+				DeletionPolicy:           v1.DeletionPolicy(t.DeletionPolicy),
+			}
+		}
+	}
+
+	if src.ReplicationTargets != nil {
+		dst.ReplicationTargets = make([]v1.ReplicationTarget, len(src.ReplicationTargets))
+		for i, t := range src.ReplicationTargets {
+			dst.ReplicationTargets[i] = v1.ReplicationTarget(t)
+		}
+	}
+
+	if src.ExternalSource != nil {
+		dst.ExternalSource = &v1.ExternalSource{
+			RefreshInterval: src.ExternalSource.RefreshInterval,
+		}
+		if src.ExternalSource.Data != nil {
+			dst.ExternalSource.Data = make([]v1.ExternalSourceData, len(src.ExternalSource.Data))
+			for i, d := range src.ExternalSource.Data {
+				dst.ExternalSource.Data[i] = v1.ExternalSourceData{
+					SecretKey: d.SecretKey,
+					RemoteRef: v1.ExternalSourceRef(d.RemoteRef),
+				}
+			}
+		}
+		if src.ExternalSource.DataFrom != nil {
+			dst.ExternalSource.DataFrom = make([]v1.ExternalSourceRef, len(src.ExternalSource.DataFrom))
+			for i, r := range src.ExternalSource.DataFrom {
+				dst.ExternalSource.DataFrom[i] = v1.ExternalSourceRef(r)
+			}
+		}
+	}
+
+	return dst
+}
+
+func convertSpecFromV1(src *v1.RemoteSecretSpec) RemoteSecretSpec {
+	dst := RemoteSecretSpec{
+		Secret: LinkableSecretSpec{
+			Name:         src.Secret.Name,
+			GenerateName: src.Secret.GenerateName,
+			Labels:       src.Secret.Labels,
+			Annotations:  src.Secret.Annotations,
+			Type:         src.Secret.Type,
+		},
+		Suspend: src.Suspend,
+		Adoption: SecretAdoption{
+			Policy:     AdoptionPolicy(src.Adoption.Policy),
+			ImportData: src.Adoption.ImportData,
+		},
+	}
+
+	if src.Secret.RequiredKeys != nil {
+		dst.Secret.RequiredKeys = make([]SecretKey, len(src.Secret.RequiredKeys))
+		for i, k := range src.Secret.RequiredKeys {
+			dst.Secret.RequiredKeys[i] = SecretKey{
+				Name:         k.Name,
+				Optional:     k.Optional,
+				DefaultValue: k.DefaultValue,
+				Pattern:      k.Pattern,
+				MinLength:    k.MinLength,
+				MaxLength:    k.MaxLength,
+				Format:       SecretKeyFormat(k.Format),
+			}
+		}
+	}
+
+	if src.Secret.LinkedTo != nil {
+		dst.Secret.LinkedTo = make([]SecretLink, len(src.Secret.LinkedTo))
+		for i, l := range src.Secret.LinkedTo {
+			dst.Secret.LinkedTo[i] = SecretLink{
+				ServiceAccount: ServiceAccountLink{
+					As:        ServiceAccountLinkType(l.ServiceAccount.As),
+					Reference: l.ServiceAccount.Reference,
+					Managed:   ManagedServiceAccountSpec(l.ServiceAccount.Managed),
+				},
+			}
+		}
+	}
+
+	if src.Targets != nil {
+		dst.Targets = make([]RemoteSecretTarget, len(src.Targets))
+		for i, t := range src.Targets {
+			dst.Targets[i] = RemoteSecretTarget{
+				Secret:                   (*SecretOverride)(t.Secret),
+				Namespace:                t.Namespace,
+				ApiUrl:                   t.ApiUrl,
+				ClusterCredentialsSecret: t.ClusterCredentialsSecret,
+				DeletionPolicy:           DeletionPolicy(t.DeletionPolicy),
+			}
+		}
+	}
+
+	if src.ReplicationTargets != nil {
+		dst.ReplicationTargets = make([]ReplicationTarget, len(src.ReplicationTargets))
+		for i, t := range src.ReplicationTargets {
+			dst.ReplicationTargets[i] = ReplicationTarget(t)
+		}
+	}
+
+	if src.ExternalSource != nil {
+		dst.ExternalSource = &ExternalSource{
+			RefreshInterval: src.ExternalSource.RefreshInterval,
+		}
+		if src.ExternalSource.Data != nil {
+			dst.ExternalSource.Data = make([]ExternalSourceData, len(src.ExternalSource.Data))
+			for i, d := range src.ExternalSource.Data {
+				dst.ExternalSource.Data[i] = ExternalSourceData{
+					SecretKey: d.SecretKey,
+					RemoteRef: ExternalSourceRef(d.RemoteRef),
+				}
+			}
+		}
+		if src.ExternalSource.DataFrom != nil {
+			dst.ExternalSource.DataFrom = make([]ExternalSourceRef, len(src.ExternalSource.DataFrom))
+			for i, r := range src.ExternalSource.DataFrom {
+				dst.ExternalSource.DataFrom[i] = ExternalSourceRef(r)
+			}
+		}
+	}
+
+	return dst
+}
+
+func convertDryRunPlanToV1(src *DryRunPlan) *v1.DryRunPlan {
+	if src == nil {
+		return nil
+	}
+
+	dst := &v1.DryRunPlan{}
+	if src.Targets != nil {
+		dst.Targets = make([]v1.TargetPlan, len(src.Targets))
+		for i, t := range src.Targets {
+			dst.Targets[i] = v1.TargetPlan{
+				Namespace:          t.Namespace,
+				ApiUrl:             t.ApiUrl,
+				Action:             v1.PlannedAction(t.Action),
+				SecretName:         t.SecretName,
+				ReplacedSecretName: t.ReplacedSecretName,
+				Changes:            t.Changes,
+				Error:              t.Error,
+			}
+			if t.ServiceAccounts != nil {
+				dst.Targets[i].ServiceAccounts = make([]v1.ServiceAccountPlan, len(t.ServiceAccounts))
+				for j, sa := range t.ServiceAccounts {
+					dst.Targets[i].ServiceAccounts[j] = v1.ServiceAccountPlan{
+						Name:     sa.Name,
+						Action:   v1.PlannedAction(sa.Action),
+						LinkType: v1.ServiceAccountLinkType(sa.LinkType),
+					}
+				}
+			}
+		}
+	}
+
+	return dst
+}
+
+func convertDryRunPlanFromV1(src *v1.DryRunPlan) *DryRunPlan {
+	if src == nil {
+		return nil
+	}
+
+	dst := &DryRunPlan{}
+	if src.Targets != nil {
+		dst.Targets = make([]TargetPlan, len(src.Targets))
+		for i, t := range src.Targets {
+			dst.Targets[i] = TargetPlan{
+				Namespace:          t.Namespace,
+				ApiUrl:             t.ApiUrl,
+				Action:             PlannedAction(t.Action),
+				SecretName:         t.SecretName,
+				ReplacedSecretName: t.ReplacedSecretName,
+				Changes:            t.Changes,
+				Error:              t.Error,
+			}
+			if t.ServiceAccounts != nil {
+				dst.Targets[i].ServiceAccounts = make([]ServiceAccountPlan, len(t.ServiceAccounts))
+				for j, sa := range t.ServiceAccounts {
+					dst.Targets[i].ServiceAccounts[j] = ServiceAccountPlan{
+						Name:     sa.Name,
+						Action:   PlannedAction(sa.Action),
+						LinkType: ServiceAccountLinkType(sa.LinkType),
+					}
+				}
+			}
+		}
+	}
+
+	return dst
+}
+
+// convertReplicationTargetStatusesToV1 converts the statuses of the replication targets. The conditions are derived from the status
+// unless the conditions kept in the conversion data still describe the same error.
+func convertReplicationTargetStatusesToV1(src []ReplicationTargetStatus, data conversionData, creationTimestamp metav1.Time) []v1.ReplicationTargetStatus {
+	if src == nil {
+		return nil
+	}
+
+	dst := make([]v1.ReplicationTargetStatus, len(src))
+	for i := range src {
+		st := &src[i]
+		conditions, ok := data.ReplicationTargetConditions[i]
+		if !ok || errorFromReplicationTargetConditions(conditions) != st.Error {
+			conditions = replicationTargetConditionsFromStatus(st, creationTimestamp)
+		}
+		dst[i] = v1.ReplicationTargetStatus{
+			Namespace:           st.Namespace,
+			RemoteSecretName:    st.RemoteSecretName,
+			ApiUrl:              st.ApiUrl,
+			TransferSecretName:  st.TransferSecretName,
+			TransferStartTime:   st.TransferStartTime,
+			TransferDataHash:    st.TransferDataHash,
+			ReplicatedDataHash:  st.ReplicatedDataHash,
+			LastReplicationTime: st.LastReplicationTime,
+			Conditions:          conditions,
+		}
+	}
+	return dst
+}
+
+// convertReplicationTargetStatusesFromV1 converts the statuses of the replication targets. The conditions that cannot be derived
+// from the converted status are kept in the conversion data.
+func convertReplicationTargetStatusesFromV1(src []v1.ReplicationTargetStatus, lost *conversionData, creationTimestamp metav1.Time) []ReplicationTargetStatus {
+	if src == nil {
+		return nil
+	}
+
+	dst := make([]ReplicationTargetStatus, len(src))
+	for i := range src {
+		st := &src[i]
+		dst[i] = ReplicationTargetStatus{
+			Namespace:           st.Namespace,
+			RemoteSecretName:    st.RemoteSecretName,
+			ApiUrl:              st.ApiUrl,
+			TransferSecretName:  st.TransferSecretName,
+			TransferStartTime:   st.TransferStartTime,
+			TransferDataHash:    st.TransferDataHash,
+			ReplicatedDataHash:  st.ReplicatedDataHash,
+			LastReplicationTime: st.LastReplicationTime,
+			Error:               errorFromReplicationTargetConditions(st.Conditions),
+		}
+
+		if !equality.Semantic.DeepEqual(replicationTargetConditionsFromStatus(&dst[i], creationTimestamp), st.Conditions) {
+			if lost.ReplicationTargetConditions == nil {
+				lost.ReplicationTargetConditions = map[int][]metav1.Condition{}
+			}
+			lost.ReplicationTargetConditions[i] = st.Conditions
+		}
+	}
+	return dst
+}
+
+func convertDataUpdateStatusToV1(src *DataUpdateStatus) *v1.DataUpdateStatus {
+	if src == nil {
+		return nil
+	}
+	return &v1.DataUpdateStatus{
+		Source:      v1.DataUpdateSource(src.Source),
+		SourceName:  src.SourceName,
+		Time:        src.Time,
+		Result:      v1.DataUpdateResult(src.Result),
+		Reason:      src.Reason,
+		Message:     src.Message,
+		Keys:        src.Keys,
+		DeletedKeys: src.DeletedKeys,
+	}
+}
+
+func convertDataUpdateStatusFromV1(src *v1.DataUpdateStatus) *DataUpdateStatus {
+	if src == nil {
+		return nil
+	}
+	return &DataUpdateStatus{
+		Source:      DataUpdateSource(src.Source),
+		SourceName:  src.SourceName,
+		Time:        src.Time,
+		Result:      DataUpdateResult(src.Result),
+		Reason:      src.Reason,
+		Message:     src.Message,
+		Keys:        src.Keys,
+		DeletedKeys: src.DeletedKeys,
+	}
+}
+
+func convertSecretStatusToV1(src *SecretStatus) v1.SecretStatus {
+	dst := v1.SecretStatus{
+		Keys:             src.Keys,
+		FingerprintKeyID: src.FingerprintKeyID,
+		Size:             src.Size,
+		Version:          src.Version,
+	}
+	if src.KeyDetails != nil {
+		dst.KeyDetails = make([]v1.SecretKeyStatus, len(src.KeyDetails))
+		for i, k := range src.KeyDetails {
+			dst.KeyDetails[i] = v1.SecretKeyStatus(k)
+		}
+	}
+	return dst
+}
+
+func convertSecretStatusFromV1(src *v1.SecretStatus) SecretStatus {
+	dst := SecretStatus{
+		Keys:             src.Keys,
+		FingerprintKeyID: src.FingerprintKeyID,
+		Size:             src.Size,
+		Version:          src.Version,
+	}
+	if src.KeyDetails != nil {
+		dst.KeyDetails = make([]SecretKeyStatus, len(src.KeyDetails))
+		for i, k := range src.KeyDetails {
+			dst.KeyDetails[i] = SecretKeyStatus(k)
+		}
+	}
+	return dst
+}
+
+func convertDataFromToV1(src *RemoteSecretDataFrom) v1.RemoteSecretDataFrom {
+	dst := v1.RemoteSecretDataFrom{
+		Name:      src.Name,
+		Namespace: src.Namespace,
+		Follow:    src.Follow,
+	}
+
+	if src.Sources != nil {
+		dst.Sources = make([]v1.RemoteSecretDataSource, len(src.Sources))
+		for i, s := range src.Sources {
+			dst.Sources[i] = v1.RemoteSecretDataSource{
+				Kind:              v1.RemoteSecretDataSourceKind(s.Kind),
+				Name:              s.Name,
+				Namespace:         s.Namespace,
+				DeleteAfterImport: s.DeleteAfterImport,
+			}
+			if s.Keys != nil {
+				dst.Sources[i].Keys = make([]v1.RemoteSecretDataSourceKey, len(s.Keys))
+				for j, k := range s.Keys {
+					dst.Sources[i].Keys[j] = v1.RemoteSecretDataSourceKey(k)
+				}
+			}
+		}
+	}
+
+	return dst
+}
+
+func convertDataFromFromV1(src *v1.RemoteSecretDataFrom) RemoteSecretDataFrom {
+	dst := RemoteSecretDataFrom{
+		Name:      src.Name,
+		Namespace: src.Namespace,
+		Follow:    src.Follow,
+	}
+
+	if src.Sources != nil {
+		dst.Sources = make([]RemoteSecretDataSource, len(src.Sources))
+		for i, s := range src.Sources {
+			dst.Sources[i] = RemoteSecretDataSource{
+				Kind:              RemoteSecretDataSourceKind(s.Kind),
+				Name:              s.Name,
+				Namespace:         s.Namespace,
+				DeleteAfterImport: s.DeleteAfterImport,
+			}
+			if s.Keys != nil {
+				dst.Sources[i].Keys = make([]RemoteSecretDataSourceKey, len(s.Keys))
+				for j, k := range s.Keys {
+					dst.Sources[i].Keys[j] = RemoteSecretDataSourceKey(k)
+				}
+			}
+		}
+	}
+
+	return dst
+}
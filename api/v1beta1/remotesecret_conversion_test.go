@@ -0,0 +1,545 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	fuzz "github.com/google/gofuzz"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	v1 "github.com/redhat-appstudio/remote-secret/api/v1"
+)
+
+var conversionTestCreationTimestamp = metav1.NewTime(time.Date(2023, 9, 1, 10, 0, 0, 0, time.UTC))
+
+func TestConversionRoundTripFromV1Beta1(t *testing.T) {
+	labels := map[string]string{"a": "b"}
+	now := metav1.NewTime(time.Date(2023, 9, 2, 10, 0, 0, 0, time.UTC))
+	rs := &RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "rs",
+			Namespace:         "ns",
+			CreationTimestamp: conversionTestCreationTimestamp,
+			Annotations:       map[string]string{"anno": "value"},
+		},
+		Spec: RemoteSecretSpec{
+			Secret: LinkableSecretSpec{
+				GenerateName: "secret-",
+				Labels:       map[string]string{"l": "v"},
+				Type:         corev1.SecretTypeBasicAuth,
+				RequiredKeys: []SecretKey{
+					{Name: "username"},
+					{Name: "url", DefaultValue: ptr.To("https://example.com"), Format: SecretKeyFormatUrl, MaxLength: ptr.To[int32](100)},
+					{Name: "token", Optional: true, Pattern: "^[a-z]+$", MinLength: ptr.To[int32](8)},
+				},
+				LinkedTo: []SecretLink{
+					{
+						ServiceAccount: ServiceAccountLink{
+							As:        ServiceAccountLinkTypeImagePullSecret,
+							Reference: corev1.LocalObjectReference{Name: "sa"},
+						},
+					},
+					{
+						ServiceAccount: ServiceAccountLink{
+							Managed: ManagedServiceAccountSpec{GenerateName: "sa-", Labels: labels},
+						},
+					},
+				},
+			},
+			Targets: []RemoteSecretTarget{
+				{
+					Namespace:      "target-ns",
+					DeletionPolicy: DeletionPolicyOrphan,
+					Secret: &SecretOverride{
+						Name:   "override",
+						Labels: &labels,
+					},
+				},
+				{
+					Namespace:                "remote-ns",
+					ApiUrl:                   "https://over.there",
+					ClusterCredentialsSecret: "kubeconfig",
+				},
+			},
+			Suspend: true,
+			Adoption: SecretAdoption{
+				Policy:     AdoptionPolicyAlways,
+				ImportData: true,
+			},
+			ReplicationTargets: []ReplicationTarget{
+				{Namespace: "replica-ns", Continuous: true},
+			},
+			ExternalSource: &ExternalSource{
+				Data:            []ExternalSourceData{{SecretKey: "token", RemoteRef: ExternalSourceRef{Key: "app/token", Property: "value", Version: "2"}}},
+				DataFrom:        []ExternalSourceRef{{Key: "app/db"}},
+				RefreshInterval: &metav1.Duration{Duration: time.Minute},
+			},
+		},
+		Status: RemoteSecretStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:               string(RemoteSecretConditionTypeDeployed),
+					Status:             metav1.ConditionFalse,
+					Reason:             string(RemoteSecretReasonPartiallyInjected),
+					LastTransitionTime: now,
+				},
+			},
+			Targets: []TargetStatus{
+				{
+					Namespace:           "target-ns",
+					SecretName:          "override",
+					ServiceAccountNames: []string{"sa-abcde"},
+					ExpectedSecret:      &TargetSecretKey{Name: "override"},
+					DeployedSecret:      &DeployedSecretStatus{Name: "override", Labels: labels},
+					Conditions: []metav1.Condition{
+						{
+							Type:               string(TargetConditionTypeReady),
+							Status:             metav1.ConditionTrue,
+							Reason:             string(TargetReasonDeployed),
+							LastTransitionTime: now,
+							ObservedGeneration: 2,
+						},
+					},
+					LastSyncTime:       &now,
+					ObservedGeneration: 2,
+					DataFingerprint:    "fingerprint",
+				},
+				{
+					Namespace:                "remote-ns",
+					ApiUrl:                   "https://over.there",
+					ClusterCredentialsSecret: "kubeconfig",
+					ExpectedSecret:           &TargetSecretKey{GenerateName: "secret-"},
+					Error:                    "failed to deploy",
+				},
+			},
+			ObservedGeneration: 2,
+			SecretStatus: SecretStatus{
+				Keys: []string{"password", "username"},
+				KeyDetails: []SecretKeyStatus{
+					{Name: "password", Fingerprint: "fp1", Size: 6, LastModified: &now},
+					{Name: "username", Fingerprint: "fp2", Size: 4, LastModified: &now},
+				},
+				FingerprintKeyID: "key",
+				Version:          "3",
+				Size:             26,
+			},
+			DryRunPlan: &DryRunPlan{
+				Targets: []TargetPlan{
+					{
+						Namespace: "target-ns",
+						Action:    PlannedActionUpdate,
+						Changes:   []string{"data key username"},
+						ServiceAccounts: []ServiceAccountPlan{
+							{Name: "sa", Action: PlannedActionLink, LinkType: ServiceAccountLinkTypeImagePullSecret},
+						},
+					},
+				},
+			},
+			ReplicationTargets: []ReplicationTargetStatus{
+				{Namespace: "replica-ns", RemoteSecretName: "rs", ReplicatedDataHash: "hash", LastReplicationTime: &now},
+				{Namespace: "other-replica-ns", RemoteSecretName: "rs", Error: "the replica failed to process the transferred data"},
+			},
+			LastDataUpdate: &DataUpdateStatus{
+				Source:      DataUpdateSourceUploadSecret,
+				SourceName:  "upload",
+				Time:        now,
+				Result:      DataUpdateResultFailed,
+				Reason:      "invalid_upload_secret",
+				Message:     "validation of upload secret failed",
+				Keys:        []string{"username"},
+				DeletedKeys: []string{"password"},
+			},
+		},
+		DataFrom: RemoteSecretDataFrom{
+			Name:      "other",
+			Namespace: "other-ns",
+			Sources: []RemoteSecretDataSource{
+				{Name: "ca", Namespace: "ca-ns", Keys: []RemoteSecretDataSourceKey{{Name: "ca.crt", As: "ca"}}},
+				{Name: "creds"},
+				{Kind: RemoteSecretDataSourceKindSecret, Name: "legacy", DeleteAfterImport: true},
+			},
+			Follow: true,
+		},
+	}
+
+	hub := &v1.RemoteSecret{}
+	assert.NoError(t, rs.DeepCopy().ConvertTo(hub))
+
+	// the errors are converted to the typed conditions and nothing needs to be remembered in the annotation
+	assert.NotContains(t, hub.Annotations, ConversionDataAnnotation)
+	assert.Equal(t, []metav1.Condition{
+		{
+			Type:               string(v1.TargetConditionTypeDeployed),
+			Status:             metav1.ConditionTrue,
+			Reason:             string(v1.RemoteSecretReasonInjected),
+			LastTransitionTime: conversionTestCreationTimestamp,
+		},
+		{
+			Type:               string(v1.TargetConditionTypeReady),
+			Status:             metav1.ConditionTrue,
+			Reason:             string(v1.TargetReasonDeployed),
+			LastTransitionTime: now,
+			ObservedGeneration: 2,
+		},
+	}, hub.Status.Targets[0].Conditions)
+	assert.Equal(t, "fingerprint", hub.Status.Targets[0].DataFingerprint)
+	assert.Equal(t, []metav1.Condition{
+		{
+			Type:               string(v1.TargetConditionTypeDeployed),
+			Status:             metav1.ConditionFalse,
+			Reason:             string(v1.RemoteSecretReasonError),
+			Message:            "failed to deploy",
+			LastTransitionTime: conversionTestCreationTimestamp,
+		},
+	}, hub.Status.Targets[1].Conditions)
+	assert.Equal(t, []metav1.Condition{
+		{
+			Type:               string(v1.ReplicationTargetConditionTypeReplicated),
+			Status:             metav1.ConditionTrue,
+			Reason:             string(v1.RemoteSecretReasonReplicated),
+			LastTransitionTime: conversionTestCreationTimestamp,
+		},
+	}, hub.Status.ReplicationTargets[0].Conditions)
+	assert.Equal(t, []metav1.Condition{
+		{
+			Type:               string(v1.ReplicationTargetConditionTypeReplicated),
+			Status:             metav1.ConditionFalse,
+			Reason:             string(v1.RemoteSecretReasonError),
+			Message:            "the replica failed to process the transferred data",
+			LastTransitionTime: conversionTestCreationTimestamp,
+		},
+	}, hub.Status.ReplicationTargets[1].Conditions)
+
+	converted := &RemoteSecret{}
+	assert.NoError(t, converted.ConvertFrom(hub))
+	assert.Equal(t, rs, converted)
+}
+
+func TestConversionRoundTripFromV1(t *testing.T) {
+	transition := metav1.NewTime(time.Date(2023, 9, 2, 10, 0, 0, 0, time.UTC))
+	hub := &v1.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "rs",
+			Namespace:         "ns",
+			CreationTimestamp: conversionTestCreationTimestamp,
+		},
+		Spec: v1.RemoteSecretSpec{
+			Secret: v1.LinkableSecretSpec{Name: "secret"},
+			Targets: []v1.RemoteSecretTarget{
+				{Namespace: "a"},
+				{Namespace: "b"},
+				{Namespace: "c"},
+			},
+		},
+		Status: v1.RemoteSecretStatus{
+			Targets: []v1.TargetStatus{
+				{
+					Namespace:      "a",
+					DeployedSecret: &v1.DeployedSecretStatus{Name: "secret"},
+					Conditions: []metav1.Condition{
+						{
+							Type:               string(v1.TargetConditionTypeDeployed),
+							Status:             metav1.ConditionTrue,
+							Reason:             string(v1.RemoteSecretReasonInjected),
+							LastTransitionTime: transition,
+							ObservedGeneration: 3,
+						},
+					},
+				},
+				{
+					Namespace: "b",
+					Conditions: []metav1.Condition{
+						{
+							Type:               string(v1.TargetConditionTypeDeployed),
+							Status:             metav1.ConditionFalse,
+							Reason:             string(v1.RemoteSecretReasonError),
+							Message:            "kaboom",
+							LastTransitionTime: conversionTestCreationTimestamp,
+						},
+					},
+				},
+				{
+					Namespace: "c",
+				},
+			},
+			ReplicationTargets: []v1.ReplicationTargetStatus{
+				{
+					Namespace:          "replica-ns",
+					RemoteSecretName:   "rs",
+					ReplicatedDataHash: "hash",
+					Conditions: []metav1.Condition{
+						{
+							Type:               string(v1.ReplicationTargetConditionTypeReplicated),
+							Status:             metav1.ConditionTrue,
+							Reason:             string(v1.RemoteSecretReasonReplicated),
+							LastTransitionTime: conversionTestCreationTimestamp,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	spoke := &RemoteSecret{}
+	assert.NoError(t, spoke.ConvertFrom(hub.DeepCopy()))
+
+	assert.Equal(t, "secret", spoke.Status.Targets[0].SecretName) //nolint:staticcheck // SA1019 - the deprecated field is still converted
+	assert.Empty(t, spoke.Status.Targets[0].Error)
+	assert.Equal(t, "kaboom", spoke.Status.Targets[1].Error)
+	assert.Empty(t, spoke.Status.ReplicationTargets[0].Error)
+	assert.NotContains(t, spoke.Annotations, ConversionDataAnnotation)
+	// only the Deployed condition of the first target cannot be derived from the error
+	assert.Len(t, spoke.Status.Targets[0].Conditions, 1)
+	assert.Nil(t, spoke.Status.Targets[1].Conditions)
+
+	converted := &v1.RemoteSecret{}
+	assert.NoError(t, spoke.ConvertTo(converted))
+	assert.Equal(t, hub, converted)
+}
+
+func TestConversionDoesNotAddDeployedCondition(t *testing.T) {
+	hub := &v1.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "rs",
+			Namespace:         "ns",
+			CreationTimestamp: conversionTestCreationTimestamp,
+		},
+		Status: v1.RemoteSecretStatus{
+			Targets: []v1.TargetStatus{
+				{
+					Namespace:      "a",
+					DeployedSecret: &v1.DeployedSecretStatus{Name: "secret"},
+				},
+			},
+		},
+	}
+
+	spoke := &RemoteSecret{}
+	assert.NoError(t, spoke.ConvertFrom(hub.DeepCopy()))
+	assert.Contains(t, spoke.Annotations, ConversionDataAnnotation)
+
+	converted := &v1.RemoteSecret{}
+	assert.NoError(t, spoke.DeepCopy().ConvertTo(converted))
+	assert.Equal(t, hub, converted)
+
+	// the error set using v1beta1 is still converted to the Deployed condition
+	spoke.Status.Targets[0].Error = "kaboom"
+	assert.NoError(t, spoke.ConvertTo(converted))
+	assert.Equal(t, "kaboom", converted.Status.Targets[0].Conditions[0].Message)
+}
+
+func TestConversionPreservesReplicationTargetConditions(t *testing.T) {
+	hub := &v1.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "rs",
+			Namespace:         "ns",
+			CreationTimestamp: conversionTestCreationTimestamp,
+		},
+		Status: v1.RemoteSecretStatus{
+			ReplicationTargets: []v1.ReplicationTargetStatus{
+				{
+					Namespace:        "replica-ns",
+					RemoteSecretName: "rs",
+					Conditions: []metav1.Condition{
+						{
+							Type:    string(v1.ReplicationTargetConditionTypeReplicated),
+							Status:  metav1.ConditionFalse,
+							Reason:  string(v1.RemoteSecretReasonError),
+							Message: "kaboom",
+							// the times are deserialized from the annotation in the local time zone, like from the API server
+							LastTransitionTime: metav1.NewTime(time.Date(2023, 9, 2, 10, 0, 0, 0, time.UTC).Local()),
+							ObservedGeneration: 2,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	spoke := &RemoteSecret{}
+	assert.NoError(t, spoke.ConvertFrom(hub.DeepCopy()))
+	assert.Equal(t, "kaboom", spoke.Status.ReplicationTargets[0].Error)
+	assert.Contains(t, spoke.Annotations, ConversionDataAnnotation)
+
+	converted := &v1.RemoteSecret{}
+	assert.NoError(t, spoke.DeepCopy().ConvertTo(converted))
+	assert.Equal(t, hub, converted)
+
+	// the conditions are derived again once the error is changed using v1beta1
+	spoke.Status.ReplicationTargets[0].Error = ""
+	spoke.Status.ReplicationTargets[0].ReplicatedDataHash = "hash"
+	assert.NoError(t, spoke.ConvertTo(converted))
+	assert.Equal(t, []metav1.Condition{
+		{
+			Type:               string(v1.ReplicationTargetConditionTypeReplicated),
+			Status:             metav1.ConditionTrue,
+			Reason:             string(v1.RemoteSecretReasonReplicated),
+			LastTransitionTime: conversionTestCreationTimestamp,
+		},
+	}, converted.Status.ReplicationTargets[0].Conditions)
+}
+
+func TestConversionPreservesDeprecatedSecretName(t *testing.T) {
+	rs := &RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "ns"},
+		Status: RemoteSecretStatus{
+			Targets: []TargetStatus{
+				{
+					Namespace:  "a",
+					SecretName: "old-name",
+				},
+			},
+		},
+	}
+
+	hub := &v1.RemoteSecret{}
+	assert.NoError(t, rs.DeepCopy().ConvertTo(hub))
+	assert.Contains(t, hub.Annotations, ConversionDataAnnotation)
+
+	converted := &RemoteSecret{}
+	assert.NoError(t, converted.ConvertFrom(hub))
+	assert.Equal(t, rs, converted)
+}
+
+func TestConversionIgnoresStaleConditions(t *testing.T) {
+	rs := &RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "rs",
+			Namespace:         "ns",
+			CreationTimestamp: conversionTestCreationTimestamp,
+		},
+		Status: RemoteSecretStatus{
+			Targets: []TargetStatus{
+				{
+					Namespace: "a",
+					Conditions: []metav1.Condition{
+						{
+							Type:               string(TargetConditionTypeReady),
+							Status:             metav1.ConditionTrue,
+							Reason:             string(TargetReasonDeployed),
+							LastTransitionTime: conversionTestCreationTimestamp,
+						},
+						{
+							Type:               "Deployed",
+							Status:             metav1.ConditionTrue,
+							Reason:             string(RemoteSecretReasonInjected),
+							LastTransitionTime: conversionTestCreationTimestamp,
+							ObservedGeneration: 3,
+						},
+					},
+					// the error was set using v1beta1 after the Deployed condition was converted from v1
+					Error: "kaboom",
+				},
+			},
+		},
+	}
+
+	hub := &v1.RemoteSecret{}
+	assert.NoError(t, rs.ConvertTo(hub))
+
+	assert.Len(t, hub.Status.Targets[0].Conditions, 2)
+	assert.Equal(t, string(v1.TargetConditionTypeDeployed), hub.Status.Targets[0].Conditions[0].Type)
+	assert.Equal(t, metav1.ConditionFalse, hub.Status.Targets[0].Conditions[0].Status)
+	assert.Equal(t, "kaboom", hub.Status.Targets[0].Conditions[0].Message)
+	assert.Equal(t, string(v1.TargetConditionTypeReady), hub.Status.Targets[0].Conditions[1].Type)
+}
+
+func TestConversionIgnoresMalformedData(t *testing.T) {
+	rs := &RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "rs",
+			Namespace:   "ns",
+			Annotations: map[string]string{ConversionDataAnnotation: "not json"},
+		},
+	}
+
+	hub := &v1.RemoteSecret{}
+	assert.NoError(t, rs.ConvertTo(hub))
+	assert.Nil(t, hub.Annotations)
+}
+
+func TestConversionRoundTripFuzz(t *testing.T) {
+	fuzzConditions := func(c fuzz.Continue, types ...string) []metav1.Condition {
+		var conditions []metav1.Condition
+		for _, i := range c.Perm(len(types)) {
+			if c.RandBool() {
+				continue
+			}
+			cond := metav1.Condition{Type: types[i]}
+			c.Fuzz(&cond.LastTransitionTime)
+			cond.ObservedGeneration = c.Int63n(3)
+			cond.Status = []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionUnknown}[c.Intn(3)]
+			cond.Reason = []string{string(v1.RemoteSecretReasonError), string(v1.RemoteSecretReasonInjected), string(v1.RemoteSecretReasonReplicated), c.RandString()}[c.Intn(4)]
+			if c.RandBool() {
+				cond.Message = c.RandString()
+			}
+			conditions = append(conditions, cond)
+		}
+		return conditions
+	}
+
+	f := fuzz.New().NilChance(0.3).Funcs(
+		func(_ *metav1.TypeMeta, _ fuzz.Continue) {
+			// the type meta is set by the conversion webhook, not by the conversion functions
+		},
+		func(t *metav1.Time, c fuzz.Continue) {
+			// the times are truncated to seconds and in the local time zone, like when they are deserialized
+			*t = metav1.NewTime(time.Unix(c.Int63n(2_000_000_000), 0))
+		},
+		func(ts *TargetStatus, c fuzz.Continue) {
+			c.FuzzNoCustom(ts)
+			// the Deployed condition is only present in v1beta1 if it was converted from v1 and still matches the error
+			ts.Conditions = fuzzConditions(c, string(TargetConditionTypeReady), "Other")
+		},
+		func(ts *v1.TargetStatus, c fuzz.Continue) {
+			c.FuzzNoCustom(ts)
+			ts.Conditions = fuzzConditions(c, string(v1.TargetConditionTypeDeployed), string(v1.TargetConditionTypeReady), "Other")
+		},
+		func(st *v1.ReplicationTargetStatus, c fuzz.Continue) {
+			c.FuzzNoCustom(st)
+			st.Conditions = fuzzConditions(c, string(v1.ReplicationTargetConditionTypeReplicated), "Other")
+		},
+	)
+
+	for i := 0; i < 500; i++ {
+		t.Run(fmt.Sprintf("from v1beta1 %d", i), func(t *testing.T) {
+			rs := &RemoteSecret{}
+			f.Fuzz(rs)
+
+			hub := &v1.RemoteSecret{}
+			assert.NoError(t, rs.DeepCopy().ConvertTo(hub))
+			converted := &RemoteSecret{}
+			assert.NoError(t, converted.ConvertFrom(hub))
+			assert.Equal(t, rs, converted)
+		})
+
+		t.Run(fmt.Sprintf("from v1 %d", i), func(t *testing.T) {
+			hub := &v1.RemoteSecret{}
+			f.Fuzz(hub)
+
+			spoke := &RemoteSecret{}
+			assert.NoError(t, spoke.ConvertFrom(hub.DeepCopy()))
+			converted := &v1.RemoteSecret{}
+			assert.NoError(t, spoke.ConvertTo(converted))
+			assert.Equal(t, hub, converted)
+		})
+	}
+}
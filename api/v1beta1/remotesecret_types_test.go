@@ -20,6 +20,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 )
 
 func TestCheckMatchingSecretTypes(t *testing.T) {
@@ -53,14 +55,14 @@ func TestValidateUploadSecret(t *testing.T) {
 		Spec: RemoteSecretSpec{
 			Secret: LinkableSecretSpec{
 				Type:         corev1.SecretTypeSSHAuth,
-				RequiredKeys: []SecretKey{{"foo"}, {"bar"}},
+				RequiredKeys: []SecretKey{{Name: "foo"}, {Name: "bar"}},
 			},
 		},
 	}
 	upload := &corev1.Secret{
 		Type: corev1.SecretTypeSSHAuth,
 		Data: map[string][]byte{
-			corev1.SSHAuthPrivateKey: []byte("ssh-key...."),
+			corev1.SSHAuthPrivateKey: generateTestSshKey(t),
 			"foo":                    []byte("whatever"),
 			"bar":                    []byte("forever"),
 		},
@@ -80,7 +82,7 @@ func TestValidateUploadSecret(t *testing.T) {
 
 	t.Run("required keys from spec are not in secret", func(t *testing.T) {
 		myRS := rs.DeepCopy()
-		myRS.Spec.Secret.RequiredKeys = []SecretKey{{"scoby-dooo"}}
+		myRS.Spec.Secret.RequiredKeys = []SecretKey{{Name: "scoby-dooo"}}
 		err := myRS.ValidateUploadSecret(upload)
 		assert.Error(t, err)
 	})
@@ -134,7 +136,8 @@ func TestValidateSecretData(t *testing.T) {
 		rs := RemoteSecret{Spec: RemoteSecretSpec{Secret: LinkableSecretSpec{
 			Type: corev1.SecretTypeTLS,
 		}}}
-		secretData := map[string][]byte{corev1.TLSCertKey: []byte("tlscert..."), corev1.TLSPrivateKeyKey: []byte("tlskey...")}
+		cert, key := generateTestCertificate(t)
+		secretData := map[string][]byte{corev1.TLSCertKey: []byte(cert), corev1.TLSPrivateKeyKey: []byte(key)}
 
 		err := rs.ValidateSecretData(secretData)
 		assert.NoError(t, err)
@@ -161,4 +164,144 @@ func TestValidateSecretData(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("optional keys are not required", func(t *testing.T) {
+		rs := RemoteSecret{Spec: RemoteSecretSpec{Secret: LinkableSecretSpec{
+			RequiredKeys: []SecretKey{{Name: "foo", Optional: true}, {Name: "bar", DefaultValue: ptr.To("x")}},
+		}}}
+
+		assert.NoError(t, rs.ValidateSecretData(map[string][]byte{}))
+	})
+
+	t.Run("checks the rules of the keys", func(t *testing.T) {
+		rs := RemoteSecret{Spec: RemoteSecretSpec{Secret: LinkableSecretSpec{
+			RequiredKeys: []SecretKey{
+				{Name: "foo", MinLength: ptr.To[int32](5)},
+				{Name: "bar", Pattern: "^[0-9]+$"},
+				{Name: "baz", Optional: true, Format: SecretKeyFormatJson},
+			},
+		}}}
+
+		err := rs.ValidateSecretData(map[string][]byte{"foo": []byte("abc"), "bar": []byte("123")})
+		assert.ErrorIs(t, err, secretDataInvalidError)
+		assert.ErrorContains(t, err, "key 'foo' is shorter than the minimum length of 5 bytes")
+		assert.NotContains(t, err.Error(), "bar")
+
+		err = rs.ValidateSecretData(map[string][]byte{"foo": []byte("abcdef"), "bar": []byte("12a"), "baz": []byte("{")})
+		assert.ErrorContains(t, err, "key 'bar' does not match the pattern '^[0-9]+$'")
+		assert.ErrorContains(t, err, "key 'baz' does not have the required format 'json'")
+		assert.NotContains(t, err.Error(), "abcdef")
+
+		assert.NoError(t, rs.ValidateSecretData(map[string][]byte{"foo": []byte("abcdef"), "bar": []byte("12"), "baz": []byte("{}")}))
+	})
+
+	t.Run("missing keys are reported before the rules", func(t *testing.T) {
+		rs := RemoteSecret{Spec: RemoteSecretSpec{Secret: LinkableSecretSpec{
+			RequiredKeys: []SecretKey{{Name: "foo", MaxLength: ptr.To[int32](1)}, {Name: "bar"}},
+		}}}
+
+		err := rs.ValidateSecretData(map[string][]byte{"foo": []byte("long")})
+		assert.ErrorIs(t, err, secretDataKeysMissingError)
+		assert.ErrorContains(t, err, "bar")
+	})
+}
+
+func TestValidateSecretDataValues(t *testing.T) {
+	rs := RemoteSecret{Spec: RemoteSecretSpec{Secret: LinkableSecretSpec{
+		Type:         corev1.SecretTypeBasicAuth,
+		RequiredKeys: []SecretKey{{Name: "foo", MaxLength: ptr.To[int32](3)}, {Name: "bar"}},
+	}}}
+
+	assert.NoError(t, rs.ValidateSecretDataValues(map[string][]byte{"foo": []byte("abc")}))
+	assert.ErrorContains(t, rs.ValidateSecretDataValues(map[string][]byte{"foo": []byte("abcd")}), "key 'foo' is longer than the maximum length of 3 bytes")
+}
+
+func TestApplySecretDataDefaults(t *testing.T) {
+	rs := RemoteSecret{Spec: RemoteSecretSpec{Secret: LinkableSecretSpec{
+		RequiredKeys: []SecretKey{
+			{Name: "foo", DefaultValue: ptr.To("default-foo")},
+			{Name: "bar", DefaultValue: ptr.To("default-bar")},
+			{Name: "baz", DefaultValue: ptr.To("")},
+			{Name: "kuku", Optional: true},
+		},
+	}}}
+
+	t.Run("fills in missing keys", func(t *testing.T) {
+		data := rs.ApplySecretDataDefaults(map[string][]byte{"foo": []byte("mine")})
+		assert.Equal(t, map[string][]byte{"foo": []byte("mine"), "bar": []byte("default-bar"), "baz": []byte("")}, data)
+	})
+
+	t.Run("nil data", func(t *testing.T) {
+		data := rs.ApplySecretDataDefaults(nil)
+		assert.Len(t, data, 3)
+	})
+}
+
+func TestDataSources(t *testing.T) {
+	rs := RemoteSecret{}
+	rs.Name = "rs"
+	rs.Namespace = "ns"
+
+	assert.Empty(t, rs.DataSources())
+	assert.True(t, rs.DataFrom.IsEmpty())
+
+	rs.DataFrom.Name = "source"
+	rs.DataFrom.Sources = []RemoteSecretDataSource{
+		{Name: "other", Namespace: "other-ns", Keys: []RemoteSecretDataSourceKey{{Name: "a"}}},
+		{Name: "third"},
+	}
+	assert.False(t, rs.DataFrom.IsEmpty())
+	assert.Equal(t, []RemoteSecretDataSource{
+		{Name: "source", Namespace: "ns"},
+		{Name: "other", Namespace: "other-ns", Keys: []RemoteSecretDataSourceKey{{Name: "a"}}},
+		{Name: "third", Namespace: "ns"},
+	}, rs.DataSources())
+	// the sources in the spec are not modified
+	assert.Empty(t, rs.DataFrom.Sources[1].Namespace)
+}
+
+func TestFollowedRemoteSecrets(t *testing.T) {
+	rs := RemoteSecret{}
+	rs.Name = "rs"
+	rs.Namespace = "ns"
+
+	assert.Empty(t, rs.FollowedRemoteSecrets())
+
+	rs.DataFrom.Name = "source"
+	assert.Empty(t, rs.FollowedRemoteSecrets())
+
+	rs.DataFrom.Follow = true
+	assert.Equal(t, []types.NamespacedName{{Name: "source", Namespace: "ns"}}, rs.FollowedRemoteSecrets())
+
+	rs.DataFrom.Namespace = "other"
+	rs.DataFrom.Sources = []RemoteSecretDataSource{{Name: "another"}}
+	assert.Equal(t, []types.NamespacedName{{Name: "source", Namespace: "other"}, {Name: "another", Namespace: "ns"}}, rs.FollowedRemoteSecrets())
+
+	// the secrets cannot be followed
+	rs.DataFrom.Sources = append(rs.DataFrom.Sources, RemoteSecretDataSource{Kind: RemoteSecretDataSourceKindSecret, Name: "secret"})
+	assert.Len(t, rs.FollowedRemoteSecrets(), 2)
+}
+
+func TestSelectData(t *testing.T) {
+	sourceData := map[string][]byte{"a": []byte("a"), "b": []byte("b")}
+
+	t.Run("all keys", func(t *testing.T) {
+		target := map[string][]byte{"a": []byte("old"), "c": []byte("c")}
+		ds := RemoteSecretDataSource{Name: "source"}
+		assert.Empty(t, ds.SelectData(target, sourceData))
+		assert.Equal(t, map[string][]byte{"a": []byte("a"), "b": []byte("b"), "c": []byte("c")}, target)
+	})
+
+	t.Run("selected and renamed keys", func(t *testing.T) {
+		target := map[string][]byte{"a": []byte("old")}
+		ds := RemoteSecretDataSource{Name: "source", Keys: []RemoteSecretDataSourceKey{{Name: "b"}, {Name: "a", As: "renamed"}}}
+		assert.Empty(t, ds.SelectData(target, sourceData))
+		assert.Equal(t, map[string][]byte{"a": []byte("old"), "b": []byte("b"), "renamed": []byte("a")}, target)
+	})
+
+	t.Run("missing keys", func(t *testing.T) {
+		target := map[string][]byte{}
+		ds := RemoteSecretDataSource{Name: "source", Keys: []RemoteSecretDataSourceKey{{Name: "x"}, {Name: "a"}, {Name: "y", As: "z"}}}
+		assert.Equal(t, []string{"x", "y"}, ds.SelectData(target, sourceData))
+		assert.Equal(t, map[string][]byte{"a": []byte("a")}, target)
+	})
 }
@@ -0,0 +1,82 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RemoteSecretPolicySpec defines the restrictions put on the remote secrets. All the policies that apply to a remote secret
+// need to allow it. The fields that are not specified don't restrict the remote secrets in any way.
+type RemoteSecretPolicySpec struct {
+	// NamespaceSelector selects the namespaces of the remote secrets that the policy applies to. If not specified,
+	// the policy applies to the remote secrets in all namespaces.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// TargetNamespaces restricts the namespaces that the remote secrets can deploy to. The namespace of the remote secret
+	// itself is always allowed in the local cluster.
+	// +optional
+	TargetNamespaces *TargetNamespacesPolicy `json:"targetNamespaces,omitempty"`
+	// ApiUrls are the glob patterns (as understood by the path.Match function of Go) of the API URLs of the remote clusters
+	// that the remote secrets can deploy to. The targets in the local cluster (i.e. the targets without the apiUrl) are
+	// not restricted by this field.
+	// +optional
+	ApiUrls []string `json:"apiUrls,omitempty"`
+	// SecretTypes are the types of the secrets that the remote secrets can define. The empty type of the secret in the remote
+	// secret is considered to be Opaque.
+	// +optional
+	SecretTypes []corev1.SecretType `json:"secretTypes,omitempty"`
+	// ServiceAccountLinkTypes are the types of the links of the secret to the service accounts that the remote secrets can use.
+	// +optional
+	ServiceAccountLinkTypes []ServiceAccountLinkType `json:"serviceAccountLinkTypes,omitempty"`
+}
+
+// TargetNamespacesPolicy specifies the allowed target namespaces. A namespace is allowed if it matches any of the names
+// or the selector.
+type TargetNamespacesPolicy struct {
+	// Names are the glob patterns (as understood by the path.Match function of Go) of the names of the allowed namespaces.
+	// +optional
+	Names []string `json:"names,omitempty"`
+	// Selector selects the allowed namespaces by their labels. Because the labels of the namespaces can only be read in
+	// the local cluster, the selector doesn't match any namespace in the remote clusters.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+
+// RemoteSecretPolicy restricts the targets, clusters and types of the remote secrets. It is enforced both when
+// the remote secrets are created or updated and when they are deployed to the targets.
+type RemoteSecretPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec RemoteSecretPolicySpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RemoteSecretPolicyList contains a list of RemoteSecretPolicy
+type RemoteSecretPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RemoteSecretPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RemoteSecretPolicy{}, &RemoteSecretPolicyList{})
+}
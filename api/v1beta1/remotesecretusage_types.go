@@ -0,0 +1,80 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RemoteSecretUsageName is the name of the RemoteSecretUsage object maintained by the operator in each namespace
+// with remote secrets.
+const RemoteSecretUsageName = "remotesecret-usage"
+
+// RemoteSecretQuota specifies the limits put on the remote secrets in a namespace. The zero values mean no limit.
+type RemoteSecretQuota struct {
+	// MaxRemoteSecrets is the maximum number of remote secrets in the namespace.
+	// +optional
+	MaxRemoteSecrets int32 `json:"maxRemoteSecrets,omitempty"`
+	// MaxTargetsPerRemoteSecret is the maximum number of targets of a single remote secret, including the replication targets.
+	// +optional
+	MaxTargetsPerRemoteSecret int32 `json:"maxTargetsPerRemoteSecret,omitempty"`
+	// MaxStoredBytes is the maximum total size of the data of all remote secrets in the namespace.
+	// +optional
+	MaxStoredBytes int64 `json:"maxStoredBytes,omitempty"`
+	// MaxStoredBytesPerRemoteSecret is the maximum size of the data of a single remote secret.
+	// +optional
+	MaxStoredBytesPerRemoteSecret int64 `json:"maxStoredBytesPerRemoteSecret,omitempty"`
+}
+
+// RemoteSecretUsageStatus describes the resources used by the remote secrets in the namespace.
+type RemoteSecretUsageStatus struct {
+	// RemoteSecrets is the number of the remote secrets in the namespace.
+	RemoteSecrets int32 `json:"remoteSecrets"`
+	// Targets is the total number of the targets of the remote secrets in the namespace, including the replication targets.
+	Targets int32 `json:"targets"`
+	// StoredBytes is the total size of the data of the remote secrets in the namespace as reported in their status.
+	StoredBytes int64 `json:"storedBytes"`
+	// Quota is the quota that applies to the namespace.
+	// +optional
+	Quota RemoteSecretQuota `json:"quota,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Remote Secrets",type=integer,JSONPath=`.status.remoteSecrets`
+//+kubebuilder:printcolumn:name="Targets",type=integer,JSONPath=`.status.targets`
+//+kubebuilder:printcolumn:name="Stored Bytes",type=integer,JSONPath=`.status.storedBytes`
+
+// RemoteSecretUsage is maintained by the operator in each namespace with remote secrets and reports how much of the quota
+// the remote secrets in the namespace use. It is always named "remotesecret-usage".
+type RemoteSecretUsage struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status RemoteSecretUsageStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RemoteSecretUsageList contains a list of RemoteSecretUsage
+type RemoteSecretUsageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RemoteSecretUsage `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RemoteSecretUsage{}, &RemoteSecretUsageList{})
+}
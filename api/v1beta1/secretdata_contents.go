@@ -0,0 +1,193 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	tlsKeyPairInvalidError       = errors.New("do not form a valid key pair")
+	dockerConfigInvalidJsonError = errors.New("is not a valid JSON object")
+	dockerConfigNoAuthsError     = errors.New("does not contain the 'auths' object")
+	dockerConfigInvalidAuthError = errors.New("contains an 'auth' that is not a base64 encoded 'username:password' pair for registry")
+	sshPrivateKeyInvalidError    = errors.New("is not a valid SSH private key")
+)
+
+// dockerConfigEntry is the part of the docker configuration that we check. We don't use the types from the kubelet's
+// credential provider to avoid the dependency on the whole kubelet.
+type dockerConfigEntry struct {
+	Auth string `json:"auth,omitempty"`
+}
+
+type dockerConfigJson struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+// secretDataContentProblems checks that the values of the keys required by the secret type have the contents that
+// the consumers of such secrets expect. Kubernetes API server only checks the presence of the keys. The keys that
+// are missing are not reported here, because that is the job of the ValidateSecretData.
+// The returned messages never contain the values of the keys.
+func secretDataContentProblems(secretType corev1.SecretType, data map[string][]byte) []string {
+	var problems []string
+	report := func(err error, keys ...string) {
+		if err != nil {
+			if len(keys) == 1 {
+				problems = append(problems, fmt.Sprintf("key '%s' %s", keys[0], err.Error()))
+			} else {
+				problems = append(problems, fmt.Sprintf("keys '%s' and '%s' %s", keys[0], keys[1], err.Error()))
+			}
+		}
+	}
+
+	switch secretType {
+	case corev1.SecretTypeTLS:
+		cert, hasCert := data[corev1.TLSCertKey]
+		key, hasKey := data[corev1.TLSPrivateKeyKey]
+		if hasCert && hasKey {
+			report(validateTlsKeyPair(cert, key), corev1.TLSCertKey, corev1.TLSPrivateKeyKey)
+		}
+	case corev1.SecretTypeDockerConfigJson:
+		if cfg, ok := data[corev1.DockerConfigJsonKey]; ok {
+			report(validateDockerConfigJson(cfg), corev1.DockerConfigJsonKey)
+		}
+	case corev1.SecretTypeDockercfg:
+		if cfg, ok := data[corev1.DockerConfigKey]; ok {
+			report(validateDockercfg(cfg), corev1.DockerConfigKey)
+		}
+	case corev1.SecretTypeSSHAuth:
+		if key, ok := data[corev1.SSHAuthPrivateKey]; ok {
+			report(validateSshPrivateKey(key), corev1.SSHAuthPrivateKey)
+		}
+	}
+
+	return problems
+}
+
+// SecretDataWarnings returns the problems of the secret data that don't make it invalid but that the user should
+// know about. Currently, these are the certificates that are expired or not yet valid at the provided time. The
+// certificates are looked for in the TLS secrets and in the keys with the pem-certificate format.
+func (rs *RemoteSecret) SecretDataWarnings(data map[string][]byte, now time.Time) []string {
+	var warnings []string
+	checked := map[string]bool{}
+	check := func(key string) {
+		value, ok := data[key]
+		if !ok || checked[key] {
+			return
+		}
+		checked[key] = true
+		warnings = append(warnings, certificateValidityWarnings(key, value, now)...)
+	}
+
+	if rs.Spec.Secret.Type == corev1.SecretTypeTLS {
+		check(corev1.TLSCertKey)
+	}
+	for _, k := range rs.Spec.Secret.RequiredKeys {
+		if k.Format == SecretKeyFormatPemCertificate {
+			check(k.Name)
+		}
+	}
+
+	return warnings
+}
+
+func validateTlsKeyPair(cert, key []byte) error {
+	if _, err := tls.X509KeyPair(cert, key); err != nil {
+		// the errors of the tls package don't contain the data itself
+		return fmt.Errorf("%w: %w", tlsKeyPairInvalidError, err)
+	}
+	return nil
+}
+
+func validateDockerConfigJson(value []byte) error {
+	cfg := dockerConfigJson{}
+	if err := json.Unmarshal(value, &cfg); err != nil {
+		return dockerConfigInvalidJsonError
+	}
+	if cfg.Auths == nil {
+		return dockerConfigNoAuthsError
+	}
+	return validateDockerConfigEntries(cfg.Auths)
+}
+
+func validateDockercfg(value []byte) error {
+	cfg := map[string]dockerConfigEntry{}
+	if err := json.Unmarshal(value, &cfg); err != nil {
+		return dockerConfigInvalidJsonError
+	}
+	return validateDockerConfigEntries(cfg)
+}
+
+func validateDockerConfigEntries(entries map[string]dockerConfigEntry) error {
+	for registry, entry := range entries {
+		if entry.Auth == "" {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+		if err != nil || !bytes.Contains(decoded, []byte(":")) {
+			return fmt.Errorf("%w %s", dockerConfigInvalidAuthError, registry)
+		}
+	}
+	return nil
+}
+
+func validateSshPrivateKey(value []byte) error {
+	if _, err := ssh.ParseRawPrivateKey(value); err != nil {
+		// we cannot check the encrypted keys any further but they are otherwise fine.
+		var passphraseMissing *ssh.PassphraseMissingError
+		if errors.As(err, &passphraseMissing) {
+			return nil
+		}
+		return sshPrivateKeyInvalidError
+	}
+	return nil
+}
+
+func certificateValidityWarnings(key string, value []byte, now time.Time) []string {
+	var warnings []string
+	rest := value
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			// the validation takes care of reporting the certificates that cannot be parsed
+			continue
+		}
+		if now.After(cert.NotAfter) {
+			warnings = append(warnings, fmt.Sprintf("the certificate '%s' in key '%s' expired at %s", cert.Subject.String(), key, cert.NotAfter.UTC().Format(time.RFC3339)))
+		} else if now.Before(cert.NotBefore) {
+			warnings = append(warnings, fmt.Sprintf("the certificate '%s' in key '%s' is not valid before %s", cert.Subject.String(), key, cert.NotBefore.UTC().Format(time.RFC3339)))
+		}
+	}
+	return warnings
+}
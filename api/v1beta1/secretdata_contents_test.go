@@ -0,0 +1,125 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestValidateSecretDataContents(t *testing.T) {
+	validate := func(secretType corev1.SecretType, data map[string][]byte) error {
+		rs := RemoteSecret{Spec: RemoteSecretSpec{Secret: LinkableSecretSpec{Type: secretType}}}
+		return rs.ValidateSecretData(data)
+	}
+
+	t.Run("tls", func(t *testing.T) {
+		cert, key := generateTestCertificate(t)
+		_, otherKey := generateTestCertificate(t)
+
+		assert.NoError(t, validate(corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: []byte(cert), corev1.TLSPrivateKeyKey: []byte(key)}))
+
+		err := validate(corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: []byte(cert), corev1.TLSPrivateKeyKey: []byte(otherKey)})
+		assert.ErrorIs(t, err, secretDataInvalidError)
+		assert.ErrorContains(t, err, "keys 'tls.crt' and 'tls.key' do not form a valid key pair")
+
+		err = validate(corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte(key)})
+		assert.ErrorContains(t, err, "keys 'tls.crt' and 'tls.key' do not form a valid key pair")
+		assert.NotContains(t, err.Error(), "PRIVATE KEY")
+	})
+
+	t.Run("dockerconfigjson", func(t *testing.T) {
+		test := func(cfg string) error {
+			return validate(corev1.SecretTypeDockerConfigJson, map[string][]byte{corev1.DockerConfigJsonKey: []byte(cfg)})
+		}
+		assert.NoError(t, test(`{"auths": {"quay.io": {"auth": "dXNlcjpwYXNz"}}}`))
+		assert.NoError(t, test(`{"auths": {"quay.io": {"identitytoken": "token"}}}`))
+		assert.ErrorContains(t, test(`{"auths": `), dockerConfigInvalidJsonError.Error())
+		assert.ErrorContains(t, test(`{"quay.io": {"auth": "dXNlcjpwYXNz"}}`), dockerConfigNoAuthsError.Error())
+		assert.ErrorContains(t, test(`{"auths": {"quay.io": {"auth": "user:pass"}}}`), dockerConfigInvalidAuthError.Error())
+		assert.ErrorContains(t, test(`{"auths": {"quay.io": {"auth": "dXNlcnBhc3M="}}}`), "for registry quay.io")
+	})
+
+	t.Run("dockercfg", func(t *testing.T) {
+		test := func(cfg string) error {
+			return validate(corev1.SecretTypeDockercfg, map[string][]byte{corev1.DockerConfigKey: []byte(cfg)})
+		}
+		assert.NoError(t, test(`{"quay.io": {"auth": "dXNlcjpwYXNz"}}`))
+		assert.ErrorContains(t, test(`[]`), dockerConfigInvalidJsonError.Error())
+		assert.ErrorContains(t, test(`{"quay.io": {"auth": "!"}}`), dockerConfigInvalidAuthError.Error())
+	})
+
+	t.Run("ssh-auth", func(t *testing.T) {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		assert.NoError(t, err)
+		encrypted, err := ssh.MarshalPrivateKeyWithPassphrase(key, "", []byte("passphrase"))
+		assert.NoError(t, err)
+
+		test := func(key []byte) error {
+			return validate(corev1.SecretTypeSSHAuth, map[string][]byte{corev1.SSHAuthPrivateKey: key})
+		}
+		assert.NoError(t, test(generateTestSshKey(t)))
+		assert.NoError(t, test(pem.EncodeToMemory(encrypted)))
+		assert.ErrorContains(t, test([]byte("ssh...")), sshPrivateKeyInvalidError.Error())
+	})
+
+	t.Run("missing keys are not reported twice", func(t *testing.T) {
+		err := validate(corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: []byte("cert")})
+		assert.ErrorIs(t, err, secretDataKeysMissingError)
+		assert.NotErrorIs(t, err, secretDataInvalidError)
+	})
+
+	t.Run("opaque", func(t *testing.T) {
+		assert.NoError(t, validate(corev1.SecretTypeOpaque, map[string][]byte{corev1.SSHAuthPrivateKey: []byte("whatever")}))
+	})
+}
+
+func TestSecretDataWarnings(t *testing.T) {
+	cert, _ := generateTestCertificate(t)
+	rs := RemoteSecret{Spec: RemoteSecretSpec{Secret: LinkableSecretSpec{
+		Type:         corev1.SecretTypeTLS,
+		RequiredKeys: []SecretKey{{Name: "ca.crt", Format: SecretKeyFormatPemCertificate}, {Name: corev1.TLSCertKey, Format: SecretKeyFormatPemCertificate}},
+	}}}
+	data := map[string][]byte{corev1.TLSCertKey: []byte(cert), "ca.crt": []byte(cert), "other": []byte(cert)}
+
+	assert.Empty(t, rs.SecretDataWarnings(data, time.Now()))
+
+	warnings := rs.SecretDataWarnings(data, time.Now().Add(2*time.Hour))
+	assert.Len(t, warnings, 2)
+	assert.Contains(t, warnings[0], "the certificate 'CN=test' in key 'tls.crt' expired at")
+	assert.Contains(t, warnings[1], "in key 'ca.crt' expired at")
+
+	warnings = rs.SecretDataWarnings(data, time.Now().Add(-time.Hour))
+	assert.Len(t, warnings, 2)
+	assert.Contains(t, warnings[0], "is not valid before")
+}
+
+func generateTestSshKey(t *testing.T) []byte {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(key, "")
+	assert.NoError(t, err)
+
+	return pem.EncodeToMemory(block)
+}
@@ -0,0 +1,199 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	secretKeyRulesInvalidError    = errors.New("the rules of the secret key are invalid")
+	secretKeyValueTooShortError   = errors.New("is shorter than the minimum length")
+	secretKeyValueTooLongError    = errors.New("is longer than the maximum length")
+	secretKeyPatternMismatchError = errors.New("does not match the pattern")
+	secretKeyInvalidPatternError  = errors.New("has an invalid pattern")
+	secretKeyInvalidFormatError   = errors.New("does not have the required format")
+	secretKeyUnknownFormatError   = errors.New("requires an unknown format")
+
+	invalidJsonError            = errors.New("not a valid JSON document")
+	invalidUrlError             = errors.New("not a valid URL")
+	relativeUrlError            = errors.New("not an absolute URL")
+	invalidBase64Error          = errors.New("not valid base64")
+	unexpectedPemBlockError     = errors.New("unexpected PEM block of type")
+	unexpectedDataAfterPemError = errors.New("unexpected data after the PEM-encoded content")
+	certificateNotFoundError    = errors.New("no PEM-encoded certificate found")
+	certificateUnparseableError = errors.New("the certificate cannot be parsed")
+	privateKeyNotFoundError     = errors.New("no PEM-encoded private key found")
+	privateKeyUnparseableError  = errors.New("the private key cannot be parsed")
+)
+
+// IsOptional tells whether the key doesn't need to be present in the secret data. This is the case if the key is
+// explicitly marked as optional or if it has a default value.
+func (k *SecretKey) IsOptional() bool {
+	return k.Optional || k.DefaultValue != nil
+}
+
+// ValidateValue checks that the provided value satisfies the rules of the key. The returned error never contains
+// the value itself, so that it can be safely reported back to the user or logged.
+func (k *SecretKey) ValidateValue(value []byte) error {
+	if k.MinLength != nil && len(value) < int(*k.MinLength) {
+		return fmt.Errorf("%w of %d bytes", secretKeyValueTooShortError, *k.MinLength)
+	}
+	if k.MaxLength != nil && len(value) > int(*k.MaxLength) {
+		return fmt.Errorf("%w of %d bytes", secretKeyValueTooLongError, *k.MaxLength)
+	}
+	if k.Pattern != "" {
+		re, err := regexp.Compile(k.Pattern)
+		if err != nil {
+			return fmt.Errorf("%w: %w", secretKeyInvalidPatternError, err)
+		}
+		if !re.Match(value) {
+			return fmt.Errorf("%w '%s'", secretKeyPatternMismatchError, k.Pattern)
+		}
+	}
+	if k.Format != "" {
+		if err := validateFormat(k.Format, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateRules checks that the rules of the key are consistent - the pattern is a valid regular expression,
+// the minimum length is not greater than the maximum length and the default value, if any, satisfies the rules.
+func (k *SecretKey) ValidateRules() error {
+	if k.Pattern != "" {
+		if _, err := regexp.Compile(k.Pattern); err != nil {
+			return fmt.Errorf("%w: key '%s' has an invalid pattern: %w", secretKeyRulesInvalidError, k.Name, err)
+		}
+	}
+	if k.MinLength != nil && k.MaxLength != nil && *k.MinLength > *k.MaxLength {
+		return fmt.Errorf("%w: key '%s' has the minimum length greater than the maximum length", secretKeyRulesInvalidError, k.Name)
+	}
+	switch k.Format {
+	case "", SecretKeyFormatPemCertificate, SecretKeyFormatPemPrivateKey, SecretKeyFormatJson, SecretKeyFormatUrl, SecretKeyFormatBase64:
+	default:
+		return fmt.Errorf("%w: key '%s' %w '%s'", secretKeyRulesInvalidError, k.Name, secretKeyUnknownFormatError, k.Format)
+	}
+	if k.DefaultValue != nil {
+		if err := k.ValidateValue([]byte(*k.DefaultValue)); err != nil {
+			return fmt.Errorf("%w: the default value of key '%s' %w", secretKeyRulesInvalidError, k.Name, err)
+		}
+	}
+	return nil
+}
+
+func validateFormat(format SecretKeyFormat, value []byte) error {
+	var err error
+	switch format {
+	case SecretKeyFormatPemCertificate:
+		err = validatePemCertificates(value)
+	case SecretKeyFormatPemPrivateKey:
+		err = validatePemPrivateKey(value)
+	case SecretKeyFormatJson:
+		if !json.Valid(value) {
+			err = invalidJsonError
+		}
+	case SecretKeyFormatUrl:
+		err = validateAbsoluteUrl(value)
+	case SecretKeyFormatBase64:
+		if _, decodeErr := base64.StdEncoding.DecodeString(strings.TrimSpace(string(value))); decodeErr != nil {
+			err = invalidBase64Error
+		}
+	default:
+		return fmt.Errorf("%w '%s'", secretKeyUnknownFormatError, format)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w '%s': %w", secretKeyInvalidFormatError, format, err)
+	}
+	return nil
+}
+
+func validatePemCertificates(value []byte) error {
+	rest := value
+	count := 0
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return fmt.Errorf("%w %s", unexpectedPemBlockError, block.Type)
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return certificateUnparseableError
+		}
+		count++
+	}
+
+	if count == 0 {
+		return certificateNotFoundError
+	}
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return unexpectedDataAfterPemError
+	}
+	return nil
+}
+
+func validatePemPrivateKey(value []byte) error {
+	block, rest := pem.Decode(value)
+	if block == nil {
+		return privateKeyNotFoundError
+	}
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return unexpectedDataAfterPemError
+	}
+
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		_, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		_, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		_, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		// encrypted or OpenSSH keys cannot be parsed without additional information, so we only check the PEM type.
+		if !strings.HasSuffix(block.Type, "PRIVATE KEY") {
+			return fmt.Errorf("%w %s", unexpectedPemBlockError, block.Type)
+		}
+	}
+	if err != nil {
+		return privateKeyUnparseableError
+	}
+	return nil
+}
+
+func validateAbsoluteUrl(value []byte) error {
+	u, err := url.Parse(string(value))
+	if err != nil {
+		return invalidUrlError
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return relativeUrlError
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+)
+
+func TestSecretKeyValidateValue(t *testing.T) {
+	certPem, keyPem := generateTestCertificate(t)
+
+	test := func(key SecretKey, value string, expectedError error) {
+		t.Helper()
+		err := key.ValidateValue([]byte(value))
+		if expectedError == nil {
+			assert.NoError(t, err)
+		} else {
+			assert.ErrorIs(t, err, expectedError)
+		}
+	}
+
+	t.Run("length", func(t *testing.T) {
+		key := SecretKey{Name: "k", MinLength: ptr.To[int32](2), MaxLength: ptr.To[int32](4)}
+		test(key, "a", secretKeyValueTooShortError)
+		test(key, "ab", nil)
+		test(key, "abcd", nil)
+		test(key, "abcde", secretKeyValueTooLongError)
+	})
+
+	t.Run("pattern", func(t *testing.T) {
+		test(SecretKey{Name: "k", Pattern: "^ghp_[a-zA-Z0-9]+$"}, "ghp_abc123", nil)
+		test(SecretKey{Name: "k", Pattern: "^ghp_[a-zA-Z0-9]+$"}, "gho_abc123", secretKeyPatternMismatchError)
+		test(SecretKey{Name: "k", Pattern: "[a-z"}, "a", secretKeyInvalidPatternError)
+	})
+
+	t.Run("pem-certificate", func(t *testing.T) {
+		key := SecretKey{Name: "k", Format: SecretKeyFormatPemCertificate}
+		test(key, certPem, nil)
+		test(key, certPem+certPem, nil)
+		test(key, "not a certificate", certificateNotFoundError)
+		test(key, keyPem, unexpectedPemBlockError)
+		test(key, certPem+"garbage", unexpectedDataAfterPemError)
+		test(key, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("nope")})), certificateUnparseableError)
+	})
+
+	t.Run("pem-private-key", func(t *testing.T) {
+		key := SecretKey{Name: "k", Format: SecretKeyFormatPemPrivateKey}
+		test(key, keyPem, nil)
+		test(key, string(pem.EncodeToMemory(&pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: []byte("opaque")})), nil)
+		test(key, "not a key", privateKeyNotFoundError)
+		test(key, certPem, unexpectedPemBlockError)
+		test(key, string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("nope")})), privateKeyUnparseableError)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		key := SecretKey{Name: "k", Format: SecretKeyFormatJson}
+		test(key, `{"auths": {}}`, nil)
+		test(key, `{"auths": `, invalidJsonError)
+	})
+
+	t.Run("url", func(t *testing.T) {
+		key := SecretKey{Name: "k", Format: SecretKeyFormatUrl}
+		test(key, "https://github.com/org/repo", nil)
+		test(key, "github.com/org/repo", relativeUrlError)
+		test(key, "https://git hub.com", invalidUrlError)
+	})
+
+	t.Run("base64", func(t *testing.T) {
+		key := SecretKey{Name: "k", Format: SecretKeyFormatBase64}
+		test(key, "aGVsbG8=", nil)
+		test(key, "aGVsbG8=\n", nil)
+		test(key, "hello!", invalidBase64Error)
+	})
+}
+
+func TestSecretKeyValidateRules(t *testing.T) {
+	assert.NoError(t, (&SecretKey{Name: "k", Pattern: "^a", MinLength: ptr.To[int32](1), MaxLength: ptr.To[int32](1)}).ValidateRules())
+	assert.ErrorIs(t, (&SecretKey{Name: "k", Pattern: "(a"}).ValidateRules(), secretKeyRulesInvalidError)
+	assert.ErrorIs(t, (&SecretKey{Name: "k", MinLength: ptr.To[int32](2), MaxLength: ptr.To[int32](1)}).ValidateRules(), secretKeyRulesInvalidError)
+	assert.ErrorIs(t, (&SecretKey{Name: "k", Format: "yaml"}).ValidateRules(), secretKeyUnknownFormatError)
+
+	err := (&SecretKey{Name: "k", DefaultValue: ptr.To("a"), MinLength: ptr.To[int32](2)}).ValidateRules()
+	assert.ErrorIs(t, err, secretKeyRulesInvalidError)
+	assert.ErrorIs(t, err, secretKeyValueTooShortError)
+}
+
+func generateTestCertificate(t *testing.T) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}))
+}
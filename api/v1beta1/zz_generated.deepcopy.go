@@ -22,10 +22,37 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataUpdateStatus) DeepCopyInto(out *DataUpdateStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeletedKeys != nil {
+		in, out := &in.DeletedKeys, &out.DeletedKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataUpdateStatus.
+func (in *DataUpdateStatus) DeepCopy() *DataUpdateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataUpdateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeployedSecretStatus) DeepCopyInto(out *DeployedSecretStatus) {
 	*out = *in
@@ -55,6 +82,89 @@ func (in *DeployedSecretStatus) DeepCopy() *DeployedSecretStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunPlan) DeepCopyInto(out *DryRunPlan) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]TargetPlan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunPlan.
+func (in *DryRunPlan) DeepCopy() *DryRunPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSource) DeepCopyInto(out *ExternalSource) {
+	*out = *in
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make([]ExternalSourceData, len(*in))
+		copy(*out, *in)
+	}
+	if in.DataFrom != nil {
+		in, out := &in.DataFrom, &out.DataFrom
+		*out = make([]ExternalSourceRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSource.
+func (in *ExternalSource) DeepCopy() *ExternalSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSourceData) DeepCopyInto(out *ExternalSourceData) {
+	*out = *in
+	out.RemoteRef = in.RemoteRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSourceData.
+func (in *ExternalSourceData) DeepCopy() *ExternalSourceData {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSourceData)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSourceRef) DeepCopyInto(out *ExternalSourceRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSourceRef.
+func (in *ExternalSourceRef) DeepCopy() *ExternalSourceRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSourceRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LinkableSecretSpec) DeepCopyInto(out *LinkableSecretSpec) {
 	*out = *in
@@ -75,7 +185,9 @@ func (in *LinkableSecretSpec) DeepCopyInto(out *LinkableSecretSpec) {
 	if in.RequiredKeys != nil {
 		in, out := &in.RequiredKeys, &out.RequiredKeys
 		*out = make([]SecretKey, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.LinkedTo != nil {
 		in, out := &in.LinkedTo, &out.LinkedTo
@@ -154,7 +266,7 @@ func (in *RemoteSecret) DeepCopyInto(out *RemoteSecret) {
 			(*out)[key] = val
 		}
 	}
-	out.DataFrom = in.DataFrom
+	in.DataFrom.DeepCopyInto(&out.DataFrom)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecret.
@@ -178,6 +290,13 @@ func (in *RemoteSecret) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RemoteSecretDataFrom) DeepCopyInto(out *RemoteSecretDataFrom) {
 	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]RemoteSecretDataSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecretDataFrom.
@@ -190,6 +309,41 @@ func (in *RemoteSecretDataFrom) DeepCopy() *RemoteSecretDataFrom {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteSecretDataSource) DeepCopyInto(out *RemoteSecretDataSource) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]RemoteSecretDataSourceKey, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecretDataSource.
+func (in *RemoteSecretDataSource) DeepCopy() *RemoteSecretDataSource {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteSecretDataSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteSecretDataSourceKey) DeepCopyInto(out *RemoteSecretDataSourceKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecretDataSourceKey.
+func (in *RemoteSecretDataSourceKey) DeepCopy() *RemoteSecretDataSourceKey {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteSecretDataSourceKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RemoteSecretList) DeepCopyInto(out *RemoteSecretList) {
 	*out = *in
@@ -222,6 +376,119 @@ func (in *RemoteSecretList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteSecretPolicy) DeepCopyInto(out *RemoteSecretPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecretPolicy.
+func (in *RemoteSecretPolicy) DeepCopy() *RemoteSecretPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteSecretPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RemoteSecretPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteSecretPolicyList) DeepCopyInto(out *RemoteSecretPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RemoteSecretPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecretPolicyList.
+func (in *RemoteSecretPolicyList) DeepCopy() *RemoteSecretPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteSecretPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RemoteSecretPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteSecretPolicySpec) DeepCopyInto(out *RemoteSecretPolicySpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TargetNamespaces != nil {
+		in, out := &in.TargetNamespaces, &out.TargetNamespaces
+		*out = new(TargetNamespacesPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ApiUrls != nil {
+		in, out := &in.ApiUrls, &out.ApiUrls
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretTypes != nil {
+		in, out := &in.SecretTypes, &out.SecretTypes
+		*out = make([]corev1.SecretType, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceAccountLinkTypes != nil {
+		in, out := &in.ServiceAccountLinkTypes, &out.ServiceAccountLinkTypes
+		*out = make([]ServiceAccountLinkType, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecretPolicySpec.
+func (in *RemoteSecretPolicySpec) DeepCopy() *RemoteSecretPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteSecretPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteSecretQuota) DeepCopyInto(out *RemoteSecretQuota) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecretQuota.
+func (in *RemoteSecretQuota) DeepCopy() *RemoteSecretQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteSecretQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RemoteSecretSpec) DeepCopyInto(out *RemoteSecretSpec) {
 	*out = *in
@@ -233,6 +500,17 @@ func (in *RemoteSecretSpec) DeepCopyInto(out *RemoteSecretSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	out.Adoption = in.Adoption
+	if in.ReplicationTargets != nil {
+		in, out := &in.ReplicationTargets, &out.ReplicationTargets
+		*out = make([]ReplicationTarget, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExternalSource != nil {
+		in, out := &in.ExternalSource, &out.ExternalSource
+		*out = new(ExternalSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecretSpec.
@@ -263,6 +541,23 @@ func (in *RemoteSecretStatus) DeepCopyInto(out *RemoteSecretStatus) {
 		}
 	}
 	in.SecretStatus.DeepCopyInto(&out.SecretStatus)
+	if in.DryRunPlan != nil {
+		in, out := &in.DryRunPlan, &out.DryRunPlan
+		*out = new(DryRunPlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplicationTargets != nil {
+		in, out := &in.ReplicationTargets, &out.ReplicationTargets
+		*out = make([]ReplicationTargetStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastDataUpdate != nil {
+		in, out := &in.LastDataUpdate, &out.LastDataUpdate
+		*out = new(DataUpdateStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecretStatus.
@@ -295,9 +590,151 @@ func (in *RemoteSecretTarget) DeepCopy() *RemoteSecretTarget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteSecretUsage) DeepCopyInto(out *RemoteSecretUsage) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecretUsage.
+func (in *RemoteSecretUsage) DeepCopy() *RemoteSecretUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteSecretUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RemoteSecretUsage) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteSecretUsageList) DeepCopyInto(out *RemoteSecretUsageList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RemoteSecretUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecretUsageList.
+func (in *RemoteSecretUsageList) DeepCopy() *RemoteSecretUsageList {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteSecretUsageList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RemoteSecretUsageList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteSecretUsageStatus) DeepCopyInto(out *RemoteSecretUsageStatus) {
+	*out = *in
+	out.Quota = in.Quota
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteSecretUsageStatus.
+func (in *RemoteSecretUsageStatus) DeepCopy() *RemoteSecretUsageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteSecretUsageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationTarget) DeepCopyInto(out *ReplicationTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationTarget.
+func (in *ReplicationTarget) DeepCopy() *ReplicationTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationTargetStatus) DeepCopyInto(out *ReplicationTargetStatus) {
+	*out = *in
+	if in.TransferStartTime != nil {
+		in, out := &in.TransferStartTime, &out.TransferStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastReplicationTime != nil {
+		in, out := &in.LastReplicationTime, &out.LastReplicationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationTargetStatus.
+func (in *ReplicationTargetStatus) DeepCopy() *ReplicationTargetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationTargetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretAdoption) DeepCopyInto(out *SecretAdoption) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretAdoption.
+func (in *SecretAdoption) DeepCopy() *SecretAdoption {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretAdoption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretKey) DeepCopyInto(out *SecretKey) {
 	*out = *in
+	if in.DefaultValue != nil {
+		in, out := &in.DefaultValue, &out.DefaultValue
+		*out = new(string)
+		**out = **in
+	}
+	if in.MinLength != nil {
+		in, out := &in.MinLength, &out.MinLength
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxLength != nil {
+		in, out := &in.MaxLength, &out.MaxLength
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKey.
@@ -310,6 +747,25 @@ func (in *SecretKey) DeepCopy() *SecretKey {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeyStatus) DeepCopyInto(out *SecretKeyStatus) {
+	*out = *in
+	if in.LastModified != nil {
+		in, out := &in.LastModified, &out.LastModified
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyStatus.
+func (in *SecretKeyStatus) DeepCopy() *SecretKeyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretLink) DeepCopyInto(out *SecretLink) {
 	*out = *in
@@ -371,6 +827,13 @@ func (in *SecretStatus) DeepCopyInto(out *SecretStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.KeyDetails != nil {
+		in, out := &in.KeyDetails, &out.KeyDetails
+		*out = make([]SecretKeyStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStatus.
@@ -400,6 +863,21 @@ func (in *ServiceAccountLink) DeepCopy() *ServiceAccountLink {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountPlan) DeepCopyInto(out *ServiceAccountPlan) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountPlan.
+func (in *ServiceAccountPlan) DeepCopy() *ServiceAccountPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetKey) DeepCopyInto(out *TargetKey) {
 	*out = *in
@@ -415,6 +893,56 @@ func (in *TargetKey) DeepCopy() *TargetKey {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetNamespacesPolicy) DeepCopyInto(out *TargetNamespacesPolicy) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetNamespacesPolicy.
+func (in *TargetNamespacesPolicy) DeepCopy() *TargetNamespacesPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetNamespacesPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetPlan) DeepCopyInto(out *TargetPlan) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceAccounts != nil {
+		in, out := &in.ServiceAccounts, &out.ServiceAccounts
+		*out = make([]ServiceAccountPlan, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetPlan.
+func (in *TargetPlan) DeepCopy() *TargetPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetSecretKey) DeepCopyInto(out *TargetSecretKey) {
 	*out = *in
@@ -448,6 +976,17 @@ func (in *TargetStatus) DeepCopyInto(out *TargetStatus) {
 		*out = new(DeployedSecretStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetStatus.
@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/redhat-appstudio/remote-secret/pkg/commaseparated"
 	"github.com/redhat-appstudio/remote-secret/pkg/logs"
 	auth "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -45,13 +46,15 @@ var (
 	onlyOneAuthServiceAccountPerNamespaceAllowed      = errors.New("there can be only one service account labeled with '" + api.RemoteSecretAuthServiceAccountLabel + "' in a namespace")
 	noKubeConfigSpecifiedForConnectionToRemoteCluster = errors.New("a secret with kubeconfig with credentials for connecting to a remote cluster is required")
 	ErrorInvalidClientConfig                          = errors.New("invalid k8s client configuration")
+	kubeConfigSecretUnavailableError                  = errors.New("failed to get the secret with the kubeconfig")
+	kubeConfigSecretNotAllowedError                   = errors.New("the secret with the kubeconfig does not allow its use by the remote secret")
 )
 
 // ClientFactory is a helper interface for the RemoteSecretReconciler that creates clients that are able to deploy to remote secret targets. The default (and only)
 // implementation is the CachingClientFactory but is hidden behind an interface so that this can be mocked out in the tests.
 type ClientFactory interface {
-	// GetClient returns a client that can be used to deploy to a target described by the targetSpec and targetStatus from a remote secret in the provided namespace
-	GetClient(ctx context.Context, currentNamespace string, targetSpec *api.RemoteSecretTarget, targetStatus *api.TargetStatus) (client.Client, error)
+	// GetClient returns a client that can be used to deploy to a target described by the targetSpec and targetStatus from the remote secret with the provided key
+	GetClient(ctx context.Context, remoteSecret client.ObjectKey, targetSpec *api.RemoteSecretTarget, targetStatus *api.TargetStatus) (client.Client, error)
 	// ServiceAccountChanged signals to the client factory that the service account changed. The client factory might react by revoking the client associated with
 	// the service account from a cache, if any, etc.
 	ServiceAccountChanged(sa client.ObjectKey)
@@ -96,7 +99,8 @@ func (cf *CachingClientFactory) ServiceAccountChanged(sa client.ObjectKey) {
 	cf.cache.Delete(key)
 }
 
-func (cf *CachingClientFactory) GetClient(ctx context.Context, currentNamespace string, targetSpec *api.RemoteSecretTarget, targetStatus *api.TargetStatus) (client.Client, error) {
+func (cf *CachingClientFactory) GetClient(ctx context.Context, remoteSecret client.ObjectKey, targetSpec *api.RemoteSecretTarget, targetStatus *api.TargetStatus) (client.Client, error) {
+	currentNamespace := remoteSecret.Namespace
 	var apiUrl string
 	var kubeConfigSecretName string
 	var targetNamespace string
@@ -138,6 +142,7 @@ func (cf *CachingClientFactory) GetClient(ctx context.Context, currentNamespace
 			ApiUrl:               apiUrl,
 			Client:               cf.LocalCluster.Client,
 			CurrentNamespace:     currentNamespace,
+			RemoteSecretName:     remoteSecret.Name,
 			KubeConfigSecretName: kubeConfigSecretName,
 		}
 	} else if localConnection {
@@ -166,6 +171,10 @@ func (cf *CachingClientFactory) GetClient(ctx context.Context, currentNamespace
 		if err := corev1.AddToScheme(scheme); err != nil {
 			return nil, fmt.Errorf("failed to initialize a new scheme with core objects. weird: %w", err)
 		}
+		// the remote secrets are needed to check the state of the replicas of the remote secrets
+		if err := api.AddToScheme(scheme); err != nil {
+			return nil, fmt.Errorf("failed to initialize a new scheme with remote secrets. weird: %w", err)
+		}
 
 		opts := client.Options{
 			Scheme: scheme,
@@ -208,6 +217,7 @@ type restConfigGetter interface {
 
 type kubeConfigRestConfigGetter struct {
 	CurrentNamespace     string
+	RemoteSecretName     string
 	ApiUrl               string
 	KubeConfigSecretName string
 	Client               client.Client
@@ -247,7 +257,12 @@ func (g *kubeConfigRestConfigGetter) GetRestConfig(ctx context.Context) (cfg *re
 func (g *kubeConfigRestConfigGetter) ensureKubeConfig(ctx context.Context) error {
 	sec := &corev1.Secret{}
 	if err := g.Client.Get(ctx, client.ObjectKey{Name: g.KubeConfigSecretName, Namespace: g.CurrentNamespace}, sec); err != nil {
-		return fmt.Errorf("failed to get the secret with the kubeconfig: %w", err)
+		return fmt.Errorf("%w: %w", kubeConfigSecretUnavailableError, err)
+	}
+	// the owner of the kubeconfig secret can restrict which remote secrets can use it. We don't want to use the credentials
+	// in such case even if the webhook let the remote secret through (e.g. because the annotation was added later).
+	if allowed, restricted := sec.Annotations[api.ClusterCredentialsAllowedRemoteSecretsAnnotation]; restricted && !commaseparated.Value(allowed).Contains(g.RemoteSecretName) {
+		return fmt.Errorf("%w: secret '%s', remote secret '%s'", kubeConfigSecretNotAllowedError, g.KubeConfigSecretName, g.RemoteSecretName)
 	}
 	g.kubeConfigData = sec.Data["kubeconfig"]
 	return nil
@@ -24,6 +24,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	// "sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
@@ -154,7 +155,7 @@ func TestGetClient(t *testing.T) {
 					}
 				}
 
-				tcl, err := cf.GetClient(context.TODO(), "ns", spec, status)
+				tcl, err := cf.GetClient(context.TODO(), client.ObjectKey{Name: "rs", Namespace: "ns"}, spec, status)
 
 				assert.Error(t, err)
 				assert.Nil(t, tcl)
@@ -214,7 +215,7 @@ func TestGetClient(t *testing.T) {
 					}
 				}
 
-				tcl, err := cf.GetClient(context.TODO(), "ns", spec, status)
+				tcl, err := cf.GetClient(context.TODO(), client.ObjectKey{Name: "rs", Namespace: "ns"}, spec, status)
 
 				assert.NoError(t, err)
 				assert.Same(t, cl, tcl)
@@ -270,6 +271,52 @@ preferences: {}
 	assert.NotNil(t, cfg)
 }
 
+func TestKubeConfigRestConfigGetter_AllowedRemoteSecrets(t *testing.T) {
+	cl := fake.NewClientBuilder().
+		WithObjects(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret",
+					Namespace: "ns",
+					Annotations: map[string]string{
+						api.ClusterCredentialsAllowedRemoteSecretsAnnotation: "rs1,rs2",
+					},
+				},
+				Data: map[string][]byte{
+					"kubeconfig": []byte("kubeconfig"),
+				},
+			},
+		).
+		Build()
+
+	t.Run("allowed remote secret", func(t *testing.T) {
+		getter := kubeConfigRestConfigGetter{
+			CurrentNamespace:     "ns",
+			RemoteSecretName:     "rs2",
+			KubeConfigSecretName: "secret",
+			Client:               cl,
+		}
+
+		_, err := getter.GetCacheKey(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("kubeconfig"), getter.kubeConfigData)
+	})
+
+	t.Run("other remote secret", func(t *testing.T) {
+		getter := kubeConfigRestConfigGetter{
+			CurrentNamespace:     "ns",
+			RemoteSecretName:     "rs3",
+			KubeConfigSecretName: "secret",
+			Client:               cl,
+		}
+
+		_, err := getter.GetCacheKey(context.TODO())
+		assert.ErrorIs(t, err, kubeConfigSecretNotAllowedError)
+		assert.Empty(t, getter.kubeConfigData)
+		assert.Equal(t, api.TargetReasonClusterCredentialsUnavailable, TargetReasonFor(err, ErrorReasonNone))
+	})
+}
+
 // NOTE: requires controller-runtime v0.15.0 to work.
 //
 // func TestInNamespaceServiceAccountRestConfigGetter(t *testing.T) {
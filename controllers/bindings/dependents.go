@@ -16,23 +16,67 @@ package bindings
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	stderrors "errors"
 	"fmt"
+	"strings"
 
 	"github.com/cenkalti/backoff/v4"
 	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var DependentsInconsistencyError = stderrors.New("inconsistency detected when deploying dependent objects")
 
+// FieldManager is the name of the field manager used when applying the dependent objects using the server-side apply.
+const FieldManager = "remote-secret-controller"
+
+// clientSideFieldManagers are the field managers of the changes of the dependent objects made without the server-side apply.
+// Such changes are owned by the FieldManager when the object is created by us, otherwise the API server names the field
+// manager after the user agent of the client. The ownership of such fields is transferred to the server-side apply of the
+// FieldManager, so that the fields are removed once no longer applied.
+var clientSideFieldManagers = sets.New(FieldManager, strings.SplitN(rest.DefaultKubernetesUserAgent(), "/", 2)[0])
+
+// linkFieldManager returns the field manager used to apply the link of the secret to a service account. The links of different
+// secrets are applied by different field managers so that applying one of them doesn't remove the others.
+func linkFieldManager(secretName string) string {
+	return subFieldManager("link", secretName)
+}
+
+// targetFieldManager returns the field manager used to apply the marks of the deployment target on a shared object, like
+// a referenced service account, so that applying the marks of one target doesn't remove the marks of others.
+func targetFieldManager(targetKey client.ObjectKey) string {
+	return subFieldManager("target", targetKey.String())
+}
+
+// maxFieldManagerLength is the maximum length of the field manager name accepted by the API server.
+const maxFieldManagerLength = 128
+
+func subFieldManager(kind string, name string) string {
+	manager := FieldManager + "-" + kind + "-" + name
+	if len(manager) > maxFieldManagerLength {
+		hash := sha256.Sum256([]byte(name))
+		manager = FieldManager + "-" + kind + "-" + hex.EncodeToString(hash[:16])
+	}
+	return manager
+}
+
 // DependentsHandler is taking care of the dependent objects of the provided target.
 type DependentsHandler[K any] struct {
 	Target           SecretDeploymentTarget
 	SecretDataGetter SecretDataGetter[K]
 	ObjectMarker     ObjectMarker
+	// ServerSideApply makes the handler deploy the secrets and the managed service accounts using the server-side apply
+	// with the FieldManager instead of the client-side diffing and updating.
+	ServerSideApply bool
+	// AdoptionPolicy specifies whether the secret with the name from the spec that already exists in the target can be
+	// taken over. The empty value is equivalent to api.AdoptionPolicyIfUnmanaged.
+	AdoptionPolicy api.AdoptionPolicy
 }
 
 // Dependents represent the secret and the list of the service accounts that are
@@ -180,6 +224,112 @@ func (d *DependentsHandler[K]) Sync(ctx context.Context, dataKey K) (*Dependents
 	return deps, "", nil
 }
 
+// DependentsPlan describes what the DependentsHandler would do with the dependent objects.
+type DependentsPlan struct {
+	// SecretAction is what would happen with the secret.
+	SecretAction api.PlannedAction
+	// SecretName is the name of the secret. It is empty if the secret would be created with a generated name.
+	SecretName string
+	// ReplacedSecretName is the name of the stale secret that would be replaced by the secret with the SecretName.
+	ReplacedSecretName string
+	// SecretChanges lists the changes that would be made to the secret.
+	SecretChanges []string
+	// ServiceAccounts lists what would happen with the service accounts.
+	ServiceAccounts []api.ServiceAccountPlan
+}
+
+// Plan computes what Sync would do with the dependent objects without changing anything in the cluster.
+func (d *DependentsHandler[K]) Plan(ctx context.Context, dataKey K) (*DependentsPlan, error) {
+	secretsHandler, saHandler := d.childHandlers()
+
+	if err := secretsHandler.CheckColliding(ctx); err != nil {
+		return nil, fmt.Errorf("collision between two targets of different managing Objects: %w", err)
+	}
+
+	staleSecret, err := secretsHandler.GetStale(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	action, secretName, changes, err := secretsHandler.Plan(ctx, dataKey, staleSecret != nil)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &DependentsPlan{
+		SecretAction:  action,
+		SecretName:    secretName,
+		SecretChanges: changes,
+	}
+
+	if staleSecret != nil {
+		plan.SecretAction = api.PlannedActionReplace
+		plan.ReplacedSecretName = staleSecret.Name
+	}
+
+	if plan.ServiceAccounts, err = saHandler.Plan(ctx, secretName); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// PlanCleanup computes what Cleanup would do with the dependent objects without changing anything in the cluster.
+func (d *DependentsHandler[K]) PlanCleanup(ctx context.Context) (*DependentsPlan, error) {
+	secretsHandler, saHandler := d.childHandlers()
+
+	sl, err := secretsHandler.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the secrets to clean for the secret deployment target (%s) %s: %w",
+			d.Target.GetType(),
+			d.Target.GetTargetObjectKey(),
+			err)
+	}
+
+	sal, err := saHandler.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the service accounts to clean up for the secret deployment target (%s) %s: %w",
+			d.Target.GetType(),
+			d.Target.GetTargetObjectKey(),
+			err)
+	}
+
+	plan := &DependentsPlan{
+		SecretAction:    api.PlannedActionNone,
+		SecretName:      d.Target.GetActualSecretName(),
+		ServiceAccounts: []api.ServiceAccountPlan{},
+	}
+
+	for _, s := range sl {
+		plan.SecretAction = api.PlannedActionDelete
+		if plan.SecretName == "" {
+			plan.SecretName = s.Name
+		}
+	}
+
+	for _, sa := range sal {
+		saPlan := api.ServiceAccountPlan{Name: sa.Name, Action: api.PlannedActionNone}
+		if managed, err := d.ObjectMarker.IsManagedBy(ctx, d.Target.GetTargetObjectKey(), sa); err != nil {
+			return nil, fmt.Errorf("failed to determine if the service account (%s) is managed while processing the secret deployment target (%s) %s: %w",
+				client.ObjectKeyFromObject(sa),
+				d.Target.GetType(),
+				d.Target.GetTargetObjectKey(),
+				err)
+		} else if managed {
+			saPlan.Action = api.PlannedActionDelete
+		} else {
+			for _, s := range sl {
+				if saHandler.Unlink(s, sa.DeepCopy()) {
+					saPlan.Action = api.PlannedActionUnlink
+				}
+			}
+		}
+		plan.ServiceAccounts = append(plan.ServiceAccounts, saPlan)
+	}
+
+	return plan, nil
+}
+
 func (d *DependentsHandler[K]) Cleanup(ctx context.Context) error {
 	secretsHandler, saHandler := d.childHandlers()
 
@@ -249,6 +399,84 @@ func (d *DependentsHandler[K]) Cleanup(ctx context.Context) error {
 	return nil
 }
 
+// FindAdoptable returns the secret that already exists in the target and that would be adopted by Sync according to
+// the adoption policy. Nil is returned if there is no such secret.
+func (d *DependentsHandler[K]) FindAdoptable(ctx context.Context) (*corev1.Secret, error) {
+	secretsHandler, _ := d.childHandlers()
+	secret, err := secretsHandler.FindAdoptable(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the adoptable secret in the secret deployment target (%s) %s: %w",
+			d.Target.GetType(),
+			d.Target.GetTargetObjectKey(),
+			err)
+	}
+	return secret, nil
+}
+
+// Orphan is an alternative to Cleanup that leaves the secret and the service accounts in the target but removes the labels
+// and annotations marking them as managed or referenced by the deployment target. The service accounts stay linked to the secret.
+func (d *DependentsHandler[K]) Orphan(ctx context.Context) error {
+	secretsHandler, saHandler := d.childHandlers()
+
+	sal, err := saHandler.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list the service accounts to orphan for the secret deployment target (%s) %s: %w",
+			d.Target.GetType(),
+			d.Target.GetTargetObjectKey(),
+			err)
+	}
+
+	sl, err := secretsHandler.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list the secrets to orphan for the secret deployment target (%s) %s: %w",
+			d.Target.GetType(),
+			d.Target.GetTargetObjectKey(),
+			err)
+	}
+
+	for _, sa := range sal {
+		if err := d.unmark(ctx, sa); err != nil {
+			return fmt.Errorf("failed to orphan the service account %s of the secret deployment target (%s) %s: %w",
+				client.ObjectKeyFromObject(sa),
+				d.Target.GetType(),
+				d.Target.GetTargetObjectKey(),
+				err)
+		}
+	}
+
+	for _, s := range sl {
+		if err := d.unmark(ctx, s); err != nil {
+			return fmt.Errorf("failed to orphan the secret %s of the secret deployment target (%s) %s: %w",
+				client.ObjectKeyFromObject(s),
+				d.Target.GetType(),
+				d.Target.GetTargetObjectKey(),
+				err)
+		}
+	}
+
+	return nil
+}
+
+// unmark removes the managed and referenced markers of the deployment target from the object and updates it in the cluster
+// if that changed it.
+func (d *DependentsHandler[K]) unmark(ctx context.Context, obj client.Object) error {
+	unmanaged, err := d.ObjectMarker.UnmarkManaged(ctx, d.Target.GetTargetObjectKey(), obj)
+	if err != nil {
+		return fmt.Errorf("failed to unmark the object as managed: %w", err)
+	}
+	unreferenced, err := d.ObjectMarker.UnmarkReferenced(ctx, d.Target.GetTargetObjectKey(), obj)
+	if err != nil {
+		return fmt.Errorf("failed to unmark the object as referenced: %w", err)
+	}
+	if !unmanaged && !unreferenced {
+		return nil
+	}
+	if err := d.Target.GetClient().Update(ctx, obj); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to update the object: %w", err)
+	}
+	return nil
+}
+
 // RevertTo reverts the reconciliation "transaction". I.e. this should be called after Sync in case the subsequent steps in the reconciliation
 // fail and the operator needs to revert the changes made in sync so that the changes remain idempotent. The provided checkpoint represents
 // the state obtained from the DependentsHandler.Target prior to making any changes by Sync().
@@ -363,11 +591,14 @@ func (d *DependentsHandler[K]) childHandlers() (*secretHandler[K], *serviceAccou
 		Target:           d.Target,
 		ObjectMarker:     d.ObjectMarker,
 		SecretDataGetter: d.SecretDataGetter,
+		ServerSideApply:  d.ServerSideApply,
+		AdoptionPolicy:   d.AdoptionPolicy,
 	}
 
 	saHandler := &serviceAccountHandler{
-		Target:       d.Target,
-		ObjectMarker: d.ObjectMarker,
+		Target:          d.Target,
+		ObjectMarker:    d.ObjectMarker,
+		ServerSideApply: d.ServerSideApply,
 	}
 
 	return secretsHandler, saHandler
@@ -26,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 func TestDependentsSync(t *testing.T) {
@@ -219,6 +220,387 @@ func TestDependentsCleanup(t *testing.T) {
 	})
 }
 
+func TestDependentsPlan(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	// the plan must not change anything in the cluster
+	failOnWrite := interceptor.Funcs{
+		Create: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			assert.Fail(t, "unexpected create")
+			return nil
+		},
+		Update: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			assert.Fail(t, "unexpected update")
+			return nil
+		},
+		Delete: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			assert.Fail(t, "unexpected delete")
+			return nil
+		},
+		Patch: func(ctx context.Context, client client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			assert.Fail(t, "unexpected patch")
+			return nil
+		},
+	}
+
+	t.Run("new secret and service accounts", func(t *testing.T) {
+		cl := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				&corev1.ServiceAccount{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "sa",
+						Namespace: "default",
+					},
+				},
+			).
+			WithInterceptorFuncs(failOnWrite).
+			Build()
+
+		h := DependentsHandler[*api.RemoteSecret]{
+			Target: &TestDeploymentTarget{
+				GetClientImpl: func() client.Client {
+					return cl
+				},
+				GetTargetNamespaceImpl: func() string {
+					return "default"
+				},
+				GetSpecImpl: func() api.LinkableSecretSpec {
+					return api.LinkableSecretSpec{
+						Name: "secret",
+						LinkedTo: []api.SecretLink{
+							{
+								ServiceAccount: api.ServiceAccountLink{
+									Reference: corev1.LocalObjectReference{
+										Name: "sa",
+									},
+								},
+							},
+							{
+								ServiceAccount: api.ServiceAccountLink{
+									As: api.ServiceAccountLinkTypeImagePullSecret,
+									Managed: api.ManagedServiceAccountSpec{
+										GenerateName: "managed-",
+									},
+								},
+							},
+						},
+					}
+				},
+			},
+			SecretDataGetter: &TestSecretDataGetter[*api.RemoteSecret]{
+				GetDataImpl: func(ctx context.Context, rs *api.RemoteSecret) (map[string][]byte, string, error) {
+					return map[string][]byte{"a": []byte("b")}, "", nil
+				},
+			},
+			ObjectMarker: &TestObjectMarker{},
+		}
+
+		plan, err := h.Plan(context.TODO(), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, api.PlannedActionCreate, plan.SecretAction)
+		assert.Equal(t, "secret", plan.SecretName)
+		assert.Equal(t, []string{"add data key 'a'"}, plan.SecretChanges)
+		assert.Equal(t, []api.ServiceAccountPlan{
+			{
+				Name:     "sa",
+				Action:   api.PlannedActionLink,
+				LinkType: api.ServiceAccountLinkTypeSecret,
+			},
+			{
+				Action:   api.PlannedActionCreate,
+				LinkType: api.ServiceAccountLinkTypeImagePullSecret,
+			},
+		}, plan.ServiceAccounts)
+	})
+
+	t.Run("replaces stale secret", func(t *testing.T) {
+		cl := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "secret",
+						Namespace: "default",
+					},
+				},
+			).
+			WithInterceptorFuncs(failOnWrite).
+			Build()
+
+		h := DependentsHandler[*api.RemoteSecret]{
+			Target: &TestDeploymentTarget{
+				GetClientImpl: func() client.Client {
+					return cl
+				},
+				GetTargetNamespaceImpl: func() string {
+					return "default"
+				},
+				GetActualSecretNameImpl: func() string {
+					return "secret"
+				},
+				GetSpecImpl: func() api.LinkableSecretSpec {
+					return api.LinkableSecretSpec{
+						Name: "updated-secret",
+					}
+				},
+			},
+			SecretDataGetter: &TestSecretDataGetter[*api.RemoteSecret]{},
+			ObjectMarker:     &TestObjectMarker{},
+		}
+
+		plan, err := h.Plan(context.TODO(), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, api.PlannedActionReplace, plan.SecretAction)
+		assert.Equal(t, "updated-secret", plan.SecretName)
+		assert.Equal(t, "secret", plan.ReplacedSecretName)
+	})
+
+	t.Run("updated secret", func(t *testing.T) {
+		cl := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "secret",
+						Namespace: "default",
+						Labels: map[string]string{
+							"removed": "label",
+						},
+					},
+					Data: map[string][]byte{
+						"a": []byte("old"),
+					},
+				},
+			).
+			WithInterceptorFuncs(failOnWrite).
+			Build()
+
+		h := DependentsHandler[*api.RemoteSecret]{
+			Target: &TestDeploymentTarget{
+				GetClientImpl: func() client.Client {
+					return cl
+				},
+				GetTargetNamespaceImpl: func() string {
+					return "default"
+				},
+				GetActualSecretNameImpl: func() string {
+					return "secret"
+				},
+				GetActualManagedLabelsImpl: func() []string {
+					return []string{"removed"}
+				},
+				GetSpecImpl: func() api.LinkableSecretSpec {
+					return api.LinkableSecretSpec{
+						Name: "secret",
+						Labels: map[string]string{
+							"added": "label",
+						},
+					}
+				},
+			},
+			SecretDataGetter: &TestSecretDataGetter[*api.RemoteSecret]{
+				GetDataImpl: func(ctx context.Context, rs *api.RemoteSecret) (map[string][]byte, string, error) {
+					return map[string][]byte{"a": []byte("new")}, "", nil
+				},
+			},
+			ObjectMarker: &TestObjectMarker{},
+		}
+
+		plan, err := h.Plan(context.TODO(), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, api.PlannedActionUpdate, plan.SecretAction)
+		assert.Equal(t, "secret", plan.SecretName)
+		assert.Equal(t, []string{"change data key 'a'", "set label 'added'", "remove label 'removed'"}, plan.SecretChanges)
+	})
+}
+
+func TestDependentsPlanCleanup(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret",
+					Namespace: "default",
+					Labels: map[string]string{
+						"managed": "obj",
+					},
+				},
+			},
+			&corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sa-refed",
+					Namespace: "default",
+					Annotations: map[string]string{
+						"linked": "obj",
+					},
+				},
+				Secrets: []corev1.ObjectReference{
+					{
+						Name: "secret",
+					},
+				},
+			},
+			&corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sa-managed",
+					Namespace: "default",
+					Labels: map[string]string{
+						"managed": "obj",
+					},
+					Annotations: map[string]string{
+						"linked": "obj",
+					},
+				},
+			},
+		).
+		Build()
+
+	h := DependentsHandler[*api.RemoteSecret]{
+		Target: &TestDeploymentTarget{
+			GetClientImpl: func() client.Client {
+				return cl
+			},
+			GetTargetNamespaceImpl: func() string {
+				return "default"
+			},
+		},
+		SecretDataGetter: &TestSecretDataGetter[*api.RemoteSecret]{},
+		ObjectMarker: &TestObjectMarker{
+			IsManagedByImpl: func(ctx context.Context, _ client.ObjectKey, o client.Object) (bool, error) {
+				return o.GetLabels()["managed"] == "obj", nil
+			},
+			IsReferencedByImpl: func(ctx context.Context, _ client.ObjectKey, o client.Object) (bool, error) {
+				return o.GetAnnotations()["linked"] == "obj", nil
+			},
+		},
+	}
+
+	plan, err := h.PlanCleanup(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, api.PlannedActionDelete, plan.SecretAction)
+	assert.Equal(t, "secret", plan.SecretName)
+	assert.ElementsMatch(t, []api.ServiceAccountPlan{
+		{
+			Name:   "sa-refed",
+			Action: api.PlannedActionUnlink,
+		},
+		{
+			Name:   "sa-managed",
+			Action: api.PlannedActionDelete,
+		},
+	}, plan.ServiceAccounts)
+
+	// nothing was changed in the cluster
+	sa := &corev1.ServiceAccount{}
+	assert.NoError(t, cl.Get(context.TODO(), client.ObjectKey{Name: "sa-managed", Namespace: "default"}, sa))
+	assert.NoError(t, cl.Get(context.TODO(), client.ObjectKey{Name: "sa-refed", Namespace: "default"}, sa))
+	assert.Len(t, sa.Secrets, 1)
+	assert.NoError(t, cl.Get(context.TODO(), client.ObjectKey{Name: "secret", Namespace: "default"}, &corev1.Secret{}))
+}
+
+func TestDependentsOrphan(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret",
+					Namespace: "default",
+					Labels: map[string]string{
+						"managed": "obj",
+					},
+					Annotations: map[string]string{
+						"linked": "obj",
+					},
+				},
+			},
+			&corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sa-refed",
+					Namespace: "default",
+					Annotations: map[string]string{
+						"linked": "obj",
+					},
+				},
+				Secrets: []corev1.ObjectReference{
+					{
+						Name: "secret",
+					},
+				},
+			},
+			&corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sa-managed",
+					Namespace: "default",
+					Labels: map[string]string{
+						"managed": "obj",
+					},
+					Annotations: map[string]string{
+						"linked": "obj",
+					},
+				},
+			},
+		).
+		Build()
+
+	h := DependentsHandler[*api.RemoteSecret]{
+		Target: &TestDeploymentTarget{
+			GetClientImpl: func() client.Client {
+				return cl
+			},
+			GetTargetNamespaceImpl: func() string {
+				return "default"
+			},
+		},
+		SecretDataGetter: &TestSecretDataGetter[*api.RemoteSecret]{},
+		ObjectMarker: &TestObjectMarker{
+			IsManagedByImpl: func(ctx context.Context, _ client.ObjectKey, o client.Object) (bool, error) {
+				return o.GetLabels()["managed"] == "obj", nil
+			},
+			IsReferencedByImpl: func(ctx context.Context, _ client.ObjectKey, o client.Object) (bool, error) {
+				return o.GetAnnotations()["linked"] == "obj", nil
+			},
+			UnmarkManagedImpl: func(ctx context.Context, _ client.ObjectKey, o client.Object) (bool, error) {
+				_, ok := o.GetLabels()["managed"]
+				delete(o.GetLabels(), "managed")
+				return ok, nil
+			},
+			UnmarkReferencedImpl: func(ctx context.Context, _ client.ObjectKey, o client.Object) (bool, error) {
+				_, ok := o.GetAnnotations()["linked"]
+				delete(o.GetAnnotations(), "linked")
+				return ok, nil
+			},
+		},
+	}
+
+	assert.NoError(t, h.Orphan(context.TODO()))
+
+	secret := &corev1.Secret{}
+	assert.NoError(t, cl.Get(context.TODO(), client.ObjectKey{Name: "secret", Namespace: "default"}, secret))
+	assert.NotContains(t, secret.Labels, "managed")
+	assert.NotContains(t, secret.Annotations, "linked")
+
+	sa := &corev1.ServiceAccount{}
+	assert.NoError(t, cl.Get(context.TODO(), client.ObjectKey{Name: "sa-managed", Namespace: "default"}, sa))
+	assert.NotContains(t, sa.Labels, "managed")
+	assert.NotContains(t, sa.Annotations, "linked")
+
+	sa = &corev1.ServiceAccount{}
+	assert.NoError(t, cl.Get(context.TODO(), client.ObjectKey{Name: "sa-refed", Namespace: "default"}, sa))
+	assert.NotContains(t, sa.Annotations, "linked")
+	// the service account stays linked to the orphaned secret
+	assert.Len(t, sa.Secrets, 1)
+}
+
 func TestDependentsRevertTo(t *testing.T) {
 	scheme := runtime.NewScheme()
 	assert.NoError(t, corev1.AddToScheme(scheme))
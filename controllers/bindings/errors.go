@@ -14,7 +14,13 @@
 
 package bindings
 
-import "errors"
+import (
+	"errors"
+	"net"
+
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/redhat-appstudio/remote-secret/pkg/policy"
+)
 
 type ErrorReason string
 
@@ -36,3 +42,31 @@ const (
 var (
 	SecretDataNotFoundError = errors.New("data not found")
 )
+
+// TargetReasonFor classifies the error that occurred during the deployment to a target into the machine-readable reason
+// reported in the status of the target. The reason returned from the Sync method of the DependentsHandler is used if the error
+// doesn't fall into any of the more specific categories.
+func TargetReasonFor(err error, reason ErrorReason) api.TargetReason {
+	var netErr net.Error
+
+	switch {
+	case err == nil:
+		return api.TargetReasonDeployed
+	case errors.Is(err, noAuthServiceAccountFound), errors.Is(err, onlyOneAuthServiceAccountPerNamespaceAllowed):
+		return api.TargetReasonAuthServiceAccountUnavailable
+	case errors.Is(err, noKubeConfigSpecifiedForConnectionToRemoteCluster), errors.Is(err, kubeConfigSecretUnavailableError), errors.Is(err, kubeConfigSecretNotAllowedError):
+		return api.TargetReasonClusterCredentialsUnavailable
+	case errors.Is(err, policy.PolicyViolationError):
+		return api.TargetReasonPolicyViolation
+	case errors.Is(err, ErrorInvalidClientConfig), errors.As(err, &netErr):
+		return api.TargetReasonClusterUnreachable
+	case errors.Is(err, managedByOtherError), errors.Is(err, adoptionForbiddenError):
+		return api.TargetReasonSecretNameCollision
+	case errors.Is(err, DependentsInconsistencyError):
+		return api.TargetReasonInconsistent
+	case reason != ErrorReasonNone:
+		return api.TargetReason(reason)
+	default:
+		return api.TargetReasonError
+	}
+}
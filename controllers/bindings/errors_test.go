@@ -0,0 +1,45 @@
+//
+// Copyright (c) 2021 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bindings
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/redhat-appstudio/remote-secret/pkg/policy"
+)
+
+func TestTargetReasonFor(t *testing.T) {
+	test := func(expected api.TargetReason, err error, reason ErrorReason) {
+		t.Run(string(expected), func(t *testing.T) {
+			assert.Equal(t, expected, TargetReasonFor(err, reason))
+		})
+	}
+
+	test(api.TargetReasonDeployed, nil, ErrorReasonNone)
+	test(api.TargetReasonAuthServiceAccountUnavailable, fmt.Errorf("failed: %w", noAuthServiceAccountFound), ErrorReasonNone)
+	test(api.TargetReasonClusterCredentialsUnavailable, fmt.Errorf("%w: %w", kubeConfigSecretUnavailableError, errors.New("not found")), ErrorReasonNone)
+	test(api.TargetReasonPolicyViolation, fmt.Errorf("the target namespace 'ns' %w 'policy'", policy.PolicyViolationError), ErrorReasonNone)
+	test(api.TargetReasonClusterUnreachable, fmt.Errorf("failed: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}), ErrorReasonSecretUpdate)
+	test(api.TargetReasonSecretNameCollision, fmt.Errorf("failed: %w", managedByOtherError), ErrorReasonSecretUpdate)
+	test(api.TargetReasonInconsistent, managedServiceAccountAlreadyExists, ErrorReasonServiceAccountUpdate)
+	test(api.TargetReasonServiceAccountUnavailable, errors.New("kaboom"), ErrorReasonServiceAccountUnavailable)
+	test(api.TargetReasonError, errors.New("kaboom"), ErrorReasonNone)
+}
@@ -15,12 +15,15 @@
 package bindings
 
 import (
+	"bytes"
 	"context"
 	stderr "errors"
 	"fmt"
+	"sort"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
 	"github.com/redhat-appstudio/remote-secret/pkg/logs"
 	"github.com/redhat-appstudio/remote-secret/pkg/sync"
 	corev1 "k8s.io/api/core/v1"
@@ -31,7 +34,8 @@ import (
 )
 
 var (
-	managedByOtherError = stderr.New("target Secret is managed by other Object")
+	managedByOtherError    = stderr.New("target Secret is managed by other Object")
+	adoptionForbiddenError = stderr.New("target Secret already exists and the adoption policy forbids adopting it")
 	// pre-allocated empty map so that we don't have to allocate new empty instances in the serviceAccountSecretDiffOpts
 	emptySecretData = map[string][]byte{}
 
@@ -87,32 +91,82 @@ type secretHandler[K any] struct {
 	Target           SecretDeploymentTarget
 	ObjectMarker     ObjectMarker
 	SecretDataGetter SecretDataGetter[K]
+	ServerSideApply  bool
+	AdoptionPolicy   api.AdoptionPolicy
 }
 
-// CheckColliding detects whether the target Secret exists and is managed by other RemoteSecret, returns error if it is.
+// CheckColliding detects whether the target Secret exists and cannot be adopted according to the adoption policy, returns error if it is.
+// With the default adoption policy, this means that the secret is managed by other RemoteSecret.
 func (h *secretHandler[K]) CheckColliding(ctx context.Context) error {
+	secret, err := h.getExisting(ctx)
+	if err != nil || secret == nil {
+		return err
+	}
+
+	return h.checkAdoptable(ctx, secret)
+}
+
+// FindAdoptable returns the secret with the name from the spec that already exists in the target if the adoption policy allows
+// adopting it. Nil is returned if there is no such secret.
+func (h *secretHandler[K]) FindAdoptable(ctx context.Context) (*corev1.Secret, error) {
+	secret, err := h.getExisting(ctx)
+	if err != nil || secret == nil {
+		return nil, err
+	}
+
+	if err := h.checkAdoptable(ctx, secret); err != nil {
+		if stderr.Is(err, managedByOtherError) || stderr.Is(err, adoptionForbiddenError) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+// getExisting returns the secret with the name from the spec if it exists in the target.
+func (h *secretHandler[K]) getExisting(ctx context.Context) (*corev1.Secret, error) {
 	// if SecretDeploymentTarget name is not specified, then we can be sure it will not collide with other targets
 	if h.Target.GetSpec().Name == "" {
-		return nil
+		return nil, nil
 	}
 
 	secret := &corev1.Secret{}
 	err := h.Target.GetClient().Get(ctx, client.ObjectKey{Name: h.Target.GetSpec().Name, Namespace: h.Target.GetTargetNamespace()}, secret)
 	if errors.IsNotFound(err) {
-		return nil
+		return nil, nil
 	}
 	if err != nil {
-		return fmt.Errorf("failed to get target Secret: %w", err)
+		return nil, fmt.Errorf("failed to get target Secret: %w", err)
 	}
 
-	managedByOther, colliding, err := h.ObjectMarker.IsManagedByOther(ctx, h.Target.GetTargetObjectKey(), secret)
-	if err != nil {
-		return fmt.Errorf("could not determine if target Secret is managed by other Object: %w", err)
-	}
-	if managedByOther {
-		return fmt.Errorf("%w: %s", managedByOtherError, colliding.String())
+	return secret, nil
+}
+
+// checkAdoptable returns an error if the provided existing secret cannot be adopted by the target according to the adoption policy.
+func (h *secretHandler[K]) checkAdoptable(ctx context.Context, secret *corev1.Secret) error {
+	switch h.AdoptionPolicy {
+	case api.AdoptionPolicyAlways:
+		return nil
+	case api.AdoptionPolicyNever:
+		managed, err := h.ObjectMarker.IsManagedBy(ctx, h.Target.GetTargetObjectKey(), secret)
+		if err != nil {
+			return fmt.Errorf("could not determine if target Secret is managed by the target: %w", err)
+		}
+		if !managed {
+			return fmt.Errorf("%w: %s", adoptionForbiddenError, client.ObjectKeyFromObject(secret))
+		}
+		return nil
+	default:
+		managedByOther, colliding, err := h.ObjectMarker.IsManagedByOther(ctx, h.Target.GetTargetObjectKey(), secret)
+		if err != nil {
+			return fmt.Errorf("could not determine if target Secret is managed by other Object: %w", err)
+		}
+		if managedByOther {
+			return fmt.Errorf("%w: %s", managedByOtherError, colliding.String())
+		}
+		return nil
 	}
-	return nil
 }
 
 // GetStale detects whether the secret referenced by the target is stale and needs to be replaced by a new one.
@@ -139,9 +193,65 @@ func (h *secretHandler[K]) GetStale(ctx context.Context) (*corev1.Secret, error)
 // Sync creates or updates the secret with the data from the given key. The recreate flag can be used to force the creation of a new secret even
 // if the target already reports an existing secret using its GetActualSecretName method. This can be used to deal with the stale secrets (see GetStale method).
 func (h *secretHandler[K]) Sync(ctx context.Context, key K, recreate bool) (*corev1.Secret, string, error) {
+	secret, diffOpts, errorReason, err := h.desiredSecret(ctx, key, recreate)
+	if err != nil {
+		return nil, errorReason, err
+	}
+
+	syncer := sync.New(h.Target.GetClient())
+
+	lg := log.FromContext(ctx).V(logs.DebugLevel)
+
+	if h.ServerSideApply {
+		lg.Info("applying binding secret", "secret", secret, "secretMetadata", &secret.ObjectMeta)
+		obj, err := h.apply(ctx, &syncer, secret)
+		if err != nil {
+			return nil, string(ErrorReasonSecretUpdate), fmt.Errorf("failed to apply the secret with the token data: %w", err)
+		}
+		return obj, "", nil
+	}
+
+	lg.Info("syncing binding secret", "secret", secret, "secretMetadata", &secret.ObjectMeta)
+
+	_, obj, err := syncer.Sync(ctx, nil, secret, diffOpts, h.labelsAndAnnotationsSyncOptions())
+	if err != nil {
+		return nil, string(ErrorReasonSecretUpdate), fmt.Errorf("failed to sync the secret with the token data: %w", err)
+	}
+	return obj.(*corev1.Secret), "", nil
+}
+
+// Plan computes what Sync would do with the secret without changing anything in the cluster. It returns the planned action,
+// the name of the secret (empty if the secret would be created with a generated name) and the list of the changes that would
+// be made to the secret.
+func (h *secretHandler[K]) Plan(ctx context.Context, key K, recreate bool) (api.PlannedAction, string, []string, error) {
+	secret, diffOpts, _, err := h.desiredSecret(ctx, key, recreate)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	syncer := sync.New(h.Target.GetClient())
+	actual, desired, err := syncer.Plan(ctx, secret, diffOpts, h.labelsAndAnnotationsSyncOptions())
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to compute the changes of the secret: %w", err)
+	}
+
+	if actual == nil {
+		return api.PlannedActionCreate, secret.Name, describeSecretChanges(&corev1.Secret{}, secret), nil
+	}
+
+	if desired == nil {
+		return api.PlannedActionNone, actual.GetName(), nil, nil
+	}
+
+	return api.PlannedActionUpdate, actual.GetName(), describeSecretChanges(actual.(*corev1.Secret), desired.(*corev1.Secret)), nil
+}
+
+// desiredSecret constructs the secret as it should look like in the target together with the options to use when comparing it
+// with the secret in the cluster.
+func (h *secretHandler[K]) desiredSecret(ctx context.Context, key K, recreate bool) (*corev1.Secret, cmp.Option, string, error) {
 	data, errorReason, err := h.SecretDataGetter.GetData(ctx, key)
 	if err != nil {
-		return nil, errorReason, fmt.Errorf("failed to obtain the secret data: %w", err)
+		return nil, nil, errorReason, fmt.Errorf("failed to obtain the secret data: %w", err)
 	}
 
 	// we're going to be modifying the spec, so let's make sure we do that on a copy (including the copies of labels and annos, for which we do need the DeepCopy instead of
@@ -175,38 +285,64 @@ func (h *secretHandler[K]) Sync(ctx context.Context, key K, recreate bool) (*cor
 		Type: desiredSpec.Type,
 	}
 
-	// we need to construct the list of labels that we want managed on the target secret
-	// (i.e the list of keys that we want to delete from the maps if they are no longer desired
-	// in our spec).
-	// Maybe a little bit non-intuitively, that actually is the set of labels/annos already present
-	// on the secret. We need those that are in the desiredSpec to stay and those that are not
-	// there to disappear. So by declaring the already present labels as managed we can make that
-	// happen.
-	managedLabels := h.Target.GetActualManagedLabels()
-	managedAnnos := h.Target.GetActualManagedAnnotations()
-
 	if secret.GenerateName == "" {
 		secret.GenerateName = h.Target.GetTargetObjectKey().Name + "-secret-"
 	}
 
 	_, err = h.ObjectMarker.MarkManaged(ctx, h.Target.GetTargetObjectKey(), secret)
 	if err != nil {
-		return nil, string(ErrorReasonSecretUpdate), fmt.Errorf("failed to mark the secret as managed in the deployment target (%s): %w", h.Target.GetType(), err)
+		return nil, nil, string(ErrorReasonSecretUpdate), fmt.Errorf("failed to mark the secret as managed in the deployment target (%s): %w", h.Target.GetType(), err)
 	}
 
-	syncer := sync.New(h.Target.GetClient())
+	return secret, diffOpts, "", nil
+}
 
-	lg := log.FromContext(ctx).V(logs.DebugLevel)
-	lg.Info("syncing binding secret", "secret", secret, "secretMetadata", &secret.ObjectMeta)
+// labelsAndAnnotationsSyncOptions constructs the list of labels that we want managed on the target secret
+// (i.e the list of keys that we want to delete from the maps if they are no longer desired
+// in our spec).
+// Maybe a little bit non-intuitively, that actually is the set of labels/annos already present
+// on the secret. We need those that are in the desiredSpec to stay and those that are not
+// there to disappear. So by declaring the already present labels as managed we can make that
+// happen.
+func (h *secretHandler[K]) labelsAndAnnotationsSyncOptions() sync.LabelsAndAnnotationsSyncOptions {
+	return sync.LabelsAndAnnotationsSyncOptions{
+		ManagedLabelKeys:      h.Target.GetActualManagedLabels(),
+		ManagedAnnotationKeys: h.Target.GetActualManagedAnnotations(),
+	}
+}
+
+// apply deploys the secret using the server-side apply. The labels and annotations that we no longer want on the secret are
+// removed by the cluster based on the managed fields of the secret, so we don't need to track them ourselves.
+//
+// Because server-side apply cannot use the generate name, the secret without a name is created instead and the cluster generates
+// the name. The fields set by the creation or by the updates done before the server-side apply was enabled are taken over
+// by the next apply (see sync.Syncer.UpgradeManagedFields), so that they are removed once they are no longer desired.
+func (h *secretHandler[K]) apply(ctx context.Context, syncer *sync.Syncer, secret *corev1.Secret) (*corev1.Secret, error) {
+	cl := h.Target.GetClient()
+
+	if secret.Name == "" {
+		if err := cl.Create(ctx, secret, client.FieldOwner(FieldManager)); err != nil {
+			return nil, fmt.Errorf("failed to create the secret with the generated name %s in namespace %s: %w", secret.GenerateName, secret.Namespace, err)
+		}
+		return secret, nil
+	}
+	secret.GenerateName = ""
+
+	existing := &corev1.Secret{}
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(secret), existing); err == nil {
+		if err := syncer.UpgradeManagedFields(ctx, existing, clientSideFieldManagers, FieldManager); err != nil {
+			return nil, fmt.Errorf("failed to take over the fields of the secret %s: %w", client.ObjectKeyFromObject(secret), err)
+		}
+	} else if !errors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get the secret %s: %w", client.ObjectKeyFromObject(secret), err)
+	}
 
-	_, obj, err := syncer.Sync(ctx, nil, secret, diffOpts, sync.LabelsAndAnnotationsSyncOptions{
-		ManagedLabelKeys:      managedLabels,
-		ManagedAnnotationKeys: managedAnnos,
-	})
+	obj, err := syncer.Apply(ctx, nil, secret, FieldManager)
 	if err != nil {
-		return nil, string(ErrorReasonSecretUpdate), fmt.Errorf("failed to sync the secret with the token data: %w", err)
+		return nil, fmt.Errorf("failed to apply the secret %s: %w", client.ObjectKeyFromObject(secret), err)
 	}
-	return obj.(*corev1.Secret), "", nil
+
+	return obj.(*corev1.Secret), nil
 }
 
 func (h *secretHandler[K]) List(ctx context.Context) ([]*corev1.Secret, error) {
@@ -240,3 +376,64 @@ func (h *secretHandler[K]) List(ctx context.Context) ([]*corev1.Secret, error) {
 
 	return ret, nil
 }
+
+// describeSecretChanges lists the changes between the actual and the desired secret in a human-readable form. The values
+// of the secret data are never included in the output, only the keys.
+func describeSecretChanges(actual *corev1.Secret, desired *corev1.Secret) []string {
+	changes := []string{}
+
+	if desired.Type != "" && actual.Type != desired.Type {
+		changes = append(changes, fmt.Sprintf("set type to '%s'", desired.Type))
+	}
+
+	ignoredDataKeys := map[string]bool{}
+	if desired.Type == corev1.SecretTypeServiceAccountToken {
+		// these are filled in by Kubernetes, see serviceAccountSecretDiffOpts
+		ignoredDataKeys = map[string]bool{"ca.crt": true, "namespace": true, "token": true}
+	}
+
+	for _, k := range sortedKeys(desired.Data) {
+		if ignoredDataKeys[k] {
+			continue
+		}
+		if v, ok := actual.Data[k]; !ok {
+			changes = append(changes, fmt.Sprintf("add data key '%s'", k))
+		} else if !bytes.Equal(v, desired.Data[k]) {
+			changes = append(changes, fmt.Sprintf("change data key '%s'", k))
+		}
+	}
+	for _, k := range sortedKeys(actual.Data) {
+		if _, ok := desired.Data[k]; !ok && !ignoredDataKeys[k] {
+			changes = append(changes, fmt.Sprintf("remove data key '%s'", k))
+		}
+	}
+
+	changes = append(changes, describeMapChanges("label", actual.Labels, desired.Labels)...)
+	changes = append(changes, describeMapChanges("annotation", actual.Annotations, desired.Annotations)...)
+
+	return changes
+}
+
+func describeMapChanges(kind string, actual map[string]string, desired map[string]string) []string {
+	changes := []string{}
+	for _, k := range sortedKeys(desired) {
+		if v, ok := actual[k]; !ok || v != desired[k] {
+			changes = append(changes, fmt.Sprintf("set %s '%s'", kind, k))
+		}
+	}
+	for _, k := range sortedKeys(actual) {
+		if _, ok := desired[k]; !ok {
+			changes = append(changes, fmt.Sprintf("remove %s '%s'", kind, k))
+		}
+	}
+	return changes
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -16,6 +16,7 @@ package bindings
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
@@ -23,8 +24,10 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 func TestSync(t *testing.T) {
@@ -227,6 +230,96 @@ func TestSync(t *testing.T) {
 	})
 }
 
+func TestSyncServerSideApply(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	var applied *corev1.Secret
+	var patchOpts client.PatchOptions
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, cl client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			assert.Equal(t, types.ApplyPatchType, patch.Type())
+			patchOpts = client.PatchOptions{}
+			patchOpts.ApplyOptions(opts)
+			applied = obj.(*corev1.Secret).DeepCopy()
+			return nil
+		},
+	}).Build()
+
+	token := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "token",
+			Namespace: "default",
+		},
+	}
+
+	deploymentTarget := &TestDeploymentTarget{
+		GetClientImpl: func() client.Client { return cl },
+		GetTargetNamespaceImpl: func() string {
+			return "ns"
+		},
+		GetTargetObjectKeyImpl: func() client.ObjectKey {
+			return client.ObjectKeyFromObject(token)
+		},
+	}
+	h := secretHandler[*api.RemoteSecret]{
+		Target:       deploymentTarget,
+		ObjectMarker: &TestObjectMarker{},
+		SecretDataGetter: &TestSecretDataGetter[*api.RemoteSecret]{
+			GetDataImpl: func(ctx context.Context, st *api.RemoteSecret) (map[string][]byte, string, error) {
+				return map[string][]byte{
+					"token": []byte("token"),
+				}, "", nil
+			},
+		},
+		ServerSideApply: true,
+	}
+
+	t.Run("named secret", func(t *testing.T) {
+		deploymentTarget.GetSpecImpl = func() api.LinkableSecretSpec {
+			return api.LinkableSecretSpec{
+				Name: "secret",
+				Labels: map[string]string{
+					"a": "b",
+				},
+				Type: corev1.SecretTypeBasicAuth,
+			}
+		}
+
+		secret, reason, err := h.Sync(context.TODO(), token, false)
+		assert.Equal(t, "", reason)
+		assert.NoError(t, err)
+
+		assert.NotNil(t, applied)
+		assert.Equal(t, FieldManager, patchOpts.FieldManager)
+		assert.Equal(t, "secret", applied.Name)
+		assert.Empty(t, applied.GenerateName)
+		assert.Equal(t, "b", applied.Labels["a"])
+		assert.Equal(t, []byte("token"), applied.Data["token"])
+		assert.Equal(t, "secret", secret.Name)
+	})
+
+	t.Run("generated name", func(t *testing.T) {
+		deploymentTarget.GetSpecImpl = func() api.LinkableSecretSpec {
+			return api.LinkableSecretSpec{
+				GenerateName: "secret-",
+			}
+		}
+		applied = nil
+
+		secret, reason, err := h.Sync(context.TODO(), token, false)
+		assert.Equal(t, "", reason)
+		assert.NoError(t, err)
+
+		// the secret is created so that the cluster generates the name
+		assert.Nil(t, applied)
+		assert.True(t, strings.HasPrefix(secret.Name, "secret-"))
+		created := &corev1.Secret{}
+		assert.NoError(t, cl.Get(context.TODO(), client.ObjectKeyFromObject(secret), created))
+		assert.Equal(t, []byte("token"), created.Data["token"])
+	})
+}
+
 func TestList(t *testing.T) {
 	scheme := runtime.NewScheme()
 	assert.NoError(t, corev1.AddToScheme(scheme))
@@ -343,4 +436,101 @@ func TestCheckColliding(t *testing.T) {
 		assert.Error(t, err)
 		assert.ErrorContains(t, err, "other")
 	})
+
+	t.Run("adoption policies", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "unmanaged",
+					Namespace: "default",
+				},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "managed",
+					Namespace: "default",
+					Labels: map[string]string{
+						"managed": "true",
+					},
+				},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "managed-by-other",
+					Namespace: "default",
+					Labels: map[string]string{
+						"managed": "other",
+					},
+				},
+			}).Build()
+
+		handler := func(secretName string, policy api.AdoptionPolicy) *secretHandler[*api.RemoteSecret] {
+			return &secretHandler[*api.RemoteSecret]{
+				Target: &TestDeploymentTarget{
+					GetClientImpl: func() client.Client { return cl },
+					GetTargetNamespaceImpl: func() string {
+						return "default"
+					},
+					GetSpecImpl: func() api.LinkableSecretSpec {
+						return api.LinkableSecretSpec{
+							Name: secretName,
+						}
+					},
+				},
+				ObjectMarker: &TestObjectMarker{
+					IsManagedByImpl: func(ctx context.Context, _ client.ObjectKey, o client.Object) (bool, error) {
+						return o.GetLabels()["managed"] == "true", nil
+					},
+					IsManagedByOtherImpl: func(ctx context.Context, _ client.ObjectKey, o client.Object) (bool, client.ObjectKey, error) {
+						return o.GetLabels()["managed"] == "other", client.ObjectKey{Name: "other", Namespace: "default"}, nil
+					},
+				},
+				SecretDataGetter: &TestSecretDataGetter[*api.RemoteSecret]{},
+				AdoptionPolicy:   policy,
+			}
+		}
+
+		expectations := map[api.AdoptionPolicy]map[string]bool{
+			"": {
+				"unmanaged":        true,
+				"managed":          true,
+				"managed-by-other": false,
+			},
+			api.AdoptionPolicyIfUnmanaged: {
+				"unmanaged":        true,
+				"managed":          true,
+				"managed-by-other": false,
+			},
+			api.AdoptionPolicyNever: {
+				"unmanaged":        false,
+				"managed":          true,
+				"managed-by-other": false,
+			},
+			api.AdoptionPolicyAlways: {
+				"unmanaged":        true,
+				"managed":          true,
+				"managed-by-other": true,
+			},
+		}
+
+		for policy, secrets := range expectations {
+			for secretName, adoptable := range secrets {
+				t.Run(string(policy)+"/"+secretName, func(t *testing.T) {
+					h := handler(secretName, policy)
+
+					err := h.CheckColliding(context.TODO())
+					found, ferr := h.FindAdoptable(context.TODO())
+					assert.NoError(t, ferr)
+
+					if adoptable {
+						assert.NoError(t, err)
+						assert.NotNil(t, found)
+					} else {
+						assert.Error(t, err)
+						assert.Nil(t, found)
+					}
+				})
+			}
+		}
+	})
 }
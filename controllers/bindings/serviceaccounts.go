@@ -18,7 +18,9 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cenkalti/backoff/v4"
 	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/redhat-appstudio/remote-secret/pkg/sync"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -39,8 +41,9 @@ const (
 )
 
 type serviceAccountHandler struct {
-	Target       SecretDeploymentTarget
-	ObjectMarker ObjectMarker
+	Target          SecretDeploymentTarget
+	ObjectMarker    ObjectMarker
+	ServerSideApply bool
 }
 
 func (h *serviceAccountHandler) Sync(ctx context.Context) ([]*corev1.ServiceAccount, string, error) {
@@ -75,6 +78,18 @@ func (h *serviceAccountHandler) LinkToSecret(ctx context.Context, serviceAccount
 		//
 		// Note that this SHOULD do at most 1 retry, but let's try a little harder than that to allow for multiple out-of-process concurrent updates
 		// on the SA.
+		if h.ServerSideApply {
+			if err := h.applyLink(ctx, sa, secret.Name, linkType); err != nil {
+				return fmt.Errorf("failed to link the secret %s to the service account %s while processing the deployment target (%s) %s: %w",
+					client.ObjectKeyFromObject(secret),
+					client.ObjectKeyFromObject(sa),
+					h.Target.GetType(),
+					h.Target.GetTargetObjectKey(),
+					err)
+			}
+			continue
+		}
+
 		attempt := func() (client.Object, error) {
 			if h.linkSecretByName(sa, secret.Name, linkType) {
 				return sa, nil
@@ -98,6 +113,127 @@ func (h *serviceAccountHandler) LinkToSecret(ctx context.Context, serviceAccount
 	return nil
 }
 
+// applyLink links the secret to the service account using the server-side apply. Each secret is linked using its own field manager
+// so that the links of the other secrets are left intact.
+//
+// The secrets of a service account are a list keyed by the name of the secret, so we can own just our entry in it. The image pull
+// secrets are an atomic list though, so the whole list has to be applied. To not overwrite the concurrent changes of the list, it is
+// applied only if the service account didn't change since we read it, retrying with the fresh service account if it did.
+func (h *serviceAccountHandler) applyLink(ctx context.Context, sa *corev1.ServiceAccount, secretName string, linkType api.ServiceAccountLinkType) error {
+	syncer := sync.New(h.Target.GetClient())
+	fieldManager := linkFieldManager(secretName)
+
+	err := backoff.Retry(func() error {
+		linked := sa.DeepCopy()
+		if !h.linkSecretByName(linked, secretName, linkType) {
+			// already linked
+			return nil
+		}
+
+		applied := &corev1.ServiceAccount{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ServiceAccount",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      sa.Name,
+				Namespace: sa.Namespace,
+			},
+		}
+		if linkType == api.ServiceAccountLinkTypeImagePullSecret {
+			applied.ResourceVersion = sa.ResourceVersion
+			applied.ImagePullSecrets = linked.ImagePullSecrets
+		} else {
+			applied.Secrets = []corev1.ObjectReference{{Name: secretName}}
+		}
+
+		obj, err := syncer.Apply(ctx, nil, applied, fieldManager)
+		if err == nil {
+			*sa = *obj.(*corev1.ServiceAccount)
+			return nil
+		}
+		if errors.IsConflict(err) {
+			if gerr := h.Target.GetClient().Get(ctx, client.ObjectKeyFromObject(sa), sa); gerr == nil {
+				return fmt.Errorf("the service account changed while linking: %w", err)
+			}
+		}
+		// permanent error interrupts the Retry even if there are still some attempts left.
+		return backoff.Permanent(err) //nolint:wrapcheck // This is an "indication error" to the Backoff framework that is not exposed further.
+	}, backoff.WithContext(backoff.WithMaxRetries(backoff.NewExponentialBackOff(), serviceAccountUpdateRetryCount), ctx))
+
+	if err != nil {
+		return fmt.Errorf("failed to apply the link to the secret %s: %w", secretName, err)
+	}
+	return nil
+}
+
+// Plan computes what Sync and LinkToSecret would do with the service accounts without changing anything in the cluster.
+// The secretName is the name of the secret the service accounts should be linked to. It can be empty if the secret would
+// be created with a generated name.
+func (h *serviceAccountHandler) Plan(ctx context.Context, secretName string) ([]api.ServiceAccountPlan, error) {
+	spec := h.Target.GetSpec()
+	actualSANames := h.Target.GetActualServiceAccountNames()
+
+	plans := []api.ServiceAccountPlan{}
+	for i, link := range spec.LinkedTo {
+		var name string
+		managed := false
+		if link.ServiceAccount.Reference.Name != "" {
+			name = link.ServiceAccount.Reference.Name
+		} else if link.ServiceAccount.Managed.Name != "" || link.ServiceAccount.Managed.GenerateName != "" {
+			managed = true
+			if len(actualSANames) > i {
+				name = actualSANames[i]
+			}
+			if name == "" {
+				name = link.ServiceAccount.Managed.Name
+			}
+		} else {
+			continue
+		}
+
+		linkType := link.ServiceAccount.EffectiveSecretLinkType()
+		plan := api.ServiceAccountPlan{Name: name, LinkType: linkType}
+
+		sa := &corev1.ServiceAccount{}
+		found := false
+		if name != "" {
+			key := client.ObjectKey{Name: name, Namespace: h.Target.GetTargetNamespace()}
+			if err := h.Target.GetClient().Get(ctx, key, sa); err != nil {
+				if !errors.IsNotFound(err) || !managed {
+					return nil, fmt.Errorf("failed to get the service account (%s): %w", key, err)
+				}
+			} else {
+				found = true
+			}
+		}
+
+		if found && managed {
+			if ok, err := h.ObjectMarker.IsReferencedBy(ctx, h.Target.GetTargetObjectKey(), sa); err != nil {
+				return nil, fmt.Errorf("failed to determine if service account %s is referenced by the deployment target (%s) %s: %w",
+					client.ObjectKeyFromObject(sa),
+					h.Target.GetType(),
+					h.Target.GetTargetObjectKey(),
+					err)
+			} else if !ok {
+				return nil, managedServiceAccountAlreadyExists
+			}
+		}
+
+		if !found {
+			plan.Action = api.PlannedActionCreate
+		} else if secretName == "" || h.linkSecretByName(sa.DeepCopy(), secretName, linkType) {
+			plan.Action = api.PlannedActionLink
+		} else {
+			plan.Action = api.PlannedActionNone
+		}
+
+		plans = append(plans, plan)
+	}
+
+	return plans, nil
+}
+
 func (h *serviceAccountHandler) linkSecretByName(sa *corev1.ServiceAccount, secretName string, linkType api.ServiceAccountLinkType) bool {
 	updated := false
 	hasLink := false
@@ -255,7 +391,16 @@ func (h *serviceAccountHandler) ensureReferencedServiceAccount(ctx context.Conte
 				err)
 	}
 
-	if changed || managedChanged {
+	if h.ServerSideApply && changed && !managedChanged {
+		// the managed marks are removed using the update below, because they might not be owned by the server-side apply
+		if err := h.applyReferencedMarks(ctx, sa); err != nil {
+			return nil, string(ErrorReasonServiceAccountUpdate), fmt.Errorf("failed to apply the annotations in the referenced service account %s while processing the deployment target (%s) %s: %w",
+				client.ObjectKeyFromObject(sa),
+				h.Target.GetType(),
+				h.Target.GetTargetObjectKey(),
+				err)
+		}
+	} else if changed || managedChanged {
 		// we need to update the service account with the new link to the target
 		if err := h.Target.GetClient().Update(ctx, sa); err != nil {
 			return nil, string(ErrorReasonServiceAccountUpdate), fmt.Errorf("failed to update the annotations in the referenced service account %s while processing the deployment target (%s) %s: %w",
@@ -384,7 +529,16 @@ func (h *serviceAccountHandler) ensureManagedServiceAccount(ctx context.Context,
 		}
 		needsUpdate = needsUpdate || changed
 
-		if needsUpdate {
+		if h.ServerSideApply {
+			// we always apply, because we cannot detect the labels and annotations that are no longer requested
+			// without looking into the managed fields. The cluster does that for us.
+			// The links to the secrets are not applied together with the metadata, so we must not take them over.
+			syncer := sync.New(h.Target.GetClient())
+			err = syncer.UpgradeManagedFields(ctx, sa, clientSideFieldManagers, FieldManager, "secrets", "imagePullSecrets")
+			if err == nil {
+				sa, err = h.applyManagedServiceAccount(ctx, sa, requestedLabels, requestedAnnotations)
+			}
+		} else if needsUpdate {
 			err = h.Target.GetClient().Update(ctx, sa)
 		}
 	}
@@ -395,3 +549,89 @@ func (h *serviceAccountHandler) ensureManagedServiceAccount(ctx context.Context,
 
 	return sa, "", nil
 }
+
+// applyManagedServiceAccount applies the requested labels and annotations together with the marks of the object marker
+// on the provided managed service account using the server-side apply. The labels and annotations that were applied
+// previously and are no longer requested are removed by the cluster.
+//
+// Note that only the metadata is applied. The links to the secrets are not, because the image pull secrets of a service
+// account are an atomic list and applying them would take over the whole list from the other field managers.
+func (h *serviceAccountHandler) applyManagedServiceAccount(ctx context.Context, sa *corev1.ServiceAccount, requestedLabels map[string]string, requestedAnnotations map[string]string) (*corev1.ServiceAccount, error) {
+	applied := &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ServiceAccount",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        sa.Name,
+			Namespace:   sa.Namespace,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+	}
+
+	for k, v := range requestedLabels {
+		applied.Labels[k] = v
+	}
+	for k, v := range requestedAnnotations {
+		applied.Annotations[k] = v
+	}
+
+	// We need to find out what labels and annotations the object marker uses. The values of those are taken from the
+	// service account though, because they can also contain the references of the other targets.
+	marks := &corev1.ServiceAccount{}
+	if _, err := h.ObjectMarker.MarkManaged(ctx, h.Target.GetTargetObjectKey(), marks); err != nil {
+		return nil, fmt.Errorf("failed to determine the marks of the managed service account: %w", err)
+	}
+	copyMarks(marks, sa, applied)
+
+	syncer := sync.New(h.Target.GetClient())
+	obj, err := syncer.Apply(ctx, nil, applied, FieldManager)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply the managed service account %s: %w", client.ObjectKeyFromObject(sa), err)
+	}
+
+	return obj.(*corev1.ServiceAccount), nil
+}
+
+// applyReferencedMarks applies the marks of the object marker on the provided referenced service account using the server-side apply.
+// The service account can be referenced by several targets, so the marks are applied using the field manager of the target.
+func (h *serviceAccountHandler) applyReferencedMarks(ctx context.Context, sa *corev1.ServiceAccount) error {
+	applied := &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ServiceAccount",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        sa.Name,
+			Namespace:   sa.Namespace,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+	}
+
+	marks := &corev1.ServiceAccount{}
+	if _, err := h.ObjectMarker.MarkReferenced(ctx, h.Target.GetTargetObjectKey(), marks); err != nil {
+		return fmt.Errorf("failed to determine the marks of the referenced service account: %w", err)
+	}
+	copyMarks(marks, sa, applied)
+
+	syncer := sync.New(h.Target.GetClient())
+	obj, err := syncer.Apply(ctx, nil, applied, targetFieldManager(h.Target.GetTargetObjectKey()))
+	if err != nil {
+		return fmt.Errorf("failed to apply the referenced service account %s: %w", client.ObjectKeyFromObject(sa), err)
+	}
+	*sa = *obj.(*corev1.ServiceAccount)
+
+	return nil
+}
+
+// copyMarks copies the values of the labels and annotations used in marks from the service account to the applied service account.
+func copyMarks(marks *corev1.ServiceAccount, sa *corev1.ServiceAccount, applied *corev1.ServiceAccount) {
+	for k := range marks.Labels {
+		applied.Labels[k] = sa.Labels[k]
+	}
+	for k := range marks.Annotations {
+		applied.Annotations[k] = sa.Annotations[k]
+	}
+}
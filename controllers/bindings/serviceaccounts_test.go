@@ -26,9 +26,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 func TestServiceAccountSecretComparator(t *testing.T) {
@@ -515,6 +517,73 @@ func TestServiceAccountSync(t *testing.T) {
 	})
 }
 
+func TestServiceAccountSyncServerSideApply(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	var applied *corev1.ServiceAccount
+	var patchOpts client.PatchOptions
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, cl client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			assert.Equal(t, types.ApplyPatchType, patch.Type())
+			patchOpts = client.PatchOptions{}
+			patchOpts.ApplyOptions(opts)
+			applied = obj.(*corev1.ServiceAccount).DeepCopy()
+			return nil
+		},
+	}).Build()
+
+	h := serviceAccountHandler{
+		Target: &TestDeploymentTarget{
+			GetClientImpl: func() client.Client { return cl },
+			GetSpecImpl: func() api.LinkableSecretSpec {
+				return api.LinkableSecretSpec{
+					LinkedTo: []api.SecretLink{
+						{
+							ServiceAccount: api.ServiceAccountLink{
+								Managed: api.ManagedServiceAccountSpec{
+									Name: "sa",
+									Labels: map[string]string{
+										"a": "b",
+									},
+									Annotations: map[string]string{
+										"c": "d",
+									},
+								},
+							},
+						},
+					},
+				}
+			},
+			GetTargetNamespaceImpl: func() string {
+				return "default"
+			},
+		},
+		ObjectMarker: &TestObjectMarker{
+			MarkManagedImpl: func(ctx context.Context, _ client.ObjectKey, o client.Object) (bool, error) {
+				if o.GetLabels() == nil {
+					o.SetLabels(map[string]string{})
+				}
+				o.GetLabels()["gelinkt_managed"] = "yay"
+				return true, nil
+			},
+		},
+		ServerSideApply: true,
+	}
+
+	sas, _, err := h.Sync(context.TODO())
+	assert.NoError(t, err)
+	assert.Len(t, sas, 1)
+
+	assert.NotNil(t, applied)
+	assert.Equal(t, FieldManager, patchOpts.FieldManager)
+	assert.Equal(t, "sa", applied.Name)
+	assert.Equal(t, map[string]string{"a": "b", "gelinkt_managed": "yay"}, applied.Labels)
+	assert.Equal(t, map[string]string{"c": "d"}, applied.Annotations)
+	assert.Empty(t, applied.Secrets)
+	assert.Empty(t, applied.ImagePullSecrets)
+}
+
 func TestLinkSecretToServiceAccount(t *testing.T) {
 	scheme := runtime.NewScheme()
 	assert.NoError(t, corev1.AddToScheme(scheme))
@@ -594,6 +663,167 @@ func TestLinkSecretToServiceAccount(t *testing.T) {
 	})
 }
 
+func TestLinkSecretToServiceAccountServerSideApply(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "default",
+		},
+	}
+
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "sa",
+			Namespace:       "default",
+			ResourceVersion: "42",
+		},
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "other"}},
+	}
+
+	var applied *corev1.ServiceAccount
+	var patchOpts client.PatchOptions
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, cl client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			assert.Equal(t, types.ApplyPatchType, patch.Type())
+			patchOpts = client.PatchOptions{}
+			patchOpts.ApplyOptions(opts)
+			applied = obj.(*corev1.ServiceAccount).DeepCopy()
+			return nil
+		},
+	}).Build()
+
+	secretSpec := api.LinkableSecretSpec{
+		LinkedTo: []api.SecretLink{
+			{
+				ServiceAccount: api.ServiceAccountLink{
+					Reference: corev1.LocalObjectReference{
+						Name: "sa",
+					},
+				},
+			},
+		},
+	}
+
+	h := serviceAccountHandler{
+		Target: &TestDeploymentTarget{
+			GetClientImpl: func() client.Client { return cl },
+			GetTargetNamespaceImpl: func() string {
+				return "default"
+			},
+			GetSpecImpl: func() api.LinkableSecretSpec {
+				return secretSpec
+			},
+		},
+		ObjectMarker:    &TestObjectMarker{},
+		ServerSideApply: true,
+	}
+
+	t.Run("link as secret", func(t *testing.T) {
+		secretSpec.LinkedTo[0].ServiceAccount.As = ""
+		assert.NoError(t, h.LinkToSecret(context.TODO(), []*corev1.ServiceAccount{sa.DeepCopy()}, secret))
+
+		assert.Equal(t, linkFieldManager("secret"), patchOpts.FieldManager)
+		assert.Equal(t, []corev1.ObjectReference{{Name: "secret"}}, applied.Secrets)
+		assert.Empty(t, applied.ImagePullSecrets)
+		assert.Empty(t, applied.ResourceVersion)
+	})
+
+	t.Run("link as image pull secret", func(t *testing.T) {
+		secretSpec.LinkedTo[0].ServiceAccount.As = api.ServiceAccountLinkTypeImagePullSecret
+		assert.NoError(t, h.LinkToSecret(context.TODO(), []*corev1.ServiceAccount{sa.DeepCopy()}, secret))
+
+		assert.Equal(t, linkFieldManager("secret"), patchOpts.FieldManager)
+		// the image pull secrets are an atomic list, so the whole list is applied only if the service account didn't change
+		assert.Equal(t, []corev1.LocalObjectReference{{Name: "other"}, {Name: "secret"}}, applied.ImagePullSecrets)
+		assert.Equal(t, "42", applied.ResourceVersion)
+		assert.Empty(t, applied.Secrets)
+	})
+
+	t.Run("doesn't apply existing link", func(t *testing.T) {
+		applied = nil
+		linked := sa.DeepCopy()
+		linked.ImagePullSecrets = append(linked.ImagePullSecrets, corev1.LocalObjectReference{Name: "secret"})
+		assert.NoError(t, h.LinkToSecret(context.TODO(), []*corev1.ServiceAccount{linked}, secret))
+		assert.Nil(t, applied)
+	})
+}
+
+func TestReferencedServiceAccountServerSideApply(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sa",
+			Namespace: "default",
+			Labels:    map[string]string{"users": "label"},
+		},
+	}
+
+	var applied *corev1.ServiceAccount
+	var patchOpts client.PatchOptions
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sa).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, cl client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			assert.Equal(t, types.ApplyPatchType, patch.Type())
+			patchOpts = client.PatchOptions{}
+			patchOpts.ApplyOptions(opts)
+			applied = obj.(*corev1.ServiceAccount).DeepCopy()
+			return nil
+		},
+		Update: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			assert.Fail(t, "the referenced service account should be applied, not updated")
+			return nil
+		},
+	}).Build()
+
+	targetKey := client.ObjectKey{Name: "rs", Namespace: "default"}
+	h := serviceAccountHandler{
+		Target: &TestDeploymentTarget{
+			GetClientImpl: func() client.Client { return cl },
+			GetSpecImpl: func() api.LinkableSecretSpec {
+				return api.LinkableSecretSpec{
+					LinkedTo: []api.SecretLink{
+						{
+							ServiceAccount: api.ServiceAccountLink{
+								Reference: corev1.LocalObjectReference{Name: "sa"},
+							},
+						},
+					},
+				}
+			},
+			GetTargetNamespaceImpl: func() string {
+				return "default"
+			},
+			GetTargetObjectKeyImpl: func() client.ObjectKey {
+				return targetKey
+			},
+		},
+		ObjectMarker: &TestObjectMarker{
+			MarkReferencedImpl: func(ctx context.Context, _ client.ObjectKey, o client.Object) (bool, error) {
+				if o.GetAnnotations() == nil {
+					o.SetAnnotations(map[string]string{})
+				}
+				o.GetAnnotations()["referenced-by"] = "rs"
+				return true, nil
+			},
+		},
+		ServerSideApply: true,
+	}
+
+	sas, _, err := h.Sync(context.TODO())
+	assert.NoError(t, err)
+	assert.Len(t, sas, 1)
+
+	assert.NotNil(t, applied)
+	assert.Equal(t, targetFieldManager(targetKey), patchOpts.FieldManager)
+	assert.Equal(t, map[string]string{"referenced-by": "rs"}, applied.Annotations)
+	// only the marks are applied, the rest of the service account is not ours
+	assert.Empty(t, applied.Labels)
+}
+
 func TestUnlinkSecretFromServiceAccount(t *testing.T) {
 	scheme := runtime.NewScheme()
 	assert.NoError(t, corev1.AddToScheme(scheme))
@@ -27,6 +27,8 @@ type NamespaceTarget struct {
 	SecretSpec   *api.LinkableSecretSpec
 	TargetSpec   *api.RemoteSecretTarget
 	TargetStatus *api.TargetStatus
+	// DataFingerprint is put on the deployed secret in the DataFingerprintAnnotation if not empty.
+	DataFingerprint string
 }
 
 var _ bindings.SecretDeploymentTarget = (*NamespaceTarget)(nil)
@@ -84,6 +86,16 @@ func (t *NamespaceTarget) GetSpec() api.LinkableSecretSpec {
 		// }
 	}
 
+	if t.DataFingerprint != "" {
+		if ret == t.SecretSpec {
+			ret = t.SecretSpec.DeepCopy()
+		}
+		if ret.Annotations == nil {
+			ret.Annotations = map[string]string{}
+		}
+		ret.Annotations[api.DataFingerprintAnnotation] = t.DataFingerprint
+	}
+
 	return *ret
 }
 
@@ -71,6 +71,20 @@ func TestNamespaceTarget_GetSpec(t *testing.T) {
 			},
 		}, nt.GetSpec())
 	})
+
+	t.Run("with data fingerprint", func(t *testing.T) {
+		nt := getTestNamespaceTarget()
+		nt.DataFingerprint = "fingerprint"
+
+		assert.Equal(t, api.LinkableSecretSpec{
+			GenerateName: "kachny-",
+			Annotations: map[string]string{
+				api.DataFingerprintAnnotation: "fingerprint",
+			},
+		}, nt.GetSpec())
+		// the spec of the remote secret is not modified
+		assert.Empty(t, nt.SecretSpec.Annotations)
+	})
 }
 
 func TestNamespaceTarget_GetTargetNamespace(t *testing.T) {
@@ -18,12 +18,15 @@ import (
 	"context"
 	stdErrors "errors"
 	"fmt"
+	"reflect"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/redhat-appstudio/remote-secret/pkg/metrics"
 
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 
 	"github.com/google/go-cmp/cmp"
@@ -39,10 +42,16 @@ import (
 	"github.com/redhat-appstudio/remote-secret/controllers/namespacetarget"
 	"github.com/redhat-appstudio/remote-secret/controllers/remotesecrets"
 	"github.com/redhat-appstudio/remote-secret/controllers/remotesecretstorage"
+	"github.com/redhat-appstudio/remote-secret/pkg/commaseparated"
 	opconfig "github.com/redhat-appstudio/remote-secret/pkg/config"
+	"github.com/redhat-appstudio/remote-secret/pkg/externalsource"
+	"github.com/redhat-appstudio/remote-secret/pkg/fingerprint"
 	"github.com/redhat-appstudio/remote-secret/pkg/logs"
+	"github.com/redhat-appstudio/remote-secret/pkg/policy"
+	"github.com/redhat-appstudio/remote-secret/pkg/quota"
 	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -57,25 +66,46 @@ import (
 )
 
 var unexpectedObjectTypeError = stdErrors.New("unexpected object type")
+var errFollowedRemoteSecretFollows = stdErrors.New("the followed remote secret follows another remote secret itself")
+var errFollowedDataKeysMissing = stdErrors.New("not found in the data")
+var errExternalSourceNotConfigured = stdErrors.New("reading the data from an external source is not configured in the operator")
 
 const linkedObjectsFinalizerName = "appstudio.redhat.com/linked-objects"
 
+// followedRemoteSecretsIndex is the name of the field index of the remote secrets by the keys of the remote secrets they follow.
+const followedRemoteSecretsIndex = "dataFrom.followedRemoteSecrets"
+
 type RemoteSecretReconciler struct {
 	client.Client
 	TargetClientFactory bindings.ClientFactory
 	Scheme              *runtime.Scheme
 	Configuration       *opconfig.OperatorConfiguration
 	RemoteSecretStorage remotesecretstorage.RemoteSecretStorage
-	finalizers          finalizer.Finalizers
+	Recorder            record.EventRecorder
+	// PolicyChecker checks the targets against the remote secret policies before the deployment. If nil, the policies are not checked.
+	PolicyChecker *policy.Checker
+	// ExternalSource reads the data of the remote secrets that specify an external source. If nil, such remote secrets cannot
+	// obtain any data.
+	ExternalSource *externalsource.Source
+	// QuotaChecker checks the size of the data that the controller stores on its own against the quota. If nil, the quota is not checked.
+	QuotaChecker *quota.Checker
+	finalizers   finalizer.Finalizers
+	// fingerprinter computes the fingerprints of the values published in the status. It is nil if no fingerprint key is configured.
+	fingerprinter *fingerprint.Fingerprinter
+	// dataFingerprinter computes the fingerprints of the whole data that detect its changes. It uses the fingerprint key if configured
+	// and the internal key of the operator otherwise.
+	dataFingerprinter *fingerprint.Fingerprinter
 }
 
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=remotesecrets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=remotesecrets/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=remotesecrets/finalizers,verbs=update
-//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;watch;create;update;list;delete
-//+kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;watch;create;update;patch;list;delete
+//+kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=serviceaccounts/token,verbs=create
-//+kubebuilder:rbac:groups="",resources=events,verbs=create
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=appstudio.redhat.com,resources=remotesecretpolicies,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 
 var _ reconcile.Reconciler = (*RemoteSecretReconciler)(nil)
 
@@ -86,16 +116,42 @@ func (r *RemoteSecretReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if err := r.finalizers.Register(storageFinalizerName, &remoteSecretStorageFinalizer{storage: r.RemoteSecretStorage}); err != nil {
 		return fmt.Errorf("failed to register the remote secret storage finalizer: %w", err)
 	}
-	if err := r.finalizers.Register(linkedObjectsFinalizerName, &remoteSecretLinksFinalizer{localClient: r.Client, clientFactory: r.TargetClientFactory, storage: r.RemoteSecretStorage}); err != nil {
+	if err := r.finalizers.Register(linkedObjectsFinalizerName, &remoteSecretLinksFinalizer{recorder: r.Recorder, clientFactory: r.TargetClientFactory, storage: r.RemoteSecretStorage, deletionPolicy: r.Configuration.DeletionPolicy}); err != nil {
 		return fmt.Errorf("failed to register the remote secret links finalizer: %w", err)
 	}
+	if len(r.Configuration.FingerprintKey) > 0 {
+		fp, err := fingerprint.New(r.Configuration.FingerprintKey)
+		if err != nil {
+			return fmt.Errorf("failed to initialize the fingerprints of the secret data: %w", err)
+		}
+		r.fingerprinter = fp
+		r.dataFingerprinter = fp
+	} else {
+		key := r.Configuration.InternalFingerprintKey
+		if len(key) == 0 {
+			// the data is considered changed after every restart, but that is better than never detecting the changes
+			var err error
+			if key, err = fingerprint.RandomKey(); err != nil {
+				return fmt.Errorf("failed to initialize the fingerprints of the data: %w", err)
+			}
+		}
+		fp, err := fingerprint.New(key)
+		if err != nil {
+			return fmt.Errorf("failed to initialize the fingerprints of the data: %w", err)
+		}
+		r.dataFingerprinter = fp
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &api.RemoteSecret{}, followedRemoteSecretsIndex, indexFollowedRemoteSecrets); err != nil {
+		return fmt.Errorf("failed to index the remote secrets by the followed remote secrets: %w", err)
+	}
 
 	pred, err := predicate.LabelSelectorPredicate(uploadSecretSelector)
 	if err != nil {
 		return fmt.Errorf("failed to construct the predicate for matching secrets. This should not happen: %w", err)
 	}
 
-	err = ctrl.NewControllerManagedBy(mgr).
+	bld := ctrl.NewControllerManagedBy(mgr).
 		// for logging purposes, this Named + Watches replaces the For(&api.RemoteSecret) call.
 		Named("remotesecret").
 		Watches(&api.RemoteSecret{}, handler.Funcs{
@@ -136,7 +192,7 @@ func (r *RemoteSecretReconciler) SetupWithManager(mgr ctrl.Manager) error {
 					q.Add(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(e.Object)})
 				}
 			},
-		}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, dryRunAnnotationChangedPredicate, dataAPIUpdateAnnotationChangedPredicate))).
 		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
 			reqs := linksToReconcileRequests(ctx, mgr.GetScheme(), o)
 			if r.Configuration.ReconcileLogging && len(reqs) > 0 {
@@ -148,6 +204,10 @@ func (r *RemoteSecretReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&corev1.Secret{},
 			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
 				reqs := r.findRemoteSecretForUploadSecret(o)
+				// the followers of the remote secret need to pick up the uploaded data, too
+				for _, req := range reqs {
+					reqs = append(reqs, r.findFollowersOfRemoteSecret(ctx, req.NamespacedName)...)
+				}
 				if r.Configuration.ReconcileLogging && len(reqs) > 0 {
 					reconcileLogger(log.FromContext(ctx)).Info("enqueing reconcile", "action", "reactOnSource", "sourceKind", "secret", "source", client.ObjectKeyFromObject(o), "remoteSecrets", reqs, "reactReason", "dataUpload")
 				}
@@ -164,13 +224,90 @@ func (r *RemoteSecretReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			}
 			return reqs
 		})).
-		Complete(r)
+		// the followers of a remote secret react on its creation, deletion, spec changes and the status updates after the data changes
+		Watches(&api.RemoteSecret{}, handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+			reqs := r.findFollowersOfRemoteSecret(ctx, client.ObjectKeyFromObject(o))
+			if r.Configuration.ReconcileLogging && len(reqs) > 0 {
+				reconcileLogger(log.FromContext(ctx)).Info("enqueing reconcile", "action", "reactOnSource", "sourceKind", "remoteSecret", "source", client.ObjectKeyFromObject(o), "remoteSecrets", reqs, "reactReason", "follow")
+			}
+			return reqs
+		}), builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, followedDataChangedPredicate))).
+		Watches(&api.RemoteSecretPolicy{}, handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+			reqs := r.findRemoteSecretsForPolicy(ctx, o)
+			if r.Configuration.ReconcileLogging && len(reqs) > 0 {
+				reconcileLogger(log.FromContext(ctx)).Info("enqueing reconcile", "action", "reactOnSource", "sourceKind", "remoteSecretPolicy", "source", client.ObjectKeyFromObject(o), "remoteSecrets", reqs, "reactReason", "policy")
+			}
+			return reqs
+		}))
+
+	err = bld.Complete(r)
 	if err != nil {
 		return fmt.Errorf("failed to configure the reconciler: %w", err)
 	}
 	return nil
 }
 
+// dryRunAnnotationChangedPredicate lets through the updates of remote secrets that add, remove or change the dry run annotation.
+// This is needed because the annotations are not part of the spec and therefore changing them doesn't change the generation.
+var dryRunAnnotationChangedPredicate = annotationChangedPredicate(api.DryRunAnnotation)
+
+// dataAPIUpdateAnnotationChangedPredicate lets through the updates of remote secrets whose data was changed by the data API.
+// The data API writes the data directly to the storage, so the annotation is the only change in the cluster we can react on.
+var dataAPIUpdateAnnotationChangedPredicate = annotationChangedPredicate(api.DataAPIUpdateAnnotation)
+
+// followedDataChangedPredicate lets through the updates of remote secrets that might have changed the data seen by their followers.
+// The data of the remote secret is not in the cluster, so we can only react on the status that the controller updates after
+// the data changes and on the annotations through which the data changes are announced. If the storage doesn't version the data
+// and no fingerprint key is configured, a change of a value to another one of the same length is only noticed by the followers
+// on their next reconciliation.
+var followedDataChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldRs, okOld := e.ObjectOld.(*api.RemoteSecret)
+		newRs, okNew := e.ObjectNew.(*api.RemoteSecret)
+		if !okOld || !okNew {
+			return false
+		}
+		return !equality.Semantic.DeepEqual(oldRs.Status.SecretStatus, newRs.Status.SecretStatus) ||
+			!equality.Semantic.DeepEqual(oldRs.Status.LastDataUpdate, newRs.Status.LastDataUpdate) ||
+			dataObtained(oldRs) != dataObtained(newRs) ||
+			oldRs.Annotations[api.DataAPIUpdateAnnotation] != newRs.Annotations[api.DataAPIUpdateAnnotation]
+	},
+}
+
+// dataObtained returns the status of the DataObtained condition of the remote secret.
+func dataObtained(rs *api.RemoteSecret) metav1.ConditionStatus {
+	if cond := meta.FindStatusCondition(rs.Status.Conditions, string(api.RemoteSecretConditionTypeDataObtained)); cond != nil {
+		return cond.Status
+	}
+	return metav1.ConditionUnknown
+}
+
+// indexFollowedRemoteSecrets returns the keys of the remote secrets that the remote secret follows as the values of the
+// followedRemoteSecretsIndex.
+func indexFollowedRemoteSecrets(o client.Object) []string {
+	rs, ok := o.(*api.RemoteSecret)
+	if !ok {
+		return nil
+	}
+	followed := rs.FollowedRemoteSecrets()
+	keys := make([]string, 0, len(followed))
+	for _, key := range followed {
+		keys = append(keys, key.String())
+	}
+	return keys
+}
+
+func annotationChangedPredicate(annotation string) predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+			return e.ObjectOld.GetAnnotations()[annotation] != e.ObjectNew.GetAnnotations()[annotation]
+		},
+	}
+}
+
 func (r *RemoteSecretReconciler) findRemoteSecretForUploadSecret(secret client.Object) []reconcile.Request {
 	remoteSecretName := secret.GetAnnotations()[api.RemoteSecretNameAnnotation]
 	if remoteSecretName == "" {
@@ -234,6 +371,42 @@ func (r *RemoteSecretReconciler) findRemoteSecretsInNamespaceForAuthSA(ctx conte
 	return ret
 }
 
+// findRemoteSecretsForPolicy returns the requests for all the remote secrets in the cluster so that the targets that became allowed or
+// disallowed by the change of the policy are (re)deployed or reported. The policies are expected to change rarely.
+func (r *RemoteSecretReconciler) findRemoteSecretsForPolicy(ctx context.Context, _ client.Object) []reconcile.Request {
+	if r.PolicyChecker == nil {
+		return nil
+	}
+
+	list := api.RemoteSecretList{}
+	if err := r.Client.List(ctx, &list); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list the remote secrets while processing a change in a remote secret policy")
+		return nil
+	}
+
+	ret := make([]reconcile.Request, len(list.Items))
+	for i := range list.Items {
+		ret[i] = reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&list.Items[i])}
+	}
+	return ret
+}
+
+// findFollowersOfRemoteSecret returns the requests for the remote secrets that follow the data of the remote secret with the provided key.
+// The followers can be in any namespace.
+func (r *RemoteSecretReconciler) findFollowersOfRemoteSecret(ctx context.Context, key client.ObjectKey) []reconcile.Request {
+	list := api.RemoteSecretList{}
+	if err := r.Client.List(ctx, &list, client.MatchingFields{followedRemoteSecretsIndex: key.String()}); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list the remote secrets while looking for the followers of a remote secret", "remoteSecret", key)
+		return nil
+	}
+
+	ret := make([]reconcile.Request, 0, len(list.Items))
+	for i := range list.Items {
+		ret = append(ret, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&list.Items[i])})
+	}
+	return ret
+}
+
 // Reconcile implements reconcile.Reconciler
 func (r *RemoteSecretReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
 	lg := log.FromContext(ctx)
@@ -288,6 +461,21 @@ func (r *RemoteSecretReconciler) Reconcile(ctx context.Context, req reconcile.Re
 		return ctrl.Result{}, nil
 	}
 
+	if err = r.movePendingDataUpdateToStatus(ctx, remoteSecret); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	dryRun := remoteSecret.Annotations[api.DryRunAnnotation] == "true"
+	if !dryRun {
+		// the plan is persisted together with the result of the first stage below
+		remoteSecret.Status.DryRunPlan = nil
+	}
+
+	// the suspension condition is also persisted together with the result of the first stage
+	if cond, changed := suspensionCondition(remoteSecret); changed {
+		setRemoteSecretCondition(ctx, remoteSecret, cond)
+	}
+
 	// the reconciliation happens in stages, results of which are described in the status conditions.
 	var dataResult stageResult[*map[string][]byte]
 	dataResult, err = handleStage(ctx, r.Client, remoteSecret, r.obtainData(ctx, remoteSecret))
@@ -295,13 +483,85 @@ func (r *RemoteSecretReconciler) Reconcile(ctx context.Context, req reconcile.Re
 		return dataResult.Cancellation.Result, err
 	}
 
+	if dryRun {
+		err = r.planDeployment(ctx, remoteSecret, dataResult.ReturnValue)
+		return ctrl.Result{}, err
+	}
+
+	if remoteSecret.Spec.Suspend {
+		// we will get reconciled again once the remote secret is resumed, because that changes its generation. The deployment
+		// then uses the data that is in the storage at that time.
+		lg.V(logs.DebugLevel).Info("RemoteSecret is suspended. skipping the deployment")
+		return ctrl.Result{}, nil
+	}
+
+	// the data from the external source needs to be read again periodically, because we don't get notified about its changes
+	var refreshResult ctrl.Result
+	if remoteSecret.Spec.ExternalSource != nil {
+		refreshResult.RequeueAfter = externalsource.RefreshInterval(remoteSecret)
+	}
+
+	if meta.IsStatusConditionFalse(remoteSecret.Status.Conditions, string(api.RemoteSecretConditionTypeDataValid)) {
+		// we will get reconciled again once the data or the spec changes. The targets keep the data they already have.
+		lg.V(logs.DebugLevel).Info("the data of the RemoteSecret is not valid. skipping the deployment")
+		return refreshResult, nil
+	}
+
+	// the replication never cancels the reconciliation but may ask for it to be repeated later to check on the replicas.
+	var replicationResult stageResult[any]
+	if len(remoteSecret.Spec.ReplicationTargets) > 0 || meta.FindStatusCondition(remoteSecret.Status.Conditions, string(api.RemoteSecretConditionTypeReplicated)) != nil {
+		replicationResult, err = handleStage(ctx, r.Client, remoteSecret, r.replicate(ctx, remoteSecret, dataResult.ReturnValue))
+		if err != nil {
+			return replicationResult.Cancellation.Result, err
+		}
+	}
+
 	var deployResult stageResult[any]
 	deployResult, err = handleStage(ctx, r.Client, remoteSecret, r.deploy(ctx, remoteSecret, dataResult.ReturnValue))
 	if err != nil || deployResult.Cancellation.Cancel {
-		return deployResult.Cancellation.Result, err
+		return earlierResult(deployResult.Cancellation.Result, refreshResult), err
+	}
+
+	return earlierResult(replicationResult.Cancellation.Result, refreshResult), nil
+}
+
+// earlierResult combines the two results so that the reconciliation is repeated at the earlier of the requested times.
+func earlierResult(a, b ctrl.Result) ctrl.Result {
+	switch {
+	case a.Requeue && a.RequeueAfter == 0:
+		return a
+	case b.Requeue && b.RequeueAfter == 0:
+		return b
+	case a.RequeueAfter == 0:
+		return b
+	case b.RequeueAfter == 0 || a.RequeueAfter <= b.RequeueAfter:
+		return a
+	default:
+		return b
 	}
+}
 
-	return ctrl.Result{}, nil
+// suspensionCondition returns the Suspended condition reflecting the spec of the remote secret and whether it needs to be set
+// in the status. The condition is only ever added to the status once the remote secret is first suspended.
+func suspensionCondition(remoteSecret *api.RemoteSecret) (metav1.Condition, bool) {
+	if remoteSecret.Spec.Suspend {
+		return metav1.Condition{
+			Type:    string(api.RemoteSecretConditionTypeSuspended),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(api.RemoteSecretReasonSuspended),
+			Message: "The reconciliation is suspended. The targets are not updated and the upload secrets are not processed.",
+		}, true
+	}
+
+	if meta.IsStatusConditionTrue(remoteSecret.Status.Conditions, string(api.RemoteSecretConditionTypeSuspended)) {
+		return metav1.Condition{
+			Type:   string(api.RemoteSecretConditionTypeSuspended),
+			Status: metav1.ConditionFalse,
+			Reason: string(api.RemoteSecretReasonResumed),
+		}, true
+	}
+
+	return metav1.Condition{}, false
 }
 
 // stageResult describes the result of reconciliation stage.
@@ -314,6 +574,9 @@ type stageResult[R any] struct {
 	ReturnValue R
 	// Cancellation describes whether and how to cancel the current reconciliation early, right after the stage.
 	Cancellation cancellation
+	// Events emit the events about the changes done in the stage. They are only emitted once the status with the result of the stage
+	// is persisted, so that the events don't announce the changes that the status doesn't reflect.
+	Events []func()
 }
 
 type cancellation struct {
@@ -325,14 +588,93 @@ type cancellation struct {
 	ReturnError error
 }
 
+// movePendingDataUpdateToStatus moves the description of the data update that the webhook stored in the LastDataUpdateAnnotation
+// to the status and deletes the secrets that the webhook copied the data from, if requested. The webhook leaves this to the controller,
+// because only now the remote secret with the updated data is persisted. For the same reason, the events about the update are only
+// emitted here. The status is updated first so that the update is not lost if removing the annotations fails.
+func (r *RemoteSecretReconciler) movePendingDataUpdateToStatus(ctx context.Context, remoteSecret *api.RemoteSecret) error {
+	_, hasDeletions := remoteSecret.Annotations[api.DeleteImportedSecretsAnnotation]
+	update, err := remotesecrets.PendingDataUpdate(remoteSecret)
+	if err != nil {
+		// there's no point in retrying, the annotation is just removed below
+		log.FromContext(ctx).Error(err, "failed to read the data update recorded by the webhook")
+	} else if update == nil && !hasDeletions {
+		return nil
+	}
+
+	if update != nil {
+		remoteSecret.Status.LastDataUpdate = update
+		if err = r.Client.Status().Update(ctx, remoteSecret); err != nil {
+			return fmt.Errorf("failed to record the data update in the status: %w", err)
+		}
+	}
+
+	r.deleteImportedSecrets(ctx, remoteSecret)
+
+	delete(remoteSecret.Annotations, api.LastDataUpdateAnnotation)
+	delete(remoteSecret.Annotations, api.DeleteImportedSecretsAnnotation)
+	if err = r.Client.Update(ctx, remoteSecret); err != nil {
+		return fmt.Errorf("failed to remove the annotations of the data update: %w", err)
+	}
+
+	if update != nil {
+		recordDataUpdateEvent(r.Recorder, remoteSecret, update)
+	}
+	return nil
+}
+
+// deleteImportedSecrets deletes the secrets listed in the DeleteImportedSecretsAnnotation. Their data is already stored at this point,
+// so the failure to delete them is only reported.
+func (r *RemoteSecretReconciler) deleteImportedSecrets(ctx context.Context, remoteSecret *api.RemoteSecret) {
+	for _, key := range commaseparated.Value(remoteSecret.Annotations[api.DeleteImportedSecretsAnnotation]).Values() {
+		namespace, name, found := strings.Cut(key, "/")
+		if !found {
+			continue
+		}
+		auditLog := logs.AuditLog(ctx).WithValues("source-secret", key, "target-remote-secret", client.ObjectKeyFromObject(remoteSecret))
+
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		if err := r.Client.Delete(ctx, secret); client.IgnoreNotFound(err) != nil {
+			auditLog.Error(err, "failed to delete the secret after importing its data")
+			r.Recorder.Eventf(remoteSecret, corev1.EventTypeWarning, api.RemoteSecretEventReasonSourceDeletionFailed, "failed to delete the secret %s after importing its data: %s", key, err.Error())
+			continue
+		}
+		auditLog.Info("deleted the secret after importing its data", "action", "DELETE")
+	}
+}
+
+// recordDataUpdateEvent emits the event about the successful data update done by the webhook.
+func recordDataUpdateEvent(recorder record.EventRecorder, remoteSecret *api.RemoteSecret, update *api.DataUpdateStatus) {
+	if update.Result != api.DataUpdateResultSucceeded {
+		return
+	}
+	switch update.Source {
+	case api.DataUpdateSourceWebhook:
+		if _, partial := remoteSecret.Annotations[api.RemoteSecretPartialUpdateAnnotation]; partial {
+			recorder.Event(remoteSecret, corev1.EventTypeNormal, api.RemoteSecretEventReasonDataUploaded, "the data was partially updated using the remote secret data fields")
+		} else {
+			recorder.Event(remoteSecret, corev1.EventTypeNormal, api.RemoteSecretEventReasonDataUploaded, "the data was uploaded using the remote secret data fields")
+		}
+	case api.DataUpdateSourceDataFrom:
+		recorder.Eventf(remoteSecret, corev1.EventTypeNormal, api.RemoteSecretEventReasonDataCopied, "the data was copied from %s", update.SourceName)
+	}
+}
+
 // handleStage tries to update the status with the condition from the provided result and returns error if the update failed or the stage itself failed before.
 func handleStage[T any](ctx context.Context, cl client.Client, remoteSecret *api.RemoteSecret, result stageResult[T]) (stageResult[T], error) {
 	setRemoteSecretCondition(ctx, remoteSecret, result.Condition)
+	// the Ready condition only aggregates the other conditions, so there's no metric for it
+	meta.SetStatusCondition(&remoteSecret.Status.Conditions, readyCondition(remoteSecret))
+	remoteSecret.Status.ObservedGeneration = remoteSecret.Generation
 
 	if serr := cl.Status().Update(ctx, remoteSecret); serr != nil {
 		return result, fmt.Errorf("failed to persist the stage result condition in the status after the stage %s: %w", result.Name, serr)
 	}
 
+	for _, emit := range result.Events {
+		emit()
+	}
+
 	if result.Cancellation.Cancel || result.Cancellation.ReturnError != nil {
 		return result, result.Cancellation.ReturnError
 	} else {
@@ -340,6 +682,55 @@ func handleStage[T any](ctx context.Context, cl client.Client, remoteSecret *api
 	}
 }
 
+// readyCondition aggregates the other conditions of the remote secret into the Ready condition. The remote secret is ready once
+// the data is obtained and deployed to all targets and replicas.
+func readyCondition(remoteSecret *api.RemoteSecret) metav1.Condition {
+	notReady := func(cond *metav1.Condition) metav1.Condition {
+		return metav1.Condition{
+			Type:    string(api.RemoteSecretConditionTypeReady),
+			Status:  metav1.ConditionFalse,
+			Reason:  cond.Reason,
+			Message: cond.Message,
+		}
+	}
+
+	conditions := remoteSecret.Status.Conditions
+
+	if cond := meta.FindStatusCondition(conditions, string(api.RemoteSecretConditionTypeSuspended)); cond != nil && cond.Status == metav1.ConditionTrue {
+		return notReady(cond)
+	}
+
+	if cond := meta.FindStatusCondition(conditions, string(api.RemoteSecretConditionTypeDataObtained)); cond == nil || cond.Status != metav1.ConditionTrue {
+		if cond == nil {
+			cond = &metav1.Condition{Reason: string(api.RemoteSecretReasonAwaitingTokenData)}
+		}
+		return notReady(cond)
+	}
+
+	if cond := meta.FindStatusCondition(conditions, string(api.RemoteSecretConditionTypeDataValid)); cond != nil && cond.Status == metav1.ConditionFalse {
+		return notReady(cond)
+	}
+
+	if cond := meta.FindStatusCondition(conditions, string(api.RemoteSecretConditionTypeDeployed)); cond == nil {
+		return notReady(&metav1.Condition{
+			Reason:  string(api.RemoteSecretReasonDeploymentPending),
+			Message: "the data has not been deployed to the targets yet",
+		})
+	} else if cond.Status != metav1.ConditionTrue && cond.Reason != string(api.RemoteSecretReasonNoTargets) {
+		return notReady(cond)
+	}
+
+	if cond := meta.FindStatusCondition(conditions, string(api.RemoteSecretConditionTypeReplicated)); cond != nil && cond.Status != metav1.ConditionTrue {
+		return notReady(cond)
+	}
+
+	return metav1.Condition{
+		Type:   string(api.RemoteSecretConditionTypeReady),
+		Status: metav1.ConditionTrue,
+		Reason: string(api.RemoteSecretReasonReady),
+	}
+}
+
 // obtainData tries to find the data of the remote secret in the backing storage.
 func (r *RemoteSecretReconciler) obtainData(ctx context.Context, remoteSecret *api.RemoteSecret) stageResult[*remotesecretstorage.SecretData] {
 	lg := log.FromContext(ctx)
@@ -347,20 +738,69 @@ func (r *RemoteSecretReconciler) obtainData(ctx context.Context, remoteSecret *a
 		Name: "data-fetch",
 	}
 
-	secretData, err := r.RemoteSecretStorage.Get(ctx, remoteSecret)
+	var secretData *remotesecretstorage.SecretData
+	var version string
+	var err error
+	follows := len(remoteSecret.FollowedRemoteSecrets()) > 0
+	// importing the data is a change like any other, so it must not happen while the remote secret is suspended or in dry run
+	importAllowed := !remoteSecret.Spec.Suspend && remoteSecret.Annotations[api.DryRunAnnotation] != "true"
+	switch {
+	case follows:
+		secretData, err = r.getFollowedData(ctx, remoteSecret)
+	case remoteSecret.Spec.ExternalSource != nil && importAllowed:
+		secretData, err = r.refreshExternalData(ctx, remoteSecret)
+	default:
+		secretData, version, err = r.RemoteSecretStorage.GetVersioned(ctx, remoteSecret)
+		if stdErrors.Is(err, secretstorage.NotFoundError) && remoteSecret.Spec.Adoption.ImportData && importAllowed {
+			secretData, err = r.importDataFromTargets(ctx, remoteSecret)
+		}
+	}
 	if err != nil {
-		if stdErrors.Is(err, secretstorage.NotFoundError) {
+		if stdErrors.Is(err, externalsource.NotFoundError) {
 			result.Condition = metav1.Condition{
 				Type:    string(api.RemoteSecretConditionTypeDataObtained),
 				Status:  metav1.ConditionFalse,
 				Reason:  string(api.RemoteSecretReasonAwaitingTokenData),
-				Message: "The data of the remote secret not found in storage. Please provide it.",
+				Message: fmt.Sprintf("The data of the remote secret not found in the external source: %s", err.Error()),
+			}
+			// we don't get notified about the changes in the external source, so let's look again after the refresh interval
+			result.Cancellation.Result = ctrl.Result{RequeueAfter: externalsource.RefreshInterval(remoteSecret)}
+		} else if stdErrors.Is(err, secretstorage.NotFoundError) {
+			message := "The data of the remote secret not found in storage. Please provide it."
+			if follows {
+				message = fmt.Sprintf("The data of the followed remote secrets not found: %s", err.Error())
+			}
+			result.Condition = metav1.Condition{
+				Type:    string(api.RemoteSecretConditionTypeDataObtained),
+				Status:  metav1.ConditionFalse,
+				Reason:  string(api.RemoteSecretReasonAwaitingTokenData),
+				Message: message,
 			}
 			if meta.IsStatusConditionTrue(remoteSecret.Status.Conditions, string(api.RemoteSecretConditionTypeDataObtained)) {
 				lg.Error(err, "Data lost from storage for the remote secret with DataObtained=true condition.")
 			}
 			// we don't want to retry the reconciliation in this case, because the data is simply not present in the storage.
 			// we will get notified once it appears there.
+		} else if stdErrors.Is(err, errFollowedRemoteSecretFollows) || stdErrors.Is(err, errFollowedDataKeysMissing) || stdErrors.Is(err, errExternalSourceNotConfigured) {
+			result.Condition = metav1.Condition{
+				Type:    string(api.RemoteSecretConditionTypeDataObtained),
+				Status:  metav1.ConditionFalse,
+				Reason:  string(api.RemoteSecretReasonError),
+				Message: err.Error(),
+			}
+			// retrying doesn't help here, we get notified once the sources change
+		} else if stdErrors.Is(err, quota.QuotaExceededError) {
+			result.Condition = metav1.Condition{
+				Type:    string(api.RemoteSecretConditionTypeDataObtained),
+				Status:  metav1.ConditionFalse,
+				Reason:  string(api.RemoteSecretReasonError),
+				Message: fmt.Sprintf("The data of the remote secret not stored: %s", err.Error()),
+			}
+			// the data in the external source might shrink or the other remote secrets might free the quota, so let's look again
+			// after the refresh interval
+			if remoteSecret.Spec.ExternalSource != nil {
+				result.Cancellation.Result = ctrl.Result{RequeueAfter: externalsource.RefreshInterval(remoteSecret)}
+			}
 		} else {
 			result.Condition = metav1.Condition{
 				Type:    string(api.RemoteSecretConditionTypeDataObtained),
@@ -374,6 +814,8 @@ func (r *RemoteSecretReconciler) obtainData(ctx context.Context, remoteSecret *a
 		// regardless of whether we want to repeat the reconciliation, we don't want to continue with the current one, because the remote secret
 		// doesn't have any data to put into the target secrets.
 		result.Cancellation.Cancel = true
+		// there's no data to be valid or invalid
+		meta.RemoveStatusCondition(&remoteSecret.Status.Conditions, string(api.RemoteSecretConditionTypeDataValid))
 		return result
 	}
 
@@ -393,12 +835,176 @@ func (r *RemoteSecretReconciler) obtainData(ctx context.Context, remoteSecret *a
 	// we need to sort the keys alphabetically so that we don't get spurious changes caused by the random
 	// iteration order of the secretData map.
 	sort.Strings(remoteSecret.Status.SecretStatus.Keys)
+	remoteSecret.Status.SecretStatus.Size = quota.DataSize(*secretData)
+	remoteSecret.Status.SecretStatus.Version = version
+	if r.fingerprinter != nil {
+		r.fingerprinter.UpdateSecretStatus(&remoteSecret.Status.SecretStatus, string(remoteSecret.UID), *secretData, metav1.Now())
+	} else {
+		// no fingerprints are published without the key, not even the ones computed using the key configured previously
+		remoteSecret.Status.SecretStatus.KeyDetails = nil
+		remoteSecret.Status.SecretStatus.FingerprintKeyID = ""
+	}
+
+	// the validity of the data is persisted together with the result of this stage. There's no metric for it, because
+	// it only describes the data obtained in this stage.
+	meta.SetStatusCondition(&remoteSecret.Status.Conditions, dataValidCondition(remoteSecret, *secretData, time.Now()))
 
 	result.ReturnValue = secretData
 
 	return result
 }
 
+// getFollowedData composes the data of the remote secret from the data of the remote secrets it follows. Only direct following
+// is supported, so an error is returned if any of the sources follows yet other remote secrets.
+func (r *RemoteSecretReconciler) getFollowedData(ctx context.Context, remoteSecret *api.RemoteSecret) (*remotesecretstorage.SecretData, error) {
+	data := remotesecretstorage.SecretData{}
+	for _, ds := range remoteSecret.DataSources() {
+		if ds.IsSecret() {
+			// the secrets cannot be followed, which is enforced by the webhook
+			continue
+		}
+		sourceKey := ds.Key()
+		source := &api.RemoteSecret{}
+		if err := r.Client.Get(ctx, sourceKey, source); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, fmt.Errorf("the followed remote secret %s doesn't exist: %w", sourceKey, secretstorage.NotFoundError)
+			}
+			return nil, fmt.Errorf("failed to get the followed remote secret %s: %w", sourceKey, err)
+		}
+
+		if len(source.FollowedRemoteSecrets()) > 0 {
+			return nil, fmt.Errorf("cannot use the data of %s: %w", sourceKey, errFollowedRemoteSecretFollows)
+		}
+
+		sourceData, err := r.RemoteSecretStorage.Get(ctx, source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the data of the followed remote secret %s: %w", sourceKey, err)
+		}
+
+		if missing := ds.SelectData(data, *sourceData); len(missing) > 0 {
+			return nil, fmt.Errorf("the keys %s of the followed remote secret %s: %w", strings.Join(missing, ", "), sourceKey, errFollowedDataKeysMissing)
+		}
+	}
+	return &data, nil
+}
+
+// refreshExternalData reads the data of the remote secret from the external source and updates the storage if the data
+// differs from what is stored there. The data exceeding the quota is not stored and the quota.QuotaExceededError is returned.
+func (r *RemoteSecretReconciler) refreshExternalData(ctx context.Context, remoteSecret *api.RemoteSecret) (*remotesecretstorage.SecretData, error) {
+	if r.ExternalSource == nil {
+		return nil, errExternalSourceNotConfigured
+	}
+
+	data, err := r.ExternalSource.Fetch(ctx, remoteSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the data from the external source: %w", err)
+	}
+	secretData := remotesecretstorage.SecretData(data)
+
+	stored, err := r.RemoteSecretStorage.Get(ctx, remoteSecret)
+	if err != nil && !stdErrors.Is(err, secretstorage.NotFoundError) {
+		return nil, fmt.Errorf("failed to get the stored data of the remote secret: %w", err)
+	}
+	if stored != nil && reflect.DeepEqual(*stored, secretData) {
+		return stored, nil
+	}
+
+	if r.QuotaChecker != nil {
+		if err := r.QuotaChecker.CheckDataSize(ctx, remoteSecret, secretData); err != nil {
+			return nil, fmt.Errorf("failed to check the size of the data from the external source: %w", err)
+		}
+	}
+
+	auditLog := logs.AuditLog(ctx).WithValues("remoteSecret", client.ObjectKeyFromObject(remoteSecret))
+	auditLog.Info("updating the data from the external source", "action", "UPDATE")
+	if err := r.RemoteSecretStorage.Store(ctx, remoteSecret, &secretData); err != nil {
+		auditLog.Error(err, "failed to store the data from the external source")
+		return nil, fmt.Errorf("failed to store the data from the external source: %w", err)
+	}
+	auditLog.Info("data from the external source updated")
+
+	return &secretData, nil
+}
+
+// dataValidCondition checks the data of the remote secret against its spec and describes the result in the DataValid condition.
+// The data might not be valid even though it is checked during the upload, because it might have been imported from the targets
+// or the spec might have changed since the data was uploaded.
+func dataValidCondition(remoteSecret *api.RemoteSecret, data map[string][]byte, now time.Time) metav1.Condition {
+	if err := remoteSecret.ValidateSecretData(data); err != nil {
+		return metav1.Condition{
+			Type:    string(api.RemoteSecretConditionTypeDataValid),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(api.RemoteSecretReasonDataInvalid),
+			Message: err.Error(),
+		}
+	}
+
+	if warnings := remoteSecret.SecretDataWarnings(data, now); len(warnings) > 0 {
+		return metav1.Condition{
+			Type:    string(api.RemoteSecretConditionTypeDataValid),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(api.RemoteSecretReasonDataWarnings),
+			Message: strings.Join(warnings, "; "),
+		}
+	}
+
+	return metav1.Condition{
+		Type:   string(api.RemoteSecretConditionTypeDataValid),
+		Status: metav1.ConditionTrue,
+		Reason: string(api.RemoteSecretReasonDataValid),
+	}
+}
+
+// importDataFromTargets looks for the first secret in the targets that can be adopted and stores its data in the storage
+// as the data of the remote secret. The secrets in the local cluster are only imported if the webhook recorded that the user
+// can read them. The secretstorage.NotFoundError is returned if there is no such secret. The data exceeding the quota is not
+// imported and the quota.QuotaExceededError is returned.
+func (r *RemoteSecretReconciler) importDataFromTargets(ctx context.Context, remoteSecret *api.RemoteSecret) (*remotesecretstorage.SecretData, error) {
+	lg := log.FromContext(ctx)
+	authorized := commaseparated.Value(remoteSecret.Annotations[api.ImportAuthorizedSecretsAnnotation])
+
+	for i := range remoteSecret.Spec.Targets {
+		spec := &remoteSecret.Spec.Targets[i]
+		depHandler, err := newDependentsHandler(ctx, r.TargetClientFactory, r.RemoteSecretStorage, remoteSecret, spec, &api.TargetStatus{}, "")
+		if err != nil {
+			// the deployment to this target will fail with the same error, so it is enough to just try the next target here
+			lg.Error(err, "failed to construct the dependents handler to look for the secret to import the data from", "target", spec)
+			continue
+		}
+
+		secret, err := depHandler.FindAdoptable(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look for the secret to import the data from: %w", err)
+		}
+		if secret == nil || len(secret.Data) == 0 {
+			continue
+		}
+		if spec.ApiUrl == "" && !authorized.Contains(client.ObjectKeyFromObject(secret).String()) {
+			lg.Info("not importing the data of the secret that the user was not authorized to read", "secret", client.ObjectKeyFromObject(secret))
+			continue
+		}
+
+		data := secret.Data
+		if r.QuotaChecker != nil {
+			if err := r.QuotaChecker.CheckDataSize(ctx, remoteSecret, data); err != nil {
+				return nil, fmt.Errorf("failed to check the size of the data imported from the secret %s: %w", client.ObjectKeyFromObject(secret), err)
+			}
+		}
+
+		auditLog := logs.AuditLog(ctx).WithValues("remoteSecret", client.ObjectKeyFromObject(remoteSecret), "secret", client.ObjectKeyFromObject(secret), "apiUrl", spec.ApiUrl)
+		auditLog.Info("importing the data of the adopted secret", "action", "UPDATE")
+		if err := r.RemoteSecretStorage.Store(ctx, remoteSecret, &data); err != nil {
+			auditLog.Error(err, "failed to import the data of the adopted secret")
+			return nil, fmt.Errorf("failed to store the data imported from the secret %s: %w", client.ObjectKeyFromObject(secret), err)
+		}
+		auditLog.Info("data of the adopted secret imported")
+
+		return &data, nil
+	}
+
+	return nil, secretstorage.NotFoundError
+}
+
 // deploy tries to deploy the secret to all the specified targets. It accumulates all errors, rather than stopping on the first one, so that we deploy
 // to as many targets as possible.
 func (r *RemoteSecretReconciler) deploy(ctx context.Context, remoteSecret *api.RemoteSecret, data *remotesecretstorage.SecretData) stageResult[any] {
@@ -407,7 +1013,7 @@ func (r *RemoteSecretReconciler) deploy(ctx context.Context, remoteSecret *api.R
 	}
 
 	aerr := &rerror.AggregatedError{}
-	r.processTargets(ctx, remoteSecret, data, aerr)
+	r.processTargets(ctx, remoteSecret, data, aerr, &result.Events)
 
 	var deploymentStatus metav1.ConditionStatus
 	var deploymentReason api.RemoteSecretReason
@@ -472,9 +1078,90 @@ func (r *RemoteSecretReconciler) deploy(ctx context.Context, remoteSecret *api.R
 	return result
 }
 
+// planDeployment computes what deploy would do in the targets and stores the result in the dry run plan in the status of the
+// remote secret. Nothing is changed in the targets.
+func (r *RemoteSecretReconciler) planDeployment(ctx context.Context, remoteSecret *api.RemoteSecret, data *remotesecretstorage.SecretData) error {
+	namespaceClassification := remotesecrets.ClassifyTargetNamespaces(remoteSecret)
+	log.FromContext(ctx).V(logs.DebugLevel).Info("namespace classification for dry run", "classification", namespaceClassification)
+
+	plan := &api.DryRunPlan{}
+
+	specIdxs := make([]remotesecrets.SpecTargetIndex, 0, len(namespaceClassification.Sync))
+	for specIdx := range namespaceClassification.Sync {
+		specIdxs = append(specIdxs, specIdx)
+	}
+	sort.Slice(specIdxs, func(i, j int) bool { return specIdxs[i] < specIdxs[j] })
+
+	for _, specIdx := range specIdxs {
+		statusIdx := namespaceClassification.Sync[specIdx]
+		spec := &remoteSecret.Spec.Targets[specIdx]
+		// we work on a copy of the status so that nothing from the plan leaks to the target statuses
+		status := &api.TargetStatus{}
+		if statusIdx != -1 {
+			status = remoteSecret.Status.Targets[statusIdx].DeepCopy()
+		}
+
+		targetPlan := api.TargetPlan{Namespace: spec.Namespace, ApiUrl: spec.ApiUrl}
+		var depPlan *bindings.DependentsPlan
+		depHandler, err := newDependentsHandler(ctx, r.TargetClientFactory, r.RemoteSecretStorage, remoteSecret, spec, status, r.dataFingerprint(remoteSecret, data))
+		if err == nil {
+			depPlan, err = depHandler.Plan(ctx, remoteSecret)
+		}
+		fillTargetPlan(&targetPlan, depPlan, err)
+		plan.Targets = append(plan.Targets, targetPlan)
+	}
+
+	removeIdxs := append([]remotesecrets.StatusTargetIndex{}, namespaceClassification.Remove...)
+	sort.Slice(removeIdxs, func(i, j int) bool { return removeIdxs[i] < removeIdxs[j] })
+	for _, statusIdx := range removeIdxs {
+		status := remoteSecret.Status.Targets[statusIdx].DeepCopy()
+
+		targetPlan := api.TargetPlan{Namespace: status.Namespace, ApiUrl: status.ApiUrl}
+		var depPlan *bindings.DependentsPlan
+		depHandler, err := newDependentsHandler(ctx, r.TargetClientFactory, r.RemoteSecretStorage, remoteSecret, nil, status, "")
+		if err == nil {
+			depPlan, err = depHandler.PlanCleanup(ctx)
+		}
+		fillTargetPlan(&targetPlan, depPlan, err)
+		plan.Targets = append(plan.Targets, targetPlan)
+	}
+
+	for originalIdx, duplicates := range namespaceClassification.DuplicateTargetSpecs {
+		for specIdx := range duplicates {
+			plan.Targets = append(plan.Targets, api.TargetPlan{
+				Namespace: remoteSecret.Spec.Targets[specIdx].Namespace,
+				ApiUrl:    remoteSecret.Spec.Targets[specIdx].ApiUrl,
+				Action:    api.PlannedActionNone,
+				Error:     fmt.Sprintf("the target at the index %d is a duplicate of the target at the index %d", specIdx, originalIdx),
+			})
+		}
+	}
+
+	remoteSecret.Status.DryRunPlan = plan
+	if err := r.Client.Status().Update(ctx, remoteSecret); err != nil {
+		return fmt.Errorf("failed to persist the dry run plan in the status: %w", err)
+	}
+
+	return nil
+}
+
+// fillTargetPlan fills in the target plan from the plan of the dependents handler or the error that prevented the computation of it.
+func fillTargetPlan(targetPlan *api.TargetPlan, depPlan *bindings.DependentsPlan, err error) {
+	if err != nil {
+		targetPlan.Error = err.Error()
+		return
+	}
+
+	targetPlan.Action = depPlan.SecretAction
+	targetPlan.SecretName = depPlan.SecretName
+	targetPlan.ReplacedSecretName = depPlan.ReplacedSecretName
+	targetPlan.Changes = depPlan.SecretChanges
+	targetPlan.ServiceAccounts = depPlan.ServiceAccounts
+}
+
 // processTargets uses remotesecrets.ClassifyTargetNamespaces to find out what to do with targets in the remote secret spec and status
 // and does what the classification tells it to.
-func (r *RemoteSecretReconciler) processTargets(ctx context.Context, remoteSecret *api.RemoteSecret, secretData *remotesecretstorage.SecretData, errorAggregate *rerror.AggregatedError) {
+func (r *RemoteSecretReconciler) processTargets(ctx context.Context, remoteSecret *api.RemoteSecret, secretData *remotesecretstorage.SecretData, errorAggregate *rerror.AggregatedError, events *[]func()) {
 	namespaceClassification := remotesecrets.ClassifyTargetNamespaces(remoteSecret)
 	log.FromContext(ctx).V(logs.DebugLevel).Info("namespace classification", "classification", namespaceClassification)
 	for specIdx, statusIdx := range namespaceClassification.Sync {
@@ -489,7 +1176,7 @@ func (r *RemoteSecretReconciler) processTargets(ctx context.Context, remoteSecre
 		} else {
 			status = &remoteSecret.Status.Targets[statusIdx]
 		}
-		err := r.deployToNamespace(ctx, remoteSecret, spec, status, secretData)
+		err := r.deployToNamespace(ctx, remoteSecret, spec, status, secretData, events)
 		if err != nil {
 			errorAggregate.Add(err)
 		}
@@ -518,6 +1205,13 @@ func (r *RemoteSecretReconciler) processTargets(ctx context.Context, remoteSecre
 				Namespace: remoteSecret.Spec.Targets[specIdx].Namespace,
 				Error:     fmt.Sprintf("the target at the index %d is a duplicate of the target at the index %d", specIdx, originalIdx),
 			}
+			meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+				Type:               string(api.TargetConditionTypeReady),
+				Status:             metav1.ConditionFalse,
+				Reason:             string(api.TargetReasonDuplicateTarget),
+				Message:            status.Error,
+				ObservedGeneration: remoteSecret.Generation,
+			})
 		}
 	}
 
@@ -537,19 +1231,28 @@ func (r *RemoteSecretReconciler) processTargets(ctx context.Context, remoteSecre
 
 // deployToNamespace deploys the secret to the provided target and fills in the provided status with the result of the deployment. The status will also contain the error
 // if the deployment failed. This returns an error if the deployment fails (this is recorded in the target status) OR if the update of the status in k8s fails (this is,
-// obviously, not recorded in the target status).
-func (r *RemoteSecretReconciler) deployToNamespace(ctx context.Context, remoteSecret *api.RemoteSecret, targetSpec *api.RemoteSecretTarget, targetStatus *api.TargetStatus, data *remotesecretstorage.SecretData) error {
+// obviously, not recorded in the target status). The event about the successful deployment is added to the events that are emitted once the
+// status is persisted.
+func (r *RemoteSecretReconciler) deployToNamespace(ctx context.Context, remoteSecret *api.RemoteSecret, targetSpec *api.RemoteSecretTarget, targetStatus *api.TargetStatus, data *remotesecretstorage.SecretData, events *[]func()) error {
 	debugLog := log.FromContext(ctx).V(logs.DebugLevel)
 
 	var depErr, checkPointErr, syncErr, updateErr error
 
-	depHandler, depErr := newDependentsHandler(ctx, r.TargetClientFactory, r.RemoteSecretStorage, remoteSecret, targetSpec, targetStatus)
-	if depErr != nil && !stdErrors.Is(depErr, bindings.ErrorInvalidClientConfig) {
-		debugLog.Error(depErr, "failed to construct the dependents handler")
+	var depHandler *bindings.DependentsHandler[*api.RemoteSecret]
+	// the policies might have changed since the webhook checked the remote secret, so we need to check them again before deploying
+	if r.PolicyChecker != nil {
+		depErr = r.PolicyChecker.CheckTarget(ctx, remoteSecret, targetSpec)
+	}
+	if depErr == nil {
+		depHandler, depErr = newDependentsHandler(ctx, r.TargetClientFactory, r.RemoteSecretStorage, remoteSecret, targetSpec, targetStatus, r.dataFingerprint(remoteSecret, data))
+		if depErr != nil && !stdErrors.Is(depErr, bindings.ErrorInvalidClientConfig) {
+			debugLog.Error(depErr, "failed to construct the dependents handler")
+		}
 	}
 
 	var checkPoint *bindings.CheckPoint
 	if depHandler != nil {
+		depHandler.ServerSideApply = r.Configuration.ServerSideApply
 		checkPoint, checkPointErr = depHandler.CheckPoint(ctx)
 		if checkPointErr != nil {
 			debugLog.Error(checkPointErr, "failed to construct a checkpoint to rollback to in case of target deployment error")
@@ -557,9 +1260,10 @@ func (r *RemoteSecretReconciler) deployToNamespace(ctx context.Context, remoteSe
 	}
 
 	var deps *bindings.Dependents
+	var syncReason string
 
 	if depHandler != nil && checkPointErr == nil {
-		deps, _, syncErr = depHandler.Sync(ctx, remoteSecret)
+		deps, syncReason, syncErr = depHandler.Sync(ctx, remoteSecret)
 	}
 
 	targetStatus.ApiUrl = targetSpec.ApiUrl
@@ -597,6 +1301,28 @@ func (r *RemoteSecretReconciler) deployToNamespace(ctx context.Context, remoteSe
 		targetStatus.DeployedSecret.Labels = depTargetSpec.Labels
 		targetStatus.DeployedSecret.Annotations = depTargetSpec.Annotations
 		targetStatus.ExpectedSecret = nil
+
+		// the sync info is only updated when something changed so that we don't update the status of the remote secret (and thus
+		// cause another reconciliation) on every reconciliation.
+		fingerprint := r.dataFingerprint(remoteSecret, data)
+		if targetStatus.ObservedGeneration != remoteSecret.Generation || targetStatus.DataFingerprint != fingerprint ||
+			!meta.IsStatusConditionTrue(targetStatus.Conditions, string(api.TargetConditionTypeReady)) {
+			now := metav1.Now()
+			targetStatus.LastSyncTime = &now
+			targetStatus.ObservedGeneration = remoteSecret.Generation
+			targetStatus.DataFingerprint = fingerprint
+			apiUrl, secretName, secretNamespace := targetSpec.ApiUrl, deps.Secret.Name, deps.Secret.Namespace
+			*events = append(*events, func() {
+				recordTargetEvent(r.Recorder, remoteSecret, apiUrl, corev1.EventTypeNormal, api.RemoteSecretEventReasonTargetDeployed,
+					"deployed the secret %s to the namespace %s", secretName, secretNamespace)
+			})
+		}
+		meta.SetStatusCondition(&targetStatus.Conditions, metav1.Condition{
+			Type:               string(api.TargetConditionTypeReady),
+			Status:             metav1.ConditionTrue,
+			Reason:             string(api.TargetReasonDeployed),
+			ObservedGeneration: remoteSecret.Generation,
+		})
 	} else {
 		targetStatus.Namespace = targetSpec.Namespace
 		targetStatus.DeployedSecret = nil
@@ -604,10 +1330,20 @@ func (r *RemoteSecretReconciler) deployToNamespace(ctx context.Context, remoteSe
 		targetStatus.ServiceAccountNames = []string{}
 		// finalizer depends on this being non-empty only in situations where we never deployed anything to the
 		// target.
-		targetStatus.Error = rerror.AggregateNonNilErrors(depErr, checkPointErr, syncErr).Error()
+		deployErr := rerror.AggregateNonNilErrors(depErr, checkPointErr, syncErr)
+		targetStatus.Error = deployErr.Error()
 		if stdErrors.Is(syncErr, bindings.DependentsInconsistencyError) {
 			inconsistent = true
 		}
+		meta.SetStatusCondition(&targetStatus.Conditions, metav1.Condition{
+			Type:               string(api.TargetConditionTypeReady),
+			Status:             metav1.ConditionFalse,
+			Reason:             string(bindings.TargetReasonFor(deployErr, bindings.ErrorReason(syncReason))),
+			Message:            targetStatus.Error,
+			ObservedGeneration: remoteSecret.Generation,
+		})
+		recordTargetEvent(r.Recorder, remoteSecret, targetSpec.ApiUrl, corev1.EventTypeWarning, api.RemoteSecretEventReasonTargetDeploymentFailed,
+			"failed to deploy to the namespace %s: %s", targetSpec.Namespace, targetStatus.Error)
 	}
 
 	// keep the backwards-compatibility for users that use this field
@@ -659,14 +1395,18 @@ func (r *RemoteSecretReconciler) deployToNamespace(ctx context.Context, remoteSe
 }
 
 func (r *RemoteSecretReconciler) deleteFromNamespace(ctx context.Context, remoteSecret *api.RemoteSecret, statusTargetIndex remotesecrets.StatusTargetIndex) error {
-	dep, err := newDependentsHandler(ctx, r.TargetClientFactory, r.RemoteSecretStorage, remoteSecret, nil, &remoteSecret.Status.Targets[statusTargetIndex])
+	dep, err := newDependentsHandler(ctx, r.TargetClientFactory, r.RemoteSecretStorage, remoteSecret, nil, &remoteSecret.Status.Targets[statusTargetIndex], "")
 	if err != nil {
 		return fmt.Errorf("failed to construct the handler to use for target cleanup: %w", err)
 	}
 
+	target := &remoteSecret.Status.Targets[statusTargetIndex]
 	if err = dep.Cleanup(ctx); err != nil {
+		recordCleanupFailedEvent(r.Recorder, remoteSecret, target, err)
 		return fmt.Errorf("failed to clean up dependent objects: %w", err)
 	}
+	recordTargetEvent(r.Recorder, remoteSecret, target.ApiUrl, corev1.EventTypeNormal, api.RemoteSecretEventReasonTargetRemoved,
+		"removed the target namespace %s", target.Namespace)
 
 	// unlike in deployToNamespace, we DO NOT update the status here straight away. That is because doing that would mess up the indices
 	// in the naming classification in processTargets which this method is a helper of.
@@ -677,24 +1417,28 @@ func (r *RemoteSecretReconciler) deleteFromNamespace(ctx context.Context, remote
 	return nil
 }
 
-func newDependentsHandler(ctx context.Context, cf bindings.ClientFactory, st remotesecretstorage.RemoteSecretStorage, remoteSecret *api.RemoteSecret, targetSpec *api.RemoteSecretTarget, targetStatus *api.TargetStatus) (*bindings.DependentsHandler[*api.RemoteSecret], error) {
-	cl, err := cf.GetClient(ctx, remoteSecret.Namespace, targetSpec, targetStatus)
+// newDependentsHandler constructs the handler of the objects in the target. The data fingerprint is put on the deployed secret and can be
+// empty if the handler is not used to deploy the secret.
+func newDependentsHandler(ctx context.Context, cf bindings.ClientFactory, st remotesecretstorage.RemoteSecretStorage, remoteSecret *api.RemoteSecret, targetSpec *api.RemoteSecretTarget, targetStatus *api.TargetStatus, dataFingerprint string) (*bindings.DependentsHandler[*api.RemoteSecret], error) {
+	cl, err := cf.GetClient(ctx, client.ObjectKeyFromObject(remoteSecret), targetSpec, targetStatus)
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct a client to use for deploying to target: %w", err)
 	}
 
 	return &bindings.DependentsHandler[*api.RemoteSecret]{
 		Target: &namespacetarget.NamespaceTarget{
-			Client:       cl,
-			TargetKey:    client.ObjectKeyFromObject(remoteSecret),
-			SecretSpec:   &remoteSecret.Spec.Secret,
-			TargetSpec:   targetSpec,
-			TargetStatus: targetStatus,
+			Client:          cl,
+			TargetKey:       client.ObjectKeyFromObject(remoteSecret),
+			SecretSpec:      &remoteSecret.Spec.Secret,
+			TargetSpec:      targetSpec,
+			TargetStatus:    targetStatus,
+			DataFingerprint: dataFingerprint,
 		},
 		SecretDataGetter: &remotesecrets.SecretDataGetter{
 			Storage: st,
 		},
-		ObjectMarker: &namespacetarget.NamespaceObjectMarker{},
+		ObjectMarker:   &namespacetarget.NamespaceObjectMarker{},
+		AdoptionPolicy: remoteSecret.Spec.Adoption.Policy,
 	}, nil
 }
 
@@ -713,14 +1457,17 @@ func (f *remoteSecretStorageFinalizer) Finalize(ctx context.Context, obj client.
 }
 
 type remoteSecretLinksFinalizer struct {
-	localClient   client.Client
+	recorder      record.EventRecorder
 	clientFactory bindings.ClientFactory
 	storage       remotesecretstorage.RemoteSecretStorage
+	// deletionPolicy is used for the targets that don't specify their own deletion policy
+	deletionPolicy api.DeletionPolicy
 }
 
 // var _ finalizer.Finalizer = (*linkedObjectsFinalizer)(nil)
 
-// Finalize removes the secret and possibly also service account synced to the actual binging being deleted
+// Finalize removes the secret and possibly also service account synced to the actual binging being deleted. The targets
+// with the Orphan deletion policy only have the objects unmarked, so that they stay in place.
 func (f *remoteSecretLinksFinalizer) Finalize(ctx context.Context, obj client.Object) (finalizer.Result, error) {
 	res := finalizer.Result{}
 	remoteSecret, ok := obj.(*api.RemoteSecret)
@@ -734,6 +1481,8 @@ func (f *remoteSecretLinksFinalizer) Finalize(ctx context.Context, obj client.Ob
 
 	lg.Info("linked objects finalizer starting to clean up dependent objects")
 
+	policies := statusTargetDeletionPolicies(remoteSecret, f.deletionPolicy)
+
 	for i := range remoteSecret.Status.Targets {
 		ts := remoteSecret.Status.Targets[i]
 		// the error is set in the deployToNamespace function and is non-empty if we were unable to even
@@ -746,21 +1495,20 @@ func (f *remoteSecretLinksFinalizer) Finalize(ctx context.Context, obj client.Ob
 		if ts.Error != "" {
 			continue
 		}
-		dep, err := newDependentsHandler(ctx, f.clientFactory, f.storage, remoteSecret, nil, &ts)
+		dep, err := newDependentsHandler(ctx, f.clientFactory, f.storage, remoteSecret, nil, &ts, "")
 		if err != nil {
 			// we're in the finalizer and we failed to even construct the dependents handler.
 			lg.Error(err, "failed to construct the dependents handler to clean up the target in the finalizer", "target", ts)
-			if eerr := f.createErrorEvent(ctx, key, ts, err); eerr != nil {
-				lg.Error(eerr, "failed to create the error event informing about the failure to cleanup", "target", ts)
-			}
+			recordCleanupFailedEvent(f.recorder, remoteSecret, &ts, err)
 			return res, nil
 		}
-		if err := dep.Cleanup(ctx); err != nil {
+		cleanup := dep.Cleanup
+		if policies[i] == api.DeletionPolicyOrphan {
+			cleanup = dep.Orphan
+		}
+		if err := cleanup(ctx); err != nil {
 			lg.Error(err, "failed to clean up the dependent objects in the finalizer", "binding", client.ObjectKeyFromObject(remoteSecret))
-			if eerr := f.createErrorEvent(ctx, key, ts, err); eerr != nil {
-				lg.Error(eerr, "failed to create the error event ifnorming about the failure to cleanup", "target", ts)
-				return res, nil
-			}
+			recordCleanupFailedEvent(f.recorder, remoteSecret, &ts, err)
 		}
 	}
 
@@ -769,78 +1517,47 @@ func (f *remoteSecretLinksFinalizer) Finalize(ctx context.Context, obj client.Ob
 	return res, nil
 }
 
-func (f *remoteSecretLinksFinalizer) createErrorEvent(ctx context.Context, rs client.ObjectKey, target api.TargetStatus, err error) error {
-	message := fmt.Sprintf("failed to delete the secret deployed to the cluster. The error message was: %s", err.Error())
-
-	retErr := rerror.NewAggregatedError()
-
-	secretName := ""
-	// should always be non-nil in this method, but let's be paranoid to avoid panics..
-	if target.DeployedSecret != nil {
-		secretName = target.DeployedSecret.Name
-	}
-	secretEv := &corev1.Event{
-		ObjectMeta: metav1.ObjectMeta{
-			GenerateName: rs.Name + "-",
-			Namespace:    rs.Namespace,
-		},
-		Message:        message,
-		Reason:         "target cleanup failed",
-		InvolvedObject: corev1.ObjectReference{Namespace: rs.Namespace, Name: rs.Name, Kind: "RemoteSecret", APIVersion: api.GroupVersion.String()},
-		Related: &corev1.ObjectReference{
-			Kind:       "Secret",
-			Namespace:  target.Namespace,
-			Name:       secretName,
-			APIVersion: "v1",
-		},
-		Type:          "Warning",
-		LastTimestamp: metav1.NewTime(time.Now()),
+// statusTargetDeletionPolicies returns the deletion policies of the targets in the status of the remote secret. The targets
+// that have no counterpart in the spec or that don't specify the deletion policy use the provided default.
+func statusTargetDeletionPolicies(remoteSecret *api.RemoteSecret, defaultPolicy api.DeletionPolicy) []api.DeletionPolicy {
+	if defaultPolicy == "" {
+		defaultPolicy = api.DeletionPolicyDelete
 	}
 
-	if target.ApiUrl != "" {
-		secretEv.Annotations = map[string]string{
-			api.ObjectClusterUrlAnnotation: target.ApiUrl,
-		}
+	ret := make([]api.DeletionPolicy, len(remoteSecret.Status.Targets))
+	for i := range ret {
+		ret[i] = defaultPolicy
 	}
 
-	if cerr := f.localClient.Create(ctx, secretEv); cerr != nil {
-		retErr.Add(fmt.Errorf("secret: %w", cerr))
+	for specIdx, statusIdx := range remotesecrets.ClassifyTargetNamespaces(remoteSecret).Sync {
+		if statusIdx >= 0 && remoteSecret.Spec.Targets[specIdx].DeletionPolicy != "" {
+			ret[statusIdx] = remoteSecret.Spec.Targets[specIdx].DeletionPolicy
+		}
 	}
 
-	message = fmt.Sprintf("failed to delete the service account deployed to the cluster. The error message was: %s", err.Error())
+	return ret
+}
 
-	for _, saName := range target.ServiceAccountNames {
-		saEv := &corev1.Event{
-			ObjectMeta: metav1.ObjectMeta{
-				GenerateName: rs.Name + "-",
-				Namespace:    rs.Namespace,
-			},
-			Message:        message,
-			Reason:         "target cleanup failed",
-			InvolvedObject: corev1.ObjectReference{Namespace: rs.Namespace, Name: rs.Name, Kind: "RemoteSecret", APIVersion: api.GroupVersion.String()},
-			Related: &corev1.ObjectReference{
-				Kind:       "ServiceAccount",
-				Namespace:  target.Namespace,
-				Name:       saName,
-				APIVersion: "v1",
-			},
-			Type:          "Warning",
-			LastTimestamp: metav1.NewTime(time.Now()),
-		}
-		if target.ApiUrl != "" {
-			secretEv.Annotations = map[string]string{
-				api.ObjectClusterUrlAnnotation: target.ApiUrl,
-			}
-		}
-		if cerr := f.localClient.Create(ctx, saEv); cerr != nil {
-			retErr.Add(fmt.Errorf("service account %s: %w", saName, cerr))
-		}
+// recordTargetEvent records an event about a target on the remote secret. The events about the targets in the remote clusters
+// carry the URL of the cluster both in the message and in the ObjectClusterUrlAnnotation.
+func recordTargetEvent(recorder record.EventRecorder, remoteSecret *api.RemoteSecret, apiUrl string, eventType, reason, messageFmt string, args ...any) {
+	var annotations map[string]string
+	if apiUrl != "" {
+		annotations = map[string]string{api.ObjectClusterUrlAnnotation: apiUrl}
+		messageFmt += " (cluster %s)"
+		args = append(args, apiUrl)
 	}
+	recorder.AnnotatedEventf(remoteSecret, annotations, eventType, reason, messageFmt, args...)
+}
 
-	if retErr.HasErrors() {
-		return fmt.Errorf("failed to create the cleanup failure event(s): %w", retErr)
+func recordCleanupFailedEvent(recorder record.EventRecorder, remoteSecret *api.RemoteSecret, target *api.TargetStatus, err error) {
+	secretName := ""
+	// should always be non-nil in this method, but let's be paranoid to avoid panics..
+	if target.DeployedSecret != nil {
+		secretName = target.DeployedSecret.Name
 	}
-	return nil
+	recordTargetEvent(recorder, remoteSecret, target.ApiUrl, corev1.EventTypeWarning, api.RemoteSecretEventReasonTargetCleanupFailed,
+		"failed to clean up the secret %s and the service accounts %v in the namespace %s: %s", secretName, target.ServiceAccountNames, target.Namespace, err.Error())
 }
 
 func reconcileLogger(lg logr.Logger) logr.Logger {
@@ -0,0 +1,525 @@
+//
+// Copyright (c) 2021 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	stdErrors "errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/redhat-appstudio/remote-secret/controllers/remotesecrets"
+	"github.com/redhat-appstudio/remote-secret/controllers/remotesecretstorage"
+	"github.com/redhat-appstudio/remote-secret/pkg/externalsource"
+	"github.com/redhat-appstudio/remote-secret/pkg/quota"
+	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage"
+	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage/memorystorage"
+)
+
+func TestReadyCondition(t *testing.T) {
+	cond := func(typ api.RemoteSecretConditionType, status metav1.ConditionStatus, reason api.RemoteSecretReason) metav1.Condition {
+		return metav1.Condition{Type: string(typ), Status: status, Reason: string(reason)}
+	}
+	dataObtained := cond(api.RemoteSecretConditionTypeDataObtained, metav1.ConditionTrue, api.RemoteSecretReasonDataFound)
+	deployed := cond(api.RemoteSecretConditionTypeDeployed, metav1.ConditionTrue, api.RemoteSecretReasonInjected)
+
+	test := func(name string, expectedStatus metav1.ConditionStatus, expectedReason api.RemoteSecretReason, conditions ...metav1.Condition) {
+		t.Run(name, func(t *testing.T) {
+			rs := &api.RemoteSecret{Status: api.RemoteSecretStatus{Conditions: conditions}}
+			ready := readyCondition(rs)
+			assert.Equal(t, string(api.RemoteSecretConditionTypeReady), ready.Type)
+			assert.Equal(t, expectedStatus, ready.Status)
+			assert.Equal(t, string(expectedReason), ready.Reason)
+		})
+	}
+
+	test("no conditions", metav1.ConditionFalse, api.RemoteSecretReasonAwaitingTokenData)
+	test("awaiting data", metav1.ConditionFalse, api.RemoteSecretReasonAwaitingTokenData,
+		cond(api.RemoteSecretConditionTypeDataObtained, metav1.ConditionFalse, api.RemoteSecretReasonAwaitingTokenData))
+	test("not deployed yet", metav1.ConditionFalse, api.RemoteSecretReasonDeploymentPending, dataObtained)
+	test("partially deployed", metav1.ConditionFalse, api.RemoteSecretReasonPartiallyInjected, dataObtained,
+		cond(api.RemoteSecretConditionTypeDeployed, metav1.ConditionFalse, api.RemoteSecretReasonPartiallyInjected))
+	test("suspended", metav1.ConditionFalse, api.RemoteSecretReasonSuspended, dataObtained, deployed,
+		cond(api.RemoteSecretConditionTypeSuspended, metav1.ConditionTrue, api.RemoteSecretReasonSuspended))
+	test("replication pending", metav1.ConditionFalse, api.RemoteSecretReasonReplicationPending, dataObtained, deployed,
+		cond(api.RemoteSecretConditionTypeReplicated, metav1.ConditionFalse, api.RemoteSecretReasonReplicationPending))
+	test("no targets", metav1.ConditionTrue, api.RemoteSecretReasonReady, dataObtained,
+		cond(api.RemoteSecretConditionTypeDeployed, metav1.ConditionFalse, api.RemoteSecretReasonNoTargets))
+	test("data invalid", metav1.ConditionFalse, api.RemoteSecretReasonDataInvalid, dataObtained, deployed,
+		cond(api.RemoteSecretConditionTypeDataValid, metav1.ConditionFalse, api.RemoteSecretReasonDataInvalid))
+	test("data with warnings", metav1.ConditionTrue, api.RemoteSecretReasonReady, dataObtained, deployed,
+		cond(api.RemoteSecretConditionTypeDataValid, metav1.ConditionTrue, api.RemoteSecretReasonDataWarnings))
+	test("deployed", metav1.ConditionTrue, api.RemoteSecretReasonReady, dataObtained, deployed,
+		cond(api.RemoteSecretConditionTypeSuspended, metav1.ConditionFalse, api.RemoteSecretReasonResumed),
+		cond(api.RemoteSecretConditionTypeReplicated, metav1.ConditionTrue, api.RemoteSecretReasonReplicated))
+}
+
+func TestDataValidCondition(t *testing.T) {
+	rs := &api.RemoteSecret{
+		Spec: api.RemoteSecretSpec{
+			Secret: api.LinkableSecretSpec{
+				Type:         corev1.SecretTypeDockerConfigJson,
+				RequiredKeys: []api.SecretKey{{Name: "ca.crt", Optional: true, Format: api.SecretKeyFormatPemCertificate}},
+			},
+		},
+	}
+	dockerConfig := []byte(`{"auths": {"quay.io": {"auth": "dXNlcjpwYXNz"}}}`)
+
+	t.Run("valid", func(t *testing.T) {
+		cond := dataValidCondition(rs, map[string][]byte{corev1.DockerConfigJsonKey: dockerConfig}, time.Now())
+		assert.Equal(t, metav1.ConditionTrue, cond.Status)
+		assert.Equal(t, string(api.RemoteSecretReasonDataValid), cond.Reason)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		cond := dataValidCondition(rs, map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"quay.io": {}}`)}, time.Now())
+		assert.Equal(t, metav1.ConditionFalse, cond.Status)
+		assert.Equal(t, string(api.RemoteSecretReasonDataInvalid), cond.Reason)
+		assert.Contains(t, cond.Message, "key '.dockerconfigjson' does not contain the 'auths' object")
+	})
+
+	t.Run("with warnings", func(t *testing.T) {
+		cert := expiredCertificate(t)
+		cond := dataValidCondition(rs, map[string][]byte{corev1.DockerConfigJsonKey: dockerConfig, "ca.crt": cert}, time.Now())
+		assert.Equal(t, metav1.ConditionTrue, cond.Status)
+		assert.Equal(t, string(api.RemoteSecretReasonDataWarnings), cond.Reason)
+		assert.Contains(t, cond.Message, "the certificate 'CN=expired' in key 'ca.crt' expired at")
+	})
+}
+
+func expiredCertificate(t *testing.T) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "expired"},
+		NotBefore:    time.Now().Add(-2 * time.Hour),
+		NotAfter:     time.Now().Add(-time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestMovePendingDataUpdateToStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	assert.NoError(t, api.AddToScheme(scheme))
+
+	rs := &api.RemoteSecret{ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "ns"}}
+	assert.NoError(t, remotesecrets.SetPendingDataUpdate(rs, remotesecrets.NewDataUpdate(api.DataUpdateSourceDataFrom, "legacy/creds", map[string][]byte{"a": nil}, nil, "", nil)))
+	rs.Annotations[api.DeleteImportedSecretsAnnotation] = "legacy/creds"
+	source := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "legacy"}}
+
+	t.Run("nothing is done before the update is persisted", func(t *testing.T) {
+		cl := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(rs.DeepCopy(), source.DeepCopy()).
+			WithStatusSubresource(&api.RemoteSecret{}).
+			WithInterceptorFuncs(interceptor.Funcs{
+				SubResourceUpdate: func(_ context.Context, _ client.Client, _ string, _ client.Object, _ ...client.SubResourceUpdateOption) error {
+					return stdErrors.New("kaboom")
+				},
+			}).
+			Build()
+		recorder := record.NewFakeRecorder(1)
+		r := &RemoteSecretReconciler{Client: cl, Recorder: recorder}
+
+		assert.Error(t, r.movePendingDataUpdateToStatus(context.TODO(), rs.DeepCopy()))
+		assert.NoError(t, cl.Get(context.TODO(), client.ObjectKeyFromObject(source), &corev1.Secret{}))
+		assert.Empty(t, recorder.Events)
+	})
+
+	t.Run("deletes the imported secrets and emits the event", func(t *testing.T) {
+		cl := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(rs.DeepCopy(), source.DeepCopy()).
+			WithStatusSubresource(&api.RemoteSecret{}).
+			Build()
+		recorder := record.NewFakeRecorder(1)
+		r := &RemoteSecretReconciler{Client: cl, Recorder: recorder}
+
+		persisted := &api.RemoteSecret{}
+		assert.NoError(t, cl.Get(context.TODO(), client.ObjectKeyFromObject(rs), persisted))
+
+		assert.NoError(t, r.movePendingDataUpdateToStatus(context.TODO(), persisted))
+		assert.True(t, errors.IsNotFound(cl.Get(context.TODO(), client.ObjectKeyFromObject(source), &corev1.Secret{})))
+		assert.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Normal DataCopied the data was copied from legacy/creds", <-recorder.Events)
+
+		assert.NoError(t, cl.Get(context.TODO(), client.ObjectKeyFromObject(rs), persisted))
+		assert.NotContains(t, persisted.Annotations, api.LastDataUpdateAnnotation)
+		assert.NotContains(t, persisted.Annotations, api.DeleteImportedSecretsAnnotation)
+		assert.Equal(t, api.DataUpdateSourceDataFrom, persisted.Status.LastDataUpdate.Source)
+	})
+}
+
+func TestHandleStageEvents(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, api.AddToScheme(scheme))
+
+	rs := &api.RemoteSecret{ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "ns"}}
+
+	emitted := 0
+	result := stageResult[any]{
+		Name:      "test",
+		Condition: metav1.Condition{Type: string(api.RemoteSecretConditionTypeDeployed), Status: metav1.ConditionTrue, Reason: string(api.RemoteSecretReasonInjected)},
+		Events:    []func(){func() { emitted++ }},
+	}
+
+	failing := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(rs.DeepCopy()).
+		WithStatusSubresource(&api.RemoteSecret{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(_ context.Context, _ client.Client, _ string, _ client.Object, _ ...client.SubResourceUpdateOption) error {
+				return stdErrors.New("kaboom")
+			},
+		}).
+		Build()
+	_, err := handleStage(context.TODO(), failing, rs.DeepCopy(), result)
+	assert.Error(t, err)
+	assert.Zero(t, emitted)
+
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(rs.DeepCopy()).WithStatusSubresource(&api.RemoteSecret{}).Build()
+	persisted := &api.RemoteSecret{}
+	assert.NoError(t, cl.Get(context.TODO(), client.ObjectKeyFromObject(rs), persisted))
+	_, err = handleStage(context.TODO(), cl, persisted, result)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, emitted)
+}
+
+func TestRecordTargetEvent(t *testing.T) {
+	rs := &api.RemoteSecret{ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "ns"}}
+
+	t.Run("local target", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(1)
+		recordTargetEvent(recorder, rs, "", corev1.EventTypeNormal, api.RemoteSecretEventReasonTargetRemoved, "removed the target namespace %s", "target")
+		assert.Equal(t, "Normal TargetRemoved removed the target namespace target", <-recorder.Events)
+	})
+
+	t.Run("remote target", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(1)
+		recordTargetEvent(recorder, rs, "https://over.there", corev1.EventTypeWarning, api.RemoteSecretEventReasonTargetDeploymentFailed, "failed to deploy to the namespace %s: %s", "target", "kaboom")
+		assert.Equal(t, "Warning TargetDeploymentFailed failed to deploy to the namespace target: kaboom (cluster https://over.there) map[appstudio.redhat.com/object-cluster-url:https://over.there]", <-recorder.Events)
+	})
+}
+
+func TestFollowing(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, api.AddToScheme(scheme))
+
+	follower := func(name, namespace, sourceName, sourceNamespace string) *api.RemoteSecret {
+		return &api.RemoteSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			DataFrom: api.RemoteSecretDataFrom{
+				Name:      sourceName,
+				Namespace: sourceNamespace,
+				Follow:    true,
+			},
+		}
+	}
+
+	source := &api.RemoteSecret{ObjectMeta: metav1.ObjectMeta{Name: "source", Namespace: "ns"}}
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			source,
+			follower("same-ns", "ns", "source", ""),
+			follower("other-ns", "other", "source", "ns"),
+			follower("unrelated", "other", "source", ""),
+			follower("chained", "other", "same-ns", "ns"),
+			&api.RemoteSecret{
+				ObjectMeta: metav1.ObjectMeta{Name: "multi", Namespace: "other"},
+				DataFrom: api.RemoteSecretDataFrom{
+					Sources: []api.RemoteSecretDataSource{{Name: "unrelated"}, {Name: "source", Namespace: "ns"}},
+					Follow:  true,
+				},
+			},
+			&api.RemoteSecret{ObjectMeta: metav1.ObjectMeta{Name: "copied", Namespace: "ns"}, DataFrom: api.RemoteSecretDataFrom{Name: "source"}},
+		).
+		WithIndex(&api.RemoteSecret{}, followedRemoteSecretsIndex, indexFollowedRemoteSecrets).
+		Build()
+
+	storage := remotesecretstorage.NewJSONSerializingRemoteSecretStorage(&memorystorage.MemoryStorage{})
+	assert.NoError(t, storage.Initialize(context.TODO()))
+
+	r := &RemoteSecretReconciler{
+		Client:              cl,
+		RemoteSecretStorage: storage,
+	}
+
+	t.Run("finds followers", func(t *testing.T) {
+		reqs := r.findFollowersOfRemoteSecret(context.TODO(), client.ObjectKeyFromObject(source))
+		assert.ElementsMatch(t, []reconcile.Request{
+			{NamespacedName: client.ObjectKey{Name: "same-ns", Namespace: "ns"}},
+			{NamespacedName: client.ObjectKey{Name: "other-ns", Namespace: "other"}},
+			{NamespacedName: client.ObjectKey{Name: "multi", Namespace: "other"}},
+		}, reqs)
+	})
+
+	t.Run("no data of the source", func(t *testing.T) {
+		_, err := r.getFollowedData(context.TODO(), follower("f", "ns", "source", ""))
+		assert.ErrorIs(t, err, secretstorage.NotFoundError)
+	})
+
+	t.Run("non-existent source", func(t *testing.T) {
+		_, err := r.getFollowedData(context.TODO(), follower("f", "ns", "non-existent", ""))
+		assert.ErrorIs(t, err, secretstorage.NotFoundError)
+	})
+
+	t.Run("reads the data of the source", func(t *testing.T) {
+		assert.NoError(t, storage.Store(context.TODO(), source, &remotesecretstorage.SecretData{"a": []byte("b")}))
+		data, err := r.getFollowedData(context.TODO(), follower("f", "ns", "source", ""))
+		assert.NoError(t, err)
+		assert.Equal(t, remotesecretstorage.SecretData{"a": []byte("b")}, *data)
+	})
+
+	t.Run("merges the data of the sources", func(t *testing.T) {
+		other := &api.RemoteSecret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "other"}}
+		assert.NoError(t, cl.Create(context.TODO(), other))
+		assert.NoError(t, storage.Store(context.TODO(), other, &remotesecretstorage.SecretData{"a": []byte("other-a"), "c": []byte("c")}))
+
+		rs := follower("f", "ns", "source", "")
+		rs.DataFrom.Sources = []api.RemoteSecretDataSource{{Name: "other", Namespace: "other", Keys: []api.RemoteSecretDataSourceKey{{Name: "c", As: "d"}}}}
+		data, err := r.getFollowedData(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Equal(t, remotesecretstorage.SecretData{"a": []byte("b"), "d": []byte("c")}, *data)
+
+		rs.DataFrom.Sources[0].Keys = nil
+		data, err = r.getFollowedData(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Equal(t, remotesecretstorage.SecretData{"a": []byte("other-a"), "c": []byte("c")}, *data)
+
+		rs.DataFrom.Sources[0].Keys = []api.RemoteSecretDataSourceKey{{Name: "x"}}
+		_, err = r.getFollowedData(context.TODO(), rs)
+		assert.ErrorIs(t, err, errFollowedDataKeysMissing)
+
+		reqs := r.findFollowersOfRemoteSecret(context.TODO(), client.ObjectKeyFromObject(other))
+		assert.Empty(t, reqs)
+	})
+
+	t.Run("source following another", func(t *testing.T) {
+		_, err := r.getFollowedData(context.TODO(), follower("f", "ns", "same-ns", "ns"))
+		assert.ErrorIs(t, err, errFollowedRemoteSecretFollows)
+	})
+}
+
+func TestFollowedDataChangedPredicate(t *testing.T) {
+	rs := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "ns"},
+		Status: api.RemoteSecretStatus{
+			SecretStatus: api.SecretStatus{Keys: []string{"a"}, Size: 2, Version: "1"},
+			Conditions:   []metav1.Condition{{Type: string(api.RemoteSecretConditionTypeDataObtained), Status: metav1.ConditionTrue}},
+		},
+	}
+	changed := func(modify func(rs *api.RemoteSecret)) bool {
+		updated := rs.DeepCopy()
+		modify(updated)
+		return followedDataChangedPredicate.Update(event.UpdateEvent{ObjectOld: rs, ObjectNew: updated})
+	}
+
+	assert.False(t, changed(func(rs *api.RemoteSecret) {}))
+	assert.False(t, changed(func(rs *api.RemoteSecret) {
+		rs.Status.Targets = []api.TargetStatus{{Namespace: "target"}}
+		rs.Status.Conditions = append(rs.Status.Conditions, metav1.Condition{Type: string(api.RemoteSecretConditionTypeDeployed), Status: metav1.ConditionTrue})
+	}))
+	assert.True(t, changed(func(rs *api.RemoteSecret) { rs.Status.SecretStatus.Version = "2" }))
+	assert.True(t, changed(func(rs *api.RemoteSecret) { rs.Status.SecretStatus.Keys = []string{"a", "b"} }))
+	assert.True(t, changed(func(rs *api.RemoteSecret) {
+		rs.Status.LastDataUpdate = &api.DataUpdateStatus{Source: api.DataUpdateSourceWebhook, Result: api.DataUpdateResultSucceeded}
+	}))
+	assert.True(t, changed(func(rs *api.RemoteSecret) { rs.Status.Conditions[0].Status = metav1.ConditionFalse }))
+	assert.True(t, changed(func(rs *api.RemoteSecret) {
+		rs.Annotations = map[string]string{api.DataAPIUpdateAnnotation: "2023-01-01T00:00:00Z"}
+	}))
+}
+
+func TestRefreshExternalData(t *testing.T) {
+	storage := remotesecretstorage.NewJSONSerializingRemoteSecretStorage(&memorystorage.MemoryStorage{})
+	assert.NoError(t, storage.Initialize(context.TODO()))
+
+	rs := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "ns", UID: "rs-uid"},
+		Spec: api.RemoteSecretSpec{
+			ExternalSource: &api.ExternalSource{
+				Data: []api.ExternalSourceData{{SecretKey: "token", RemoteRef: api.ExternalSourceRef{Key: "token"}}},
+			},
+		},
+	}
+
+	t.Run("not configured", func(t *testing.T) {
+		r := &RemoteSecretReconciler{RemoteSecretStorage: storage}
+		_, err := r.refreshExternalData(context.TODO(), rs)
+		assert.ErrorIs(t, err, errExternalSourceNotConfigured)
+	})
+
+	newReconciler := func(t *testing.T, value string) *RemoteSecretReconciler {
+		source, err := externalsource.NewSource(nil, `{"fake":{"data":[{"key":"ns/token","value":"`+value+`"}]}}`, "{namespace}/")
+		assert.NoError(t, err)
+		assert.NoError(t, source.Initialize(context.TODO()))
+		return &RemoteSecretReconciler{RemoteSecretStorage: storage, ExternalSource: source}
+	}
+
+	t.Run("stores the data", func(t *testing.T) {
+		data, err := newReconciler(t, "a").refreshExternalData(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Equal(t, remotesecretstorage.SecretData{"token": []byte("a")}, *data)
+
+		stored, err := storage.Get(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Equal(t, remotesecretstorage.SecretData{"token": []byte("a")}, *stored)
+	})
+
+	t.Run("updates the changed data", func(t *testing.T) {
+		data, err := newReconciler(t, "b").refreshExternalData(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Equal(t, remotesecretstorage.SecretData{"token": []byte("b")}, *data)
+
+		stored, err := storage.Get(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Equal(t, remotesecretstorage.SecretData{"token": []byte("b")}, *stored)
+	})
+
+	t.Run("doesn't store the data exceeding the quota", func(t *testing.T) {
+		r := newReconciler(t, "too long")
+		r.QuotaChecker = &quota.Checker{Quota: &api.RemoteSecretQuota{MaxStoredBytesPerRemoteSecret: 10}}
+
+		_, err := r.refreshExternalData(context.TODO(), rs)
+		assert.ErrorIs(t, err, quota.QuotaExceededError)
+
+		stored, err := storage.Get(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Equal(t, remotesecretstorage.SecretData{"token": []byte("b")}, *stored)
+
+		result := r.obtainData(context.TODO(), rs.DeepCopy())
+		assert.True(t, result.Cancellation.Cancel)
+		assert.NoError(t, result.Cancellation.ReturnError)
+		assert.Equal(t, externalsource.RefreshInterval(rs), result.Cancellation.Result.RequeueAfter)
+		assert.Equal(t, string(api.RemoteSecretConditionTypeDataObtained), result.Condition.Type)
+		assert.Equal(t, metav1.ConditionFalse, result.Condition.Status)
+		assert.Contains(t, result.Condition.Message, "quota")
+	})
+
+	t.Run("missing in the external source", func(t *testing.T) {
+		r := newReconciler(t, "b")
+		missing := rs.DeepCopy()
+		missing.Spec.ExternalSource.Data[0].RemoteRef.Key = "missing"
+		_, err := r.refreshExternalData(context.TODO(), missing)
+		assert.ErrorIs(t, err, externalsource.NotFoundError)
+	})
+}
+
+func TestImportDataFromTargets(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	assert.NoError(t, api.AddToScheme(scheme))
+
+	rs := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "rs",
+			Namespace:   "ns",
+			UID:         "rs-uid",
+			Annotations: map[string]string{api.ImportAuthorizedSecretsAnnotation: "target-ns/existing"},
+		},
+		Spec: api.RemoteSecretSpec{
+			Secret:   api.LinkableSecretSpec{Name: "existing"},
+			Adoption: api.SecretAdoption{ImportData: true},
+			Targets:  []api.RemoteSecretTarget{{Namespace: "target-ns"}},
+		},
+	}
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "target-ns"},
+		Data:       map[string][]byte{"token": []byte("too long")},
+	}
+
+	newReconciler := func(t *testing.T, q *api.RemoteSecretQuota) (*RemoteSecretReconciler, remotesecretstorage.RemoteSecretStorage) {
+		storage := remotesecretstorage.NewJSONSerializingRemoteSecretStorage(&memorystorage.MemoryStorage{})
+		assert.NoError(t, storage.Initialize(context.TODO()))
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing.DeepCopy()).Build()
+		return &RemoteSecretReconciler{
+			TargetClientFactory: singleClientFactory{cl: cl},
+			RemoteSecretStorage: storage,
+			QuotaChecker:        &quota.Checker{Client: cl, Quota: q},
+		}, storage
+	}
+
+	t.Run("imports the data", func(t *testing.T) {
+		r, storage := newReconciler(t, &api.RemoteSecretQuota{})
+
+		data, err := r.importDataFromTargets(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Equal(t, remotesecretstorage.SecretData(existing.Data), *data)
+
+		stored, err := storage.Get(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Equal(t, remotesecretstorage.SecretData(existing.Data), *stored)
+	})
+
+	t.Run("doesn't import the data exceeding the quota", func(t *testing.T) {
+		r, storage := newReconciler(t, &api.RemoteSecretQuota{MaxStoredBytesPerRemoteSecret: 10})
+
+		_, err := r.importDataFromTargets(context.TODO(), rs)
+		assert.ErrorIs(t, err, quota.QuotaExceededError)
+
+		_, err = storage.Get(context.TODO(), rs)
+		assert.ErrorIs(t, err, secretstorage.NotFoundError)
+	})
+}
+
+func TestEarlierResult(t *testing.T) {
+	assert.Equal(t, reconcile.Result{}, earlierResult(reconcile.Result{}, reconcile.Result{}))
+	assert.Equal(t, reconcile.Result{RequeueAfter: time.Hour}, earlierResult(reconcile.Result{}, reconcile.Result{RequeueAfter: time.Hour}))
+	assert.Equal(t, reconcile.Result{RequeueAfter: time.Minute}, earlierResult(reconcile.Result{RequeueAfter: time.Minute}, reconcile.Result{RequeueAfter: time.Hour}))
+	assert.Equal(t, reconcile.Result{RequeueAfter: time.Minute}, earlierResult(reconcile.Result{RequeueAfter: time.Hour}, reconcile.Result{RequeueAfter: time.Minute}))
+	assert.Equal(t, reconcile.Result{Requeue: true}, earlierResult(reconcile.Result{RequeueAfter: time.Hour}, reconcile.Result{Requeue: true}))
+}
+
+func TestDataAPIUpdateAnnotationChangedPredicate(t *testing.T) {
+	rs := func(annotations map[string]string) *api.RemoteSecret {
+		return &api.RemoteSecret{ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "ns", Annotations: annotations}}
+	}
+	notified := rs(map[string]string{api.DataAPIUpdateAnnotation: "2023-01-01T00:00:00.000000001Z"})
+
+	assert.True(t, dataAPIUpdateAnnotationChangedPredicate.Update(event.UpdateEvent{ObjectOld: rs(nil), ObjectNew: notified}))
+	assert.True(t, dataAPIUpdateAnnotationChangedPredicate.Update(event.UpdateEvent{
+		ObjectOld: notified,
+		ObjectNew: rs(map[string]string{api.DataAPIUpdateAnnotation: "2023-01-01T00:00:00.000000002Z"}),
+	}))
+	assert.False(t, dataAPIUpdateAnnotationChangedPredicate.Update(event.UpdateEvent{ObjectOld: notified, ObjectNew: notified.DeepCopy()}))
+	assert.False(t, dataAPIUpdateAnnotationChangedPredicate.Update(event.UpdateEvent{ObjectOld: rs(nil), ObjectNew: rs(map[string]string{"other": "x"})}))
+}
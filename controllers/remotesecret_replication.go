@@ -0,0 +1,282 @@
+//
+// Copyright (c) 2021 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/redhat-appstudio/remote-secret/controllers/remotesecretstorage"
+	"github.com/redhat-appstudio/remote-secret/pkg/logs"
+	"github.com/redhat-appstudio/remote-secret/pkg/policy"
+	"github.com/redhat-appstudio/remote-secret/pkg/rerror"
+)
+
+const (
+	// replicationCheckInterval is how often we check whether a pending transfer of the data to a replica finished.
+	replicationCheckInterval = 10 * time.Second
+	// replicationRetryInterval is how long we wait before retrying a failed replication.
+	replicationRetryInterval = 1 * time.Minute
+	// replicationTransferTimeout is how long we wait for the replica to obtain the data before giving up on the transfer.
+	replicationTransferTimeout = 5 * time.Minute
+)
+
+var (
+	replicationTransferTimedOutError = stdErrors.New("the data was not transferred to the replica in time")
+	replicationTransferFailedError   = stdErrors.New("the replica failed to process the transferred data")
+)
+
+type replicationTargetKey struct {
+	apiUrl           string
+	namespace        string
+	remoteSecretName string
+}
+
+// replicate transfers the data to the replication targets of the remote secret. This never cancels the reconciliation, so that
+// the failures to replicate don't prevent the deployment to the targets. Instead, the result asks for a later reconciliation
+// if a transfer is pending or if the replication failed.
+func (r *RemoteSecretReconciler) replicate(ctx context.Context, remoteSecret *api.RemoteSecret, data *remotesecretstorage.SecretData) stageResult[any] {
+	result := stageResult[any]{
+		Name: "replication",
+	}
+
+	statuses := make(map[replicationTargetKey]api.ReplicationTargetStatus, len(remoteSecret.Status.ReplicationTargets))
+	for _, st := range remoteSecret.Status.ReplicationTargets {
+		statuses[replicationTargetKey{apiUrl: st.ApiUrl, namespace: st.Namespace, remoteSecretName: st.RemoteSecretName}] = st
+	}
+
+	hash := r.dataFingerprint(remoteSecret, data)
+	aerr := &rerror.AggregatedError{}
+	pending := false
+
+	newStatuses := make([]api.ReplicationTargetStatus, len(remoteSecret.Spec.ReplicationTargets))
+	for i := range remoteSecret.Spec.ReplicationTargets {
+		spec := &remoteSecret.Spec.ReplicationTargets[i]
+		key := replicationTargetKey{apiUrl: spec.ApiUrl, namespace: spec.Namespace, remoteSecretName: replicaName(remoteSecret, spec)}
+
+		status, ok := statuses[key]
+		if !ok {
+			status = api.ReplicationTargetStatus{
+				Namespace:        key.namespace,
+				RemoteSecretName: key.remoteSecretName,
+				ApiUrl:           key.apiUrl,
+			}
+		}
+
+		targetPending, err := r.replicateTo(ctx, remoteSecret, spec, &status, hash, data)
+		if err != nil {
+			status.Error = err.Error()
+			aerr.Add(err)
+		} else {
+			status.Error = ""
+		}
+		pending = pending || targetPending
+
+		newStatuses[i] = status
+	}
+
+	remoteSecret.Status.ReplicationTargets = newStatuses
+
+	switch {
+	case len(newStatuses) == 0:
+		result.Condition = metav1.Condition{
+			Type:    string(api.RemoteSecretConditionTypeReplicated),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(api.RemoteSecretReasonNoTargets),
+			Message: "there are no replication targets",
+		}
+	case aerr.HasErrors():
+		log.FromContext(ctx).Error(aerr, "failed to replicate the data to some replication targets")
+		result.Condition = metav1.Condition{
+			Type:    string(api.RemoteSecretConditionTypeReplicated),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(api.RemoteSecretReasonError),
+			Message: aerr.Error(),
+		}
+		result.Cancellation.Result = ctrl.Result{RequeueAfter: replicationRetryInterval}
+	case pending:
+		result.Condition = metav1.Condition{
+			Type:    string(api.RemoteSecretConditionTypeReplicated),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(api.RemoteSecretReasonReplicationPending),
+			Message: "waiting for the replicas to obtain the data",
+		}
+		result.Cancellation.Result = ctrl.Result{RequeueAfter: replicationCheckInterval}
+	default:
+		result.Condition = metav1.Condition{
+			Type:   string(api.RemoteSecretConditionTypeReplicated),
+			Status: metav1.ConditionTrue,
+			Reason: string(api.RemoteSecretReasonReplicated),
+		}
+	}
+
+	return result
+}
+
+// replicateTo moves the replication to a single replication target forward. It either creates the transfer secret or checks
+// whether the pending transfer finished. It returns true if the transfer is still pending.
+func (r *RemoteSecretReconciler) replicateTo(ctx context.Context, remoteSecret *api.RemoteSecret, spec *api.ReplicationTarget, status *api.ReplicationTargetStatus, hash string, data *remotesecretstorage.SecretData) (bool, error) {
+	debugLog := log.FromContext(ctx).V(logs.DebugLevel).WithValues("replicationTarget", spec)
+
+	// without the fingerprint, the changes of the data cannot be detected, so the data is only replicated once even if continuous
+	if status.TransferSecretName == "" && status.LastReplicationTime != nil && (!spec.Continuous || status.ReplicatedDataHash == hash) {
+		debugLog.Info("data already replicated")
+		return false, nil
+	}
+
+	cl, err := r.TargetClientFactory.GetClient(ctx, client.ObjectKeyFromObject(remoteSecret), &api.RemoteSecretTarget{
+		Namespace:                spec.Namespace,
+		ApiUrl:                   spec.ApiUrl,
+		ClusterCredentialsSecret: spec.ClusterCredentialsSecret,
+	}, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to construct a client to use for the replication: %w", err)
+	}
+
+	if status.TransferSecretName != "" {
+		return checkTransfer(ctx, cl, status)
+	}
+
+	// the remote secret could have become disallowed by a policy since it was admitted
+	if r.PolicyChecker != nil {
+		if err := r.PolicyChecker.CheckTarget(ctx, remoteSecret, policy.ReplicationTargetLocation(spec)); err != nil {
+			return false, fmt.Errorf("the data cannot be replicated: %w", err)
+		}
+	}
+
+	transferSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: remoteSecret.Name + "-replication-",
+			Namespace:    spec.Namespace,
+			Labels: map[string]string{
+				api.UploadSecretLabel: "remotesecret",
+			},
+			Annotations: map[string]string{
+				api.RemoteSecretNameAnnotation: status.RemoteSecretName,
+			},
+		},
+		Type: remoteSecret.Spec.Secret.Type,
+		Data: *data,
+	}
+
+	auditLog := logs.AuditLog(ctx).WithValues("remoteSecret", client.ObjectKeyFromObject(remoteSecret), "replica", client.ObjectKey{Name: status.RemoteSecretName, Namespace: status.Namespace}, "apiUrl", status.ApiUrl)
+	auditLog.Info("replication of the secret data initiated")
+	if err := cl.Create(ctx, transferSecret); err != nil {
+		auditLog.Error(err, "failed to create the transfer secret")
+		return false, fmt.Errorf("failed to create the transfer secret: %w", err)
+	}
+
+	now := metav1.Now()
+	status.TransferSecretName = transferSecret.Name
+	status.TransferStartTime = &now
+	status.TransferDataHash = hash
+
+	return true, nil
+}
+
+// checkTransfer checks whether the transfer secret was processed and the replica obtained the data. The result of the processing
+// is read from the last data update in the status of the replica or, if the failed upload secrets are kept, from the transfer secret
+// itself. The transfer only succeeds once the last data update of the replica names the transfer secret, because the replica might
+// have obtained some other data in the meantime. The transfer secret is deleted if it was not processed in time.
+func checkTransfer(ctx context.Context, cl client.Client, status *api.ReplicationTargetStatus) (bool, error) {
+	timedOut := status.TransferStartTime == nil || time.Since(status.TransferStartTime.Time) > replicationTransferTimeout
+
+	finishTransfer := func() {
+		status.TransferSecretName = ""
+		status.TransferStartTime = nil
+		status.TransferDataHash = ""
+	}
+
+	transferSecret := &corev1.Secret{}
+	err := cl.Get(ctx, client.ObjectKey{Name: status.TransferSecretName, Namespace: status.Namespace}, transferSecret)
+	if err == nil {
+		if message, failed := transferSecret.Annotations[api.UploadErrorAnnotation]; failed {
+			finishTransfer()
+			return false, fmt.Errorf("%w: %s", replicationTransferFailedError, message)
+		}
+		if !timedOut {
+			return true, nil
+		}
+		if err := cl.Delete(ctx, transferSecret); err != nil && !errors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to delete the transfer secret that was not processed in time: %w", err)
+		}
+		finishTransfer()
+		return false, replicationTransferTimedOutError
+	} else if !errors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to check the state of the transfer secret: %w", err)
+	}
+
+	replica := &api.RemoteSecret{}
+	if err := cl.Get(ctx, client.ObjectKey{Name: status.RemoteSecretName, Namespace: status.Namespace}, replica); err != nil {
+		if errors.IsNotFound(err) && !timedOut {
+			// the replica might be just being created in the remote cluster and not yet visible to us
+			return true, nil
+		}
+		finishTransfer()
+		return false, fmt.Errorf("failed to get the replica to check it obtained the data: %w", err)
+	}
+
+	// the transfer secret was processed and the upload controller recorded the result in the status of the replica
+	update := replica.Status.LastDataUpdate
+	recorded := update != nil && update.Source == api.DataUpdateSourceUploadSecret && update.SourceName == status.TransferSecretName
+	if recorded && update.Result == api.DataUpdateResultFailed {
+		finishTransfer()
+		return false, fmt.Errorf("%w: %s", replicationTransferFailedError, update.Message)
+	}
+
+	if !recorded || update.Result != api.DataUpdateResultSucceeded || !meta.IsStatusConditionTrue(replica.Status.Conditions, string(api.RemoteSecretConditionTypeDataObtained)) {
+		if !timedOut {
+			return true, nil
+		}
+		finishTransfer()
+		return false, replicationTransferTimedOutError
+	}
+
+	now := metav1.Now()
+	status.ReplicatedDataHash = status.TransferDataHash
+	status.LastReplicationTime = &now
+	finishTransfer()
+
+	logs.AuditLog(ctx).Info("replication of the secret data completed", "replica", client.ObjectKeyFromObject(replica), "apiUrl", status.ApiUrl)
+
+	return false, nil
+}
+
+func replicaName(remoteSecret *api.RemoteSecret, spec *api.ReplicationTarget) string {
+	if spec.RemoteSecretName != "" {
+		return spec.RemoteSecretName
+	}
+	return remoteSecret.Name
+}
+
+// dataFingerprint identifies the version of the data. It is used to track the version of the data replicated to the replicas
+// and deployed to the targets. It is published in the status and on the deployed secrets, so it is a hash keyed with the fingerprint
+// key (or the internal key of the operator if there is none), which prevents guessing the data by hashing the candidates. It is salted
+// with the UID of the remote secret so that the same data in different remote secrets doesn't have the same fingerprint. It is computed
+// from the data itself, so it changes with the data regardless of where the data comes from.
+func (r *RemoteSecretReconciler) dataFingerprint(remoteSecret *api.RemoteSecret, data *remotesecretstorage.SecretData) string {
+	return r.dataFingerprinter.OfData(string(remoteSecret.UID), *data)
+}
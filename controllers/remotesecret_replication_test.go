@@ -0,0 +1,358 @@
+//
+// Copyright (c) 2021 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/redhat-appstudio/remote-secret/controllers/remotesecretstorage"
+	"github.com/redhat-appstudio/remote-secret/pkg/fingerprint"
+	"github.com/redhat-appstudio/remote-secret/pkg/policy"
+)
+
+type singleClientFactory struct {
+	cl client.Client
+}
+
+func (f singleClientFactory) GetClient(_ context.Context, _ client.ObjectKey, _ *api.RemoteSecretTarget, _ *api.TargetStatus) (client.Client, error) {
+	return f.cl, nil
+}
+
+func (f singleClientFactory) ServiceAccountChanged(_ client.ObjectKey) {}
+
+func TestReplicate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	assert.NoError(t, api.AddToScheme(scheme))
+
+	data := &remotesecretstorage.SecretData{"a": []byte("b")}
+	fp, err := fingerprint.New([]byte("key"))
+	assert.NoError(t, err)
+
+	newRemoteSecret := func(continuous bool) *api.RemoteSecret {
+		return &api.RemoteSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rs",
+				Namespace: "source",
+				UID:       "uid",
+			},
+			Spec: api.RemoteSecretSpec{
+				ReplicationTargets: []api.ReplicationTarget{
+					{
+						Namespace:  "replica-ns",
+						Continuous: continuous,
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("creates transfer secret", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+		r := RemoteSecretReconciler{TargetClientFactory: singleClientFactory{cl: cl}, dataFingerprinter: fp}
+		rs := newRemoteSecret(false)
+
+		result := r.replicate(context.TODO(), rs, data)
+
+		assert.Equal(t, string(api.RemoteSecretReasonReplicationPending), result.Condition.Reason)
+		assert.Equal(t, replicationCheckInterval, result.Cancellation.Result.RequeueAfter)
+		assert.False(t, result.Cancellation.Cancel)
+		assert.Len(t, rs.Status.ReplicationTargets, 1)
+
+		status := rs.Status.ReplicationTargets[0]
+		assert.Equal(t, "rs", status.RemoteSecretName)
+		assert.NotEmpty(t, status.TransferSecretName)
+		assert.Equal(t, r.dataFingerprint(rs, data), status.TransferDataHash)
+
+		transferSecret := &corev1.Secret{}
+		assert.NoError(t, cl.Get(context.TODO(), client.ObjectKey{Name: status.TransferSecretName, Namespace: "replica-ns"}, transferSecret))
+		assert.Equal(t, "remotesecret", transferSecret.Labels[api.UploadSecretLabel])
+		assert.Equal(t, "rs", transferSecret.Annotations[api.RemoteSecretNameAnnotation])
+		assert.Equal(t, []byte("b"), transferSecret.Data["a"])
+	})
+
+	newReplica := func(lastUpdateSourceName string) *api.RemoteSecret {
+		return &api.RemoteSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "replica-ns"},
+			Status: api.RemoteSecretStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:   string(api.RemoteSecretConditionTypeDataObtained),
+						Status: metav1.ConditionTrue,
+						Reason: string(api.RemoteSecretReasonDataFound),
+					},
+				},
+				LastDataUpdate: &api.DataUpdateStatus{
+					Source:     api.DataUpdateSourceUploadSecret,
+					SourceName: lastUpdateSourceName,
+					Result:     api.DataUpdateResultSucceeded,
+				},
+			},
+		}
+	}
+	pendingTransfer := func() []api.ReplicationTargetStatus {
+		now := metav1.Now()
+		return []api.ReplicationTargetStatus{
+			{
+				Namespace:          "replica-ns",
+				RemoteSecretName:   "rs",
+				TransferSecretName: "rs-replication-abcde",
+				TransferStartTime:  &now,
+				TransferDataHash:   "hash",
+			},
+		}
+	}
+
+	t.Run("waits until replica records the transfer", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newReplica("some-other-upload")).Build()
+		r := RemoteSecretReconciler{TargetClientFactory: singleClientFactory{cl: cl}, dataFingerprinter: fp}
+		rs := newRemoteSecret(false)
+		rs.Status.ReplicationTargets = pendingTransfer()
+
+		result := r.replicate(context.TODO(), rs, data)
+
+		assert.Equal(t, string(api.RemoteSecretReasonReplicationPending), result.Condition.Reason)
+		assert.Equal(t, replicationCheckInterval, result.Cancellation.Result.RequeueAfter)
+
+		status := rs.Status.ReplicationTargets[0]
+		assert.Equal(t, "rs-replication-abcde", status.TransferSecretName)
+		assert.Empty(t, status.ReplicatedDataHash)
+		assert.Nil(t, status.LastReplicationTime)
+	})
+
+	t.Run("completes when replica obtained the data", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newReplica("rs-replication-abcde")).Build()
+		r := RemoteSecretReconciler{TargetClientFactory: singleClientFactory{cl: cl}, dataFingerprinter: fp}
+		rs := newRemoteSecret(false)
+		rs.Status.ReplicationTargets = pendingTransfer()
+
+		result := r.replicate(context.TODO(), rs, data)
+
+		assert.Equal(t, metav1.ConditionTrue, result.Condition.Status)
+		assert.Equal(t, string(api.RemoteSecretReasonReplicated), result.Condition.Reason)
+		assert.Zero(t, result.Cancellation.Result.RequeueAfter)
+
+		status := rs.Status.ReplicationTargets[0]
+		assert.Empty(t, status.TransferSecretName)
+		assert.Nil(t, status.TransferStartTime)
+		assert.Equal(t, "hash", status.ReplicatedDataHash)
+		assert.NotNil(t, status.LastReplicationTime)
+	})
+
+	t.Run("reports rejected transfer", func(t *testing.T) {
+		replica := &api.RemoteSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "replica-ns"},
+			Status: api.RemoteSecretStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:   string(api.RemoteSecretConditionTypeDataObtained),
+						Status: metav1.ConditionTrue,
+						Reason: string(api.RemoteSecretReasonDataFound),
+					},
+				},
+				LastDataUpdate: &api.DataUpdateStatus{
+					Source:     api.DataUpdateSourceUploadSecret,
+					SourceName: "rs-replication-abcde",
+					Result:     api.DataUpdateResultFailed,
+					Message:    "the data is invalid",
+				},
+			},
+		}
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(replica).Build()
+		r := RemoteSecretReconciler{TargetClientFactory: singleClientFactory{cl: cl}, dataFingerprinter: fp}
+		rs := newRemoteSecret(false)
+		now := metav1.Now()
+		rs.Status.ReplicationTargets = []api.ReplicationTargetStatus{
+			{
+				Namespace:          "replica-ns",
+				RemoteSecretName:   "rs",
+				TransferSecretName: "rs-replication-abcde",
+				TransferStartTime:  &now,
+			},
+		}
+
+		result := r.replicate(context.TODO(), rs, data)
+
+		assert.Equal(t, metav1.ConditionFalse, result.Condition.Status)
+		assert.Equal(t, string(api.RemoteSecretReasonError), result.Condition.Reason)
+		assert.Equal(t, replicationRetryInterval, result.Cancellation.Result.RequeueAfter)
+		assert.Contains(t, rs.Status.ReplicationTargets[0].Error, "the data is invalid")
+		assert.Empty(t, rs.Status.ReplicationTargets[0].TransferSecretName)
+	})
+
+	t.Run("reports rejected transfer kept for debugging", func(t *testing.T) {
+		transferSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "rs-replication-abcde",
+				Namespace:   "replica-ns",
+				Annotations: map[string]string{api.UploadErrorAnnotation: "the data is invalid"},
+			},
+		}
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(transferSecret).Build()
+		r := RemoteSecretReconciler{TargetClientFactory: singleClientFactory{cl: cl}, dataFingerprinter: fp}
+		rs := newRemoteSecret(false)
+		now := metav1.Now()
+		rs.Status.ReplicationTargets = []api.ReplicationTargetStatus{
+			{
+				Namespace:          "replica-ns",
+				RemoteSecretName:   "rs",
+				TransferSecretName: "rs-replication-abcde",
+				TransferStartTime:  &now,
+			},
+		}
+
+		result := r.replicate(context.TODO(), rs, data)
+
+		assert.Equal(t, string(api.RemoteSecretReasonError), result.Condition.Reason)
+		assert.Contains(t, rs.Status.ReplicationTargets[0].Error, "the data is invalid")
+		assert.Empty(t, rs.Status.ReplicationTargets[0].TransferSecretName)
+	})
+
+	t.Run("deletes unprocessed transfer secret after timeout", func(t *testing.T) {
+		transferSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "rs-replication-abcde", Namespace: "replica-ns"},
+		}
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(transferSecret).Build()
+		r := RemoteSecretReconciler{TargetClientFactory: singleClientFactory{cl: cl}, dataFingerprinter: fp}
+		rs := newRemoteSecret(false)
+		start := metav1.NewTime(time.Now().Add(-2 * replicationTransferTimeout))
+		rs.Status.ReplicationTargets = []api.ReplicationTargetStatus{
+			{
+				Namespace:          "replica-ns",
+				RemoteSecretName:   "rs",
+				TransferSecretName: "rs-replication-abcde",
+				TransferStartTime:  &start,
+			},
+		}
+
+		result := r.replicate(context.TODO(), rs, data)
+
+		assert.Equal(t, string(api.RemoteSecretReasonError), result.Condition.Reason)
+		assert.Equal(t, replicationTransferTimedOutError.Error(), rs.Status.ReplicationTargets[0].Error)
+		err := cl.Get(context.TODO(), client.ObjectKeyFromObject(transferSecret), &corev1.Secret{})
+		assert.True(t, errors.IsNotFound(err))
+	})
+
+	t.Run("replicates changed data only if continuous", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+		r := RemoteSecretReconciler{TargetClientFactory: singleClientFactory{cl: cl}, dataFingerprinter: fp}
+		now := metav1.Now()
+
+		for _, continuous := range []bool{false, true} {
+			rs := newRemoteSecret(continuous)
+			rs.Status.ReplicationTargets = []api.ReplicationTargetStatus{
+				{
+					Namespace:           "replica-ns",
+					RemoteSecretName:    "rs",
+					ReplicatedDataHash:  "outdated",
+					LastReplicationTime: &now,
+				},
+			}
+
+			r.replicate(context.TODO(), rs, data)
+
+			assert.Equal(t, continuous, rs.Status.ReplicationTargets[0].TransferSecretName != "", "continuous: %t", continuous)
+		}
+	})
+
+	t.Run("doesn't replicate unchanged data", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+		r := RemoteSecretReconciler{TargetClientFactory: singleClientFactory{cl: cl}, dataFingerprinter: fp}
+		now := metav1.Now()
+
+		rs := newRemoteSecret(true)
+		rs.Status.ReplicationTargets = []api.ReplicationTargetStatus{
+			{
+				Namespace:           "replica-ns",
+				RemoteSecretName:    "rs",
+				ReplicatedDataHash:  fp.OfData("uid", *data),
+				LastReplicationTime: &now,
+			},
+		}
+
+		r.replicate(context.TODO(), rs, data)
+
+		assert.Empty(t, rs.Status.ReplicationTargets[0].TransferSecretName)
+	})
+
+	t.Run("refuses replication not allowed by a policy", func(t *testing.T) {
+		cl := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(&api.RemoteSecretPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+				Spec: api.RemoteSecretPolicySpec{
+					TargetNamespaces: &api.TargetNamespacesPolicy{Names: []string{"prod-*"}},
+				},
+			}).
+			Build()
+		r := RemoteSecretReconciler{TargetClientFactory: singleClientFactory{cl: cl}, PolicyChecker: &policy.Checker{Client: cl}, dataFingerprinter: fp}
+		rs := newRemoteSecret(false)
+
+		result := r.replicate(context.TODO(), rs, data)
+
+		assert.Equal(t, string(api.RemoteSecretReasonError), result.Condition.Reason)
+		assert.Contains(t, rs.Status.ReplicationTargets[0].Error, policy.PolicyViolationError.Error())
+		assert.Empty(t, rs.Status.ReplicationTargets[0].TransferSecretName)
+	})
+
+	t.Run("no targets", func(t *testing.T) {
+		r := RemoteSecretReconciler{dataFingerprinter: fp}
+		rs := newRemoteSecret(false)
+		rs.Spec.ReplicationTargets = nil
+		rs.Status.ReplicationTargets = []api.ReplicationTargetStatus{{Namespace: "replica-ns", RemoteSecretName: "rs"}}
+
+		result := r.replicate(context.TODO(), rs, data)
+
+		assert.Equal(t, string(api.RemoteSecretReasonNoTargets), result.Condition.Reason)
+		assert.Empty(t, rs.Status.ReplicationTargets)
+	})
+}
+
+func TestDataFingerprint(t *testing.T) {
+	rs := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{UID: "1"},
+		Status: api.RemoteSecretStatus{
+			SecretStatus: api.SecretStatus{Version: "42"},
+		},
+	}
+	data := &remotesecretstorage.SecretData{"a": []byte("b")}
+
+	fp, err := fingerprint.New([]byte("key"))
+	assert.NoError(t, err)
+	r := RemoteSecretReconciler{dataFingerprinter: fp}
+
+	t.Run("keyed", func(t *testing.T) {
+		assert.Equal(t, fp.OfData("1", *data), r.dataFingerprint(rs, data))
+	})
+
+	t.Run("changes with the data regardless of the storage version", func(t *testing.T) {
+		assert.NotEqual(t, r.dataFingerprint(rs, data), r.dataFingerprint(rs, &remotesecretstorage.SecretData{"a": []byte("c")}))
+
+		unversioned := rs.DeepCopy()
+		unversioned.Status.SecretStatus.Version = ""
+		assert.Equal(t, r.dataFingerprint(rs, data), r.dataFingerprint(unversioned, data))
+	})
+}
@@ -0,0 +1,98 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotesecrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+)
+
+// NewDataUpdate describes the update of the data of the remote secret that wrote the keys of the data and deleted the deleted keys.
+// The update failed if the error is not nil.
+func NewDataUpdate(source api.DataUpdateSource, sourceName string, data map[string][]byte, deletedKeys []string, reason string, err error) *api.DataUpdateStatus {
+	update := &api.DataUpdateStatus{
+		Source:     source,
+		SourceName: sourceName,
+		Time:       metav1.Now(),
+		Result:     api.DataUpdateResultSucceeded,
+	}
+
+	if err != nil {
+		update.Result = api.DataUpdateResultFailed
+		update.Reason = reason
+		update.Message = err.Error()
+	}
+
+	if len(data) > 0 {
+		update.Keys = make([]string, 0, len(data))
+		for k := range data {
+			update.Keys = append(update.Keys, k)
+		}
+		sort.Strings(update.Keys)
+	}
+
+	if len(deletedKeys) > 0 {
+		update.DeletedKeys = append([]string{}, deletedKeys...)
+		sort.Strings(update.DeletedKeys)
+	}
+
+	return update
+}
+
+// RecordDataUpdate stores the description of the data update in the status of the remote secret. Only the LastDataUpdate is
+// patched, so that the concurrent changes of the status by the controller are not overwritten.
+func RecordDataUpdate(ctx context.Context, cl client.Client, remoteSecret *api.RemoteSecret, update *api.DataUpdateStatus) error {
+	orig := remoteSecret.DeepCopy()
+	remoteSecret.Status.LastDataUpdate = update
+	if err := cl.Status().Patch(ctx, remoteSecret, client.MergeFrom(orig)); err != nil {
+		return fmt.Errorf("failed to record the data update in the status of the remote secret: %w", err)
+	}
+	return nil
+}
+
+// SetPendingDataUpdate stores the description of the data update in the LastDataUpdateAnnotation of the remote secret. This is used
+// by the webhook that cannot set the status of the remote secret.
+func SetPendingDataUpdate(remoteSecret *api.RemoteSecret, update *api.DataUpdateStatus) error {
+	raw, err := json.Marshal(update)
+	if err != nil {
+		return fmt.Errorf("failed to serialize the data update: %w", err)
+	}
+	if remoteSecret.Annotations == nil {
+		remoteSecret.Annotations = map[string]string{}
+	}
+	remoteSecret.Annotations[api.LastDataUpdateAnnotation] = string(raw)
+	return nil
+}
+
+// PendingDataUpdate returns the description of the data update stored in the LastDataUpdateAnnotation of the remote secret or nil
+// if there is none.
+func PendingDataUpdate(remoteSecret *api.RemoteSecret) (*api.DataUpdateStatus, error) {
+	raw, ok := remoteSecret.Annotations[api.LastDataUpdateAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	update := &api.DataUpdateStatus{}
+	if err := json.Unmarshal([]byte(raw), update); err != nil {
+		return nil, fmt.Errorf("failed to parse the %s annotation: %w", api.LastDataUpdateAnnotation, err)
+	}
+	return update, nil
+}
@@ -0,0 +1,70 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotesecrets
+
+import (
+	"errors"
+	"testing"
+
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDataUpdate(t *testing.T) {
+	t.Run("succeeded", func(t *testing.T) {
+		update := NewDataUpdate(api.DataUpdateSourceUploadSecret, "upload", map[string][]byte{"b": nil, "a": nil}, []string{"d", "c"}, "ignored", nil)
+
+		assert.Equal(t, api.DataUpdateSourceUploadSecret, update.Source)
+		assert.Equal(t, "upload", update.SourceName)
+		assert.Equal(t, api.DataUpdateResultSucceeded, update.Result)
+		assert.Empty(t, update.Reason)
+		assert.Empty(t, update.Message)
+		assert.Equal(t, []string{"a", "b"}, update.Keys)
+		assert.Equal(t, []string{"c", "d"}, update.DeletedKeys)
+		assert.False(t, update.Time.IsZero())
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		update := NewDataUpdate(api.DataUpdateSourceDataAPI, "", nil, nil, "invalid_data", errors.New("the data is invalid"))
+
+		assert.Equal(t, api.DataUpdateResultFailed, update.Result)
+		assert.Equal(t, "invalid_data", update.Reason)
+		assert.Equal(t, "the data is invalid", update.Message)
+		assert.Nil(t, update.Keys)
+		assert.Nil(t, update.DeletedKeys)
+	})
+}
+
+func TestPendingDataUpdate(t *testing.T) {
+	rs := &api.RemoteSecret{}
+
+	update, err := PendingDataUpdate(rs)
+	assert.NoError(t, err)
+	assert.Nil(t, update)
+
+	expected := NewDataUpdate(api.DataUpdateSourceWebhook, "", map[string][]byte{"a": nil}, nil, "", nil)
+	assert.NoError(t, SetPendingDataUpdate(rs, expected))
+
+	update, err = PendingDataUpdate(rs)
+	assert.NoError(t, err)
+	assert.Equal(t, expected.Keys, update.Keys)
+	assert.Equal(t, expected.Source, update.Source)
+	// the time is serialized with the precision of seconds
+	assert.Equal(t, expected.Time.Unix(), update.Time.Unix())
+
+	rs.Annotations[api.LastDataUpdateAnnotation] = "not json"
+	_, err = PendingDataUpdate(rs)
+	assert.Error(t, err)
+}
@@ -16,6 +16,7 @@ package remotesecretstorage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
@@ -25,14 +26,23 @@ import (
 
 type SecretData = map[string][]byte
 
+// maxPartialUpdateAttempts is the number of times the partial update is attempted when the data is changed concurrently.
+const maxPartialUpdateAttempts = 5
+
 // RemoteSecretStorage is a specialized secret storage for remote secrets.
 type RemoteSecretStorage interface {
 	secretstorage.TypedSecretStorage[api.RemoteSecret, SecretData]
 
 	// PartialUpdate merges the data already present in the remote secret with the "dataUpdates".
 	// New keys will be added, existing keys updated and keys from the "deleteKeys" array will be
-	// removed from the data.
+	// removed from the data. The update is retried when the data is changed concurrently, so that no concurrent change is lost.
+	// The secret storages that don't support the compare-and-swap cannot guarantee that, so the update fails with an error
+	// wrapping the secretstorage.CompareAndSwapNotSupportedError there.
 	PartialUpdate(ctx context.Context, id *api.RemoteSecret, dataUpdates *SecretData, deleteKeys []string) error
+
+	// PartialUpdateIfVersion is like PartialUpdate but it only updates the data if it has the expected version. An error
+	// wrapping the secretstorage.ConflictError is returned otherwise.
+	PartialUpdateIfVersion(ctx context.Context, id *api.RemoteSecret, dataUpdates *SecretData, deleteKeys []string, expectedVersion string) error
 }
 
 // NewJSONSerializingRemoteSecretStorage is a convenience function to construct a RemoteSecretStorage instance
@@ -61,23 +71,69 @@ type remoteSecretStorage struct {
 var _ RemoteSecretStorage = (*remoteSecretStorage)(nil)
 
 func (rss *remoteSecretStorage) PartialUpdate(ctx context.Context, id *api.RemoteSecret, dataUpdates *SecretData, deleteKeys []string) error {
-	currentData, err := rss.Get(ctx, id)
+	var err error
+	for attempt := 0; attempt < maxPartialUpdateAttempts; attempt++ {
+		var currentData *SecretData
+		var version string
+		currentData, version, err = rss.GetVersioned(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get the remote secret %s for partial update: %w", client.ObjectKeyFromObject(id), err)
+		}
+
+		mergeData(currentData, dataUpdates, deleteKeys)
+
+		_, err = rss.CompareAndSwap(ctx, id, currentData, version)
+		if !errors.Is(err, secretstorage.ConflictError) {
+			break
+		}
+	}
+
+	if errors.Is(err, secretstorage.CompareAndSwapNotSupportedError) {
+		return fmt.Errorf("failed to perform the partial update, because it could lose the concurrent changes of the data: %w", err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to perform the partial update: %w", err)
+	}
+	return nil
+}
+
+func (rss *remoteSecretStorage) PartialUpdateIfVersion(ctx context.Context, id *api.RemoteSecret, dataUpdates *SecretData, deleteKeys []string, expectedVersion string) error {
+	currentData, version, err := rss.GetVersioned(ctx, id)
 	if err != nil {
 		return fmt.Errorf("failed to get the remote secret %s for partial update: %w", client.ObjectKeyFromObject(id), err)
 	}
+	if version != expectedVersion {
+		return fmt.Errorf("failed to perform the partial update: %w: expected version '%s' but found '%s'", secretstorage.ConflictError, expectedVersion, version)
+	}
+
+	mergeData(currentData, dataUpdates, deleteKeys)
+
+	if _, err := rss.CompareAndSwap(ctx, id, currentData, version); err != nil {
+		return fmt.Errorf("failed to perform the partial update: %w", err)
+	}
+	return nil
+}
 
+// StorageWriteRejectionReason returns the reason of the upload rejection metric for the error returned from writing the data
+// to the storage.
+func StorageWriteRejectionReason(err error) string {
+	if errors.Is(err, secretstorage.ConflictError) {
+		return "version_conflict"
+	}
+	if errors.Is(err, secretstorage.CompareAndSwapNotSupportedError) {
+		return "partial_update_not_supported"
+	}
+	return "storage_write_failed"
+}
+
+func mergeData(data *SecretData, dataUpdates *SecretData, deleteKeys []string) {
 	if dataUpdates != nil {
 		for k, v := range *dataUpdates {
-			(*currentData)[k] = v
+			(*data)[k] = v
 		}
 	}
 
 	for _, k := range deleteKeys {
-		delete(*currentData, k)
+		delete(*data, k)
 	}
-
-	if err := rss.Store(ctx, id, currentData); err != nil {
-		return fmt.Errorf("failed to perform the partial update: %w", err)
-	}
-	return nil
 }
@@ -16,6 +16,8 @@ package remotesecretstorage
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
@@ -80,3 +82,64 @@ func TestPartialUpdate(t *testing.T) {
 		assert.Equal(t, (*updated)["k1"], []byte("v1"))
 	})
 }
+
+func TestPartialUpdateIfVersion(t *testing.T) {
+	rss := NewJSONSerializingRemoteSecretStorage(&memorystorage.MemoryStorage{})
+	id := &api.RemoteSecret{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+			UID:       "asdf",
+		},
+	}
+	assert.NoError(t, rss.Store(context.TODO(), id, &SecretData{"k1": []byte("v1")}))
+	_, version, err := rss.GetVersioned(context.TODO(), id)
+	assert.NoError(t, err)
+
+	t.Run("updates with matching version", func(t *testing.T) {
+		assert.NoError(t, rss.PartialUpdateIfVersion(context.TODO(), id, &SecretData{"k2": []byte("v2")}, nil, version))
+
+		updated, newVersion, err := rss.GetVersioned(context.TODO(), id)
+		assert.NoError(t, err)
+		assert.Len(t, *updated, 2)
+		assert.NotEqual(t, version, newVersion)
+	})
+
+	t.Run("fails with stale version", func(t *testing.T) {
+		err := rss.PartialUpdateIfVersion(context.TODO(), id, nil, []string{"k1"}, version)
+		assert.ErrorIs(t, err, secretstorage.ConflictError)
+
+		updated, err := rss.Get(context.TODO(), id)
+		assert.NoError(t, err)
+		assert.Len(t, *updated, 2)
+	})
+}
+
+func TestPartialUpdateWithoutCompareAndSwap(t *testing.T) {
+	storage := &memorystorage.MemoryStorage{}
+	rss := NewJSONSerializingRemoteSecretStorage(&secretstorage.TestSecretStorage{
+		GetImpl:   storage.Get,
+		StoreImpl: storage.Store,
+	})
+	id := &api.RemoteSecret{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+			UID:       "asdf",
+		},
+	}
+	assert.NoError(t, rss.Store(context.TODO(), id, &SecretData{"k1": []byte("v1")}))
+
+	err := rss.PartialUpdate(context.TODO(), id, &SecretData{"k2": []byte("v2")}, nil)
+	assert.ErrorIs(t, err, secretstorage.CompareAndSwapNotSupportedError)
+	assert.Equal(t, "partial_update_not_supported", StorageWriteRejectionReason(err))
+
+	updated, err := rss.Get(context.TODO(), id)
+	assert.NoError(t, err)
+	assert.Equal(t, SecretData{"k1": []byte("v1")}, *updated)
+}
+
+func TestStorageWriteRejectionReason(t *testing.T) {
+	assert.Equal(t, "version_conflict", StorageWriteRejectionReason(fmt.Errorf("failed: %w", secretstorage.ConflictError)))
+	assert.Equal(t, "storage_write_failed", StorageWriteRejectionReason(errors.New("kaboom")))
+}
@@ -0,0 +1,132 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/redhat-appstudio/remote-secret/pkg/logs"
+	"github.com/redhat-appstudio/remote-secret/pkg/metrics"
+	"github.com/redhat-appstudio/remote-secret/pkg/quota"
+	kuberrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//+kubebuilder:rbac:groups=appstudio.redhat.com,resources=remotesecretusages,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups=appstudio.redhat.com,resources=remotesecretusages/status,verbs=get;update
+
+// RemoteSecretUsageReconciler maintains the RemoteSecretUsage object in each namespace with remote secrets and the corresponding
+// metrics. The reconciliation requests are keyed by the namespace and the fixed name of the usage object.
+type RemoteSecretUsageReconciler struct {
+	client.Client
+	// Quota is the quota reported in the usage objects.
+	Quota *api.RemoteSecretQuota
+}
+
+var _ reconcile.Reconciler = (*RemoteSecretUsageReconciler)(nil)
+
+func (r *RemoteSecretUsageReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := ctrl.NewControllerManagedBy(mgr).
+		Named("remotesecretusage").
+		Watches(&api.RemoteSecret{}, handler.EnqueueRequestsFromMapFunc(usageRequestForNamespace)).
+		Watches(&api.RemoteSecretUsage{}, handler.EnqueueRequestsFromMapFunc(usageRequestForNamespace)).
+		Complete(r); err != nil {
+		return fmt.Errorf("failed to configure the remote secret usage reconciler: %w", err)
+	}
+	return nil
+}
+
+func usageRequestForNamespace(_ context.Context, o client.Object) []reconcile.Request {
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Name: api.RemoteSecretUsageName, Namespace: o.GetNamespace()}}}
+}
+
+// Reconcile implements reconcile.Reconciler
+func (r *RemoteSecretUsageReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	lg := log.FromContext(ctx)
+
+	if req.Name != api.RemoteSecretUsageName {
+		// this can only happen if someone creates a usage object with another name. We just ignore it.
+		return reconcile.Result{}, nil
+	}
+
+	list := &api.RemoteSecretList{}
+	if err := r.List(ctx, list, client.InNamespace(req.Namespace)); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to list the remote secrets in the namespace %s: %w", req.Namespace, err)
+	}
+
+	usage := &api.RemoteSecretUsage{}
+	if err := r.Get(ctx, req.NamespacedName, usage); err != nil {
+		if !kuberrors.IsNotFound(err) {
+			return reconcile.Result{}, fmt.Errorf("failed to get the remote secret usage in the namespace %s: %w", req.Namespace, err)
+		}
+		usage = nil
+	}
+
+	if len(list.Items) == 0 {
+		metrics.DeleteNamespaceUsageMetric(req.Namespace)
+		if usage != nil {
+			lg.V(logs.DebugLevel).Info("deleting the remote secret usage from a namespace without remote secrets")
+			if err := r.Delete(ctx, usage); client.IgnoreNotFound(err) != nil {
+				return reconcile.Result{}, fmt.Errorf("failed to delete the remote secret usage in the namespace %s: %w", req.Namespace, err)
+			}
+		}
+		return reconcile.Result{}, nil
+	}
+
+	status := computeUsage(list.Items)
+	if r.Quota != nil {
+		status.Quota = *r.Quota
+	}
+	metrics.UpdateNamespaceUsageMetric(req.Namespace, &status)
+
+	if usage == nil {
+		usage = &api.RemoteSecretUsage{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      api.RemoteSecretUsageName,
+				Namespace: req.Namespace,
+			},
+		}
+		if err := r.Create(ctx, usage); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to create the remote secret usage in the namespace %s: %w", req.Namespace, err)
+		}
+	} else if usage.Status == status {
+		return reconcile.Result{}, nil
+	}
+
+	usage.Status = status
+	if err := r.Status().Update(ctx, usage); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to update the status of the remote secret usage in the namespace %s: %w", req.Namespace, err)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+func computeUsage(remoteSecrets []api.RemoteSecret) api.RemoteSecretUsageStatus {
+	status := api.RemoteSecretUsageStatus{
+		RemoteSecrets: int32(len(remoteSecrets)),
+	}
+	for i := range remoteSecrets {
+		status.Targets += int32(quota.TargetCount(&remoteSecrets[i]))
+		status.StoredBytes += remoteSecrets[i].Status.SecretStatus.Size
+	}
+	return status
+}
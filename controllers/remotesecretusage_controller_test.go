@@ -0,0 +1,101 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	kuberrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestRemoteSecretUsageReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, api.AddToScheme(scheme))
+
+	rs := func(name string, size int64, targets int) *api.RemoteSecret {
+		ret := &api.RemoteSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			Status: api.RemoteSecretStatus{
+				SecretStatus: api.SecretStatus{Size: size},
+			},
+		}
+		for i := 0; i < targets; i++ {
+			ret.Spec.Targets = append(ret.Spec.Targets, api.RemoteSecretTarget{Namespace: "target"})
+		}
+		return ret
+	}
+
+	replicating := rs("b", 5, 1)
+	replicating.Spec.ReplicationTargets = []api.ReplicationTarget{{Namespace: "replica"}}
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(rs("a", 10, 1), replicating).
+		WithStatusSubresource(&api.RemoteSecretUsage{}).
+		Build()
+
+	r := &RemoteSecretUsageReconciler{
+		Client: cl,
+		Quota:  &api.RemoteSecretQuota{MaxRemoteSecrets: 5},
+	}
+
+	key := client.ObjectKey{Name: api.RemoteSecretUsageName, Namespace: "ns"}
+
+	_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+	assert.NoError(t, err)
+
+	usage := &api.RemoteSecretUsage{}
+	assert.NoError(t, cl.Get(context.TODO(), key, usage))
+	assert.Equal(t, api.RemoteSecretUsageStatus{
+		RemoteSecrets: 2,
+		Targets:       3,
+		StoredBytes:   15,
+		Quota:         api.RemoteSecretQuota{MaxRemoteSecrets: 5},
+	}, usage.Status)
+
+	t.Run("updates the usage", func(t *testing.T) {
+		assert.NoError(t, cl.Delete(context.TODO(), rs("b", 0, 0)))
+
+		_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+		assert.NoError(t, err)
+
+		assert.NoError(t, cl.Get(context.TODO(), key, usage))
+		assert.Equal(t, int32(1), usage.Status.RemoteSecrets)
+		assert.Equal(t, int32(1), usage.Status.Targets)
+		assert.Equal(t, int64(10), usage.Status.StoredBytes)
+	})
+
+	t.Run("deletes the usage without remote secrets", func(t *testing.T) {
+		assert.NoError(t, cl.Delete(context.TODO(), rs("a", 0, 0)))
+
+		_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+		assert.NoError(t, err)
+
+		assert.True(t, kuberrors.IsNotFound(cl.Get(context.TODO(), key, usage)))
+	})
+
+	t.Run("ignores other names", func(t *testing.T) {
+		_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: client.ObjectKey{Name: "other", Namespace: "ns"}})
+		assert.NoError(t, err)
+	})
+}
@@ -21,11 +21,14 @@ import (
 	"github.com/redhat-appstudio/remote-secret/controllers/bindings"
 	"github.com/redhat-appstudio/remote-secret/controllers/remotesecretstorage"
 	"github.com/redhat-appstudio/remote-secret/pkg/config"
+	"github.com/redhat-appstudio/remote-secret/pkg/externalsource"
+	"github.com/redhat-appstudio/remote-secret/pkg/policy"
+	"github.com/redhat-appstudio/remote-secret/pkg/quota"
 	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 )
 
-func SetupAllReconcilers(mgr controllerruntime.Manager, cfg *config.OperatorConfiguration, secretStorage secretstorage.SecretStorage, cf bindings.ClientFactory) error {
+func SetupAllReconcilers(mgr controllerruntime.Manager, cfg *config.OperatorConfiguration, secretStorage secretstorage.SecretStorage, cf bindings.ClientFactory, externalSource *externalsource.Source) error {
 	ctx := context.Background()
 
 	remoteSecretStorage := remotesecretstorage.NewJSONSerializingRemoteSecretStorage(secretStorage)
@@ -39,6 +42,10 @@ func SetupAllReconcilers(mgr controllerruntime.Manager, cfg *config.OperatorConf
 		Scheme:              mgr.GetScheme(),
 		Configuration:       cfg,
 		RemoteSecretStorage: remoteSecretStorage,
+		Recorder:            mgr.GetEventRecorderFor("remotesecret-controller"),
+		PolicyChecker:       &policy.Checker{Client: mgr.GetClient()},
+		ExternalSource:      externalSource,
+		QuotaChecker:        &quota.Checker{Client: mgr.GetClient(), Quota: &cfg.Quota},
 	}).SetupWithManager(mgr); err != nil {
 		return err
 	}
@@ -48,9 +55,19 @@ func SetupAllReconcilers(mgr controllerruntime.Manager, cfg *config.OperatorConf
 	}
 
 	if err := (&TokenUploadReconciler{
-		Client:              mgr.GetClient(),
-		Scheme:              mgr.GetScheme(),
-		RemoteSecretStorage: remoteSecretStorage,
+		Client:                mgr.GetClient(),
+		Scheme:                mgr.GetScheme(),
+		RemoteSecretStorage:   remoteSecretStorage,
+		Recorder:              mgr.GetEventRecorderFor("remotesecret-controller"),
+		QuotaChecker:          &quota.Checker{Client: mgr.GetClient(), Quota: &cfg.Quota},
+		FailedUploadRetention: cfg.FailedUploadRetention,
+	}).SetupWithManager(mgr); err != nil {
+		return err
+	}
+
+	if err := (&RemoteSecretUsageReconciler{
+		Client: mgr.GetClient(),
+		Quota:  &cfg.Quota,
 	}).SetupWithManager(mgr); err != nil {
 		return err
 	}
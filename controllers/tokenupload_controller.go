@@ -30,16 +30,23 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/redhat-appstudio/remote-secret/controllers/remotesecrets"
 	"github.com/redhat-appstudio/remote-secret/controllers/remotesecretstorage"
 	"github.com/redhat-appstudio/remote-secret/pkg/commaseparated"
 	"github.com/redhat-appstudio/remote-secret/pkg/logs"
+	"github.com/redhat-appstudio/remote-secret/pkg/quota"
+	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage"
 )
 
 var uploadSecretSelector = metav1.LabelSelector{
@@ -54,9 +61,11 @@ var uploadSecretSelector = metav1.LabelSelector{
 
 var remoteSecretDoesntExist = errors.New("remote secret does not exist")
 var remoteSecretNilNoError = errors.New("unexpected state: both remote secret and error is nil")
+var remoteSecretFollowsAnother = errors.New("the remote secret follows the data of other remote secrets")
+var remoteSecretHasExternalSource = errors.New("the data of the remote secret is read from an external source")
 var metricOperationNameLabel = "secret_data_upload"
 
-//+kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;watch;create;update;list;delete
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=remotesecrets,verbs=get;list;watch;create;update;patch;delete
 
@@ -65,6 +74,12 @@ type TokenUploadReconciler struct {
 	client.Client
 	Scheme              *runtime.Scheme
 	RemoteSecretStorage remotesecretstorage.RemoteSecretStorage
+	Recorder            record.EventRecorder
+	// QuotaChecker checks the size of the uploaded data against the quota. If nil, the quota is not checked.
+	QuotaChecker *quota.Checker
+	// FailedUploadRetention is how long the upload secrets whose data failed to be uploaded are kept in the cluster for debugging.
+	// They are deleted immediately if zero.
+	FailedUploadRetention time.Duration
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -76,6 +91,7 @@ func (r *TokenUploadReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	if err := ctrl.NewControllerManagedBy(mgr).
 		For(&corev1.Secret{}, builder.WithPredicates(pred)).
+		Watches(&api.RemoteSecret{}, handler.EnqueueRequestsFromMapFunc(r.findUploadSecretsForRemoteSecret), builder.WithPredicates(remoteSecretResumedPredicate)).
 		Complete(r); err != nil {
 		err = fmt.Errorf("failed to build the controller manager: %w", err)
 		return err
@@ -83,6 +99,50 @@ func (r *TokenUploadReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return nil
 }
 
+// remoteSecretResumedPredicate only lets through the updates of remote secrets that stop being suspended.
+var remoteSecretResumedPredicate = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldRs, ok := e.ObjectOld.(*api.RemoteSecret)
+		if !ok {
+			return false
+		}
+		newRs, ok := e.ObjectNew.(*api.RemoteSecret)
+		if !ok {
+			return false
+		}
+		return oldRs.Spec.Suspend && !newRs.Spec.Suspend
+	},
+}
+
+// findUploadSecretsForRemoteSecret finds the upload secrets that target the provided remote secret. This is used to process
+// the upload secrets that were left in place while the remote secret was suspended.
+func (r *TokenUploadReconciler) findUploadSecretsForRemoteSecret(ctx context.Context, o client.Object) []reconcile.Request {
+	lg := log.FromContext(ctx)
+
+	selector, err := metav1.LabelSelectorAsSelector(&uploadSecretSelector)
+	if err != nil {
+		lg.Error(err, "failed to construct the upload secret selector. This should not happen")
+		return nil
+	}
+
+	list := &corev1.SecretList{}
+	if err := r.List(ctx, list, client.InNamespace(o.GetNamespace()), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		lg.Error(err, "failed to list the upload secrets of a resumed remote secret", "remoteSecret", client.ObjectKeyFromObject(o))
+		return nil
+	}
+
+	var ret []reconcile.Request
+	for i := range list.Items {
+		if list.Items[i].Annotations[api.RemoteSecretNameAnnotation] == o.GetName() {
+			ret = append(ret, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&list.Items[i])})
+		}
+	}
+	return ret
+}
+
 func (r *TokenUploadReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	lg := log.FromContext(ctx)
 	lg.V(logs.DebugLevel).Info("starting reconciliation")
@@ -103,48 +163,110 @@ func (r *TokenUploadReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, nil
 	}
 
+	// The failed upload secrets are only kept for debugging and are not processed again.
+	if failedAt, ok := uploadSecret.Annotations[api.UploadFailedAtAnnotation]; ok {
+		return r.expireFailedUploadSecret(ctx, uploadSecret, failedAt)
+	}
+
+	// The data of a suspended remote secret must not change, so we leave the upload secret in place. It is processed once
+	// the remote secret is resumed. If the remote secret cannot be found, the error is handled by the reconcileRemoteSecret below.
+	if remoteSecret, findErr := r.findRemoteSecret(ctx, uploadSecret); findErr == nil && remoteSecret != nil && remoteSecret.Spec.Suspend {
+		lg.Info("the remote secret is suspended, leaving the upload secret in place until it is resumed", "remoteSecret", client.ObjectKeyFromObject(remoteSecret))
+		return ctrl.Result{}, nil
+	}
+
 	// We first find/create the RemoteSecret since we need it to store the data. Only after we have stored the data
 	// in secretStorage can we delete the uploadSecret. The deletion triggers RS reconciliation in which the data is
 	// fetched from the storage and propagated to the targets by RS controller.
-	err := r.reconcileRemoteSecret(ctx, uploadSecret)
+	remoteSecret, err := r.reconcileRemoteSecret(ctx, uploadSecret)
 
-	// we immediately delete the Secret
-	delErr := r.Delete(ctx, uploadSecret)
-	if delErr != nil {
-		// We failed to delete the secret, so we error out so that we can try again in the next reconciliation round.
-		// We therefore also DON'T create the error event in this case like we do later on in this method.
-		return ctrl.Result{}, fmt.Errorf("cannot delete the Secret: %w", err)
+	var result ctrl.Result
+	if err != nil && r.FailedUploadRetention > 0 {
+		// we keep the failed upload secret for debugging, but mark it so that it is not processed again
+		if uploadSecret.Annotations == nil {
+			uploadSecret.Annotations = map[string]string{}
+		}
+		uploadSecret.Annotations[api.UploadFailedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		uploadSecret.Annotations[api.UploadErrorAnnotation] = err.Error()
+		if updErr := r.Update(ctx, uploadSecret); updErr != nil {
+			// We failed to mark the secret, so we error out so that we can try again in the next reconciliation round.
+			return ctrl.Result{}, fmt.Errorf("cannot mark the Secret as failed: %w", updErr)
+		}
+		result.RequeueAfter = r.FailedUploadRetention
+	} else {
+		// we immediately delete the Secret
+		delErr := r.Delete(ctx, uploadSecret)
+		if delErr != nil {
+			// We failed to delete the secret, so we error out so that we can try again in the next reconciliation round.
+			// We therefore also DON'T create the error event in this case like we do later on in this method.
+			return ctrl.Result{}, fmt.Errorf("cannot delete the Secret: %w", err)
+		}
 	}
 
 	// NOTE: it is useless to return any error to the controller runtime after this point, because we just
-	// deleted the secret that is being reconciled and so its repeated reconciliation would short-circuit
-	// on the non-nil DeletionTimestamp. Therefore, in case of errors, we just create the error event and
-	// return a "success" to the controller runtime.
+	// deleted (or marked as failed) the secret that is being reconciled and so its repeated reconciliation would
+	// short-circuit. Therefore, in case of errors, we just record the error events and return a "success" to the
+	// controller runtime.
+
+	if remoteSecret != nil {
+		var deletedKeys []string
+		if _, partialUpdate := uploadSecret.Annotations[api.RemoteSecretPartialUpdateAnnotation]; partialUpdate {
+			deletedKeys = commaseparated.Value(uploadSecret.Annotations[api.RemoteSecretDeletedKeysAnnotation]).Values()
+		}
+		update := remotesecrets.NewDataUpdate(api.DataUpdateSourceUploadSecret, uploadSecret.Name, uploadSecret.Data, deletedKeys, uploadRejectionReason(err), err)
+		if recErr := remotesecrets.RecordDataUpdate(ctx, r.Client, remoteSecret, update); recErr != nil {
+			lg.Error(recErr, "failed to record the result of the upload in the status of the remote secret")
+		}
+	}
 
 	if err != nil {
-		r.createErrorEvent(ctx, uploadSecret, err, lg)
+		lg.Error(err, "failed to process the upload secret")
+		// the uploader can find out about the failure from the event on the upload secret even if it cannot read the remote secret
+		r.Recorder.Event(uploadSecret, corev1.EventTypeWarning, api.RemoteSecretEventReasonDataUploadFailed, err.Error())
+		if remoteSecret != nil {
+			r.Recorder.Eventf(remoteSecret, corev1.EventTypeWarning, api.RemoteSecretEventReasonDataUploadFailed, "failed to process the upload secret %s: %s", uploadSecret.Name, err.Error())
+		}
 	} else {
-		r.tryDeleteEvent(ctx, uploadSecret.Name, req.Namespace, lg)
+		r.Recorder.Eventf(remoteSecret, corev1.EventTypeNormal, api.RemoteSecretEventReasonDataUploaded, "the data was uploaded using the upload secret %s", uploadSecret.Name)
 	}
 
+	return result, nil
+}
+
+// expireFailedUploadSecret deletes the failed upload secret once its retention period passes.
+func (r *TokenUploadReconciler) expireFailedUploadSecret(ctx context.Context, uploadSecret *corev1.Secret, failedAt string) (ctrl.Result, error) {
+	failedTime, err := time.Parse(time.RFC3339, failedAt)
+	if err != nil {
+		log.FromContext(ctx).Info("the time of the failure of the upload secret is not valid, deleting it now", "failedAt", failedAt)
+		failedTime = time.Time{}
+	}
+
+	if remaining := time.Until(failedTime.Add(r.FailedUploadRetention)); remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	if err = r.Delete(ctx, uploadSecret); err != nil && !kuberrors.IsNotFound(err) {
+		return ctrl.Result{}, fmt.Errorf("cannot delete the failed upload Secret: %w", err)
+	}
 	return ctrl.Result{}, nil
 }
 
-func (r *TokenUploadReconciler) reconcileRemoteSecret(ctx context.Context, uploadSecret *corev1.Secret) error {
+// reconcileRemoteSecret stores the data of the upload secret in the remote secret, creating the remote secret if needed. The remote
+// secret is returned even if storing the data fails, unless it couldn't be found or created.
+func (r *TokenUploadReconciler) reconcileRemoteSecret(ctx context.Context, uploadSecret *corev1.Secret) (*api.RemoteSecret, error) {
 	_, partialUpdate := uploadSecret.Annotations[api.RemoteSecretPartialUpdateAnnotation]
+	expectedVersion, versioned := uploadSecret.Annotations[api.ExpectedDataVersionAnnotation]
 	auditLog := logs.AuditLog(ctx).WithValues("partialUpdate", partialUpdate)
 	auditLog.Info("reconciling upload secret")
 	remoteSecret, err := r.findRemoteSecret(ctx, uploadSecret)
 	if err != nil {
-		metrics.UploadRejectionsCounter.WithLabelValues(metricOperationNameLabel, "find_remote_secret_failed").Inc()
-		return fmt.Errorf("attempt to find the remote secret failed: %w", err)
+		return nil, rejectUpload("find_remote_secret_failed", fmt.Errorf("attempt to find the remote secret failed: %w", err))
 
 	}
 	if !partialUpdate && remoteSecret == nil {
 		remoteSecret, err = r.createRemoteSecret(ctx, uploadSecret)
 		if err != nil {
-			metrics.UploadRejectionsCounter.WithLabelValues(metricOperationNameLabel, "create_remote_secret_failed").Inc()
-			return fmt.Errorf("failed to create the remote secret: %w", err)
+			return nil, rejectUpload("create_remote_secret_failed", fmt.Errorf("failed to create the remote secret: %w", err))
 		}
 	}
 
@@ -152,77 +274,165 @@ func (r *TokenUploadReconciler) reconcileRemoteSecret(ctx context.Context, uploa
 	// When we're doing a partial update, the remote secret is not created though, and therefore
 	// we need to check that we have a remote secret to work with here before continuing.
 	if remoteSecret == nil {
-		return remoteSecretDoesntExist
+		return nil, remoteSecretDoesntExist
 	}
 	auditLog = auditLog.WithValues("remoteSecret", client.ObjectKeyFromObject(remoteSecret))
 
+	if sources := remoteSecret.FollowedRemoteSecrets(); len(sources) > 0 {
+		auditLog.Info("data upload rejected because the remote secret follows other remote secrets", "followedRemoteSecrets", sources)
+		return remoteSecret, rejectUpload("remote_secret_follows", fmt.Errorf("cannot upload the data to the remote secret: %w", remoteSecretFollowsAnother))
+	}
+
+	if remoteSecret.Spec.ExternalSource != nil {
+		auditLog.Info("data upload rejected because the data of the remote secret is read from an external source")
+		return remoteSecret, rejectUpload("remote_secret_has_external_source", fmt.Errorf("cannot upload the data to the remote secret: %w", remoteSecretHasExternalSource))
+	}
+
 	if partialUpdate {
 		auditLog.Info("manual secret partial update initiated", "action", "UPDATE")
 
 		keysToDelete := commaseparated.Value(uploadSecret.Annotations[api.RemoteSecretDeletedKeysAnnotation]).Values()
 
-		if err = r.RemoteSecretStorage.PartialUpdate(ctx, remoteSecret, &uploadSecret.Data, keysToDelete); err != nil {
+		if err = remoteSecret.ValidateSecretDataValues(uploadSecret.Data); err != nil {
+			auditLog.Info("manual secret partial update not started because of invalid upload secret")
+			return remoteSecret, rejectUpload("invalid_upload_secret", fmt.Errorf("validation of upload secret failed: %w ", err))
+		}
+
+		merged, err := r.partiallyUpdatedData(ctx, remoteSecret, uploadSecret.Data, keysToDelete)
+		if err != nil {
+			return remoteSecret, rejectUpload("storage_read_failed", err)
+		}
+
+		if r.QuotaChecker != nil {
+			if err = r.QuotaChecker.CheckDataSize(ctx, remoteSecret, merged); err != nil {
+				auditLog.Info("manual secret partial update not started because of the quota")
+				return remoteSecret, rejectUpload(quotaRejectionReason(err), fmt.Errorf("the partial update cannot be stored: %w", err))
+			}
+		}
+
+		if err = r.RemoteSecretStorage.CheckDataSize(&merged); err != nil {
+			auditLog.Info("manual secret partial update not started because the data is too large for the secret storage")
+			return remoteSecret, rejectUpload(dataSizeRejectionReason(err), fmt.Errorf("the partial update cannot be stored: %w", err))
+		}
+
+		if versioned {
+			err = r.RemoteSecretStorage.PartialUpdateIfVersion(ctx, remoteSecret, &uploadSecret.Data, keysToDelete, expectedVersion)
+		} else {
+			err = r.RemoteSecretStorage.PartialUpdate(ctx, remoteSecret, &uploadSecret.Data, keysToDelete)
+		}
+		if err != nil {
 			err = fmt.Errorf("failed to partially update the secret data: %w", err)
 			auditLog.Error(err, "manual secret partial update failed")
-			return err
+			return remoteSecret, rejectUpload(remotesecretstorage.StorageWriteRejectionReason(err), err)
 		}
 		auditLog.Info("manual secret partial update completed")
 	} else {
+		uploadSecret.Data = remoteSecret.ApplySecretDataDefaults(uploadSecret.Data)
 		err = remoteSecret.ValidateUploadSecret(uploadSecret)
 		if err != nil {
 			auditLog.Info("manual secret upload not started because of invalid upload secret")
-			metrics.UploadRejectionsCounter.WithLabelValues(metricOperationNameLabel, "invalid_upload_secret").Inc()
-			return fmt.Errorf("validation of upload secret failed: %w ", err)
+			return remoteSecret, rejectUpload("invalid_upload_secret", fmt.Errorf("validation of upload secret failed: %w ", err))
+		}
+
+		if r.QuotaChecker != nil {
+			if err = r.QuotaChecker.CheckDataSize(ctx, remoteSecret, uploadSecret.Data); err != nil {
+				auditLog.Info("manual secret upload not started because of the quota")
+				return remoteSecret, rejectUpload(quotaRejectionReason(err), fmt.Errorf("the uploaded data cannot be stored: %w", err))
+			}
+		}
+
+		if err = r.RemoteSecretStorage.CheckDataSize(&uploadSecret.Data); err != nil {
+			auditLog.Info("manual secret upload not started because the data is too large for the secret storage")
+			return remoteSecret, rejectUpload(dataSizeRejectionReason(err), fmt.Errorf("the uploaded data cannot be stored: %w", err))
 		}
 
 		auditLog.Info("manual secret upload initiated", "action", "UPDATE")
-		if err = r.RemoteSecretStorage.Store(ctx, remoteSecret, &uploadSecret.Data); err != nil {
+		if versioned {
+			_, err = r.RemoteSecretStorage.CompareAndSwap(ctx, remoteSecret, &uploadSecret.Data, expectedVersion)
+		} else {
+			err = r.RemoteSecretStorage.Store(ctx, remoteSecret, &uploadSecret.Data)
+		}
+		if err != nil {
 			err = fmt.Errorf("failed to store the remote secret data: %w", err)
-			metrics.UploadRejectionsCounter.WithLabelValues(metricOperationNameLabel, "storage_write_failed").Inc()
 			auditLog.Error(err, "manual secret upload failed")
-			return err
+			return remoteSecret, rejectUpload(remotesecretstorage.StorageWriteRejectionReason(err), err)
 		}
 		auditLog.Info("manual secret upload completed")
 	}
 
-	return nil
+	return remoteSecret, nil
 }
 
-// reports error in both Log and Event in current Namespace
-func (r *TokenUploadReconciler) createErrorEvent(ctx context.Context, secret *corev1.Secret, err error, lg logr.Logger) {
-	r.tryDeleteEvent(ctx, secret.Name, secret.Namespace, lg)
+// partiallyUpdatedData returns the data of the remote secret as it will look like after the partial update so that its size can be
+// checked before the update. Because the upload secret only contains the changes, the current data needs to be read from the storage.
+func (r *TokenUploadReconciler) partiallyUpdatedData(ctx context.Context, remoteSecret *api.RemoteSecret, updates map[string][]byte, keysToDelete []string) (map[string][]byte, error) {
+	current, err := r.RemoteSecretStorage.Get(ctx, remoteSecret)
+	if err != nil && !errors.Is(err, secretstorage.NotFoundError) {
+		return nil, fmt.Errorf("failed to read the current data to check the size of the partial update: %w", err)
+	}
 
-	secretErrEvent := &corev1.Event{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      secret.Name,
-			Namespace: secret.Namespace,
-		},
-		Message:        err.Error(),
-		Reason:         "cannot process upload secret",
-		InvolvedObject: corev1.ObjectReference{Namespace: secret.Namespace, Name: secret.Name, Kind: secret.Kind, APIVersion: secret.APIVersion},
-		Type:           "Error",
-		LastTimestamp:  metav1.NewTime(time.Now()),
+	merged := map[string][]byte{}
+	if current != nil {
+		for k, v := range *current {
+			merged[k] = v
+		}
+	}
+	// the same order as in the PartialUpdate of the storage
+	for k, v := range updates {
+		merged[k] = v
+	}
+	for _, k := range keysToDelete {
+		delete(merged, k)
 	}
 
-	err = r.Create(ctx, secretErrEvent)
-	if err != nil {
-		lg.Error(err, "event creation failed for upload secret")
+	return merged, nil
+}
+
+// uploadRejection is the error of a failed upload together with the reason used in the metric of the rejected uploads and in
+// the status of the remote secret.
+type uploadRejection struct {
+	reason string
+	err    error
+}
+
+func (e *uploadRejection) Error() string {
+	return e.err.Error()
+}
+
+func (e *uploadRejection) Unwrap() error {
+	return e.err
+}
+
+// rejectUpload records the rejection of the upload in the metrics and returns the error with the reason of the rejection.
+func rejectUpload(reason string, err error) error {
+	metrics.UploadRejectionsCounter.WithLabelValues(metricOperationNameLabel, reason).Inc()
+	return &uploadRejection{reason: reason, err: err}
+}
+
+// uploadRejectionReason returns the reason of the rejection of the upload that failed with the error.
+func uploadRejectionReason(err error) string {
+	var rejection *uploadRejection
+	if errors.As(err, &rejection) {
+		return rejection.reason
 	}
+	return "upload_failed"
 }
 
-// Contract: having exactly one event if upload failed and no events if uploaded.
-// For this need to delete the event every attempt
-func (r *TokenUploadReconciler) tryDeleteEvent(ctx context.Context, secretName string, ns string, lg logr.Logger) {
-	stored := &corev1.Event{}
-	err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: ns}, stored)
+// quotaRejectionReason returns the reason of the rejection metric for the error returned from the quota check.
+func quotaRejectionReason(err error) string {
+	if errors.Is(err, quota.QuotaExceededError) {
+		return "quota_exceeded"
+	}
+	return "quota_check_failed"
+}
 
-	if err == nil {
-		lg.V(logs.DebugLevel).Info("event found and will be deleted", "event.name", stored.Name)
-		err = r.Delete(ctx, stored)
-		if err != nil {
-			lg.Error(err, "can not delete event")
-		}
+// dataSizeRejectionReason returns the reason of the rejection metric for the error returned from the check of the data size
+// against the capabilities of the secret storage.
+func dataSizeRejectionReason(err error) string {
+	if errors.Is(err, secretstorage.DataTooLargeError) {
+		return "data_too_large"
 	}
+	return "data_size_check_failed"
 }
 
 func (r *TokenUploadReconciler) findRemoteSecret(ctx context.Context, uploadSecret *corev1.Secret) (*api.RemoteSecret, error) {
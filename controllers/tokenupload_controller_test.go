@@ -17,8 +17,11 @@ package controllers
 import (
 	"context"
 	"testing"
+	"time"
 
 	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/redhat-appstudio/remote-secret/controllers/remotesecretstorage"
+	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage/memorystorage"
 
 	"github.com/stretchr/testify/assert"
 
@@ -27,7 +30,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 func TestCreateRemoteSecret(t *testing.T) {
@@ -87,3 +95,204 @@ func TestCreateRemoteSecret(t *testing.T) {
 	assert.NotNil(t, rs3)
 	assert.Equal(t, rs1.Name, rs3.Name)
 }
+
+func TestUploadToSuspendedRemoteSecret(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, api.AddToScheme(scheme))
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	uploadSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-remote-secret-upload",
+			Namespace: "default",
+			Labels: map[string]string{
+				api.UploadSecretLabel: "remotesecret",
+			},
+			Annotations: map[string]string{
+				api.RemoteSecretNameAnnotation: "test-remote-secret",
+			},
+		},
+		Data: map[string][]byte{
+			"a": []byte("b"),
+		},
+	}
+
+	otherUploadSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-remote-secret-upload",
+			Namespace: "default",
+			Labels: map[string]string{
+				api.UploadSecretLabel: "remotesecret",
+			},
+			Annotations: map[string]string{
+				api.RemoteSecretNameAnnotation: "other-remote-secret",
+			},
+		},
+	}
+
+	remoteSecret := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-remote-secret",
+			Namespace: "default",
+		},
+		Spec: api.RemoteSecretSpec{
+			Suspend: true,
+		},
+	}
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(uploadSecret, otherUploadSecret, remoteSecret).
+		Build()
+
+	// the storage is nil so any attempt to store the data would panic
+	r := TokenUploadReconciler{
+		Client: cl,
+		Scheme: scheme,
+	}
+
+	t.Run("leaves the upload secret in place", func(t *testing.T) {
+		_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(uploadSecret)})
+		assert.NoError(t, err)
+
+		assert.NoError(t, cl.Get(context.TODO(), client.ObjectKeyFromObject(uploadSecret), &corev1.Secret{}))
+	})
+
+	t.Run("finds the upload secrets on resume", func(t *testing.T) {
+		reqs := r.findUploadSecretsForRemoteSecret(context.TODO(), remoteSecret)
+		assert.Equal(t, []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(uploadSecret)}}, reqs)
+	})
+
+	t.Run("reacts only on resume", func(t *testing.T) {
+		resumed := remoteSecret.DeepCopy()
+		resumed.Spec.Suspend = false
+
+		assert.True(t, remoteSecretResumedPredicate.Update(event.UpdateEvent{ObjectOld: remoteSecret, ObjectNew: resumed}))
+		assert.False(t, remoteSecretResumedPredicate.Update(event.UpdateEvent{ObjectOld: resumed, ObjectNew: remoteSecret}))
+		assert.False(t, remoteSecretResumedPredicate.Update(event.UpdateEvent{ObjectOld: remoteSecret, ObjectNew: remoteSecret}))
+		assert.False(t, remoteSecretResumedPredicate.Create(event.CreateEvent{Object: resumed}))
+	})
+}
+
+func TestUploadOutcome(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, api.AddToScheme(scheme))
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	uploadSecret := func(name string, data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					api.UploadSecretLabel: "remotesecret",
+				},
+				Annotations: map[string]string{
+					api.RemoteSecretNameAnnotation: "test-remote-secret",
+				},
+			},
+			Data: data,
+		}
+	}
+
+	remoteSecret := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-remote-secret",
+			Namespace: "default",
+		},
+		Spec: api.RemoteSecretSpec{
+			Secret: api.LinkableSecretSpec{RequiredKeys: []api.SecretKey{{Name: "token"}}},
+		},
+	}
+
+	valid := uploadSecret("valid", map[string][]byte{"token": []byte("tkn"), "a": []byte("b")})
+	invalid := uploadSecret("invalid", map[string][]byte{"a": []byte("b")})
+	expired := uploadSecret("expired", nil)
+	expired.Annotations[api.UploadFailedAtAnnotation] = time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(valid, invalid, expired, remoteSecret).
+		WithStatusSubresource(&api.RemoteSecret{}).
+		Build()
+
+	storage := remotesecretstorage.NewJSONSerializingRemoteSecretStorage(&memorystorage.MemoryStorage{})
+	assert.NoError(t, storage.Initialize(context.TODO()))
+
+	r := TokenUploadReconciler{
+		Client:                cl,
+		Scheme:                scheme,
+		RemoteSecretStorage:   storage,
+		Recorder:              record.NewFakeRecorder(10),
+		FailedUploadRetention: time.Hour,
+	}
+
+	lastDataUpdate := func(t *testing.T) *api.DataUpdateStatus {
+		rs := &api.RemoteSecret{}
+		assert.NoError(t, cl.Get(context.TODO(), client.ObjectKeyFromObject(remoteSecret), rs))
+		if !assert.NotNil(t, rs.Status.LastDataUpdate) {
+			return &api.DataUpdateStatus{}
+		}
+		return rs.Status.LastDataUpdate
+	}
+
+	t.Run("records the successful upload", func(t *testing.T) {
+		res, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(valid)})
+		assert.NoError(t, err)
+		assert.Zero(t, res.RequeueAfter)
+
+		update := lastDataUpdate(t)
+		assert.Equal(t, api.DataUpdateSourceUploadSecret, update.Source)
+		assert.Equal(t, "valid", update.SourceName)
+		assert.Equal(t, api.DataUpdateResultSucceeded, update.Result)
+		assert.Equal(t, []string{"a", "token"}, update.Keys)
+		assert.Empty(t, update.Reason)
+	})
+
+	t.Run("records the failed upload and keeps the upload secret", func(t *testing.T) {
+		res, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(invalid)})
+		assert.NoError(t, err)
+		assert.Equal(t, time.Hour, res.RequeueAfter)
+
+		update := lastDataUpdate(t)
+		assert.Equal(t, "invalid", update.SourceName)
+		assert.Equal(t, api.DataUpdateResultFailed, update.Result)
+		assert.Equal(t, "invalid_upload_secret", update.Reason)
+		assert.NotEmpty(t, update.Message)
+
+		kept := &corev1.Secret{}
+		assert.NoError(t, cl.Get(context.TODO(), client.ObjectKeyFromObject(invalid), kept))
+		assert.NotEmpty(t, kept.Annotations[api.UploadFailedAtAnnotation])
+		assert.Equal(t, update.Message, kept.Annotations[api.UploadErrorAnnotation])
+
+		// the kept secret is not processed again until its retention passes
+		res, err = r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(invalid)})
+		assert.NoError(t, err)
+		assert.Greater(t, res.RequeueAfter, time.Duration(0))
+		assert.NoError(t, cl.Get(context.TODO(), client.ObjectKeyFromObject(invalid), &corev1.Secret{}))
+	})
+
+	t.Run("deletes the failed upload secret after the retention", func(t *testing.T) {
+		_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(expired)})
+		assert.NoError(t, err)
+		assert.Error(t, cl.Get(context.TODO(), client.ObjectKeyFromObject(expired), &corev1.Secret{}))
+	})
+
+	t.Run("rejects the upload with stale expected version", func(t *testing.T) {
+		stale := uploadSecret("stale", map[string][]byte{"token": []byte("other")})
+		stale.Annotations[api.ExpectedDataVersionAnnotation] = ""
+		assert.NoError(t, cl.Create(context.TODO(), stale))
+
+		_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(stale)})
+		assert.NoError(t, err)
+
+		update := lastDataUpdate(t)
+		assert.Equal(t, "stale", update.SourceName)
+		assert.Equal(t, api.DataUpdateResultFailed, update.Result)
+		assert.Equal(t, "version_conflict", update.Reason)
+
+		data, err := storage.Get(context.TODO(), remoteSecret)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("tkn"), (*data)["token"])
+	})
+}
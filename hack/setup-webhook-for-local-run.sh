@@ -6,6 +6,8 @@
 # creates/patches the MutatingWebhookConfiguration so that requests are routed out of minikube into the localhost where
 # controller (with webhook) will be running.
 
+# The conversion webhook of the RemoteSecret CRD is patched the same way.
+
 # Note that if you change your mind and want to run the controller in cluster, you will have to create/restore the original
 # MutatingWebhookConfiguration. The easiest way is to run `make deploy_minikube`
 
@@ -25,3 +27,10 @@ yq eval '
   .webhooks[0].clientConfig.caBundle = strenv(CA_BUNDLE)
 ' "${THIS_DIR}/../config/webhook/base/manifests.yaml" \
 | kubectl apply -f -
+
+kubectl patch crd remotesecrets.appstudio.redhat.com --type=json -p "[
+  {\"op\": \"replace\", \"path\": \"/spec/conversion/webhook/clientConfig\", \"value\": {
+    \"url\": \"https://host.minikube.internal:9443/convert\",
+    \"caBundle\": \"${CA_BUNDLE}\"
+  }}
+]"
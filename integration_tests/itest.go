@@ -48,16 +48,16 @@ var ITest = struct {
 }{}
 
 type TestClientFactory struct {
-	GetClientImpl             func(ctx context.Context, currentNamespace string, targetSpec *v1beta1.RemoteSecretTarget, targetStatus *v1beta1.TargetStatus) (client.Client, error)
+	GetClientImpl             func(ctx context.Context, remoteSecret client.ObjectKey, targetSpec *v1beta1.RemoteSecretTarget, targetStatus *v1beta1.TargetStatus) (client.Client, error)
 	ServiceAccountChangedImpl func(client.ObjectKey)
 }
 
 // GetClient implements bindings.ClientFactory
-func (tcf *TestClientFactory) GetClient(ctx context.Context, currentNamespace string, targetSpec *v1beta1.RemoteSecretTarget, targetStatus *v1beta1.TargetStatus) (client.Client, error) {
+func (tcf *TestClientFactory) GetClient(ctx context.Context, remoteSecret client.ObjectKey, targetSpec *v1beta1.RemoteSecretTarget, targetStatus *v1beta1.TargetStatus) (client.Client, error) {
 	if tcf.GetClientImpl == nil {
 		return nil, nil
 	}
-	return tcf.GetClientImpl(ctx, currentNamespace, targetSpec, targetStatus)
+	return tcf.GetClientImpl(ctx, remoteSecret, targetSpec, targetStatus)
 }
 
 // ServiceAccountChanged implements bindings.ClientFactory
@@ -95,6 +95,14 @@ func (i *ITestStorage) PartialUpdate(ctx context.Context, id *api.RemoteSecret,
 
 }
 
+func (i *ITestStorage) PartialUpdateIfVersion(ctx context.Context, id *api.RemoteSecret, dataUpdates *remotesecretstorage.SecretData, deleteKeys []string, expectedVersion string) error {
+	err := i.remoteSecretStorage.PartialUpdateIfVersion(ctx, id, dataUpdates, deleteKeys, expectedVersion)
+	if err != nil {
+		return fmt.Errorf("partial update error: %w", err)
+	}
+	return nil
+}
+
 func (i *ITestStorage) Initialize(ctx context.Context) error {
 	i.memoryStorage = &memorystorage.MemoryStorage{}
 	i.remoteSecretStorage = remotesecretstorage.NewJSONSerializingRemoteSecretStorage(i.memoryStorage)
@@ -125,6 +133,22 @@ func (i *ITestStorage) Get(ctx context.Context, id *api.RemoteSecret) (*remotese
 	return data, nil
 }
 
+func (i *ITestStorage) GetVersioned(ctx context.Context, id *api.RemoteSecret) (*remotesecretstorage.SecretData, string, error) {
+	data, version, err := i.remoteSecretStorage.GetVersioned(ctx, id)
+	if err != nil {
+		return nil, "", fmt.Errorf("get error: %w", err)
+	}
+	return data, version, nil
+}
+
+func (i *ITestStorage) CompareAndSwap(ctx context.Context, id *api.RemoteSecret, data *remotesecretstorage.SecretData, expectedVersion string) (string, error) {
+	version, err := i.remoteSecretStorage.CompareAndSwap(ctx, id, data, expectedVersion)
+	if err != nil {
+		return "", fmt.Errorf("store error: %w", err)
+	}
+	return version, nil
+}
+
 func (i *ITestStorage) Delete(ctx context.Context, id *api.RemoteSecret) error {
 	err := i.remoteSecretStorage.Delete(ctx, id)
 	if err != nil {
@@ -134,6 +158,13 @@ func (i *ITestStorage) Delete(ctx context.Context, id *api.RemoteSecret) error {
 
 }
 
+func (i *ITestStorage) CheckDataSize(data *remotesecretstorage.SecretData) error {
+	if err := i.remoteSecretStorage.CheckDataSize(data); err != nil {
+		return fmt.Errorf("check data size error: %w", err)
+	}
+	return nil
+}
+
 // SecretStorage returns backend storage
 func (i *ITestStorage) SecretStorage() secretstorage.SecretStorage {
 	return i.memoryStorage
@@ -172,6 +172,18 @@ var _ = Describe("RemoteSecret", func() {
 							Value:     1,
 						},
 					})
+
+					rs := *crenv.First[*api.RemoteSecret](&test.InCluster)
+					g.Expect(meta.IsStatusConditionTrue(rs.Status.Conditions, string(api.RemoteSecretConditionTypeReady))).To(BeTrue())
+					g.Expect(rs.Status.ObservedGeneration).To(Equal(rs.Generation))
+					g.Expect(rs.Status.Targets).To(HaveLen(1))
+					g.Expect(meta.IsStatusConditionTrue(rs.Status.Targets[0].Conditions, string(api.TargetConditionTypeReady))).To(BeTrue())
+					g.Expect(rs.Status.Targets[0].LastSyncTime).NotTo(BeNil())
+					g.Expect(rs.Status.Targets[0].ObservedGeneration).To(Equal(rs.Generation))
+					g.Expect(rs.Status.Targets[0].DataFingerprint).NotTo(BeEmpty())
+					g.Expect(rs.Status.Targets[0].DeployedSecret.Annotations).To(HaveKeyWithValue(api.DataFingerprintAnnotation, rs.Status.Targets[0].DataFingerprint))
+					g.Expect(rs.Status.SecretStatus.KeyDetails).NotTo(BeEmpty())
+					g.Expect(rs.Status.SecretStatus.FingerprintKeyID).NotTo(BeEmpty())
 				})
 			})
 		})
@@ -712,6 +724,357 @@ var _ = Describe("RemoteSecret", func() {
 		})
 	})
 
+	Describe("Dry run", func() {
+		var test crenv.TestSetup
+		var targetA, targetB string
+
+		BeforeEach(func() {
+			targetA = string(uuid.NewUUID())
+			targetB = string(uuid.NewUUID())
+			Expect(ITest.Client.Create(ITest.Context, &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: targetA},
+			})).To(Succeed())
+			Expect(ITest.Client.Create(ITest.Context, &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: targetB},
+			})).To(Succeed())
+
+			test = crenv.TestSetup{
+				ToCreate: []client.Object{
+					&api.RemoteSecret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "test-remote-secret",
+							Namespace: "default",
+						},
+						Spec: api.RemoteSecretSpec{
+							Secret: api.LinkableSecretSpec{
+								Name: "injected-secret",
+							},
+							Targets: []api.RemoteSecretTarget{{
+								Namespace: targetA,
+							}},
+						},
+					},
+				},
+				ReconciliationTrigger: remoteSecretReconciliationTrigger,
+			}
+
+			test.BeforeEach(ITest.Context, ITest.Client, nil)
+			rs := *crenv.First[*api.RemoteSecret](&test.InCluster)
+			Expect(rs).NotTo(BeNil())
+			Expect(ITest.Storage.Store(ITest.Context, rs, &remotesecretstorage.SecretData{
+				"a": []byte("b"),
+			})).To(Succeed())
+
+			test.ReconcileWithCluster(ITest.Context, func(g Gomega) {
+				g.Expect(ITest.Client.Get(ITest.Context, client.ObjectKey{Name: "injected-secret", Namespace: targetA}, &corev1.Secret{})).To(Succeed())
+			})
+		})
+
+		AfterEach(func() {
+			test.AfterEach(ITest.Context)
+		})
+
+		It("reports the plan without changing the targets", func() {
+			rs := *crenv.First[*api.RemoteSecret](&test.InCluster)
+			Expect(rs).NotTo(BeNil())
+			rs.Annotations = map[string]string{api.DryRunAnnotation: "true"}
+			rs.Spec.Targets = []api.RemoteSecretTarget{{Namespace: targetB}}
+			Expect(ITest.Client.Update(ITest.Context, rs)).To(Succeed())
+
+			test.SettleWithCluster(ITest.Context, func(g Gomega) {
+				rs = *crenv.First[*api.RemoteSecret](&test.InCluster)
+				g.Expect(rs.Status.DryRunPlan).NotTo(BeNil())
+				g.Expect(rs.Status.DryRunPlan.Targets).To(ConsistOf(
+					And(
+						HaveField("Namespace", targetB),
+						HaveField("Action", api.PlannedActionCreate),
+						HaveField("SecretName", "injected-secret"),
+					),
+					And(
+						HaveField("Namespace", targetA),
+						HaveField("Action", api.PlannedActionDelete),
+						HaveField("SecretName", "injected-secret"),
+					),
+				))
+				g.Expect(ITest.Client.Get(ITest.Context, client.ObjectKey{Name: "injected-secret", Namespace: targetA}, &corev1.Secret{})).To(Succeed())
+				g.Expect(ITest.Client.Get(ITest.Context, client.ObjectKey{Name: "injected-secret", Namespace: targetB}, &corev1.Secret{})).NotTo(Succeed())
+			})
+		})
+
+		It("deploys once the annotation is removed", func() {
+			rs := *crenv.First[*api.RemoteSecret](&test.InCluster)
+			Expect(rs).NotTo(BeNil())
+			rs.Annotations = map[string]string{api.DryRunAnnotation: "true"}
+			rs.Spec.Targets = []api.RemoteSecretTarget{{Namespace: targetB}}
+			Expect(ITest.Client.Update(ITest.Context, rs)).To(Succeed())
+
+			test.SettleWithCluster(ITest.Context, func(g Gomega) {
+				rs = *crenv.First[*api.RemoteSecret](&test.InCluster)
+				g.Expect(rs.Status.DryRunPlan).NotTo(BeNil())
+			})
+
+			delete(rs.Annotations, api.DryRunAnnotation)
+			Expect(ITest.Client.Update(ITest.Context, rs)).To(Succeed())
+
+			test.SettleWithCluster(ITest.Context, func(g Gomega) {
+				rs = *crenv.First[*api.RemoteSecret](&test.InCluster)
+				g.Expect(rs.Status.DryRunPlan).To(BeNil())
+				g.Expect(ITest.Client.Get(ITest.Context, client.ObjectKey{Name: "injected-secret", Namespace: targetA}, &corev1.Secret{})).NotTo(Succeed())
+				g.Expect(ITest.Client.Get(ITest.Context, client.ObjectKey{Name: "injected-secret", Namespace: targetB}, &corev1.Secret{})).To(Succeed())
+			})
+		})
+	})
+
+	Describe("Suspend", func() {
+		var test crenv.TestSetup
+		var targetA, targetB string
+
+		BeforeEach(func() {
+			targetA = string(uuid.NewUUID())
+			targetB = string(uuid.NewUUID())
+			Expect(ITest.Client.Create(ITest.Context, &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: targetA},
+			})).To(Succeed())
+			Expect(ITest.Client.Create(ITest.Context, &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: targetB},
+			})).To(Succeed())
+
+			test = crenv.TestSetup{
+				ToCreate: []client.Object{
+					&api.RemoteSecret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "test-remote-secret",
+							Namespace: "default",
+						},
+						Spec: api.RemoteSecretSpec{
+							Secret: api.LinkableSecretSpec{
+								Name: "injected-secret",
+							},
+							Targets: []api.RemoteSecretTarget{{
+								Namespace: targetA,
+							}},
+						},
+					},
+				},
+				ReconciliationTrigger: remoteSecretReconciliationTrigger,
+			}
+
+			test.BeforeEach(ITest.Context, ITest.Client, nil)
+			rs := *crenv.First[*api.RemoteSecret](&test.InCluster)
+			Expect(rs).NotTo(BeNil())
+			Expect(ITest.Storage.Store(ITest.Context, rs, &remotesecretstorage.SecretData{
+				"a": []byte("b"),
+			})).To(Succeed())
+
+			test.ReconcileWithCluster(ITest.Context, func(g Gomega) {
+				g.Expect(ITest.Client.Get(ITest.Context, client.ObjectKey{Name: "injected-secret", Namespace: targetA}, &corev1.Secret{})).To(Succeed())
+			})
+		})
+
+		AfterEach(func() {
+			test.AfterEach(ITest.Context)
+		})
+
+		It("doesn't change the targets while suspended and catches up on resume", func() {
+			rs := *crenv.First[*api.RemoteSecret](&test.InCluster)
+			Expect(rs).NotTo(BeNil())
+			rs.Spec.Suspend = true
+			rs.Spec.Targets = []api.RemoteSecretTarget{{Namespace: targetB}}
+			Expect(ITest.Client.Update(ITest.Context, rs)).To(Succeed())
+
+			test.SettleWithCluster(ITest.Context, func(g Gomega) {
+				rs = *crenv.First[*api.RemoteSecret](&test.InCluster)
+				cond := meta.FindStatusCondition(rs.Status.Conditions, string(api.RemoteSecretConditionTypeSuspended))
+				g.Expect(cond).NotTo(BeNil())
+				g.Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+				g.Expect(ITest.Client.Get(ITest.Context, client.ObjectKey{Name: "injected-secret", Namespace: targetA}, &corev1.Secret{})).To(Succeed())
+				g.Expect(ITest.Client.Get(ITest.Context, client.ObjectKey{Name: "injected-secret", Namespace: targetB}, &corev1.Secret{})).NotTo(Succeed())
+			})
+
+			Expect(ITest.Storage.Store(ITest.Context, rs, &remotesecretstorage.SecretData{
+				"a": []byte("c"),
+			})).To(Succeed())
+
+			rs.Spec.Suspend = false
+			Expect(ITest.Client.Update(ITest.Context, rs)).To(Succeed())
+
+			test.SettleWithCluster(ITest.Context, func(g Gomega) {
+				rs = *crenv.First[*api.RemoteSecret](&test.InCluster)
+				cond := meta.FindStatusCondition(rs.Status.Conditions, string(api.RemoteSecretConditionTypeSuspended))
+				g.Expect(cond).NotTo(BeNil())
+				g.Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				g.Expect(cond.Reason).To(Equal(string(api.RemoteSecretReasonResumed)))
+				g.Expect(ITest.Client.Get(ITest.Context, client.ObjectKey{Name: "injected-secret", Namespace: targetA}, &corev1.Secret{})).NotTo(Succeed())
+				secret := &corev1.Secret{}
+				g.Expect(ITest.Client.Get(ITest.Context, client.ObjectKey{Name: "injected-secret", Namespace: targetB}, secret)).To(Succeed())
+				g.Expect(secret.Data["a"]).To(Equal([]byte("c")))
+			})
+		})
+	})
+
+	Describe("Policy", func() {
+		var test crenv.TestSetup
+		var targetA, targetB string
+		var policy *api.RemoteSecretPolicy
+
+		BeforeEach(func() {
+			targetA = string(uuid.NewUUID())
+			targetB = string(uuid.NewUUID())
+			Expect(ITest.Client.Create(ITest.Context, &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: targetA},
+			})).To(Succeed())
+			Expect(ITest.Client.Create(ITest.Context, &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: targetB},
+			})).To(Succeed())
+
+			test = crenv.TestSetup{
+				ToCreate: []client.Object{
+					&api.RemoteSecret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "test-remote-secret",
+							Namespace: "default",
+						},
+						Spec: api.RemoteSecretSpec{
+							Secret: api.LinkableSecretSpec{
+								Name: "injected-secret",
+							},
+							Targets: []api.RemoteSecretTarget{{
+								Namespace: targetA,
+							}, {
+								Namespace: targetB,
+							}},
+						},
+					},
+				},
+				ReconciliationTrigger: remoteSecretReconciliationTrigger,
+			}
+
+			test.BeforeEach(ITest.Context, ITest.Client, nil)
+			rs := *crenv.First[*api.RemoteSecret](&test.InCluster)
+			Expect(rs).NotTo(BeNil())
+			Expect(ITest.Storage.Store(ITest.Context, rs, &remotesecretstorage.SecretData{
+				"a": []byte("b"),
+			})).To(Succeed())
+
+			test.ReconcileWithCluster(ITest.Context, func(g Gomega) {
+				g.Expect(ITest.Client.Get(ITest.Context, client.ObjectKey{Name: "injected-secret", Namespace: targetB}, &corev1.Secret{})).To(Succeed())
+			})
+
+			// the policy is created after the remote secret so that the webhook doesn't reject it
+			policy = &api.RemoteSecretPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-policy",
+				},
+				Spec: api.RemoteSecretPolicySpec{
+					TargetNamespaces: &api.TargetNamespacesPolicy{
+						Names: []string{targetA},
+					},
+				},
+			}
+			Expect(ITest.Client.Create(ITest.Context, policy)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(ITest.Client.Delete(ITest.Context, policy)).To(Succeed())
+			test.AfterEach(ITest.Context)
+		})
+
+		It("refuses to deploy to the disallowed targets", func() {
+			test.SettleWithCluster(ITest.Context, func(g Gomega) {
+				rs := *crenv.First[*api.RemoteSecret](&test.InCluster)
+				g.Expect(rs.Status.Targets).To(HaveLen(2))
+				for _, ts := range rs.Status.Targets {
+					cond := meta.FindStatusCondition(ts.Conditions, string(api.TargetConditionTypeReady))
+					g.Expect(cond).NotTo(BeNil())
+					if ts.Namespace == targetA {
+						g.Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+					} else {
+						g.Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+						g.Expect(cond.Reason).To(Equal(string(api.TargetReasonPolicyViolation)))
+						g.Expect(ts.Error).To(ContainSubstring("is not allowed by the remote secret policy 'test-policy'"))
+					}
+				}
+			})
+		})
+
+		It("rejects new disallowed targets in the webhook", func() {
+			rs := *crenv.First[*api.RemoteSecret](&test.InCluster)
+			Expect(rs).NotTo(BeNil())
+			rs.Spec.Targets = append(rs.Spec.Targets, api.RemoteSecretTarget{Namespace: "other"})
+			err := ITest.Client.Update(ITest.Context, rs)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the target namespace 'other' is not allowed by the remote secret policy 'test-policy'"))
+		})
+	})
+
+	Describe("Adoption", func() {
+		var test crenv.TestSetup
+		var targetNs string
+
+		BeforeEach(func() {
+			targetNs = string(uuid.NewUUID())
+			Expect(ITest.Client.Create(ITest.Context, &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: targetNs},
+			})).To(Succeed())
+			Expect(ITest.Client.Create(ITest.Context, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "existing-secret",
+					Namespace: targetNs,
+				},
+				Data: map[string][]byte{
+					"a": []byte("b"),
+				},
+			})).To(Succeed())
+
+			test = crenv.TestSetup{
+				ToCreate: []client.Object{
+					&api.RemoteSecret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "test-remote-secret",
+							Namespace: "default",
+						},
+						Spec: api.RemoteSecretSpec{
+							Secret: api.LinkableSecretSpec{
+								Name: "existing-secret",
+							},
+							Targets: []api.RemoteSecretTarget{{
+								Namespace: targetNs,
+							}},
+							Adoption: api.SecretAdoption{
+								Policy:     api.AdoptionPolicyIfUnmanaged,
+								ImportData: true,
+							},
+						},
+					},
+				},
+				ReconciliationTrigger: remoteSecretReconciliationTrigger,
+			}
+
+			test.BeforeEach(ITest.Context, ITest.Client, nil)
+		})
+
+		AfterEach(func() {
+			test.AfterEach(ITest.Context)
+		})
+
+		It("imports the data of the existing secret and adopts it", func() {
+			test.SettleWithCluster(ITest.Context, func(g Gomega) {
+				rs := *crenv.First[*api.RemoteSecret](&test.InCluster)
+				g.Expect(rs).NotTo(BeNil())
+				g.Expect(meta.IsStatusConditionTrue(rs.Status.Conditions, string(api.RemoteSecretConditionTypeDataObtained))).To(BeTrue())
+				g.Expect(rs.Status.SecretStatus.Keys).To(Equal([]string{"a"}))
+
+				data, err := ITest.Storage.Get(ITest.Context, rs)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect((*data)["a"]).To(Equal([]byte("b")))
+
+				secret := &corev1.Secret{}
+				g.Expect(ITest.Client.Get(ITest.Context, client.ObjectKey{Name: "existing-secret", Namespace: targetNs}, secret)).To(Succeed())
+				g.Expect(secret.Annotations).To(HaveKeyWithValue(api.ManagingRemoteSecretNameAnnotation, client.ObjectKeyFromObject(rs).String()))
+			})
+		})
+	})
+
 	Describe("READ", func() {
 		When("data in storage", func() {
 			test := crenv.TestSetup{
@@ -743,8 +1106,9 @@ var _ = Describe("RemoteSecret", func() {
 				test.ReconcileWithCluster(ITest.Context, func(g Gomega) {
 					rs := *crenv.First[*api.RemoteSecret](&test.InCluster)
 					g.Expect(rs).NotTo(BeNil())
-					g.Expect(rs.Status.Conditions).To(HaveLen(2))
+					g.Expect(rs.Status.Conditions).To(HaveLen(4))
 					g.Expect(meta.IsStatusConditionTrue(rs.Status.Conditions, string(api.RemoteSecretConditionTypeDataObtained))).To(BeTrue())
+					g.Expect(meta.IsStatusConditionTrue(rs.Status.Conditions, string(api.RemoteSecretConditionTypeDataValid))).To(BeTrue())
 				})
 			})
 
@@ -753,7 +1117,7 @@ var _ = Describe("RemoteSecret", func() {
 				test.ReconcileWithCluster(ITest.Context, func(g Gomega) {
 					rs := *crenv.First[*api.RemoteSecret](&test.InCluster)
 					g.Expect(rs).NotTo(BeNil())
-					g.Expect(rs.Status.Conditions).To(HaveLen(2))
+					g.Expect(rs.Status.Conditions).To(HaveLen(4))
 					g.Expect(meta.IsStatusConditionTrue(rs.Status.Conditions, string(api.RemoteSecretConditionTypeDataObtained))).To(BeTrue())
 				})
 
@@ -763,8 +1127,9 @@ var _ = Describe("RemoteSecret", func() {
 				test.ReconcileWithCluster(ITest.Context, func(g Gomega) {
 					rs := *crenv.First[*api.RemoteSecret](&test.InCluster)
 					g.Expect(rs).NotTo(BeNil())
-					g.Expect(rs.Status.Conditions).To(HaveLen(2))
+					g.Expect(rs.Status.Conditions).To(HaveLen(3))
 					g.Expect(meta.IsStatusConditionTrue(rs.Status.Conditions, string(api.RemoteSecretConditionTypeDataObtained))).To(BeFalse())
+					g.Expect(meta.FindStatusCondition(rs.Status.Conditions, string(api.RemoteSecretConditionTypeDataValid))).To(BeNil())
 				})
 			})
 		})
@@ -804,6 +1169,69 @@ var _ = Describe("RemoteSecret", func() {
 		})
 		When("targets present", func() {
 		})
+		When("target has the Orphan deletion policy", func() {
+			var test crenv.TestSetup
+			var targetNs string
+
+			BeforeEach(func() {
+				targetNs = string(uuid.NewUUID())
+				Expect(ITest.Client.Create(ITest.Context, &corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{Name: targetNs},
+				})).To(Succeed())
+
+				test = crenv.TestSetup{
+					ToCreate: []client.Object{
+						&api.RemoteSecret{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "test-remote-secret",
+								Namespace: "default",
+							},
+							Spec: api.RemoteSecretSpec{
+								Secret: api.LinkableSecretSpec{
+									Name: "injected-secret",
+								},
+								Targets: []api.RemoteSecretTarget{{
+									Namespace:      targetNs,
+									DeletionPolicy: api.DeletionPolicyOrphan,
+								}},
+							},
+						},
+					},
+					ReconciliationTrigger: remoteSecretReconciliationTrigger,
+				}
+
+				test.BeforeEach(ITest.Context, ITest.Client, nil)
+				rs := *crenv.First[*api.RemoteSecret](&test.InCluster)
+				Expect(rs).NotTo(BeNil())
+				Expect(ITest.Storage.Store(ITest.Context, rs, &remotesecretstorage.SecretData{
+					"a": []byte("b"),
+				})).To(Succeed())
+
+				test.ReconcileWithCluster(ITest.Context, func(g Gomega) {
+					g.Expect(ITest.Client.Get(ITest.Context, client.ObjectKey{Name: "injected-secret", Namespace: targetNs}, &corev1.Secret{})).To(Succeed())
+				})
+			})
+
+			AfterEach(func() {
+				test.AfterEach(ITest.Context)
+			})
+
+			It("leaves the unmarked secret in the target", func() {
+				rs := *crenv.First[*api.RemoteSecret](&test.InCluster)
+				Expect(rs).NotTo(BeNil())
+				Expect(ITest.Client.Delete(ITest.Context, rs)).To(Succeed())
+
+				test.SettleWithCluster(ITest.Context, func(g Gomega) {
+					g.Expect(crenv.GetAll[*api.RemoteSecret](&test.InCluster)).To(BeEmpty())
+					secret := &corev1.Secret{}
+					g.Expect(ITest.Client.Get(ITest.Context, client.ObjectKey{Name: "injected-secret", Namespace: targetNs}, secret)).To(Succeed())
+					g.Expect(secret.Labels).NotTo(HaveKey(api.LinkedByRemoteSecretLabel))
+					g.Expect(secret.Annotations).NotTo(HaveKey(api.LinkedRemoteSecretsAnnotation))
+					g.Expect(secret.Annotations).NotTo(HaveKey(api.ManagingRemoteSecretNameAnnotation))
+					g.Expect(secret.Data["a"]).To(Equal([]byte("b")))
+				})
+			})
+		})
 	})
 	Describe("Interactions", func() {
 		When("two RemoteSecrets have the same target", func() {
@@ -35,6 +35,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
+	apiv1 "github.com/redhat-appstudio/remote-secret/api/v1"
 	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
 	"github.com/redhat-appstudio/remote-secret/pkg/config"
 	corev1 "k8s.io/api/core/v1"
@@ -54,8 +55,21 @@ var _ = BeforeSuite(func() {
 
 	ITest.Context, ITest.Cancel = context.WithCancel(context.TODO())
 
+	scheme := runtime.NewScheme()
+
+	err := corev1.AddToScheme(scheme)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = api.AddToScheme(scheme)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = apiv1.AddToScheme(scheme)
+	Expect(err).NotTo(HaveOccurred())
+
 	By("bootstrapping the test environment")
 	ITest.TestEnvironment = &envtest.Environment{
+		// the scheme makes the environment configure the conversion webhook for the RemoteSecret CRD
+		Scheme:                scheme,
 		CRDDirectoryPaths:     []string{filepath.Join("..", "config", "crd", "bases")},
 		ErrorIfCRDPathMissing: true,
 		WebhookInstallOptions: envtest.WebhookInstallOptions{
@@ -67,14 +81,6 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 	Expect(cfg).NotTo(BeNil())
 
-	scheme := runtime.NewScheme()
-
-	err = corev1.AddToScheme(scheme)
-	Expect(err).NotTo(HaveOccurred())
-
-	err = api.AddToScheme(scheme)
-	Expect(err).NotTo(HaveOccurred())
-
 	ITest.Client, err = client.New(cfg, client.Options{Scheme: scheme})
 	Expect(err).NotTo(HaveOccurred())
 
@@ -97,17 +103,17 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).NotTo(HaveOccurred())
 
-	ITest.OperatorConfiguration = &config.OperatorConfiguration{}
+	ITest.OperatorConfiguration = &config.OperatorConfiguration{FingerprintKey: []byte("fingerprint-key")}
 
 	ITest.ClientFactory = TestClientFactory{
-		GetClientImpl: func(_ context.Context, _ string, _ *api.RemoteSecretTarget, _ *api.TargetStatus) (client.Client, error) {
+		GetClientImpl: func(_ context.Context, _ client.ObjectKey, _ *api.RemoteSecretTarget, _ *api.TargetStatus) (client.Client, error) {
 			// effectively, this switches off any support for auth or deployment to remote clusters in the integration tests..
 			return mgr.GetClient(), nil
 		},
 	}
 
-	Expect(controllers.SetupAllReconcilers(mgr, ITest.OperatorConfiguration, ITest.Storage.SecretStorage(), &ITest.ClientFactory)).To(Succeed())
-	Expect(webhook.SetupAllWebhooks(mgr, ITest.Storage.SecretStorage())).To(Succeed())
+	Expect(controllers.SetupAllReconcilers(mgr, ITest.OperatorConfiguration, ITest.Storage.SecretStorage(), &ITest.ClientFactory, nil)).To(Succeed())
+	Expect(webhook.SetupAllWebhooks(mgr, ITest.OperatorConfiguration, ITest.Storage.SecretStorage())).To(Succeed())
 
 	go func() {
 		err = mgr.Start(ITest.Context)
@@ -172,13 +172,18 @@ var _ = Describe("TokenUploadController", func() {
 					g.Expect(crenv.GetAll[*api.RemoteSecret](&test.InCluster)).To(HaveLen(1))
 
 					// RS is still in awaiting data state
-					g.Expect((*crenv.First[*api.RemoteSecret](&test.InCluster)).Status.Conditions).To(HaveLen(1))
+					g.Expect((*crenv.First[*api.RemoteSecret](&test.InCluster)).Status.Conditions).To(HaveLen(2))
 					g.Expect((*crenv.First[*api.RemoteSecret](&test.InCluster)).Status.Conditions[0].Reason).To(Equal(string(api.RemoteSecretReasonAwaitingTokenData)))
-
-					// Error event should be created
-					event := &corev1.Event{}
-					g.Expect(ITest.Client.Get(ITest.Context, client.ObjectKeyFromObject(uploadSecret), event)).To(Succeed())
-					g.Expect(event.Type).To(Equal("Error"))
+					g.Expect(meta.IsStatusConditionFalse((*crenv.First[*api.RemoteSecret](&test.InCluster)).Status.Conditions, string(api.RemoteSecretConditionTypeReady))).To(BeTrue())
+
+					// Error event should be recorded on the upload secret
+					events := &corev1.EventList{}
+					g.Expect(ITest.Client.List(ITest.Context, events, client.InNamespace(uploadSecret.Namespace))).To(Succeed())
+					g.Expect(events.Items).To(ContainElement(And(
+						HaveField("InvolvedObject.Name", uploadSecret.Name),
+						HaveField("Type", corev1.EventTypeWarning),
+						HaveField("Reason", api.RemoteSecretEventReasonDataUploadFailed),
+					)))
 				})
 			}
 
@@ -19,8 +19,10 @@ package main
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/redhat-appstudio/remote-secret/pkg/availability"
 
@@ -41,23 +43,37 @@ import (
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	crebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	apiv1 "github.com/redhat-appstudio/remote-secret/api/v1"
 	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
 	"github.com/redhat-appstudio/remote-secret/controllers"
 	"github.com/redhat-appstudio/remote-secret/controllers/bindings"
 	"github.com/redhat-appstudio/remote-secret/pkg/cmd"
 	"github.com/redhat-appstudio/remote-secret/pkg/config"
+	"github.com/redhat-appstudio/remote-secret/pkg/dataapi"
+	"github.com/redhat-appstudio/remote-secret/pkg/fingerprint"
 	"github.com/redhat-appstudio/remote-secret/pkg/logs"
+	"github.com/redhat-appstudio/remote-secret/pkg/storagemigration"
 )
 
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
+
+	unsupportedDeletionPolicyError      = errors.New("unsupported deletion policy")
+	unsupportedTargetAuthorizationError = errors.New("unsupported target authorization mode")
+	negativeQuotaError                  = errors.New("the quota limits cannot be negative")
+	emptyFingerprintKeyError            = errors.New("the fingerprint key file is empty")
+	negativeFailedUploadRetentionError  = errors.New("the retention of the failed upload secrets cannot be negative")
 )
 
+// serviceAccountNamespaceFile contains the namespace of the operator when running in a cluster.
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace" //#nosec G101 -- false positive, this is just a path
+
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(corev1.AddToScheme(scheme))
 	utilruntime.Must(api.AddToScheme(scheme))
+	utilruntime.Must(apiv1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -91,12 +107,25 @@ func main() {
 		os.Exit(1)
 	}
 
+	if len(cfg.FingerprintKey) == 0 {
+		if cfg.InternalFingerprintKey, err = loadInternalFingerprintKey(ctx, mgr); err != nil {
+			setupLog.Error(err, "failed to load the internal fingerprint key")
+			os.Exit(1)
+		}
+	}
+
 	secretStorage, err := cmd.CreateInitializedSecretStorage(ctx, mgr.GetClient(), mgr.GetAPIReader(), &args.CommonCliArgs)
 	if err != nil {
 		setupLog.Error(err, "failed to initialize the secret storage")
 		os.Exit(1)
 	}
 
+	externalSource, err := cmd.CreateInitializedExternalSource(ctx, mgr.GetClient(), &args.ExternalSourceCliArgs)
+	if err != nil {
+		setupLog.Error(err, "failed to initialize the external source")
+		os.Exit(1)
+	}
+
 	cf := &bindings.CachingClientFactory{
 		LocalCluster: bindings.LocalClusterConnectionDetails{
 			Client: mgr.GetClient(),
@@ -109,12 +138,19 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = controllers.SetupAllReconcilers(mgr, &cfg, secretStorage, cf); err != nil {
+	if args.DataAPIBindAddress != "" {
+		if err = dataapi.SetupServer(mgr, &cfg, secretStorage, args.DataAPIBindAddress, args.DataAPICertDir); err != nil {
+			setupLog.Error(err, "failed to set up the data API server")
+			os.Exit(1)
+		}
+	}
+
+	if err = controllers.SetupAllReconcilers(mgr, &cfg, secretStorage, cf, externalSource); err != nil {
 		setupLog.Error(err, "failed to set up the controllers")
 		os.Exit(1)
 	}
 
-	if err = webhook.SetupAllWebhooks(mgr, secretStorage); err != nil {
+	if err = webhook.SetupAllWebhooks(mgr, &cfg, secretStorage); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "RemoteSecret")
 		os.Exit(1)
 	}
@@ -131,6 +167,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	if args.MigrateStorage {
+		migrator := &storagemigration.StorageVersionMigrator{Reader: mgr.GetAPIReader(), Client: mgr.GetClient()}
+		if err := mgr.Add(migrator); err != nil {
+			setupLog.Error(err, "unable to set up the storage version migration")
+			os.Exit(1)
+		}
+	}
+
 	checker := &availability.StorageWatchdog{SecretStorage: secretStorage}
 	if err := mgr.Add(checker); err != nil {
 		setupLog.Error(err, "unable to set up availability checks")
@@ -146,11 +190,76 @@ func main() {
 
 func LoadFrom(args *cmd.OperatorCliArgs) (config.OperatorConfiguration, error) {
 	ret := config.OperatorConfiguration{
-		ReconcileLogging: args.ReconcileLogging,
+		ReconcileLogging:      args.ReconcileLogging,
+		ServerSideApply:       args.ServerSideApply,
+		DeletionPolicy:        api.DeletionPolicy(args.DeletionPolicy),
+		TargetAuthorization:   config.TargetAuthorizationMode(args.TargetAuthorization),
+		FailedUploadRetention: args.FailedUploadSecretRetention,
+		Quota: api.RemoteSecretQuota{
+			MaxRemoteSecrets:              args.QuotaMaxRemoteSecrets,
+			MaxTargetsPerRemoteSecret:     args.QuotaMaxTargetsPerRemoteSecret,
+			MaxStoredBytes:                args.QuotaMaxStoredBytes,
+			MaxStoredBytesPerRemoteSecret: args.QuotaMaxStoredBytesPerRemoteSecret,
+		},
+	}
+
+	switch ret.DeletionPolicy {
+	case api.DeletionPolicyDelete, api.DeletionPolicyOrphan:
+	default:
+		return ret, fmt.Errorf("%w: %s", unsupportedDeletionPolicyError, args.DeletionPolicy)
+	}
+
+	switch ret.TargetAuthorization {
+	case config.TargetAuthorizationDisabled, config.TargetAuthorizationAudit, config.TargetAuthorizationEnforce:
+	default:
+		return ret, fmt.Errorf("%w: %s", unsupportedTargetAuthorizationError, args.TargetAuthorization)
+	}
+
+	if ret.Quota.MaxRemoteSecrets < 0 || ret.Quota.MaxTargetsPerRemoteSecret < 0 || ret.Quota.MaxStoredBytes < 0 || ret.Quota.MaxStoredBytesPerRemoteSecret < 0 {
+		return ret, fmt.Errorf("%w: %+v", negativeQuotaError, ret.Quota)
+	}
+
+	if ret.FailedUploadRetention < 0 {
+		return ret, fmt.Errorf("%w: %s", negativeFailedUploadRetentionError, ret.FailedUploadRetention)
 	}
+
+	if args.FingerprintKeyFile != "" {
+		key, err := os.ReadFile(args.FingerprintKeyFile)
+		if err != nil {
+			return ret, fmt.Errorf("failed to read the fingerprint key: %w", err)
+		}
+		if len(key) == 0 {
+			return ret, fmt.Errorf("%w: %s", emptyFingerprintKeyError, args.FingerprintKeyFile)
+		}
+		ret.FingerprintKey = key
+	}
+
 	return ret, nil
 }
 
+// loadInternalFingerprintKey loads the internal fingerprint key from the namespace of the operator. When not running in a cluster,
+// a random key is used, so the data is considered changed after every restart.
+func loadInternalFingerprintKey(ctx context.Context, mgr manager.Manager) ([]byte, error) {
+	namespace, err := os.ReadFile(serviceAccountNamespaceFile)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read the namespace of the operator: %w", err)
+		}
+		setupLog.Info("not running in a cluster, the data fingerprints will use a random key that changes with every restart")
+		key, err := fingerprint.RandomKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate the internal fingerprint key: %w", err)
+		}
+		return key, nil
+	}
+
+	key, err := fingerprint.LoadInternalKey(ctx, mgr.GetAPIReader(), mgr.GetClient(), strings.TrimSpace(string(namespace)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the internal fingerprint key from the namespace %s: %w", strings.TrimSpace(string(namespace)), err)
+	}
+	return key, nil
+}
+
 func createManager(lg logr.Logger, args cmd.OperatorCliArgs) (manager.Manager, error) {
 	restConfig := ctrl.GetConfigOrDie()
 	disableHTTP2 := func(c *tls.Config) {
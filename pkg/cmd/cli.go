@@ -15,6 +15,8 @@
 package cmd
 
 import (
+	"time"
+
 	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage/awsstorage/awscli"
 	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage/vaultstorage/vaultcli"
 )
@@ -32,15 +34,17 @@ type LoggingCliArgs struct {
 // CommonCliArgs are the command line arguments and environment variable definitions understood by the configuration
 // infrastructure shared between the operator and the oauth service.
 type CommonCliArgs struct {
-	InstanceId        string           `arg:"--instance-id,env" default:"spi-1" help:"ID of this SPI instance. Used to avoid conflicts when multiple SPI instances uses shared resources (e.g. secretstorage)."`
-	MetricsAddr       string           `arg:"--metrics-bind-address, env" default:"127.0.0.1:8080" help:"The address the metric endpoint binds to."`
-	ProbeAddr         string           `arg:"--health-probe-bind-address, env" default:":8081" help:"The address the probe endpoint binds to."`
-	ConfigFile        string           `arg:"--config-file, env" default:"/etc/spi/config.yaml" help:"The location of the configuration file."`
-	AllowInsecureURLs bool             `arg:"--allow-insecure-urls, env" default:"false" help:"Whether is allowed or not to use insecure http URLs in service provider or vault configurations."`
-	TokenStorage      TokenStorageType `arg:"--tokenstorage, env" default:"vault" help:"The type of the token storage. Supported types: 'vault', 'aws' (experimental)."`
-	PprofBindAddress  string           `arg:"--pprof-bind-address, env" default:"0" help:"Is the TCP address that the controller should bind to for serving pprof. Disabled by default."`
-	StorageConfigJSON string           `arg:"--storage-config-json, env" help:"JSON with ESO ClusterSecretStore provider's configuration. Example: '{\"fake\":{}}'"`
-	DisableHTTP2      bool             `arg:"--disable-http2, env" default:"true" help:"whether to support the HTTP/2 protocol in the webhook."`
+	InstanceId         string           `arg:"--instance-id,env" default:"spi-1" help:"ID of this SPI instance. Used to avoid conflicts when multiple SPI instances uses shared resources (e.g. secretstorage)."`
+	MetricsAddr        string           `arg:"--metrics-bind-address, env" default:"127.0.0.1:8080" help:"The address the metric endpoint binds to."`
+	ProbeAddr          string           `arg:"--health-probe-bind-address, env" default:":8081" help:"The address the probe endpoint binds to."`
+	ConfigFile         string           `arg:"--config-file, env" default:"/etc/spi/config.yaml" help:"The location of the configuration file."`
+	AllowInsecureURLs  bool             `arg:"--allow-insecure-urls, env" default:"false" help:"Whether is allowed or not to use insecure http URLs in service provider or vault configurations."`
+	TokenStorage       TokenStorageType `arg:"--tokenstorage, env" default:"vault" help:"The type of the token storage. Supported types: 'vault', 'aws' (experimental)."`
+	PprofBindAddress   string           `arg:"--pprof-bind-address, env" default:"0" help:"Is the TCP address that the controller should bind to for serving pprof. Disabled by default."`
+	StorageConfigJSON  string           `arg:"--storage-config-json, env" help:"JSON with ESO ClusterSecretStore provider's configuration. Example: '{\"fake\":{}}'"`
+	DisableHTTP2       bool             `arg:"--disable-http2, env" default:"true" help:"whether to support the HTTP/2 protocol in the webhook."`
+	StorageMaxDataSize int              `arg:"--storage-max-data-size, env" default:"0" help:"The maximum size of the data of a single remote secret in bytes that the secret storage can store. Overrides the limit of the secret storage type. 0 means the limit of the secret storage type is used."`
+	StorageChunking    bool             `arg:"--storage-chunking, env" default:"false" help:"Split the data larger than the maximum data size of the secret storage into several chunks instead of rejecting it."`
 	vaultcli.VaultCliArgs
 	awscli.AWSCliArgs
 }
@@ -48,7 +52,36 @@ type CommonCliArgs struct {
 type OperatorCliArgs struct {
 	CommonCliArgs
 	LoggingCliArgs
-	EnableLeaderElection bool `arg:"--leader-elect, env" default:"false" help:"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager."`
+	EnableLeaderElection        bool          `arg:"--leader-elect, env" default:"false" help:"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager."`
+	ServerSideApply             bool          `arg:"--server-side-apply, env" default:"false" help:"Use the server-side apply to deploy the secrets and managed service accounts to the targets."`
+	DeletionPolicy              string        `arg:"--deletion-policy, env" default:"Delete" help:"What happens with the objects in the targets when a remote secret is deleted, unless the target specifies otherwise. Supported values: 'Delete', 'Orphan'."`
+	MigrateStorage              bool          `arg:"--migrate-storage-version, env" default:"false" help:"Rewrite all the remote secrets on startup so that they are stored in the current storage version of the CRD."`
+	TargetAuthorization         string        `arg:"--target-authorization, env" default:"Disabled" help:"Whether the webhook checks that the user can create secrets in the namespaces of the newly added targets. Supported values: 'Disabled', 'Audit', 'Enforce'."`
+	FingerprintKeyFile          string        `arg:"--fingerprint-key-file, env" default:"" help:"The file with the key used to compute the fingerprints of the values of the secret data published in the status of the remote secrets. No fingerprints are published if not specified."`
+	FailedUploadSecretRetention time.Duration `arg:"--failed-upload-secret-retention, env" default:"0" help:"How long the upload secrets whose data failed to be uploaded are kept for debugging, e.g. '1h'. They are deleted immediately if 0."`
+	QuotaCliArgs
+	ExternalSourceCliArgs
+	DataAPICliArgs
+}
+
+// DataAPICliArgs define the command line arguments for configuring the aggregated API server serving the data subresource of the remote secrets.
+type DataAPICliArgs struct {
+	DataAPIBindAddress string `arg:"--data-api-bind-address, env" default:"" help:"The address the aggregated API server serving the data subresource of the remote secrets binds to. The server is disabled if not specified."`
+	DataAPICertDir     string `arg:"--data-api-cert-dir, env" default:"/tmp/k8s-data-api-server/serving-certs" help:"The directory with the tls.crt and tls.key serving certificate of the data API server."`
+}
+
+// ExternalSourceCliArgs define the command line arguments for configuring the external source of the data of the remote secrets.
+type ExternalSourceCliArgs struct {
+	ExternalSourceConfigJSON string `arg:"--external-source-config-json, env" help:"JSON with ESO ClusterSecretStore provider's configuration of the external secret store to read the data of the remote secrets with an external source from. Example: '{\"fake\":{}}'. Reading from an external source is disabled if not specified."`
+	ExternalSourceKeyPrefix  string `arg:"--external-source-key-prefix, env" default:"{namespace}/" help:"The prefix of the keys in the external secret store that the remote secrets can read. '{namespace}' is replaced with the namespace of the remote secret."`
+}
+
+// QuotaCliArgs define the command line arguments for configuring the quota of the remote secrets in each namespace.
+type QuotaCliArgs struct {
+	QuotaMaxRemoteSecrets              int32 `arg:"--quota-max-remote-secrets, env" default:"0" help:"The maximum number of remote secrets in a namespace. 0 means no limit."`
+	QuotaMaxTargetsPerRemoteSecret     int32 `arg:"--quota-max-targets-per-remote-secret, env" default:"0" help:"The maximum number of targets of a single remote secret. 0 means no limit."`
+	QuotaMaxStoredBytes                int64 `arg:"--quota-max-stored-bytes, env" default:"0" help:"The maximum total size of the data of the remote secrets in a namespace in bytes. 0 means no limit."`
+	QuotaMaxStoredBytesPerRemoteSecret int64 `arg:"--quota-max-stored-bytes-per-remote-secret, env" default:"0" help:"The maximum size of the data of a single remote secret in bytes. 0 means no limit."`
 }
 
 type TokenStorageType string
@@ -23,6 +23,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
+	"github.com/redhat-appstudio/remote-secret/pkg/externalsource"
+	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage/chunking"
 	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage/es"
 	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage/memorystorage"
 	stmetrics "github.com/redhat-appstudio/remote-secret/pkg/secretstorage/metrics"
@@ -62,6 +64,12 @@ func CreateInitializedSecretStorage(ctx context.Context, client client.Client, r
 	if storage == nil {
 		return nil, fmt.Errorf("%w: '%s'", errNilSecretStorage, args.TokenStorage)
 	}
+	if args.StorageMaxDataSize > 0 {
+		storage = secretstorage.WithMaxDataSize(storage, args.StorageMaxDataSize)
+	}
+	if args.StorageChunking {
+		storage = &chunking.ChunkingSecretStorage{SecretStorage: storage}
+	}
 	storage = &stmetrics.MeteredSecretStorage{
 		SecretStorage:     storage,
 		StorageType:       string(args.TokenStorage),
@@ -73,3 +81,21 @@ func CreateInitializedSecretStorage(ctx context.Context, client client.Client, r
 	lg.Info("Secret storage initialized successfully", "type", args.TokenStorage)
 	return storage, nil
 }
+
+// CreateInitializedExternalSource creates the external source of the data of the remote secrets. Returns nil if the external
+// source is not configured.
+func CreateInitializedExternalSource(ctx context.Context, client client.Client, args *ExternalSourceCliArgs) (*externalsource.Source, error) {
+	if args.ExternalSourceConfigJSON == "" {
+		return nil, nil
+	}
+
+	source, err := externalsource.NewSource(client, args.ExternalSourceConfigJSON, args.ExternalSourceKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the external source: %w", err)
+	}
+	if err = source.Initialize(ctx); err != nil {
+		return nil, fmt.Errorf("failed to initialize the external source: %w", err)
+	}
+	log.FromContext(ctx).Info("External source initialized successfully", "keyPrefix", args.ExternalSourceKeyPrefix)
+	return source, nil
+}
@@ -13,14 +13,52 @@
 
 package config
 
+import (
+	"time"
+
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+)
+
 type instanceIdContextKeyType struct{}
 
 var InstanceIdContextKey = instanceIdContextKeyType{}
 
 type OperatorConfiguration struct {
 	ReconcileLogging bool
+	// ServerSideApply makes the controller deploy the dependent objects using the server-side apply.
+	ServerSideApply bool
+	// DeletionPolicy is the deletion policy used for the targets that don't specify one explicitly.
+	DeletionPolicy api.DeletionPolicy
+	// TargetAuthorization specifies whether the webhook checks that the user can create secrets in the namespaces of the newly
+	// added targets.
+	TargetAuthorization TargetAuthorizationMode
+	// Quota is the quota applied to the remote secrets in every namespace.
+	Quota api.RemoteSecretQuota
+	// FingerprintKey is the key used to compute the fingerprints of the values of the secret data that are published in the status
+	// of the remote secrets. If empty, no fingerprints are published.
+	FingerprintKey []byte
+	// InternalFingerprintKey is the key generated by the operator that is used instead of the FingerprintKey to compute
+	// the fingerprints of the whole data, which detect the changes of the data, if the FingerprintKey is empty. It is never used
+	// for the fingerprints of the values.
+	InternalFingerprintKey []byte
+	// FailedUploadRetention is how long the upload secrets whose data failed to be uploaded are kept for debugging. They are
+	// deleted immediately if zero.
+	FailedUploadRetention time.Duration
 }
 
+// TargetAuthorizationMode specifies how the webhook authorizes the targets of the remote secrets against the requesting user.
+type TargetAuthorizationMode string
+
+const (
+	// TargetAuthorizationDisabled switches off the checks. The targets are only limited by the permissions of the service account
+	// used to deploy to them.
+	TargetAuthorizationDisabled TargetAuthorizationMode = "Disabled"
+	// TargetAuthorizationAudit performs the checks but only records the targets the user is not allowed to use in the audit log.
+	TargetAuthorizationAudit TargetAuthorizationMode = "Audit"
+	// TargetAuthorizationEnforce rejects the remote secrets with targets that the user is not allowed to use.
+	TargetAuthorizationEnforce TargetAuthorizationMode = "Enforce"
+)
+
 const (
 	MetricsNamespace = "redhat_appstudio"
 	MetricsSubsystem = "remotesecret"
@@ -0,0 +1,145 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataapi
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	authv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups="",resources=configmaps,resourceNames=extension-apiserver-authentication,verbs=get
+
+// authenticationConfigMapKey is the config map in which the Kubernetes API server publishes the configuration of the authentication
+// of the requests it proxies to the aggregated API servers.
+var authenticationConfigMapKey = client.ObjectKey{Namespace: "kube-system", Name: "extension-apiserver-authentication"}
+
+var (
+	errFrontProxyNotConfigured  = errors.New("the client CA of the front proxy is not configured in the cluster")
+	errInvalidClientCA          = errors.New("the client CA of the front proxy doesn't contain any valid certificates")
+	errNotFromFrontProxy        = errors.New("the request doesn't come from the front proxy")
+	errFrontProxyNameNotAllowed = errors.New("the common name of the front proxy client certificate is not allowed")
+	errNoUserInRequestHeaders   = errors.New("the request doesn't identify the user")
+)
+
+// RequestHeaderAuthenticator authenticates the requests proxied by the Kubernetes API server. The API server authenticates the
+// user, connects using its front proxy client certificate and passes the identity of the user in the request headers.
+type RequestHeaderAuthenticator struct {
+	// ClientCAs verify the front proxy client certificate. The verification itself is done by the TLS server.
+	ClientCAs *x509.CertPool
+	// AllowedNames are the allowed common names of the front proxy client certificate. Any name is allowed if empty.
+	AllowedNames []string
+	// UsernameHeaders are the headers in which the username can be passed. The first non-empty one is used.
+	UsernameHeaders []string
+	// GroupHeaders are the headers in which the groups of the user are passed.
+	GroupHeaders []string
+	// ExtraHeaderPrefixes are the prefixes of the headers in which the extra information about the user is passed.
+	ExtraHeaderPrefixes []string
+}
+
+// LoadRequestHeaderAuthenticator reads the configuration of the front proxy authentication from the cluster.
+func LoadRequestHeaderAuthenticator(ctx context.Context, reader client.Reader) (*RequestHeaderAuthenticator, error) {
+	cm := &corev1.ConfigMap{}
+	if err := reader.Get(ctx, authenticationConfigMapKey, cm); err != nil {
+		return nil, fmt.Errorf("failed to read the authentication configuration of the aggregated API servers: %w", err)
+	}
+
+	caPEM := cm.Data["requestheader-client-ca-file"]
+	if caPEM == "" {
+		return nil, errFrontProxyNotConfigured
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(caPEM)) {
+		return nil, errInvalidClientCA
+	}
+
+	ret := &RequestHeaderAuthenticator{ClientCAs: pool}
+	for key, target := range map[string]*[]string{
+		"requestheader-allowed-names":        &ret.AllowedNames,
+		"requestheader-username-headers":     &ret.UsernameHeaders,
+		"requestheader-group-headers":        &ret.GroupHeaders,
+		"requestheader-extra-headers-prefix": &ret.ExtraHeaderPrefixes,
+	} {
+		if val := cm.Data[key]; val != "" {
+			if err := json.Unmarshal([]byte(val), target); err != nil {
+				return nil, fmt.Errorf("failed to parse %s in the authentication configuration of the aggregated API servers: %w", key, err)
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+// Authenticate returns the user on whose behalf the front proxy makes the request.
+func (a *RequestHeaderAuthenticator) Authenticate(r *http.Request) (authv1.UserInfo, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return authv1.UserInfo{}, errNotFromFrontProxy
+	}
+
+	if len(a.AllowedNames) > 0 {
+		cn := r.TLS.VerifiedChains[0][0].Subject.CommonName
+		allowed := false
+		for _, n := range a.AllowedNames {
+			if n == cn {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return authv1.UserInfo{}, fmt.Errorf("%w: %s", errFrontProxyNameNotAllowed, cn)
+		}
+	}
+
+	user := authv1.UserInfo{}
+	for _, h := range a.UsernameHeaders {
+		if user.Username = r.Header.Get(h); user.Username != "" {
+			break
+		}
+	}
+	if user.Username == "" {
+		return authv1.UserInfo{}, errNoUserInRequestHeaders
+	}
+
+	for _, h := range a.GroupHeaders {
+		user.Groups = append(user.Groups, r.Header.Values(h)...)
+	}
+
+	for name, values := range r.Header {
+		for _, prefix := range a.ExtraHeaderPrefixes {
+			if !strings.HasPrefix(strings.ToLower(name), strings.ToLower(prefix)) {
+				continue
+			}
+			key := strings.ToLower(name[len(prefix):])
+			if unescaped, err := url.PathUnescape(key); err == nil {
+				key = unescaped
+			}
+			if user.Extra == nil {
+				user.Extra = map[string]authv1.ExtraValue{}
+			}
+			user.Extra[key] = append(user.Extra[key], values...)
+		}
+	}
+
+	return user, nil
+}
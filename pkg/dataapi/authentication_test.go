@@ -0,0 +1,125 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataapi
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	authv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testCAPEM(t *testing.T) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "front-proxy-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestLoadRequestHeaderAuthenticator(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	t.Run("loads the configuration", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "extension-apiserver-authentication", Namespace: "kube-system"},
+			Data: map[string]string{
+				"requestheader-client-ca-file":       testCAPEM(t),
+				"requestheader-allowed-names":        `["front-proxy-client"]`,
+				"requestheader-username-headers":     `["X-Remote-User"]`,
+				"requestheader-group-headers":        `["X-Remote-Group"]`,
+				"requestheader-extra-headers-prefix": `["X-Remote-Extra-"]`,
+			},
+		}).Build()
+
+		auth, err := LoadRequestHeaderAuthenticator(context.TODO(), cl)
+		assert.NoError(t, err)
+		assert.NotNil(t, auth.ClientCAs)
+		assert.Equal(t, []string{"front-proxy-client"}, auth.AllowedNames)
+		assert.Equal(t, []string{"X-Remote-User"}, auth.UsernameHeaders)
+		assert.Equal(t, []string{"X-Remote-Group"}, auth.GroupHeaders)
+		assert.Equal(t, []string{"X-Remote-Extra-"}, auth.ExtraHeaderPrefixes)
+	})
+
+	t.Run("front proxy not configured", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "extension-apiserver-authentication", Namespace: "kube-system"},
+			Data:       map[string]string{"client-ca-file": testCAPEM(t)},
+		}).Build()
+
+		_, err := LoadRequestHeaderAuthenticator(context.TODO(), cl)
+		assert.ErrorIs(t, err, errFrontProxyNotConfigured)
+	})
+}
+
+func TestAuthenticate(t *testing.T) {
+	auth := &RequestHeaderAuthenticator{
+		AllowedNames:        []string{"front-proxy-client"},
+		UsernameHeaders:     []string{"X-Remote-User"},
+		GroupHeaders:        []string{"X-Remote-Group"},
+		ExtraHeaderPrefixes: []string{"X-Remote-Extra-"},
+	}
+
+	request := func(cn string) *http.Request {
+		req := httptest.NewRequest(http.MethodPut, "/", nil)
+		req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: cn}}}}}
+		req.Header.Set("X-Remote-User", "alice")
+		req.Header.Add("X-Remote-Group", "devs")
+		req.Header.Add("X-Remote-Group", "system:authenticated")
+		req.Header.Add("X-Remote-Extra-Acme.com%2Fproject", "a")
+		return req
+	}
+
+	user, err := auth.Authenticate(request("front-proxy-client"))
+	assert.NoError(t, err)
+	assert.Equal(t, authv1.UserInfo{
+		Username: "alice",
+		Groups:   []string{"devs", "system:authenticated"},
+		Extra:    map[string]authv1.ExtraValue{"acme.com/project": {"a"}},
+	}, user)
+
+	_, err = auth.Authenticate(request("someone-else"))
+	assert.ErrorIs(t, err, errFrontProxyNameNotAllowed)
+
+	req := request("front-proxy-client")
+	req.TLS = nil
+	_, err = auth.Authenticate(req)
+	assert.ErrorIs(t, err, errNotFromFrontProxy)
+}
@@ -0,0 +1,427 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	authv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/redhat-appstudio/remote-secret/controllers/remotesecrets"
+	"github.com/redhat-appstudio/remote-secret/controllers/remotesecretstorage"
+	"github.com/redhat-appstudio/remote-secret/pkg/logs"
+	"github.com/redhat-appstudio/remote-secret/pkg/metrics"
+	"github.com/redhat-appstudio/remote-secret/pkg/quota"
+	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage"
+)
+
+// maxRequestBodySize limits the size of the request bodies read by the server. The actual limit of the data is enforced by the
+// quota and the secret storage.
+const maxRequestBodySize = 3 * 1024 * 1024
+
+const metricOperationLabel = "data_api"
+
+// RemoteSecretData is the body of the PUT requests to the data subresource of the remote secrets. It replaces all the data
+// of the remote secret. The PATCH requests use the JSON merge patch of the same structure, in which the keys with null values
+// are deleted from the data.
+type RemoteSecretData struct {
+	metav1.TypeMeta `json:",inline"`
+	// Data are the base64 encoded values of the keys.
+	Data map[string][]byte `json:"data,omitempty"`
+	// StringData are the plain text values of the keys. They take precedence over the values in the Data.
+	StringData map[string]string `json:"stringData,omitempty"`
+}
+
+// remoteSecretDataPatch is the JSON merge patch of the RemoteSecretData. The nil values delete the keys.
+type remoteSecretDataPatch struct {
+	Data       map[string]*[]byte `json:"data,omitempty"`
+	StringData map[string]*string `json:"stringData,omitempty"`
+}
+
+var (
+	errRemoteSecretFollows           = errors.New("the remote secret follows the data of other remote secrets")
+	errRemoteSecretHasExternalSource = errors.New("the data of the remote secret is read from an external source")
+	errRemoteSecretSuspended         = errors.New("the data of a suspended remote secret cannot be changed")
+	errNotAllowed                    = errors.New("user is not allowed to change the data of the remote secret")
+)
+
+// dataChange describes the change of the data requested using the data API, so that it can be recorded in the status of the
+// remote secret.
+type dataChange struct {
+	data        map[string][]byte
+	deletedKeys []string
+	// reason is the reason of the rejection of the change, if any.
+	reason string
+}
+
+// reject counts the rejection of the change in the metrics and remembers its reason.
+func (c *dataChange) reject(reason string) {
+	metrics.UploadRejectionsCounter.WithLabelValues(metricOperationLabel, reason).Inc()
+	c.reason = reason
+}
+
+// verbs maps the supported HTTP methods to the verbs of the authorization checks.
+var verbs = map[string]string{
+	http.MethodPut:    "update",
+	http.MethodPatch:  "patch",
+	http.MethodDelete: "delete",
+}
+
+// handleData serves the requests to the data subresource of the remote secret with the provided key.
+func (s *Server) handleData(w http.ResponseWriter, r *http.Request, key client.ObjectKey) {
+	ctx := r.Context()
+
+	verb, ok := verbs[r.Method]
+	if !ok {
+		writeStatus(ctx, w, apierrors.NewMethodNotSupported(remoteSecretsResource, r.Method))
+		return
+	}
+
+	user, err := s.Authenticator.Authenticate(r)
+	if err != nil {
+		lg(ctx).Info("rejecting unauthenticated request", "reason", err.Error())
+		writeStatus(ctx, w, apierrors.NewUnauthorized(err.Error()))
+		return
+	}
+
+	auditLog := logs.AuditLog(ctx).WithValues("remoteSecret", key, "user", user.Username, "verb", verb)
+	ctx = log.IntoContext(ctx, lg(ctx).WithValues("remoteSecret", key, "user", user.Username))
+
+	if err := s.authorize(ctx, user, verb, key); err != nil {
+		auditLog.Info("data API request rejected", "reason", err.Error())
+		if errors.Is(err, errNotAllowed) {
+			writeStatus(ctx, w, apierrors.NewForbidden(remoteSecretsResource, key.Name, err))
+		} else {
+			writeStatus(ctx, w, apierrors.NewInternalError(err))
+		}
+		return
+	}
+
+	rs := &api.RemoteSecret{}
+	if err := s.Client.Get(ctx, key, rs); err != nil {
+		if apierrors.IsNotFound(err) {
+			writeStatus(ctx, w, apierrors.NewNotFound(remoteSecretsResource, key.Name))
+		} else {
+			writeStatus(ctx, w, apierrors.NewInternalError(fmt.Errorf("failed to get the remote secret: %w", err)))
+		}
+		return
+	}
+
+	change := &dataChange{}
+	if err := checkDataChangeAllowed(rs, change); err != nil {
+		auditLog.Info("data API request rejected", "reason", err.Error())
+		s.recordDataUpdate(ctx, rs, change, err)
+		writeStatus(ctx, w, apierrors.NewConflict(remoteSecretsResource, key.Name, err))
+		return
+	}
+
+	var statusErr *apierrors.StatusError
+	switch r.Method {
+	case http.MethodPut:
+		statusErr = s.replaceData(ctx, r, rs, change)
+	case http.MethodPatch:
+		statusErr = s.patchData(ctx, r, rs, change)
+	case http.MethodDelete:
+		statusErr = s.deleteData(ctx, rs, change)
+	}
+	if statusErr != nil {
+		// the malformed requests are only reported to the caller
+		if change.reason != "" {
+			s.recordDataUpdate(ctx, rs, change, statusErr)
+		}
+		writeStatus(ctx, w, statusErr)
+		return
+	}
+
+	s.recordDataUpdate(ctx, rs, change, nil)
+	s.notifyDataUpdated(ctx, rs)
+	writeSuccess(ctx, w)
+}
+
+// authorize checks that the user can use the verb on the data subresource of the remote secret using the standard RBAC.
+func (s *Server) authorize(ctx context.Context, user authv1.UserInfo, verb string, key client.ObjectKey) error {
+	extra := make(map[string]authzv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authzv1.ExtraValue(v)
+	}
+
+	sar := &authzv1.SubjectAccessReview{
+		Spec: authzv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authzv1.ResourceAttributes{
+				Namespace:   key.Namespace,
+				Verb:        verb,
+				Group:       GroupName,
+				Version:     Version,
+				Resource:    resourceName,
+				Subresource: subresourceName,
+				Name:        key.Name,
+			},
+			UID:    user.UID,
+			User:   user.Username,
+			Groups: user.Groups,
+			Extra:  extra,
+		},
+	}
+
+	if err := s.Client.Create(ctx, sar); err != nil {
+		return fmt.Errorf("failed to create a subject access review to check if the user can %s the data of the remote secret: %w", verb, err)
+	}
+
+	if !sar.Status.Allowed {
+		if sar.Status.Reason != "" {
+			return fmt.Errorf("%w: %s", errNotAllowed, sar.Status.Reason)
+		}
+		return errNotAllowed
+	}
+	return nil
+}
+
+// checkDataChangeAllowed checks that the data of the remote secret can be changed at all, regardless of the new data.
+func checkDataChangeAllowed(rs *api.RemoteSecret, change *dataChange) error {
+	if len(rs.FollowedRemoteSecrets()) > 0 {
+		change.reject("remote_secret_follows")
+		return errRemoteSecretFollows
+	}
+	if rs.Spec.ExternalSource != nil {
+		change.reject("remote_secret_has_external_source")
+		return errRemoteSecretHasExternalSource
+	}
+	if rs.Spec.Suspend {
+		change.reject("remote_secret_suspended")
+		return errRemoteSecretSuspended
+	}
+	return nil
+}
+
+// replaceData replaces the data of the remote secret with the data in the body of the request.
+func (s *Server) replaceData(ctx context.Context, r *http.Request, rs *api.RemoteSecret, change *dataChange) *apierrors.StatusError {
+	body := &RemoteSecretData{}
+	if statusErr := decodeBody(r, body); statusErr != nil {
+		return statusErr
+	}
+
+	data := body.Data
+	if data == nil {
+		data = map[string][]byte{}
+	}
+	for k, v := range body.StringData {
+		data[k] = []byte(v)
+	}
+	data = rs.ApplySecretDataDefaults(data)
+	change.data = data
+
+	auditLog := logs.AuditLog(ctx).WithValues("remoteSecret", client.ObjectKeyFromObject(rs))
+
+	if err := rs.ValidateSecretData(data); err != nil {
+		change.reject("invalid_data")
+		auditLog.Info("data API upload not started because of invalid data")
+		return invalid(rs, err)
+	}
+	if statusErr := s.checkDataSize(ctx, rs, data, change); statusErr != nil {
+		auditLog.Info("data API upload not started because the data is too large", "reason", statusErr.Error())
+		return statusErr
+	}
+
+	auditLog.Info("data API upload initiated", "action", "UPDATE")
+	if err := s.Storage.Store(ctx, rs, &data); err != nil {
+		change.reject(remotesecretstorage.StorageWriteRejectionReason(err))
+		auditLog.Error(err, "data API upload failed")
+		return apierrors.NewInternalError(fmt.Errorf("failed to store the data: %w", err))
+	}
+	auditLog.Info("data API upload completed")
+	s.Recorder.Event(rs, corev1.EventTypeNormal, api.RemoteSecretEventReasonDataUploaded, "the data was uploaded using the data API")
+
+	return nil
+}
+
+// patchData applies the JSON merge patch in the body of the request to the data of the remote secret.
+func (s *Server) patchData(ctx context.Context, r *http.Request, rs *api.RemoteSecret, change *dataChange) *apierrors.StatusError {
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/merge-patch+json" {
+		return apierrors.NewGenericServerResponse(http.StatusUnsupportedMediaType, "patch", remoteSecretsResource, rs.Name,
+			"only the application/merge-patch+json content type is supported", 0, false)
+	}
+
+	patch := &remoteSecretDataPatch{}
+	if statusErr := decodeBody(r, patch); statusErr != nil {
+		return statusErr
+	}
+
+	updates := map[string][]byte{}
+	var deletes []string
+	for k, v := range patch.Data {
+		if v == nil {
+			deletes = append(deletes, k)
+		} else {
+			updates[k] = *v
+		}
+	}
+	for k, v := range patch.StringData {
+		if v == nil {
+			deletes = append(deletes, k)
+		} else {
+			updates[k] = []byte(*v)
+		}
+	}
+
+	change.data = updates
+	change.deletedKeys = deletes
+
+	auditLog := logs.AuditLog(ctx).WithValues("remoteSecret", client.ObjectKeyFromObject(rs), "deletedKeys", deletes)
+
+	current, err := s.Storage.Get(ctx, rs)
+	if err != nil && !errors.Is(err, secretstorage.NotFoundError) {
+		change.reject("storage_read_failed")
+		return apierrors.NewInternalError(fmt.Errorf("failed to read the current data: %w", err))
+	}
+	// the same order as in the PartialUpdate of the storage
+	merged := map[string][]byte{}
+	if current != nil {
+		for k, v := range *current {
+			merged[k] = v
+		}
+	}
+	for k, v := range updates {
+		merged[k] = v
+	}
+	for _, k := range deletes {
+		delete(merged, k)
+	}
+
+	// unlike the partial updates using the upload secrets, the whole resulting data is validated, so that the required keys
+	// cannot be deleted
+	if err := rs.ValidateSecretData(merged); err != nil {
+		change.reject("invalid_data")
+		auditLog.Info("data API partial update not started because of invalid data")
+		return invalid(rs, err)
+	}
+	if statusErr := s.checkDataSize(ctx, rs, merged, change); statusErr != nil {
+		auditLog.Info("data API partial update not started because the data is too large", "reason", statusErr.Error())
+		return statusErr
+	}
+
+	auditLog.Info("data API partial update initiated", "action", "UPDATE")
+	if err := s.Storage.PartialUpdate(ctx, rs, &updates, deletes); err != nil {
+		change.reject(remotesecretstorage.StorageWriteRejectionReason(err))
+		auditLog.Error(err, "data API partial update failed")
+		return apierrors.NewInternalError(fmt.Errorf("failed to partially update the data: %w", err))
+	}
+	auditLog.Info("data API partial update completed")
+	s.Recorder.Event(rs, corev1.EventTypeNormal, api.RemoteSecretEventReasonDataUploaded, "the data was partially updated using the data API")
+
+	return nil
+}
+
+// deleteData deletes the data of the remote secret from the storage. The remote secret itself stays in place and waits for new data.
+func (s *Server) deleteData(ctx context.Context, rs *api.RemoteSecret, change *dataChange) *apierrors.StatusError {
+	// the keys of the stored data are only known from the status
+	change.deletedKeys = rs.Status.SecretStatus.Keys
+
+	auditLog := logs.AuditLog(ctx).WithValues("remoteSecret", client.ObjectKeyFromObject(rs))
+
+	auditLog.Info("data API deletion initiated", "action", "DELETE")
+	if err := s.Storage.Delete(ctx, rs); err != nil && !errors.Is(err, secretstorage.NotFoundError) {
+		change.reject("storage_delete_failed")
+		auditLog.Error(err, "data API deletion failed")
+		return apierrors.NewInternalError(fmt.Errorf("failed to delete the data: %w", err))
+	}
+	auditLog.Info("data API deletion completed")
+	s.Recorder.Event(rs, corev1.EventTypeNormal, api.RemoteSecretEventReasonDataDeleted, "the data was deleted using the data API")
+
+	return nil
+}
+
+// checkDataSize checks the data against the quota and the maximum data size of the secret storage.
+func (s *Server) checkDataSize(ctx context.Context, rs *api.RemoteSecret, data map[string][]byte, change *dataChange) *apierrors.StatusError {
+	if s.QuotaChecker != nil {
+		if err := s.QuotaChecker.CheckDataSize(ctx, rs, data); err != nil {
+			if errors.Is(err, quota.QuotaExceededError) {
+				change.reject("quota_exceeded")
+				return apierrors.NewForbidden(remoteSecretsResource, rs.Name, err)
+			}
+			change.reject("quota_check_failed")
+			return apierrors.NewInternalError(fmt.Errorf("failed to check the quota: %w", err))
+		}
+	}
+
+	if err := s.Storage.CheckDataSize(&data); err != nil {
+		if errors.Is(err, secretstorage.DataTooLargeError) {
+			change.reject("data_too_large")
+			return apierrors.NewRequestEntityTooLargeError(err.Error())
+		}
+		change.reject("data_size_check_failed")
+		return apierrors.NewInternalError(fmt.Errorf("failed to check the data size: %w", err))
+	}
+	return nil
+}
+
+// recordDataUpdate records the result of the change of the data in the status of the remote secret. The failure to record it
+// does not fail the request, because the data itself was already handled.
+func (s *Server) recordDataUpdate(ctx context.Context, rs *api.RemoteSecret, change *dataChange, err error) {
+	update := remotesecrets.NewDataUpdate(api.DataUpdateSourceDataAPI, "", change.data, change.deletedKeys, change.reason, err)
+	if recErr := remotesecrets.RecordDataUpdate(ctx, s.Client, rs, update); recErr != nil {
+		lg(ctx).Error(recErr, "failed to record the result of the data API request in the status of the remote secret")
+	}
+}
+
+// notifyDataUpdated asks for the reconciliation of the remote secret so that the new data is deployed to the targets. This is
+// done by changing the DataAPIUpdateAnnotation, so that the controller picks the change up even if it runs in another replica.
+func (s *Server) notifyDataUpdated(ctx context.Context, rs *api.RemoteSecret) {
+	orig := rs.DeepCopy()
+	if rs.Annotations == nil {
+		rs.Annotations = map[string]string{}
+	}
+	rs.Annotations[api.DataAPIUpdateAnnotation] = time.Now().UTC().Format(time.RFC3339Nano)
+	if err := s.Client.Patch(ctx, rs, client.MergeFrom(orig)); err != nil {
+		lg(ctx).Error(err, "failed to notify about the changed data, the remote secret will pick it up on its next reconciliation")
+	}
+}
+
+func decodeBody(r *http.Request, obj any) *apierrors.StatusError {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBodySize+1))
+	if err != nil {
+		return apierrors.NewBadRequest(fmt.Sprintf("failed to read the request body: %s", err))
+	}
+	if len(body) > maxRequestBodySize {
+		return apierrors.NewRequestEntityTooLargeError(fmt.Sprintf("the request body is larger than %d bytes", maxRequestBodySize))
+	}
+	if err := json.Unmarshal(body, obj); err != nil {
+		return apierrors.NewBadRequest(fmt.Sprintf("failed to decode the request body: %s", err))
+	}
+	return nil
+}
+
+func invalid(rs *api.RemoteSecret, err error) *apierrors.StatusError {
+	return &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusUnprocessableEntity,
+		Reason:  metav1.StatusReasonInvalid,
+		Message: fmt.Sprintf("the data of the remote secret %s is not valid: %s", rs.Name, err),
+		Details: &metav1.StatusDetails{Group: GroupName, Kind: dataKind, Name: rs.Name},
+	}}
+}
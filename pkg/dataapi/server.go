@@ -0,0 +1,255 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dataapi implements the aggregated API server that serves the data subresource of the remote secrets. The data is
+// written directly to the secret storage, so that it is never persisted in etcd, unlike the data in the upload secrets or in
+// the data fields of the remote secrets.
+package dataapi
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/redhat-appstudio/remote-secret/controllers/remotesecretstorage"
+	"github.com/redhat-appstudio/remote-secret/pkg/config"
+	"github.com/redhat-appstudio/remote-secret/pkg/quota"
+	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage"
+)
+
+const (
+	// GroupName is the API group served by the data API server. It cannot be the group of the remote secrets, because
+	// that is served by the CRD.
+	GroupName = "data.appstudio.redhat.com"
+	// Version is the version of the API served by the data API server.
+	Version = "v1alpha1"
+
+	resourceName    = "remotesecrets"
+	subresourceName = "data"
+	dataKind        = "RemoteSecretData"
+)
+
+// GroupVersion is the group and version served by the data API server.
+var GroupVersion = schema.GroupVersion{Group: GroupName, Version: Version}
+
+var remoteSecretsResource = GroupVersion.WithResource(resourceName).GroupResource()
+
+// Server is the aggregated API server serving the data subresource of the remote secrets. It runs in all the replicas, because
+// the data is written to the secret storage directly and the controller is notified about the changed data using the
+// DataAPIUpdateAnnotation on the remote secret.
+type Server struct {
+	// BindAddress is the address the server listens on.
+	BindAddress string
+	// CertDir is the directory with the tls.crt and tls.key serving certificate.
+	CertDir string
+	// Reader is used to read the configuration of the front proxy authentication from the cluster.
+	Reader client.Reader
+	// Authenticator authenticates the requests. It is loaded from the cluster when the server starts if nil.
+	Authenticator *RequestHeaderAuthenticator
+	// Client is used to read the remote secrets and to check the permissions of the users using the subject access reviews.
+	Client client.Client
+	// Storage is where the data of the remote secrets is written.
+	Storage remotesecretstorage.RemoteSecretStorage
+	// QuotaChecker checks the size of the data against the quota. If nil, the quota is not checked.
+	QuotaChecker *quota.Checker
+	Recorder     record.EventRecorder
+}
+
+var _ manager.Runnable = (*Server)(nil)
+var _ manager.LeaderElectionRunnable = (*Server)(nil)
+var _ http.Handler = (*Server)(nil)
+
+// SetupServer adds the data API server to the manager.
+func SetupServer(mgr manager.Manager, cfg *config.OperatorConfiguration, secretStorage secretstorage.SecretStorage, bindAddress, certDir string) error {
+	storage := remotesecretstorage.NewJSONSerializingRemoteSecretStorage(secretStorage)
+	if err := storage.Initialize(context.Background()); err != nil {
+		return fmt.Errorf("failed to initialize the remote secret storage of the data API: %w", err)
+	}
+
+	if err := mgr.Add(&Server{
+		BindAddress:  bindAddress,
+		CertDir:      certDir,
+		Reader:       mgr.GetAPIReader(),
+		Client:       mgr.GetClient(),
+		Storage:      storage,
+		QuotaChecker: &quota.Checker{Client: mgr.GetClient(), Quota: &cfg.Quota},
+		Recorder:     mgr.GetEventRecorderFor("remotesecret-data-api"),
+	}); err != nil {
+		return fmt.Errorf("failed to add the data API server to the manager: %w", err)
+	}
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. The server runs in all the replicas, because the service
+// in front of the operator sends the requests to any of them.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements manager.Runnable. It serves the requests until the context is cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	lg := log.FromContext(ctx).WithName("data-api")
+
+	if s.Authenticator == nil {
+		auth, err := LoadRequestHeaderAuthenticator(ctx, s.Reader)
+		if err != nil {
+			return err
+		}
+		s.Authenticator = auth
+	}
+
+	certWatcher, err := certwatcher.New(filepath.Join(s.CertDir, "tls.crt"), filepath.Join(s.CertDir, "tls.key"))
+	if err != nil {
+		return fmt.Errorf("failed to load the serving certificate of the data API server: %w", err)
+	}
+	go func() {
+		if err := certWatcher.Start(ctx); err != nil {
+			lg.Error(err, "certificate watcher of the data API server failed")
+		}
+	}()
+
+	cfg := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: certWatcher.GetCertificate,
+		ClientCAs:      s.Authenticator.ClientCAs,
+		// the discovery doesn't require authentication, the data endpoints check the verified chains themselves
+		ClientAuth: tls.VerifyClientCertIfGiven,
+	}
+
+	listener, err := tls.Listen("tcp", s.BindAddress, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.BindAddress, err)
+	}
+
+	srv := &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+		// the requests should not be cancelled when the server starts shutting down, so we only keep the logger from the context
+		BaseContext: func(net.Listener) context.Context {
+			return log.IntoContext(context.Background(), lg)
+		},
+	}
+
+	idleConnsClosed := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		lg.Info("shutting down the data API server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			lg.Error(err, "error shutting down the data API server")
+		}
+		close(idleConnsClosed)
+	}()
+
+	lg.Info("serving the data API", "address", s.BindAddress)
+	if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("the data API server failed: %w", err)
+	}
+
+	<-idleConnsClosed
+	return nil
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	groupPath := "/apis/" + GroupName
+	versionPath := groupPath + "/" + Version
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	switch {
+	case path == groupPath && r.Method == http.MethodGet:
+		writeJSON(r.Context(), w, http.StatusOK, apiGroup())
+	case path == versionPath && r.Method == http.MethodGet:
+		writeJSON(r.Context(), w, http.StatusOK, apiResourceList())
+	case strings.HasPrefix(path, versionPath+"/"):
+		// namespaces/<namespace>/remotesecrets/<name>/data
+		segments := strings.Split(strings.TrimPrefix(path, versionPath+"/"), "/")
+		if len(segments) != 5 || segments[0] != "namespaces" || segments[2] != resourceName || segments[4] != subresourceName {
+			writeStatus(r.Context(), w, apierrors.NewNotFound(GroupVersion.WithResource(strings.Join(segments, "/")).GroupResource(), ""))
+			return
+		}
+		s.handleData(w, r, client.ObjectKey{Namespace: segments[1], Name: segments[3]})
+	default:
+		writeStatus(r.Context(), w, apierrors.NewNotFound(schema.GroupResource{}, path))
+	}
+}
+
+func apiGroup() *metav1.APIGroup {
+	version := metav1.GroupVersionForDiscovery{GroupVersion: GroupVersion.String(), Version: Version}
+	return &metav1.APIGroup{
+		TypeMeta:         metav1.TypeMeta{Kind: "APIGroup", APIVersion: "v1"},
+		Name:             GroupName,
+		Versions:         []metav1.GroupVersionForDiscovery{version},
+		PreferredVersion: version,
+	}
+}
+
+func apiResourceList() *metav1.APIResourceList {
+	return &metav1.APIResourceList{
+		TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+		GroupVersion: GroupVersion.String(),
+		APIResources: []metav1.APIResource{
+			{
+				Name:       resourceName + "/" + subresourceName,
+				Namespaced: true,
+				Kind:       dataKind,
+				Verbs:      metav1.Verbs{"update", "patch", "delete"},
+			},
+		},
+	}
+}
+
+func writeStatus(ctx context.Context, w http.ResponseWriter, statusErr *apierrors.StatusError) {
+	status := statusErr.Status()
+	status.TypeMeta = metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}
+	writeJSON(ctx, w, int(status.Code), &status)
+}
+
+func writeSuccess(ctx context.Context, w http.ResponseWriter) {
+	writeJSON(ctx, w, http.StatusOK, &metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusSuccess,
+		Code:     http.StatusOK,
+	})
+}
+
+func writeJSON(ctx context.Context, w http.ResponseWriter, code int, obj any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		lg(ctx).Error(err, "failed to write the response")
+	}
+}
+
+func lg(ctx context.Context) logr.Logger {
+	return log.FromContext(ctx)
+}
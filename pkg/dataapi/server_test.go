@@ -0,0 +1,260 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataapi
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	authzv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/redhat-appstudio/remote-secret/controllers/remotesecretstorage"
+	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage"
+	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage/memorystorage"
+)
+
+const dataPath = "/apis/data.appstudio.redhat.com/v1alpha1/namespaces/ns/remotesecrets/%s/data"
+
+func newTestServer(t *testing.T, objs ...client.Object) (*Server, remotesecretstorage.RemoteSecretStorage) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, api.AddToScheme(scheme))
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&api.RemoteSecret{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				sar, ok := obj.(*authzv1.SubjectAccessReview)
+				if !ok {
+					return cl.Create(ctx, obj, opts...)
+				}
+				attrs := sar.Spec.ResourceAttributes
+				sar.Status.Allowed = sar.Spec.User == "alice" && attrs.Group == GroupName && attrs.Resource == "remotesecrets" && attrs.Subresource == "data"
+				return nil
+			},
+		}).
+		Build()
+
+	storage := remotesecretstorage.NewJSONSerializingRemoteSecretStorage(&memorystorage.MemoryStorage{})
+	assert.NoError(t, storage.Initialize(context.TODO()))
+
+	return &Server{
+		Authenticator: &RequestHeaderAuthenticator{
+			AllowedNames:    []string{"front-proxy-client"},
+			UsernameHeaders: []string{"X-Remote-User"},
+			GroupHeaders:    []string{"X-Remote-Group"},
+		},
+		Client:   cl,
+		Storage:  storage,
+		Recorder: record.NewFakeRecorder(10),
+	}, storage
+}
+
+func newRequest(method, name, user, contentType, body string) *http.Request {
+	req := httptest.NewRequest(method, strings.Replace(dataPath, "%s", name, 1), strings.NewReader(body))
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "front-proxy-client"}}}}}
+	if user != "" {
+		req.Header.Set("X-Remote-User", user)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req
+}
+
+func serve(s *Server, req *http.Request) (int, *metav1.Status) {
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	status := &metav1.Status{}
+	_ = json.Unmarshal(rec.Body.Bytes(), status)
+	return rec.Code, status
+}
+
+func TestDiscovery(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/apis/data.appstudio.redhat.com/v1alpha1", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	list := &metav1.APIResourceList{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), list))
+	assert.Equal(t, "data.appstudio.redhat.com/v1alpha1", list.GroupVersion)
+	assert.Len(t, list.APIResources, 1)
+	assert.Equal(t, "remotesecrets/data", list.APIResources[0].Name)
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/apis/data.appstudio.redhat.com", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/apis/data.appstudio.redhat.com/v1alpha1/namespaces/ns/remotesecrets/rs", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestPut(t *testing.T) {
+	rs := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "ns"},
+		Spec: api.RemoteSecretSpec{
+			Secret: api.LinkableSecretSpec{RequiredKeys: []api.SecretKey{{Name: "token"}}},
+		},
+	}
+	s, storage := newTestServer(t, rs)
+
+	t.Run("stores the data", func(t *testing.T) {
+		code, _ := serve(s, newRequest(http.MethodPut, "rs", "alice", "application/json", `{"data":{"a":"Yg=="},"stringData":{"token":"tkn"}}`))
+		assert.Equal(t, http.StatusOK, code)
+
+		data, err := storage.Get(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Equal(t, remotesecretstorage.SecretData{"a": []byte("b"), "token": []byte("tkn")}, *data)
+		assert.NotEmpty(t, dataAPIUpdate(t, s, "rs"))
+
+		update := lastDataUpdate(t, s, "rs")
+		assert.Equal(t, api.DataUpdateSourceDataAPI, update.Source)
+		assert.Equal(t, api.DataUpdateResultSucceeded, update.Result)
+		assert.Equal(t, []string{"a", "token"}, update.Keys)
+	})
+
+	t.Run("validates the data", func(t *testing.T) {
+		notified := dataAPIUpdate(t, s, "rs")
+		code, status := serve(s, newRequest(http.MethodPut, "rs", "alice", "application/json", `{"stringData":{"a":"b"}}`))
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+		assert.Equal(t, metav1.StatusReasonInvalid, status.Reason)
+		assert.Equal(t, notified, dataAPIUpdate(t, s, "rs"))
+
+		update := lastDataUpdate(t, s, "rs")
+		assert.Equal(t, api.DataUpdateResultFailed, update.Result)
+		assert.Equal(t, "invalid_data", update.Reason)
+		assert.Equal(t, []string{"a"}, update.Keys)
+		assert.NotEmpty(t, update.Message)
+	})
+
+	t.Run("requires authentication", func(t *testing.T) {
+		req := newRequest(http.MethodPut, "rs", "alice", "application/json", `{"stringData":{"token":"b"}}`)
+		req.TLS = nil
+		code, _ := serve(s, req)
+		assert.Equal(t, http.StatusUnauthorized, code)
+
+		code, _ = serve(s, newRequest(http.MethodPut, "rs", "", "application/json", `{"stringData":{"token":"b"}}`))
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+
+	t.Run("requires authorization", func(t *testing.T) {
+		code, status := serve(s, newRequest(http.MethodPut, "rs", "bob", "application/json", `{"stringData":{"token":"b"}}`))
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Equal(t, metav1.StatusReasonForbidden, status.Reason)
+	})
+
+	t.Run("non-existent remote secret", func(t *testing.T) {
+		code, _ := serve(s, newRequest(http.MethodPut, "other", "alice", "application/json", `{"stringData":{"token":"b"}}`))
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("unsupported method", func(t *testing.T) {
+		code, _ := serve(s, newRequest(http.MethodPost, "rs", "alice", "application/json", `{}`))
+		assert.Equal(t, http.StatusMethodNotAllowed, code)
+	})
+}
+
+func TestPatch(t *testing.T) {
+	rs := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "ns"},
+		Spec: api.RemoteSecretSpec{
+			Secret: api.LinkableSecretSpec{RequiredKeys: []api.SecretKey{{Name: "token"}}},
+		},
+	}
+	s, storage := newTestServer(t, rs)
+	assert.NoError(t, storage.Store(context.TODO(), rs, &remotesecretstorage.SecretData{"token": []byte("tkn"), "a": []byte("a"), "b": []byte("b")}))
+
+	t.Run("updates and deletes the keys", func(t *testing.T) {
+		code, _ := serve(s, newRequest(http.MethodPatch, "rs", "alice", "application/merge-patch+json", `{"data":{"a":"eA==","b":null},"stringData":{"c":"c"}}`))
+		assert.Equal(t, http.StatusOK, code)
+
+		data, err := storage.Get(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Equal(t, remotesecretstorage.SecretData{"token": []byte("tkn"), "a": []byte("x"), "c": []byte("c")}, *data)
+		assert.NotEmpty(t, dataAPIUpdate(t, s, "rs"))
+
+		update := lastDataUpdate(t, s, "rs")
+		assert.Equal(t, api.DataUpdateResultSucceeded, update.Result)
+		assert.Equal(t, []string{"a", "c"}, update.Keys)
+		assert.Equal(t, []string{"b"}, update.DeletedKeys)
+	})
+
+	t.Run("cannot delete the required keys", func(t *testing.T) {
+		code, _ := serve(s, newRequest(http.MethodPatch, "rs", "alice", "application/merge-patch+json", `{"stringData":{"token":null}}`))
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+	})
+
+	t.Run("only merge patch", func(t *testing.T) {
+		code, _ := serve(s, newRequest(http.MethodPatch, "rs", "alice", "application/json-patch+json", `[]`))
+		assert.Equal(t, http.StatusUnsupportedMediaType, code)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	rs := &api.RemoteSecret{ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "ns"}, Status: api.RemoteSecretStatus{SecretStatus: api.SecretStatus{Keys: []string{"a"}}}}
+	suspended := &api.RemoteSecret{ObjectMeta: metav1.ObjectMeta{Name: "suspended", Namespace: "ns"}, Spec: api.RemoteSecretSpec{Suspend: true}}
+	s, storage := newTestServer(t, rs, suspended)
+	assert.NoError(t, storage.Store(context.TODO(), rs, &remotesecretstorage.SecretData{"a": []byte("b")}))
+	assert.NoError(t, storage.Store(context.TODO(), suspended, &remotesecretstorage.SecretData{"a": []byte("b")}))
+
+	code, _ := serve(s, newRequest(http.MethodDelete, "rs", "alice", "", ""))
+	assert.Equal(t, http.StatusOK, code)
+	_, err := storage.Get(context.TODO(), rs)
+	assert.ErrorIs(t, err, secretstorage.NotFoundError)
+	assert.NotEmpty(t, dataAPIUpdate(t, s, "rs"))
+	assert.Equal(t, []string{"a"}, lastDataUpdate(t, s, "rs").DeletedKeys)
+
+	code, status := serve(s, newRequest(http.MethodDelete, "suspended", "alice", "", ""))
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Contains(t, status.Message, errRemoteSecretSuspended.Error())
+	_, err = storage.Get(context.TODO(), suspended)
+	assert.NoError(t, err)
+	assert.Equal(t, "remote_secret_suspended", lastDataUpdate(t, s, "suspended").Reason)
+	assert.Empty(t, dataAPIUpdate(t, s, "suspended"))
+}
+
+func lastDataUpdate(t *testing.T, s *Server, name string) *api.DataUpdateStatus {
+	rs := &api.RemoteSecret{}
+	assert.NoError(t, s.Client.Get(context.TODO(), client.ObjectKey{Name: name, Namespace: "ns"}, rs))
+	if !assert.NotNil(t, rs.Status.LastDataUpdate) {
+		return &api.DataUpdateStatus{}
+	}
+	return rs.Status.LastDataUpdate
+}
+
+func dataAPIUpdate(t *testing.T, s *Server, name string) string {
+	rs := &api.RemoteSecret{}
+	assert.NoError(t, s.Client.Get(context.TODO(), client.ObjectKey{Name: name, Namespace: "ns"}, rs))
+	return rs.Annotations[api.DataAPIUpdateAnnotation]
+}
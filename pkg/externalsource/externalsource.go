@@ -0,0 +1,197 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package externalsource reads the data of the remote secrets with an external source from the external secret store
+// using the providers of the External Secrets Operator.
+package externalsource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	es "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
+	"github.com/external-secrets/external-secrets/pkg/provider/aws"
+	"github.com/external-secrets/external-secrets/pkg/provider/fake"
+	"github.com/external-secrets/external-secrets/pkg/provider/vault"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/redhat-appstudio/remote-secret/pkg/logs"
+)
+
+// init ESO Providers
+var (
+	_ = fake.Provider{}
+	_ = vault.Connector{}
+	_ = aws.Provider{}
+)
+
+// NamespacePlaceholder is replaced by the namespace of the remote secret in the key prefix.
+const NamespacePlaceholder = "{namespace}"
+
+// DefaultRefreshInterval is how often the data is read from the external secret store if the remote secret doesn't specify it.
+const DefaultRefreshInterval = time.Hour
+
+// MinimumRefreshInterval is the shortest refresh interval the remote secrets can specify so that the external secret store is not
+// overloaded.
+const MinimumRefreshInterval = time.Minute
+
+var (
+	// NotFoundError is returned when a secret referenced by the remote secret doesn't exist in the external secret store.
+	NotFoundError   = errors.New("secret not found in the external secret store")
+	invalidKeyError = errors.New("the key of the secret in the external secret store is not valid")
+)
+
+// Source reads the data from the external secret store described by the configuration of an ESO provider.
+type Source struct {
+	// ProviderConfig is the configuration of the ESO provider used to access the external secret store.
+	ProviderConfig *es.SecretStoreProvider
+	// KeyPrefix is prepended to all the keys specified in the remote secrets. It can contain the NamespacePlaceholder so that
+	// the remote secrets in each namespace can only read their own part of the external secret store.
+	KeyPrefix string
+	// Client is the client used by the provider to read the credentials to the external secret store.
+	Client   client.Client
+	store    es.ClusterSecretStore
+	provider es.Provider
+}
+
+// NewSource creates a new source from the JSON configuration of the ESO provider. The source needs to be initialized before use.
+func NewSource(cl client.Client, providerConfJSON string, keyPrefix string) (*Source, error) {
+	providerConf := &es.SecretStoreProvider{}
+	if err := json.Unmarshal([]byte(providerConfJSON), providerConf); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the configuration of the external source provider: %w", err)
+	}
+
+	return &Source{ProviderConfig: providerConf, KeyPrefix: keyPrefix, Client: cl}, nil
+}
+
+// Initialize finds the ESO provider for the configuration.
+func (s *Source) Initialize(ctx context.Context) error {
+	s.store = es.ClusterSecretStore{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       es.ClusterSecretStoreKind,
+			APIVersion: es.ClusterSecretStoreKindAPIVersion,
+		},
+		Spec: es.SecretStoreSpec{
+			Provider: s.ProviderConfig,
+		},
+	}
+
+	var err error
+	s.provider, err = es.GetProvider(&s.store)
+	if err != nil {
+		return fmt.Errorf("failed to get the provider of the external source: %w", err)
+	}
+	lg(ctx).V(logs.DebugLevel).Info("initialized the external source")
+	return nil
+}
+
+// Fetch reads the data of the remote secret from the external secret store. The secrets in the dataFrom of the external source
+// are read first in the order of the list and the secrets in the data are put over them.
+func (s *Source) Fetch(ctx context.Context, rs *api.RemoteSecret) (map[string][]byte, error) {
+	if rs.Spec.ExternalSource == nil {
+		return nil, nil
+	}
+
+	cl, err := s.provider.NewClient(ctx, &s.store, s.Client, rs.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the client of the external source: %w", err)
+	}
+	defer func() {
+		if err := cl.Close(ctx); err != nil {
+			lg(ctx).Error(err, "failed to close the client of the external source")
+		}
+	}()
+
+	data := map[string][]byte{}
+
+	for _, ref := range rs.Spec.ExternalSource.DataFrom {
+		remoteRef, err := s.remoteRef(rs.Namespace, &ref)
+		if err != nil {
+			return nil, err
+		}
+		values, err := cl.GetSecretMap(ctx, remoteRef)
+		if err != nil {
+			return nil, wrapProviderError(ref.Key, err)
+		}
+		for k, v := range values {
+			data[k] = v
+		}
+	}
+
+	for _, d := range rs.Spec.ExternalSource.Data {
+		remoteRef, err := s.remoteRef(rs.Namespace, &d.RemoteRef)
+		if err != nil {
+			return nil, err
+		}
+		value, err := cl.GetSecret(ctx, remoteRef)
+		if err != nil {
+			return nil, wrapProviderError(d.RemoteRef.Key, err)
+		}
+		data[d.SecretKey] = value
+	}
+
+	return data, nil
+}
+
+// remoteRef converts the reference in the remote secret to the reference understood by the ESO providers, applying the prefix.
+func (s *Source) remoteRef(namespace string, ref *api.ExternalSourceRef) (es.ExternalSecretDataRemoteRef, error) {
+	if err := ValidateKey(ref.Key); err != nil {
+		return es.ExternalSecretDataRemoteRef{}, err
+	}
+	return es.ExternalSecretDataRemoteRef{
+		Key:      strings.ReplaceAll(s.KeyPrefix, NamespacePlaceholder, namespace) + ref.Key,
+		Property: ref.Property,
+		Version:  ref.Version,
+	}, nil
+}
+
+// ValidateKey checks that the key cannot be used to escape the configured prefix.
+func ValidateKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("%w: the key is empty", invalidKeyError)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == ".." {
+			return fmt.Errorf("%w: %s", invalidKeyError, key)
+		}
+	}
+	return nil
+}
+
+// RefreshInterval returns the interval in which the data of the remote secret should be read from the external secret store.
+func RefreshInterval(rs *api.RemoteSecret) time.Duration {
+	if rs.Spec.ExternalSource == nil || rs.Spec.ExternalSource.RefreshInterval == nil || rs.Spec.ExternalSource.RefreshInterval.Duration <= 0 {
+		return DefaultRefreshInterval
+	}
+	return rs.Spec.ExternalSource.RefreshInterval.Duration
+}
+
+func wrapProviderError(key string, err error) error {
+	if errors.Is(err, es.NoSecretErr) {
+		return fmt.Errorf("%w: %s", NotFoundError, key)
+	}
+	return fmt.Errorf("failed to read the secret %s from the external source: %w", key, err)
+}
+
+func lg(ctx context.Context) logr.Logger {
+	return log.FromContext(ctx, "externalsource", "eso")
+}
@@ -0,0 +1,143 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package externalsource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+)
+
+const fakeProviderConfig = `{"fake":{"data":[
+	{"key":"ns/db", "value":"{\"user\":\"admin\",\"password\":\"secret\"}", "valueMap":{"user":"admin","password":"secret"}},
+	{"key":"ns/token", "value":"tkn"},
+	{"key":"other/token", "value":"other"}
+]}}`
+
+func newTestSource(t *testing.T) *Source {
+	s, err := NewSource(nil, fakeProviderConfig, "{namespace}/")
+	assert.NoError(t, err)
+	assert.NoError(t, s.Initialize(context.TODO()))
+	return s
+}
+
+func TestNewSource(t *testing.T) {
+	t.Run("invalid json", func(t *testing.T) {
+		s, err := NewSource(nil, `{"fake":`, "")
+		assert.Error(t, err)
+		assert.Nil(t, s)
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		s, err := NewSource(nil, `{}`, "")
+		assert.NoError(t, err)
+		assert.Error(t, s.Initialize(context.TODO()))
+	})
+}
+
+func TestFetch(t *testing.T) {
+	s := newTestSource(t)
+
+	t.Run("data and dataFrom", func(t *testing.T) {
+		rs := &api.RemoteSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "ns"},
+			Spec: api.RemoteSecretSpec{
+				ExternalSource: &api.ExternalSource{
+					DataFrom: []api.ExternalSourceRef{{Key: "db"}},
+					Data: []api.ExternalSourceData{
+						{SecretKey: "token", RemoteRef: api.ExternalSourceRef{Key: "token"}},
+						{SecretKey: "user", RemoteRef: api.ExternalSourceRef{Key: "db", Property: "password"}},
+					},
+				},
+			},
+		}
+
+		data, err := s.Fetch(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]byte{
+			"user":     []byte("secret"),
+			"password": []byte("secret"),
+			"token":    []byte("tkn"),
+		}, data)
+	})
+
+	t.Run("prefixed by namespace", func(t *testing.T) {
+		rs := &api.RemoteSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "other"},
+			Spec: api.RemoteSecretSpec{
+				ExternalSource: &api.ExternalSource{
+					Data: []api.ExternalSourceData{{SecretKey: "token", RemoteRef: api.ExternalSourceRef{Key: "token"}}},
+				},
+			},
+		}
+
+		data, err := s.Fetch(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]byte{"token": []byte("other")}, data)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		rs := &api.RemoteSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "ns"},
+			Spec: api.RemoteSecretSpec{
+				ExternalSource: &api.ExternalSource{
+					Data: []api.ExternalSourceData{{SecretKey: "token", RemoteRef: api.ExternalSourceRef{Key: "missing"}}},
+				},
+			},
+		}
+
+		data, err := s.Fetch(context.TODO(), rs)
+		assert.ErrorIs(t, err, NotFoundError)
+		assert.Nil(t, data)
+	})
+
+	t.Run("escaping the prefix", func(t *testing.T) {
+		rs := &api.RemoteSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "ns"},
+			Spec: api.RemoteSecretSpec{
+				ExternalSource: &api.ExternalSource{
+					Data: []api.ExternalSourceData{{SecretKey: "token", RemoteRef: api.ExternalSourceRef{Key: "../other/token"}}},
+				},
+			},
+		}
+
+		data, err := s.Fetch(context.TODO(), rs)
+		assert.ErrorIs(t, err, invalidKeyError)
+		assert.Nil(t, data)
+	})
+}
+
+func TestValidateKey(t *testing.T) {
+	assert.NoError(t, ValidateKey("a/b..c/d"))
+	assert.Error(t, ValidateKey(""))
+	assert.Error(t, ValidateKey(".."))
+	assert.Error(t, ValidateKey("a/../b"))
+}
+
+func TestRefreshInterval(t *testing.T) {
+	rs := &api.RemoteSecret{}
+	assert.Equal(t, DefaultRefreshInterval, RefreshInterval(rs))
+
+	rs.Spec.ExternalSource = &api.ExternalSource{}
+	assert.Equal(t, DefaultRefreshInterval, RefreshInterval(rs))
+
+	rs.Spec.ExternalSource.RefreshInterval = &metav1.Duration{Duration: 5 * time.Minute}
+	assert.Equal(t, 5*time.Minute, RefreshInterval(rs))
+}
@@ -0,0 +1,136 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fingerprint computes the fingerprints of the values of the secret data. The fingerprints are keyed hashes, so
+// they can be published in the status of the remote secrets without revealing the values to anyone who could try to
+// guess them. The fingerprints are also salted, so that the same value doesn't have the same fingerprint in different
+// remote secrets, which would let the users compare them with the fingerprints of the values they uploaded themselves.
+package fingerprint
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+)
+
+var errEmptyKey = errors.New("the fingerprint key cannot be empty")
+
+// Fingerprinter computes the fingerprints of the values using a key held by the operator.
+type Fingerprinter struct {
+	key   []byte
+	keyID string
+}
+
+// New creates a new fingerprinter using the provided key.
+func New(key []byte) (*Fingerprinter, error) {
+	if len(key) == 0 {
+		return nil, errEmptyKey
+	}
+
+	f := &Fingerprinter{key: key}
+	// the key ID is itself a fingerprint, so it identifies the key without revealing it. It differs from the ID used when the
+	// fingerprints of the values were not salted, so that those are not compared with the salted ones.
+	f.keyID = f.Of([]byte("salted-key-id"))[:16]
+	return f, nil
+}
+
+// KeyID identifies the key used to compute the fingerprints.
+func (f *Fingerprinter) KeyID() string {
+	return f.keyID
+}
+
+// Of computes the fingerprint of the value.
+func (f *Fingerprinter) Of(value []byte) string {
+	mac := hmac.New(sha256.New, f.key)
+	mac.Write(value)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// OfValue computes the fingerprint of the value of the key of the data. The salt makes the same value have different fingerprints
+// in different contexts, just like in OfData.
+func (f *Fingerprinter) OfValue(salt string, key string, value []byte) string {
+	mac := hmac.New(sha256.New, f.key)
+	mac.Write([]byte(salt))
+	// a different separator than in OfData, so that the fingerprint of a value never equals the fingerprint of a data with a single key
+	mac.Write([]byte{1})
+	mac.Write([]byte(key))
+	mac.Write([]byte{0})
+	mac.Write(value)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// OfData computes the fingerprint of the whole data. The salt makes the same data have different fingerprints in different
+// contexts.
+func (f *Fingerprinter) OfData(salt string, data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	mac := hmac.New(sha256.New, f.key)
+	mac.Write([]byte(salt))
+	for _, k := range keys {
+		mac.Write([]byte{0})
+		mac.Write([]byte(k))
+		mac.Write([]byte{0})
+		mac.Write(data[k])
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// UpdateSecretStatus describes the keys of the data in the status. The fingerprints of the values are salted with the provided salt
+// and the name of the key. The time of the last modification of the keys whose values
+// didn't change since the status was last updated is kept. If the previous fingerprints were computed using a different key, the
+// values are assumed unchanged if they have the same size, because there is no way to compare them.
+func (f *Fingerprinter) UpdateSecretStatus(status *api.SecretStatus, salt string, data map[string][]byte, now metav1.Time) {
+	previous := make(map[string]*api.SecretKeyStatus, len(status.KeyDetails))
+	for i := range status.KeyDetails {
+		previous[status.KeyDetails[i].Name] = &status.KeyDetails[i]
+	}
+	sameKey := status.FingerprintKeyID == f.keyID
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	details := make([]api.SecretKeyStatus, len(keys))
+	for i, k := range keys {
+		details[i] = api.SecretKeyStatus{
+			Name:         k,
+			Fingerprint:  f.OfValue(salt, k, data[k]),
+			Size:         int64(len(data[k])),
+			LastModified: &now,
+		}
+
+		prev := previous[k]
+		if prev == nil || prev.LastModified == nil || prev.Size != details[i].Size {
+			continue
+		}
+		if !sameKey || prev.Fingerprint == details[i].Fingerprint {
+			details[i].LastModified = prev.LastModified
+		}
+	}
+
+	status.KeyDetails = details
+	status.FingerprintKeyID = f.keyID
+}
@@ -0,0 +1,132 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fingerprint
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+)
+
+func TestOf(t *testing.T) {
+	f1, err := New([]byte("key1"))
+	assert.NoError(t, err)
+	f2, err := New([]byte("key2"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, f1.Of([]byte("value")), f1.Of([]byte("value")))
+	assert.NotEqual(t, f1.Of([]byte("value")), f1.Of([]byte("other")))
+	assert.NotEqual(t, f1.Of([]byte("value")), f2.Of([]byte("value")))
+	assert.NotEqual(t, f1.KeyID(), f2.KeyID())
+
+	_, err = New(nil)
+	assert.Error(t, err)
+}
+
+func TestOfValue(t *testing.T) {
+	f1, err := New([]byte("key1"))
+	assert.NoError(t, err)
+	f2, err := New([]byte("key2"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, f1.OfValue("1", "a", []byte("value")), f1.OfValue("1", "a", []byte("value")))
+	assert.NotEqual(t, f1.OfValue("1", "a", []byte("value")), f1.OfValue("1", "a", []byte("other")))
+	assert.NotEqual(t, f1.OfValue("1", "a", []byte("value")), f1.OfValue("2", "a", []byte("value")))
+	assert.NotEqual(t, f1.OfValue("1", "a", []byte("value")), f1.OfValue("1", "b", []byte("value")))
+	assert.NotEqual(t, f1.OfValue("1", "a", []byte("value")), f2.OfValue("1", "a", []byte("value")))
+	assert.NotEqual(t, f1.OfValue("1", "a", []byte("value")), f1.OfData("1", map[string][]byte{"a": []byte("value")}))
+}
+
+func TestOfData(t *testing.T) {
+	f1, err := New([]byte("key1"))
+	assert.NoError(t, err)
+	f2, err := New([]byte("key2"))
+	assert.NoError(t, err)
+
+	data := map[string][]byte{"a": []byte("b"), "c": []byte("d")}
+	sameData := map[string][]byte{"c": []byte("d"), "a": []byte("b")}
+	shiftedData := map[string][]byte{"a": []byte("bc"), "": []byte("d")}
+
+	assert.Equal(t, f1.OfData("1", data), f1.OfData("1", sameData))
+	assert.NotEqual(t, f1.OfData("1", data), f1.OfData("2", data))
+	assert.NotEqual(t, f1.OfData("1", data), f1.OfData("1", shiftedData))
+	assert.NotEqual(t, f1.OfData("1", data), f2.OfData("1", data))
+}
+
+func TestUpdateSecretStatus(t *testing.T) {
+	f, err := New([]byte("key"))
+	assert.NoError(t, err)
+
+	before := metav1.NewTime(time.Date(2023, 9, 1, 10, 0, 0, 0, time.UTC))
+	now := metav1.NewTime(time.Date(2023, 9, 2, 10, 0, 0, 0, time.UTC))
+
+	t.Run("new keys", func(t *testing.T) {
+		status := &api.SecretStatus{}
+		f.UpdateSecretStatus(status, "uid", map[string][]byte{"b": []byte("bb"), "a": []byte("a")}, now)
+
+		assert.Equal(t, f.KeyID(), status.FingerprintKeyID)
+		assert.Equal(t, []api.SecretKeyStatus{
+			{Name: "a", Fingerprint: f.OfValue("uid", "a", []byte("a")), Size: 1, LastModified: &now},
+			{Name: "b", Fingerprint: f.OfValue("uid", "b", []byte("bb")), Size: 2, LastModified: &now},
+		}, status.KeyDetails)
+	})
+
+	t.Run("same value in different remote secrets", func(t *testing.T) {
+		status1 := &api.SecretStatus{}
+		f.UpdateSecretStatus(status1, "uid1", map[string][]byte{"a": []byte("value")}, now)
+		status2 := &api.SecretStatus{}
+		f.UpdateSecretStatus(status2, "uid2", map[string][]byte{"a": []byte("value")}, now)
+
+		assert.NotEqual(t, status1.KeyDetails[0].Fingerprint, status2.KeyDetails[0].Fingerprint)
+	})
+
+	t.Run("only changed keys are modified", func(t *testing.T) {
+		status := &api.SecretStatus{
+			FingerprintKeyID: f.KeyID(),
+			KeyDetails: []api.SecretKeyStatus{
+				{Name: "same", Fingerprint: f.OfValue("uid", "same", []byte("same")), Size: 4, LastModified: &before},
+				{Name: "changed", Fingerprint: f.OfValue("uid", "changed", []byte("abcd")), Size: 4, LastModified: &before},
+				{Name: "deleted", Fingerprint: f.OfValue("uid", "deleted", []byte("x")), Size: 1, LastModified: &before},
+			},
+		}
+		f.UpdateSecretStatus(status, "uid", map[string][]byte{"same": []byte("same"), "changed": []byte("efgh")}, now)
+
+		assert.Equal(t, []api.SecretKeyStatus{
+			{Name: "changed", Fingerprint: f.OfValue("uid", "changed", []byte("efgh")), Size: 4, LastModified: &now},
+			{Name: "same", Fingerprint: f.OfValue("uid", "same", []byte("same")), Size: 4, LastModified: &before},
+		}, status.KeyDetails)
+	})
+
+	t.Run("different fingerprint key", func(t *testing.T) {
+		status := &api.SecretStatus{
+			FingerprintKeyID: "other",
+			KeyDetails: []api.SecretKeyStatus{
+				{Name: "same-size", Fingerprint: "fp1", Size: 4, LastModified: &before},
+				{Name: "other-size", Fingerprint: "fp2", Size: 4, LastModified: &before},
+			},
+		}
+		f.UpdateSecretStatus(status, "uid", map[string][]byte{"same-size": []byte("abcd"), "other-size": []byte("abcde")}, now)
+
+		assert.Equal(t, f.KeyID(), status.FingerprintKeyID)
+		assert.Equal(t, []api.SecretKeyStatus{
+			{Name: "other-size", Fingerprint: f.OfValue("uid", "other-size", []byte("abcde")), Size: 5, LastModified: &now},
+			{Name: "same-size", Fingerprint: f.OfValue("uid", "same-size", []byte("abcd")), Size: 4, LastModified: &before},
+		}, status.KeyDetails)
+	})
+}
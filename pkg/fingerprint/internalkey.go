@@ -0,0 +1,90 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fingerprint
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// InternalKeySecretName is the name of the secret in the namespace of the operator that holds the internal key. The internal key
+// is used to compute the fingerprints of the data that detect its changes when no fingerprint key is configured.
+const InternalKeySecretName = "remotesecret-internal-fingerprint-key" //#nosec G101 -- false positive, this is just the name of the secret
+
+// internalKeyDataKey is the key of the data of the secret that holds the internal key.
+const internalKeyDataKey = "key"
+
+// randomKeyLength is the number of the random bytes of the generated keys.
+const randomKeyLength = 32
+
+// LoadInternalKey reads the internal key from the secret in the provided namespace. If the secret doesn't exist, it is created with
+// a new random key, so that all the replicas of the operator use the same key, which also survives the restarts.
+func LoadInternalKey(ctx context.Context, reader client.Reader, cl client.Client, namespace string) ([]byte, error) {
+	key := client.ObjectKey{Name: InternalKeySecretName, Namespace: namespace}
+
+	secret := &corev1.Secret{}
+	err := reader.Get(ctx, key, secret)
+	if err == nil {
+		return internalKeyFrom(secret)
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to read the internal fingerprint key: %w", err)
+	}
+
+	random, err := RandomKey()
+	if err != nil {
+		return nil, err
+	}
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		Data:       map[string][]byte{internalKeyDataKey: random},
+	}
+	if err := cl.Create(ctx, secret); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("failed to create the internal fingerprint key: %w", err)
+		}
+		// another replica was faster
+		if err := reader.Get(ctx, key, secret); err != nil {
+			return nil, fmt.Errorf("failed to read the internal fingerprint key: %w", err)
+		}
+		return internalKeyFrom(secret)
+	}
+
+	return random, nil
+}
+
+// RandomKey generates a new random key. The fingerprints computed using it cannot be compared with the fingerprints computed
+// by other processes.
+func RandomKey() ([]byte, error) {
+	key := make([]byte, randomKeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate a random fingerprint key: %w", err)
+	}
+	return key, nil
+}
+
+func internalKeyFrom(secret *corev1.Secret) ([]byte, error) {
+	key := secret.Data[internalKeyDataKey]
+	if len(key) == 0 {
+		return nil, fmt.Errorf("%w: the secret %s/%s has no %s", errEmptyKey, secret.Namespace, secret.Name, internalKeyDataKey)
+	}
+	return key, nil
+}
@@ -0,0 +1,77 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fingerprint
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestLoadInternalKey(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	t.Run("creates and reuses the key", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		key, err := LoadInternalKey(context.TODO(), cl, cl, "operator")
+		assert.NoError(t, err)
+		assert.Len(t, key, randomKeyLength)
+
+		again, err := LoadInternalKey(context.TODO(), cl, cl, "operator")
+		assert.NoError(t, err)
+		assert.Equal(t, key, again)
+	})
+
+	t.Run("uses the key created concurrently", func(t *testing.T) {
+		existing := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: InternalKeySecretName, Namespace: "operator"},
+			Data:       map[string][]byte{internalKeyDataKey: []byte("existing")},
+		}
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+		gets := 0
+		// the first read doesn't see the secret created by another replica
+		reader := interceptor.NewClient(cl, interceptor.Funcs{
+			Get: func(ctx context.Context, cl client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				gets++
+				if gets == 1 {
+					return cl.Get(ctx, client.ObjectKey{Name: "missing", Namespace: key.Namespace}, obj, opts...)
+				}
+				return cl.Get(ctx, key, obj, opts...)
+			},
+		})
+
+		key, err := LoadInternalKey(context.TODO(), reader, cl, "operator")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("existing"), key)
+	})
+
+	t.Run("rejects empty key", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: InternalKeySecretName, Namespace: "operator"},
+		}).Build()
+
+		_, err := LoadInternalKey(context.TODO(), cl, cl, "operator")
+		assert.ErrorIs(t, err, errEmptyKey)
+	})
+}
@@ -54,8 +54,18 @@ var RemoteSecretConditionGauge = prometheus.NewGaugeVec(
 	[]string{"name", "namespace", "condition", "status"},
 )
 
+var NamespaceUsageGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: config.MetricsNamespace,
+		Subsystem: config.MetricsSubsystem,
+		Name:      "namespace_usage",
+		Help:      "The resources used by the remote secrets in a namespace that are subject to the quota",
+	},
+	[]string{"namespace", "resource"},
+)
+
 func RegisterCommonMetrics(registerer prometheus.Registerer) error {
-	registerer.MustRegister(UploadRejectionsCounter, RemoteSecretConditionGauge, StorageAvailabilityGauge)
+	registerer.MustRegister(UploadRejectionsCounter, RemoteSecretConditionGauge, StorageAvailabilityGauge, NamespaceUsageGauge)
 	return nil
 }
 
@@ -71,3 +81,15 @@ func UpdateRemoteSecretConditionMetric(ctx context.Context, rs *api.RemoteSecret
 	lg.V(logs.DebugLevel).Info("UpdateRemoteSecretConditionMetric", "name", rs.Name, "namespace", rs.Namespace, "condition", condition.Type, "status", string(condition.Status), "value", value)
 	RemoteSecretConditionGauge.WithLabelValues(rs.Name, rs.Namespace, condition.Type, string(condition.Status)).Set(value)
 }
+
+// UpdateNamespaceUsageMetric sets the usage metrics of the namespace to the values from the usage status.
+func UpdateNamespaceUsageMetric(namespace string, usage *api.RemoteSecretUsageStatus) {
+	NamespaceUsageGauge.WithLabelValues(namespace, "remote_secrets").Set(float64(usage.RemoteSecrets))
+	NamespaceUsageGauge.WithLabelValues(namespace, "targets").Set(float64(usage.Targets))
+	NamespaceUsageGauge.WithLabelValues(namespace, "stored_bytes").Set(float64(usage.StoredBytes))
+}
+
+// DeleteNamespaceUsageMetric removes the usage metrics of the namespace.
+func DeleteNamespaceUsageMetric(namespace string) {
+	NamespaceUsageGauge.DeletePartialMatch(prometheus.Labels{"namespace": namespace})
+}
@@ -20,6 +20,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	prometheusTest "github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
 )
 
 func TestRegisterMetrics(t *testing.T) {
@@ -47,6 +49,11 @@ func TestRegisterMetrics(t *testing.T) {
 		}, func() {
 			StorageAvailabilityGauge.Inc()
 		}, "redhat_appstudio_remotesecret_secretstorage_system_available", 1},
+		{"namespace usage gauge", func() {
+			NamespaceUsageGauge.Reset()
+		}, func() {
+			UpdateNamespaceUsageMetric("default", &api.RemoteSecretUsageStatus{RemoteSecrets: 1, Targets: 2, StoredBytes: 3})
+		}, "redhat_appstudio_remotesecret_namespace_usage", 3},
 	}
 	// The execution loop
 	for _, tt := range tests {
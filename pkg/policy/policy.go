@@ -0,0 +1,244 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PolicyViolationError is returned (wrapped) from the checks when the remote secret is not allowed by one of the policies.
+var PolicyViolationError = errors.New("is not allowed by the remote secret policy")
+
+// Checker checks the remote secrets against the RemoteSecretPolicy objects in the cluster.
+type Checker struct {
+	// Client is used to read the policies and the labels of the namespaces in the local cluster.
+	Client client.Client
+}
+
+// Check checks the remote secret and its targets and replication targets against the policies that apply to it. If the old version
+// of the remote secret is provided, only the targets that are not present in it are checked and the secret type and service account
+// links are only checked if they changed. This makes sure the remote secrets that became disallowed by a new policy can still be updated.
+// Their targets are refused at the deployment time, see CheckTarget.
+func (c *Checker) Check(ctx context.Context, old *api.RemoteSecret, rs *api.RemoteSecret) error {
+	policies, err := c.applicablePolicies(ctx, rs.Namespace)
+	if err != nil {
+		return err
+	}
+
+	checkSecretNeeded := old == nil || old.Spec.Secret.Type != rs.Spec.Secret.Type || !reflect.DeepEqual(old.Spec.Secret.LinkedTo, rs.Spec.Secret.LinkedTo)
+	type targetLocation struct {
+		apiUrl, namespace string
+	}
+	existingTargets := map[targetLocation]bool{}
+	if old != nil {
+		for _, t := range old.Spec.Targets {
+			existingTargets[targetLocation{apiUrl: t.ApiUrl, namespace: t.Namespace}] = true
+		}
+		for _, t := range old.Spec.ReplicationTargets {
+			existingTargets[targetLocation{apiUrl: t.ApiUrl, namespace: t.Namespace}] = true
+		}
+	}
+
+	for i := range policies {
+		if checkSecretNeeded {
+			if err := checkSecret(&policies[i], rs); err != nil {
+				return err
+			}
+		}
+		for ti := range rs.Spec.Targets {
+			if existingTargets[targetLocation{apiUrl: rs.Spec.Targets[ti].ApiUrl, namespace: rs.Spec.Targets[ti].Namespace}] {
+				continue
+			}
+			if err := c.checkTarget(ctx, &policies[i], rs, &rs.Spec.Targets[ti]); err != nil {
+				return err
+			}
+		}
+		for ti := range rs.Spec.ReplicationTargets {
+			rt := &rs.Spec.ReplicationTargets[ti]
+			if existingTargets[targetLocation{apiUrl: rt.ApiUrl, namespace: rt.Namespace}] {
+				continue
+			}
+			if err := c.checkTarget(ctx, &policies[i], rs, ReplicationTargetLocation(rt)); err != nil {
+				return fmt.Errorf("replication target: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ReplicationTargetLocation returns the target describing where the data is replicated to, so that the replication targets can be checked
+// against the policies just like the targets. The data is transferred to the replicas through secrets, so the same rules apply to them.
+func ReplicationTargetLocation(rt *api.ReplicationTarget) *api.RemoteSecretTarget {
+	return &api.RemoteSecretTarget{
+		Namespace: rt.Namespace,
+		ApiUrl:    rt.ApiUrl,
+	}
+}
+
+// CheckTarget checks whether the remote secret can be deployed to the provided target according to the policies that apply
+// to the remote secret. This includes the checks of the parts of the remote secret that are common to all the targets, like
+// the secret type.
+func (c *Checker) CheckTarget(ctx context.Context, rs *api.RemoteSecret, target *api.RemoteSecretTarget) error {
+	policies, err := c.applicablePolicies(ctx, rs.Namespace)
+	if err != nil {
+		return err
+	}
+
+	for i := range policies {
+		if err := checkSecret(&policies[i], rs); err != nil {
+			return err
+		}
+		if err := c.checkTarget(ctx, &policies[i], rs, target); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Checker) applicablePolicies(ctx context.Context, namespace string) ([]api.RemoteSecretPolicy, error) {
+	list := &api.RemoteSecretPolicyList{}
+	if err := c.Client.List(ctx, list); err != nil {
+		return nil, fmt.Errorf("failed to list the remote secret policies: %w", err)
+	}
+
+	var namespaceLabels labels.Set
+	namespaceLoaded := false
+	var ret []api.RemoteSecretPolicy
+	for _, p := range list.Items {
+		if p.Spec.NamespaceSelector != nil {
+			if !namespaceLoaded {
+				ns := &corev1.Namespace{}
+				if err := c.Client.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+					return nil, fmt.Errorf("failed to get the namespace %s to match the remote secret policies: %w", namespace, err)
+				}
+				namespaceLabels = labels.Set(ns.Labels)
+				namespaceLoaded = true
+			}
+			matches, err := selectorMatches(p.Spec.NamespaceSelector, namespaceLabels)
+			if err != nil {
+				return nil, fmt.Errorf("invalid namespace selector in the remote secret policy %s: %w", p.Name, err)
+			}
+			if !matches {
+				continue
+			}
+		}
+		ret = append(ret, p)
+	}
+
+	return ret, nil
+}
+
+func checkSecret(p *api.RemoteSecretPolicy, rs *api.RemoteSecret) error {
+	if len(p.Spec.SecretTypes) > 0 {
+		secretType := rs.Spec.Secret.Type
+		if secretType == "" {
+			secretType = corev1.SecretTypeOpaque
+		}
+		if !contains(p.Spec.SecretTypes, secretType) {
+			return fmt.Errorf("the secret type '%s' %w '%s'", secretType, PolicyViolationError, p.Name)
+		}
+	}
+
+	if len(p.Spec.ServiceAccountLinkTypes) > 0 {
+		for _, link := range rs.Spec.Secret.LinkedTo {
+			linkType := link.ServiceAccount.EffectiveSecretLinkType()
+			if !contains(p.Spec.ServiceAccountLinkTypes, linkType) {
+				return fmt.Errorf("the service account link type '%s' %w '%s'", linkType, PolicyViolationError, p.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Checker) checkTarget(ctx context.Context, p *api.RemoteSecretPolicy, rs *api.RemoteSecret, target *api.RemoteSecretTarget) error {
+	if target.ApiUrl != "" && len(p.Spec.ApiUrls) > 0 {
+		matches, err := matchesAnyPattern(p.Spec.ApiUrls, target.ApiUrl)
+		if err != nil {
+			return fmt.Errorf("invalid API URL pattern in the remote secret policy %s: %w", p.Name, err)
+		}
+		if !matches {
+			return fmt.Errorf("the cluster '%s' %w '%s'", target.ApiUrl, PolicyViolationError, p.Name)
+		}
+	}
+
+	if p.Spec.TargetNamespaces == nil || (target.ApiUrl == "" && target.Namespace == rs.Namespace) {
+		return nil
+	}
+
+	matches, err := matchesAnyPattern(p.Spec.TargetNamespaces.Names, target.Namespace)
+	if err != nil {
+		return fmt.Errorf("invalid namespace name pattern in the remote secret policy %s: %w", p.Name, err)
+	}
+
+	if !matches && target.ApiUrl == "" && p.Spec.TargetNamespaces.Selector != nil {
+		ns := &corev1.Namespace{}
+		if err := c.Client.Get(ctx, client.ObjectKey{Name: target.Namespace}, ns); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to get the target namespace %s to match the remote secret policy %s: %w", target.Namespace, p.Name, err)
+		}
+		matches, err = selectorMatches(p.Spec.TargetNamespaces.Selector, labels.Set(ns.Labels))
+		if err != nil {
+			return fmt.Errorf("invalid target namespace selector in the remote secret policy %s: %w", p.Name, err)
+		}
+	}
+
+	if !matches {
+		return fmt.Errorf("the target namespace '%s' %w '%s'", target.Namespace, PolicyViolationError, p.Name)
+	}
+
+	return nil
+}
+
+func selectorMatches(selector *metav1.LabelSelector, lbls labels.Set) (bool, error) {
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse the label selector: %w", err)
+	}
+	return sel.Matches(lbls), nil
+}
+
+func matchesAnyPattern(patterns []string, value string) (bool, error) {
+	for _, pattern := range patterns {
+		matches, err := path.Match(pattern, value)
+		if err != nil {
+			return false, fmt.Errorf("failed to match the pattern '%s': %w", pattern, err)
+		}
+		if matches {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func contains[T comparable](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,235 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newChecker(t *testing.T, objs ...client.Object) *Checker {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	assert.NoError(t, api.AddToScheme(scheme))
+
+	objs = append(objs,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "a"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"team": "b"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a-dev", Labels: map[string]string{"deployable-by": "a"}}},
+	)
+
+	return &Checker{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()}
+}
+
+func remoteSecret(targets ...api.RemoteSecretTarget) *api.RemoteSecret {
+	return &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rs",
+			Namespace: "team-a",
+		},
+		Spec: api.RemoteSecretSpec{
+			Targets: targets,
+		},
+	}
+}
+
+func TestCheck_NoPolicies(t *testing.T) {
+	c := newChecker(t)
+	assert.NoError(t, c.Check(context.TODO(), nil, remoteSecret(api.RemoteSecretTarget{Namespace: "anywhere", ApiUrl: "https://any.cluster"})))
+}
+
+func TestCheck_TargetNamespaces(t *testing.T) {
+	c := newChecker(t, &api.RemoteSecretPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+		Spec: api.RemoteSecretPolicySpec{
+			TargetNamespaces: &api.TargetNamespacesPolicy{
+				Names: []string{"prod-*"},
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"deployable-by": "a"},
+				},
+			},
+		},
+	})
+
+	t.Run("allows namespace matching the name pattern", func(t *testing.T) {
+		assert.NoError(t, c.Check(context.TODO(), nil, remoteSecret(api.RemoteSecretTarget{Namespace: "prod-app"})))
+	})
+
+	t.Run("allows namespace matching the selector", func(t *testing.T) {
+		assert.NoError(t, c.Check(context.TODO(), nil, remoteSecret(api.RemoteSecretTarget{Namespace: "team-a-dev"})))
+	})
+
+	t.Run("allows the namespace of the remote secret", func(t *testing.T) {
+		assert.NoError(t, c.Check(context.TODO(), nil, remoteSecret(api.RemoteSecretTarget{Namespace: "team-a"})))
+	})
+
+	t.Run("rejects other namespaces", func(t *testing.T) {
+		err := c.Check(context.TODO(), nil, remoteSecret(api.RemoteSecretTarget{Namespace: "team-b"}))
+		assert.ErrorIs(t, err, PolicyViolationError)
+		assert.ErrorContains(t, err, "the target namespace 'team-b' is not allowed by the remote secret policy 'policy'")
+	})
+
+	t.Run("doesn't use selector in remote clusters", func(t *testing.T) {
+		err := c.Check(context.TODO(), nil, remoteSecret(api.RemoteSecretTarget{Namespace: "team-a-dev", ApiUrl: "https://remote.cluster"}))
+		assert.ErrorIs(t, err, PolicyViolationError)
+		assert.NoError(t, c.Check(context.TODO(), nil, remoteSecret(api.RemoteSecretTarget{Namespace: "prod-app", ApiUrl: "https://remote.cluster"})))
+	})
+
+	t.Run("doesn't check targets present in the old remote secret", func(t *testing.T) {
+		old := remoteSecret(api.RemoteSecretTarget{Namespace: "team-b"})
+		new := remoteSecret(api.RemoteSecretTarget{Namespace: "team-b"}, api.RemoteSecretTarget{Namespace: "prod-app"})
+		assert.NoError(t, c.Check(context.TODO(), old, new))
+
+		new.Spec.Targets = append(new.Spec.Targets, api.RemoteSecretTarget{Namespace: "team-b", ApiUrl: "https://remote.cluster"})
+		assert.ErrorIs(t, c.Check(context.TODO(), old, new), PolicyViolationError)
+	})
+}
+
+func TestCheck_ReplicationTargets(t *testing.T) {
+	c := newChecker(t, &api.RemoteSecretPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+		Spec: api.RemoteSecretPolicySpec{
+			ApiUrls:          []string{"https://*.staging.cluster:6443"},
+			TargetNamespaces: &api.TargetNamespacesPolicy{Names: []string{"prod-*"}},
+		},
+	})
+
+	withReplicationTargets := func(rts ...api.ReplicationTarget) *api.RemoteSecret {
+		rs := remoteSecret()
+		rs.Spec.ReplicationTargets = rts
+		return rs
+	}
+
+	assert.NoError(t, c.Check(context.TODO(), nil, withReplicationTargets(api.ReplicationTarget{Namespace: "prod-app", ApiUrl: "https://api.staging.cluster:6443"})))
+
+	err := c.Check(context.TODO(), nil, withReplicationTargets(api.ReplicationTarget{Namespace: "prod-app", ApiUrl: "https://api.prod.cluster:6443"}))
+	assert.ErrorIs(t, err, PolicyViolationError)
+	assert.ErrorContains(t, err, "replication target: the cluster 'https://api.prod.cluster:6443'")
+
+	err = c.Check(context.TODO(), nil, withReplicationTargets(api.ReplicationTarget{Namespace: "team-b"}))
+	assert.ErrorIs(t, err, PolicyViolationError)
+
+	t.Run("doesn't check replication targets present in the old remote secret", func(t *testing.T) {
+		old := withReplicationTargets(api.ReplicationTarget{Namespace: "team-b"})
+		assert.NoError(t, c.Check(context.TODO(), old, old.DeepCopy()))
+	})
+
+	t.Run("checks at the deployment time", func(t *testing.T) {
+		rs := withReplicationTargets(api.ReplicationTarget{Namespace: "team-b"})
+		assert.ErrorIs(t, c.CheckTarget(context.TODO(), rs, ReplicationTargetLocation(&rs.Spec.ReplicationTargets[0])), PolicyViolationError)
+	})
+}
+
+func TestCheck_ApiUrls(t *testing.T) {
+	c := newChecker(t, &api.RemoteSecretPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+		Spec: api.RemoteSecretPolicySpec{
+			ApiUrls: []string{"https://*.staging.cluster:6443"},
+		},
+	})
+
+	assert.NoError(t, c.Check(context.TODO(), nil, remoteSecret(api.RemoteSecretTarget{Namespace: "ns", ApiUrl: "https://api.staging.cluster:6443"})))
+	assert.NoError(t, c.Check(context.TODO(), nil, remoteSecret(api.RemoteSecretTarget{Namespace: "ns"})))
+
+	err := c.Check(context.TODO(), nil, remoteSecret(api.RemoteSecretTarget{Namespace: "ns", ApiUrl: "https://api.prod.cluster:6443"}))
+	assert.ErrorIs(t, err, PolicyViolationError)
+	assert.ErrorContains(t, err, "the cluster 'https://api.prod.cluster:6443'")
+}
+
+func TestCheck_SecretTypesAndLinks(t *testing.T) {
+	c := newChecker(t, &api.RemoteSecretPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+		Spec: api.RemoteSecretPolicySpec{
+			SecretTypes:             []corev1.SecretType{corev1.SecretTypeOpaque, corev1.SecretTypeDockerConfigJson},
+			ServiceAccountLinkTypes: []api.ServiceAccountLinkType{api.ServiceAccountLinkTypeImagePullSecret},
+		},
+	})
+
+	rs := remoteSecret()
+	assert.NoError(t, c.Check(context.TODO(), nil, rs))
+
+	rs.Spec.Secret.Type = corev1.SecretTypeBasicAuth
+	err := c.Check(context.TODO(), nil, rs)
+	assert.ErrorIs(t, err, PolicyViolationError)
+	assert.ErrorContains(t, err, "the secret type 'kubernetes.io/basic-auth'")
+
+	t.Run("unchanged type is not checked on update", func(t *testing.T) {
+		assert.NoError(t, c.Check(context.TODO(), rs.DeepCopy(), rs))
+	})
+
+	rs.Spec.Secret.Type = corev1.SecretTypeDockerConfigJson
+	rs.Spec.Secret.LinkedTo = []api.SecretLink{{ServiceAccount: api.ServiceAccountLink{As: api.ServiceAccountLinkTypeImagePullSecret}}}
+	assert.NoError(t, c.Check(context.TODO(), nil, rs))
+
+	rs.Spec.Secret.LinkedTo = append(rs.Spec.Secret.LinkedTo, api.SecretLink{ServiceAccount: api.ServiceAccountLink{}})
+	err = c.Check(context.TODO(), nil, rs)
+	assert.ErrorIs(t, err, PolicyViolationError)
+	assert.ErrorContains(t, err, "the service account link type 'secret'")
+}
+
+func TestCheck_NamespaceSelector(t *testing.T) {
+	c := newChecker(t, &api.RemoteSecretPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-b-policy"},
+		Spec: api.RemoteSecretPolicySpec{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"team": "b"},
+			},
+			SecretTypes: []corev1.SecretType{corev1.SecretTypeOpaque},
+		},
+	})
+
+	rs := remoteSecret()
+	rs.Spec.Secret.Type = corev1.SecretTypeBasicAuth
+	assert.NoError(t, c.Check(context.TODO(), nil, rs))
+
+	rs.Namespace = "team-b"
+	assert.ErrorIs(t, c.Check(context.TODO(), nil, rs), PolicyViolationError)
+}
+
+func TestCheckTarget(t *testing.T) {
+	c := newChecker(t,
+		&api.RemoteSecretPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "namespaces"},
+			Spec: api.RemoteSecretPolicySpec{
+				TargetNamespaces: &api.TargetNamespacesPolicy{
+					Names: []string{"allowed"},
+				},
+			},
+		},
+		&api.RemoteSecretPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "types"},
+			Spec: api.RemoteSecretPolicySpec{
+				SecretTypes: []corev1.SecretType{corev1.SecretTypeOpaque},
+			},
+		},
+	)
+
+	rs := remoteSecret(api.RemoteSecretTarget{Namespace: "allowed"}, api.RemoteSecretTarget{Namespace: "disallowed"})
+	assert.NoError(t, c.CheckTarget(context.TODO(), rs, &rs.Spec.Targets[0]))
+	assert.ErrorIs(t, c.CheckTarget(context.TODO(), rs, &rs.Spec.Targets[1]), PolicyViolationError)
+
+	rs.Spec.Secret.Type = corev1.SecretTypeTLS
+	err := c.CheckTarget(context.TODO(), rs, &rs.Spec.Targets[0])
+	assert.ErrorIs(t, err, PolicyViolationError)
+	assert.ErrorContains(t, err, "'types'")
+}
@@ -0,0 +1,125 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// QuotaExceededError is returned (wrapped) from the checks when the change would exceed the quota.
+var QuotaExceededError = errors.New("quota exceeded")
+
+// Checker checks the remote secrets against the quota.
+type Checker struct {
+	// Client is used to read the other remote secrets in the namespace.
+	Client client.Client
+	// Quota is the quota to check against. If nil, nothing is limited.
+	Quota *api.RemoteSecretQuota
+}
+
+// DataSize returns the size of the secret data as counted against the quota - the sum of the lengths of the keys and the values.
+func DataSize(data map[string][]byte) int64 {
+	var size int64
+	for k, v := range data {
+		size += int64(len(k) + len(v))
+	}
+	return size
+}
+
+// TargetCount returns the number of the targets of the remote secret as counted against the quota. The replication targets are counted
+// as well, because each of them also receives the data.
+func TargetCount(rs *api.RemoteSecret) int {
+	return len(rs.Spec.Targets) + len(rs.Spec.ReplicationTargets)
+}
+
+// CheckRemoteSecretCount checks that another remote secret can be created in the namespace.
+func (c *Checker) CheckRemoteSecretCount(ctx context.Context, namespace string) error {
+	if c.Quota == nil || c.Quota.MaxRemoteSecrets == 0 {
+		return nil
+	}
+
+	list := &api.RemoteSecretList{}
+	if err := c.Client.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("failed to list the remote secrets in the namespace %s: %w", namespace, err)
+	}
+
+	if len(list.Items) >= int(c.Quota.MaxRemoteSecrets) {
+		return fmt.Errorf("%w: the namespace %s already contains the maximum number of %d remote secrets", QuotaExceededError, namespace, c.Quota.MaxRemoteSecrets)
+	}
+
+	return nil
+}
+
+// CheckTargetCount checks that the remote secret doesn't have too many targets, including the replication targets. The old remote secret can be nil. If it is not,
+// the check only fails if the number of targets increased, so that the remote secrets created before the quota was introduced can
+// still be updated.
+func (c *Checker) CheckTargetCount(old, new *api.RemoteSecret) error {
+	if c.Quota == nil || c.Quota.MaxTargetsPerRemoteSecret == 0 {
+		return nil
+	}
+
+	count := TargetCount(new)
+	if count <= int(c.Quota.MaxTargetsPerRemoteSecret) || (old != nil && count <= TargetCount(old)) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: the remote secret %s has %d targets but the maximum is %d", QuotaExceededError, new.Name, count, c.Quota.MaxTargetsPerRemoteSecret)
+}
+
+// DataSizeLimited tells whether the size of the data is limited by the quota. This can be used to avoid computing the size of the data
+// when it is not needed.
+func (c *Checker) DataSizeLimited() bool {
+	return c.Quota != nil && (c.Quota.MaxStoredBytes > 0 || c.Quota.MaxStoredBytesPerRemoteSecret > 0)
+}
+
+// CheckDataSize checks that the provided data can be stored as the data of the remote secret. The sizes of the data of the other
+// remote secrets in the namespace are taken from their status.
+func (c *Checker) CheckDataSize(ctx context.Context, rs *api.RemoteSecret, data map[string][]byte) error {
+	if !c.DataSizeLimited() {
+		return nil
+	}
+
+	size := DataSize(data)
+	if c.Quota.MaxStoredBytesPerRemoteSecret > 0 && size > c.Quota.MaxStoredBytesPerRemoteSecret {
+		return fmt.Errorf("%w: the data of the remote secret %s has %d bytes but the maximum is %d", QuotaExceededError, rs.Name, size, c.Quota.MaxStoredBytesPerRemoteSecret)
+	}
+
+	if c.Quota.MaxStoredBytes == 0 {
+		return nil
+	}
+
+	list := &api.RemoteSecretList{}
+	if err := c.Client.List(ctx, list, client.InNamespace(rs.Namespace)); err != nil {
+		return fmt.Errorf("failed to list the remote secrets in the namespace %s: %w", rs.Namespace, err)
+	}
+
+	total := size
+	for i := range list.Items {
+		if list.Items[i].Name != rs.Name {
+			total += list.Items[i].Status.SecretStatus.Size
+		}
+	}
+
+	if total > c.Quota.MaxStoredBytes {
+		return fmt.Errorf("%w: the data of the remote secrets in the namespace %s would have %d bytes but the maximum is %d", QuotaExceededError, rs.Namespace, total, c.Quota.MaxStoredBytes)
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newChecker(t *testing.T, quota *api.RemoteSecretQuota, objs ...client.Object) *Checker {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, api.AddToScheme(scheme))
+
+	return &Checker{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Quota:  quota,
+	}
+}
+
+func remoteSecret(name string, size int64, targets ...api.RemoteSecretTarget) *api.RemoteSecret {
+	return &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ns",
+		},
+		Spec: api.RemoteSecretSpec{
+			Targets: targets,
+		},
+		Status: api.RemoteSecretStatus{
+			SecretStatus: api.SecretStatus{
+				Size: size,
+			},
+		},
+	}
+}
+
+func TestDataSize(t *testing.T) {
+	assert.Equal(t, int64(0), DataSize(nil))
+	assert.Equal(t, int64(7), DataSize(map[string][]byte{"a": []byte("bc"), "de": []byte("fg")}))
+}
+
+func TestCheckRemoteSecretCount(t *testing.T) {
+	t.Run("no quota", func(t *testing.T) {
+		c := newChecker(t, nil, remoteSecret("a", 0))
+		assert.NoError(t, c.CheckRemoteSecretCount(context.TODO(), "ns"))
+	})
+
+	c := newChecker(t, &api.RemoteSecretQuota{MaxRemoteSecrets: 2}, remoteSecret("a", 0))
+	assert.NoError(t, c.CheckRemoteSecretCount(context.TODO(), "ns"))
+
+	assert.NoError(t, c.Client.Create(context.TODO(), remoteSecret("b", 0)))
+	err := c.CheckRemoteSecretCount(context.TODO(), "ns")
+	assert.ErrorIs(t, err, QuotaExceededError)
+	assert.ErrorContains(t, err, "the namespace ns already contains the maximum number of 2 remote secrets")
+
+	assert.NoError(t, c.CheckRemoteSecretCount(context.TODO(), "other-ns"))
+}
+
+func TestCheckTargetCount(t *testing.T) {
+	c := newChecker(t, &api.RemoteSecretQuota{MaxTargetsPerRemoteSecret: 1})
+
+	one := remoteSecret("rs", 0, api.RemoteSecretTarget{Namespace: "a"})
+	two := remoteSecret("rs", 0, api.RemoteSecretTarget{Namespace: "a"}, api.RemoteSecretTarget{Namespace: "b"})
+	three := remoteSecret("rs", 0, api.RemoteSecretTarget{Namespace: "a"}, api.RemoteSecretTarget{Namespace: "b"}, api.RemoteSecretTarget{Namespace: "c"})
+
+	assert.NoError(t, c.CheckTargetCount(nil, one))
+	assert.ErrorIs(t, c.CheckTargetCount(nil, two), QuotaExceededError)
+	assert.ErrorIs(t, c.CheckTargetCount(one, two), QuotaExceededError)
+
+	t.Run("counts the replication targets", func(t *testing.T) {
+		replicating := remoteSecret("rs", 0, api.RemoteSecretTarget{Namespace: "a"})
+		replicating.Spec.ReplicationTargets = []api.ReplicationTarget{{Namespace: "b"}}
+		err := c.CheckTargetCount(one, replicating)
+		assert.ErrorIs(t, err, QuotaExceededError)
+		assert.ErrorContains(t, err, "has 2 targets")
+	})
+
+	t.Run("allows updates not increasing the number of targets", func(t *testing.T) {
+		assert.NoError(t, c.CheckTargetCount(two, two))
+		assert.NoError(t, c.CheckTargetCount(three, two))
+		assert.ErrorIs(t, c.CheckTargetCount(two, three), QuotaExceededError)
+	})
+}
+
+func TestCheckDataSize(t *testing.T) {
+	data := map[string][]byte{"key": []byte("value")}
+
+	t.Run("no quota", func(t *testing.T) {
+		c := newChecker(t, &api.RemoteSecretQuota{MaxRemoteSecrets: 1})
+		assert.False(t, c.DataSizeLimited())
+		assert.NoError(t, c.CheckDataSize(context.TODO(), remoteSecret("rs", 0), data))
+	})
+
+	t.Run("per remote secret", func(t *testing.T) {
+		c := newChecker(t, &api.RemoteSecretQuota{MaxStoredBytesPerRemoteSecret: 8})
+		assert.True(t, c.DataSizeLimited())
+		assert.NoError(t, c.CheckDataSize(context.TODO(), remoteSecret("rs", 0), data))
+
+		err := c.CheckDataSize(context.TODO(), remoteSecret("rs", 0), map[string][]byte{"key": []byte("longer value")})
+		assert.ErrorIs(t, err, QuotaExceededError)
+		assert.ErrorContains(t, err, "has 15 bytes but the maximum is 8")
+	})
+
+	t.Run("per namespace", func(t *testing.T) {
+		c := newChecker(t, &api.RemoteSecretQuota{MaxStoredBytes: 20}, remoteSecret("other", 10), remoteSecret("rs", 100))
+
+		// the size of the remote secret being updated is replaced by the size of the new data
+		assert.NoError(t, c.CheckDataSize(context.TODO(), remoteSecret("rs", 100), data))
+
+		err := c.CheckDataSize(context.TODO(), remoteSecret("rs", 100), map[string][]byte{"key": []byte("longer value")})
+		assert.ErrorIs(t, err, QuotaExceededError)
+		assert.ErrorContains(t, err, "would have 25 bytes but the maximum is 20")
+	})
+}
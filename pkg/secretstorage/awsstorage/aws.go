@@ -43,6 +43,14 @@ const (
 	// old one is clear completely.
 	// Repeats have exponential time between tries, see https://github.com/cenkalti/backoff/blob/v4/exponential.go
 	secretCreationRetryCount = 10
+
+	// MaxDataSize is the maximum size of the binary secret value in AWS Secrets Manager.
+	MaxDataSize = 65536
+
+	// currentVersionStage is the staging label of the current version of the secret in AWS Secrets Manager.
+	currentVersionStage = "AWSCURRENT"
+	// pendingVersionStage is the staging label of the versions stored by the compare-and-swap before they become current.
+	pendingVersionStage = "REMOTESECRETPENDING"
 )
 
 // awsClient is an interface grouping methods from aws secretsmanager.Client that we need for implementation of our aws tokenstorage
@@ -54,6 +62,8 @@ type awsClient interface {
 	ListSecrets(ctx context.Context, params *secretsmanager.ListSecretsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error)
 	UpdateSecret(ctx context.Context, params *secretsmanager.UpdateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretOutput, error)
 	DeleteSecret(ctx context.Context, params *secretsmanager.DeleteSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteSecretOutput, error)
+	PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error)
+	UpdateSecretVersionStage(ctx context.Context, params *secretsmanager.UpdateSecretVersionStageInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretVersionStageOutput, error)
 }
 
 type AwsSecretStorage struct {
@@ -100,6 +110,12 @@ func (s *AwsSecretStorage) Store(ctx context.Context, id secretstorage.SecretID,
 }
 
 func (s *AwsSecretStorage) Get(ctx context.Context, id secretstorage.SecretID) ([]byte, error) {
+	data, _, err := s.GetVersioned(ctx, id)
+	return data, err
+}
+
+// GetVersioned implements secretstorage.SecretStorage. The version is the ID of the current version of the secret in AWS Secrets Manager.
+func (s *AwsSecretStorage) GetVersioned(ctx context.Context, id secretstorage.SecretID) ([]byte, string, error) {
 	lg := lg(ctx).WithValues("secretId", id)
 
 	secretName := s.generateAwsSecretName(&id)
@@ -108,20 +124,80 @@ func (s *AwsSecretStorage) Get(ctx context.Context, id secretstorage.SecretID) (
 
 	if err != nil {
 		if isAwsNotFoundError(err) {
-			return nil, fmt.Errorf("%w: %s", secretstorage.NotFoundError, err.Error())
+			return nil, "", fmt.Errorf("%w: %s", secretstorage.NotFoundError, err.Error())
 		} else if isAwsSecretMarkedForDeletionError(err) {
 			// data is still there, but secret is marked for deletion. we can return not found error
 			lg.Info("secret marked for deletion in aws storage, retuning NotFound error")
-			return nil, fmt.Errorf("%w: %s", secretstorage.NotFoundError, "secret is marked for deletion in aws storage")
+			return nil, "", fmt.Errorf("%w: %s", secretstorage.NotFoundError, "secret is marked for deletion in aws storage")
 		} else if isAwsInvalidRequestError(err) {
 			lg.Error(err, "invalid request to aws secret storage")
-			return nil, fmt.Errorf("invalid request to aws secret storage: %w", err)
+			return nil, "", fmt.Errorf("invalid request to aws secret storage: %w", err)
 		}
 
 		lg.Error(err, "unknown error on reading aws secret storage")
-		return nil, errAWSUnknownError
+		return nil, "", errAWSUnknownError
+	}
+	return getResult.SecretBinary, aws.ToString(getResult.VersionId), nil
+}
+
+// CompareAndSwap implements secretstorage.SecretStorage. The new data is first stored as a pending version of the secret.
+// The current version stage is then moved to it from the expected version, which fails if the expected version is no
+// longer current.
+func (s *AwsSecretStorage) CompareAndSwap(ctx context.Context, id secretstorage.SecretID, data []byte, expectedVersion string) (string, error) {
+	lg := lg(ctx).WithValues("secretId", id, "expectedVersion", expectedVersion)
+	lg.V(logs.DebugLevel).Info("storing data using compare-and-swap")
+
+	name := s.generateAwsSecretName(&id)
+
+	if expectedVersion == "" {
+		createInput := s.createSecretInput(&id, data)
+		out, err := s.client.CreateSecret(ctx, createInput)
+		if err != nil {
+			if isAwsResourceExistsError(err) {
+				return "", fmt.Errorf("%w: the data already exists", secretstorage.ConflictError)
+			} else if isAwsScheduledForDeletionError(err) {
+				if err := s.doCreateWithRetry(ctx, createInput); err != nil {
+					lg.Error(err, "secret creation failed")
+					return "", errASWSecretCreationFailed
+				}
+				// the version of the created secret is not known at this point
+				_, version, err := s.GetVersioned(ctx, id)
+				return version, err
+			}
+			lg.Error(err, "secret creation failed")
+			return "", errASWSecretCreationFailed
+		}
+		return aws.ToString(out.VersionId), nil
+	}
+
+	putOut, err := s.client.PutSecretValue(ctx, &secretsmanager.PutSecretValueInput{
+		SecretId:      name,
+		SecretBinary:  data,
+		VersionStages: []string{pendingVersionStage},
+	})
+	if err != nil {
+		if isAwsNotFoundError(err) || isAwsSecretMarkedForDeletionError(err) {
+			return "", fmt.Errorf("%w: the data no longer exists", secretstorage.ConflictError)
+		}
+		lg.Error(err, "storing the pending version of the secret failed")
+		return "", errASWSecretCreationFailed
+	}
+
+	_, err = s.client.UpdateSecretVersionStage(ctx, &secretsmanager.UpdateSecretVersionStageInput{
+		SecretId:            name,
+		VersionStage:        aws.String(currentVersionStage),
+		MoveToVersionId:     putOut.VersionId,
+		RemoveFromVersionId: aws.String(expectedVersion),
+	})
+	if err != nil {
+		if isAwsInvalidParameterError(err) || isAwsNotFoundError(err) {
+			return "", fmt.Errorf("%w: %s", secretstorage.ConflictError, err.Error())
+		}
+		lg.Error(err, "making the pending version of the secret current failed")
+		return "", errASWSecretCreationFailed
 	}
-	return getResult.SecretBinary, nil
+
+	return aws.ToString(putOut.VersionId), nil
 }
 
 func (s *AwsSecretStorage) Delete(ctx context.Context, id secretstorage.SecretID) error {
@@ -137,6 +213,10 @@ func (s *AwsSecretStorage) Delete(ctx context.Context, id secretstorage.SecretID
 	return nil
 }
 
+func (s *AwsSecretStorage) Capabilities() secretstorage.Capabilities {
+	return secretstorage.Capabilities{MaxDataSize: MaxDataSize}
+}
+
 func (s *AwsSecretStorage) checkCredentials(ctx context.Context) error {
 	// let's try to do simple request to verify that credentials are correct or fail fast
 	_, err := s.client.ListSecrets(ctx, &secretsmanager.ListSecretsInput{MaxResults: aws.Int32(1)})
@@ -150,20 +230,7 @@ func (s *AwsSecretStorage) createOrUpdateAwsSecret(ctx context.Context, secretId
 	lg := lg(ctx)
 	lg.V(logs.DebugLevel).Info("creating the AWS secret")
 
-	name := s.generateAwsSecretName(secretId)
-	createInput := &secretsmanager.CreateSecretInput{
-		Name:         name,
-		SecretBinary: data,
-		Tags: []types.Tag{
-			{
-				Key:   aws.String("namespace"),
-				Value: aws.String(secretId.Namespace),
-			}, {
-				Key:   aws.String("name"),
-				Value: aws.String(secretId.Name),
-			},
-		},
-	}
+	createInput := s.createSecretInput(secretId, data)
 	_, errCreate := s.client.CreateSecret(ctx, createInput)
 	if errCreate != nil {
 		if isAwsResourceExistsError(errCreate) {
@@ -188,6 +255,22 @@ func (s *AwsSecretStorage) createOrUpdateAwsSecret(ctx context.Context, secretId
 	return nil
 }
 
+func (s *AwsSecretStorage) createSecretInput(secretId *secretstorage.SecretID, data []byte) *secretsmanager.CreateSecretInput {
+	return &secretsmanager.CreateSecretInput{
+		Name:         s.generateAwsSecretName(secretId),
+		SecretBinary: data,
+		Tags: []types.Tag{
+			{
+				Key:   aws.String("namespace"),
+				Value: aws.String(secretId.Namespace),
+			}, {
+				Key:   aws.String("name"),
+				Value: aws.String(secretId.Name),
+			},
+		},
+	}
+}
+
 func (s *AwsSecretStorage) doCreateWithRetry(ctx context.Context, createInput *secretsmanager.CreateSecretInput) error {
 	lg := lg(ctx).WithValues("secretname", createInput.Name)
 	err := backoff.Retry(func() error {
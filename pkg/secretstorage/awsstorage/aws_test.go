@@ -229,6 +229,90 @@ func TestUpdate(t *testing.T) {
 	})
 }
 
+func TestCompareAndSwap(t *testing.T) {
+	t.Run("creates when no version expected", func(t *testing.T) {
+		cl := &mockAwsClient{
+			createFn: func(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
+				return &secretsmanager.CreateSecretOutput{VersionId: aws.String("v1")}, nil
+			},
+		}
+
+		strg := newStorage(cl)
+
+		version, err := strg.CompareAndSwap(context.TODO(), testSecretID, testData, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "v1", version)
+		assert.False(t, cl.putCalled)
+	})
+
+	t.Run("conflicts when no version expected and secret exists", func(t *testing.T) {
+		cl := &mockAwsClient{
+			createFn: func(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
+				return nil, &types.ResourceExistsException{}
+			},
+		}
+
+		strg := newStorage(cl)
+
+		_, err := strg.CompareAndSwap(context.TODO(), testSecretID, testData, "")
+		assert.ErrorIs(t, err, secretstorage.ConflictError)
+		assert.False(t, cl.updateCalled)
+	})
+
+	t.Run("swaps the current version", func(t *testing.T) {
+		cl := &mockAwsClient{
+			putFn: func(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+				assert.Equal(t, []string{pendingVersionStage}, params.VersionStages)
+				return &secretsmanager.PutSecretValueOutput{VersionId: aws.String("v2")}, nil
+			},
+			updateStageFn: func(ctx context.Context, params *secretsmanager.UpdateSecretVersionStageInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretVersionStageOutput, error) {
+				assert.Equal(t, currentVersionStage, aws.ToString(params.VersionStage))
+				assert.Equal(t, "v2", aws.ToString(params.MoveToVersionId))
+				assert.Equal(t, "v1", aws.ToString(params.RemoveFromVersionId))
+				return nil, nil
+			},
+		}
+
+		strg := newStorage(cl)
+
+		version, err := strg.CompareAndSwap(context.TODO(), testSecretID, testData, "v1")
+		assert.NoError(t, err)
+		assert.Equal(t, "v2", version)
+		assert.True(t, cl.putCalled)
+		assert.True(t, cl.updateStageCalled)
+	})
+
+	t.Run("conflicts when the current version differs", func(t *testing.T) {
+		cl := &mockAwsClient{
+			putFn: func(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+				return &secretsmanager.PutSecretValueOutput{VersionId: aws.String("v3")}, nil
+			},
+			updateStageFn: func(ctx context.Context, params *secretsmanager.UpdateSecretVersionStageInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretVersionStageOutput, error) {
+				return nil, &types.InvalidParameterException{}
+			},
+		}
+
+		strg := newStorage(cl)
+
+		_, err := strg.CompareAndSwap(context.TODO(), testSecretID, testData, "v1")
+		assert.ErrorIs(t, err, secretstorage.ConflictError)
+	})
+
+	t.Run("conflicts when the secret no longer exists", func(t *testing.T) {
+		cl := &mockAwsClient{
+			putFn: func(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+				return nil, &types.ResourceNotFoundException{}
+			},
+		}
+
+		strg := newStorage(cl)
+
+		_, err := strg.CompareAndSwap(context.TODO(), testSecretID, testData, "v1")
+		assert.ErrorIs(t, err, secretstorage.ConflictError)
+		assert.False(t, cl.updateStageCalled)
+	})
+}
+
 func TestGet(t *testing.T) {
 	t.Run("ok", func(t *testing.T) {
 		ctx := context.TODO()
@@ -395,6 +479,12 @@ type mockAwsClient struct {
 
 	deleteFn     func(ctx context.Context, params *secretsmanager.DeleteSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteSecretOutput, error)
 	deleteCalled bool
+
+	putFn     func(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error)
+	putCalled bool
+
+	updateStageFn     func(ctx context.Context, params *secretsmanager.UpdateSecretVersionStageInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretVersionStageOutput, error)
+	updateStageCalled bool
 }
 
 func (c *mockAwsClient) CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
@@ -417,3 +507,11 @@ func (c *mockAwsClient) DeleteSecret(ctx context.Context, params *secretsmanager
 	c.deleteCalled = true
 	return c.deleteFn(ctx, params, optFns...)
 }
+func (c *mockAwsClient) PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+	c.putCalled = true
+	return c.putFn(ctx, params, optFns...)
+}
+func (c *mockAwsClient) UpdateSecretVersionStage(ctx context.Context, params *secretsmanager.UpdateSecretVersionStageInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretVersionStageOutput, error) {
+	c.updateStageCalled = true
+	return c.updateStageFn(ctx, params, optFns...)
+}
@@ -54,6 +54,10 @@ func isAwsInvalidRequestError(err error) bool {
 	return isAWSErr(err, secretsmanager.ErrCodeInvalidRequestException, "")
 }
 
+func isAwsInvalidParameterError(err error) bool {
+	return isAWSErr(err, secretsmanager.ErrCodeInvalidParameterException, "")
+}
+
 func isAwsResourceExistsError(err error) bool {
 	return isAWSErr(err, secretsmanager.ErrCodeResourceExistsException, "")
 }
@@ -0,0 +1,316 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chunking
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/redhat-appstudio/remote-secret/pkg/logs"
+	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ secretstorage.SecretStorage = (*ChunkingSecretStorage)(nil)
+
+var (
+	// manifestPrefix starts the data stored under the original id when the data is split into chunks. It is followed by
+	// the generation of the chunks and the number of the chunks separated by a colon. The manifests written before
+	// the chunks had generations only contain the number of the chunks. The data serialized by the typed storages never starts
+	// with a NUL byte.
+	manifestPrefix = []byte("\x00chunked:")
+
+	corruptedManifestError = errors.New("corrupted chunk manifest")
+	missingChunkError      = errors.New("missing chunk of the data")
+)
+
+// chunkIdSuffix is appended to the name of the id of the data to form the ids of the chunks. The underscore cannot be part of
+// the names of the Kubernetes objects, so the ids of the chunks cannot clash with the ids of other data.
+const chunkIdSuffix = "_chunk_"
+
+// generationLength is the number of the random bytes of the generation of the chunks.
+const generationLength = 8
+
+// ChunkingSecretStorage is a wrapper around SecretStorage that splits the data larger than the maximum data size of the wrapped
+// storage into chunks stored under separate ids. The data under the original id is then replaced by a small manifest
+// with the generation and the number of the chunks. The data that fits into the wrapped storage is stored as is, so the wrapper
+// can be put in front of a storage with existing data.
+//
+// Each write of the chunked data stores the chunks under a new generation and only then switches the manifest to it, so the readers
+// never see a mix of the old and the new chunks. The chunks of the previous generation are deleted afterwards. The version
+// of the data is the version of the manifest in the wrapped storage, so the compare-and-swap is supported if the wrapped storage
+// supports it.
+type ChunkingSecretStorage struct {
+	SecretStorage secretstorage.SecretStorage
+	// ChunkSize is the maximum size of a single chunk. If 0, the maximum data size of the wrapped storage is used.
+	ChunkSize int
+}
+
+// manifest describes the chunks of the data. The zero value describes the data that is not split into chunks.
+type manifest struct {
+	generation string
+	count      int
+}
+
+// Initialize implements secretstorage.SecretStorage
+func (c *ChunkingSecretStorage) Initialize(ctx context.Context) error {
+	if err := c.SecretStorage.Initialize(ctx); err != nil {
+		return fmt.Errorf("failed to initialize the chunked secret storage: %w", err)
+	}
+	return nil
+}
+
+// Examine implements secretstorage.SecretStorage
+func (c *ChunkingSecretStorage) Examine(ctx context.Context) error {
+	if err := c.SecretStorage.Examine(ctx); err != nil {
+		return fmt.Errorf("failed to examine the chunked secret storage: %w", err)
+	}
+	return nil
+}
+
+// Capabilities implements secretstorage.SecretStorage. The size of the data is not limited because it is split
+// into as many chunks as needed.
+func (c *ChunkingSecretStorage) Capabilities() secretstorage.Capabilities {
+	return secretstorage.Capabilities{}
+}
+
+// Store implements secretstorage.SecretStorage. The previous manifest under the id is read first so that the chunks that are
+// no longer needed can be deleted.
+func (c *ChunkingSecretStorage) Store(ctx context.Context, id secretstorage.SecretID, data []byte) error {
+	old, err := c.currentManifest(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := c.write(ctx, id, data, func(entry []byte) error {
+		return c.SecretStorage.Store(ctx, id, entry) //nolint:wrapcheck // the error is wrapped by write
+	}); err != nil {
+		return err
+	}
+
+	return c.deleteChunks(ctx, id, old)
+}
+
+// Get implements secretstorage.SecretStorage
+func (c *ChunkingSecretStorage) Get(ctx context.Context, id secretstorage.SecretID) ([]byte, error) {
+	data, err := c.SecretStorage.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the data: %w", err)
+	}
+
+	return c.assemble(ctx, id, data)
+}
+
+// Delete implements secretstorage.SecretStorage
+func (c *ChunkingSecretStorage) Delete(ctx context.Context, id secretstorage.SecretID) error {
+	old, err := c.currentManifest(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := c.SecretStorage.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete the data: %w", err)
+	}
+
+	return c.deleteChunks(ctx, id, old)
+}
+
+// GetVersioned implements secretstorage.SecretStorage. The version of the data is the version of the entry under the id
+// in the wrapped storage, i.e. of the manifest if the data is split into chunks.
+func (c *ChunkingSecretStorage) GetVersioned(ctx context.Context, id secretstorage.SecretID) ([]byte, string, error) {
+	data, version, err := c.SecretStorage.GetVersioned(ctx, id)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get the data: %w", err)
+	}
+
+	data, err = c.assemble(ctx, id, data)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, version, nil
+}
+
+// CompareAndSwap implements secretstorage.SecretStorage. The new chunks are written first and the manifest is then swapped
+// using the compare-and-swap of the wrapped storage. If the swap fails, the new chunks are deleted again.
+func (c *ChunkingSecretStorage) CompareAndSwap(ctx context.Context, id secretstorage.SecretID, data []byte, expectedVersion string) (string, error) {
+	old, err := c.currentManifest(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	var version string
+	if err := c.write(ctx, id, data, func(entry []byte) error {
+		var err error
+		version, err = c.SecretStorage.CompareAndSwap(ctx, id, entry, expectedVersion)
+		return err //nolint:wrapcheck // the error is wrapped by write
+	}); err != nil {
+		return "", err
+	}
+
+	return version, c.deleteChunks(ctx, id, old)
+}
+
+// write stores the data in chunks of a new generation if needed and then stores the data itself or the manifest using the provided
+// function. The chunks of the new generation are deleted if the function fails.
+func (c *ChunkingSecretStorage) write(ctx context.Context, id secretstorage.SecretID, data []byte, storeEntry func(entry []byte) error) error {
+	chunkSize := c.chunkSize()
+	var chunkCount int
+	if chunkSize == 0 || len(data) <= chunkSize {
+		if !bytes.HasPrefix(data, manifestPrefix) {
+			if err := storeEntry(data); err != nil {
+				return fmt.Errorf("failed to store the data: %w", err)
+			}
+			return nil
+		}
+		// the data would be confused with the manifest, so we need to store it in a (single) chunk
+		chunkCount = 1
+		chunkSize = len(data)
+	} else {
+		chunkCount = (len(data) + chunkSize - 1) / chunkSize
+	}
+
+	generation, err := newGeneration()
+	if err != nil {
+		return err
+	}
+	m := manifest{generation: generation, count: chunkCount}
+
+	log.FromContext(ctx).V(logs.DebugLevel).Info("storing the data in chunks", "secretId", id, "chunks", chunkCount, "generation", generation)
+
+	for i := 0; i < chunkCount; i++ {
+		start := i * chunkSize
+		end := start + chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		if err := c.SecretStorage.Store(ctx, chunkId(id, m.generation, i), data[start:end]); err != nil {
+			c.deleteUnusedChunks(ctx, id, manifest{generation: generation, count: i})
+			return fmt.Errorf("failed to store the chunk %d of the data: %w", i, err)
+		}
+	}
+
+	if err := storeEntry(m.bytes()); err != nil {
+		c.deleteUnusedChunks(ctx, id, m)
+		return fmt.Errorf("failed to store the chunk manifest: %w", err)
+	}
+
+	return nil
+}
+
+// assemble returns the data from the chunks if the provided entry is a manifest. Otherwise the entry itself is the data.
+func (c *ChunkingSecretStorage) assemble(ctx context.Context, id secretstorage.SecretID, entry []byte) ([]byte, error) {
+	m, err := parseManifest(entry)
+	if err != nil {
+		return nil, err
+	}
+	if m.count == 0 {
+		return entry, nil
+	}
+
+	ret := make([]byte, 0, len(entry))
+	for i := 0; i < m.count; i++ {
+		chunk, err := c.SecretStorage.Get(ctx, chunkId(id, m.generation, i))
+		if err != nil {
+			// we must not propagate the NotFoundError of the chunk, because the data itself exists
+			return nil, fmt.Errorf("%w: failed to get the chunk %d of %d: %s", missingChunkError, i, m.count, err.Error())
+		}
+		ret = append(ret, chunk...)
+	}
+
+	return ret, nil
+}
+
+func (c *ChunkingSecretStorage) chunkSize() int {
+	if c.ChunkSize > 0 {
+		return c.ChunkSize
+	}
+	return c.SecretStorage.Capabilities().MaxDataSize
+}
+
+// currentManifest returns the manifest of the data currently stored under the id. The zero manifest is returned if there is no data
+// or if the data is not split into chunks.
+func (c *ChunkingSecretStorage) currentManifest(ctx context.Context, id secretstorage.SecretID) (manifest, error) {
+	data, err := c.SecretStorage.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, secretstorage.NotFoundError) {
+			return manifest{}, nil
+		}
+		return manifest{}, fmt.Errorf("failed to get the current data: %w", err)
+	}
+
+	return parseManifest(data)
+}
+
+func (c *ChunkingSecretStorage) deleteChunks(ctx context.Context, id secretstorage.SecretID, m manifest) error {
+	for i := 0; i < m.count; i++ {
+		if err := c.SecretStorage.Delete(ctx, chunkId(id, m.generation, i)); err != nil && !errors.Is(err, secretstorage.NotFoundError) {
+			return fmt.Errorf("failed to delete the chunk %d of the data: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// deleteUnusedChunks deletes the chunks of a generation that the manifest was not switched to. The failure is only logged, because
+// the chunks are not reachable anyway.
+func (c *ChunkingSecretStorage) deleteUnusedChunks(ctx context.Context, id secretstorage.SecretID, m manifest) {
+	if err := c.deleteChunks(ctx, id, m); err != nil {
+		log.FromContext(ctx).Error(err, "failed to delete the unused chunks", "secretId", id, "generation", m.generation)
+	}
+}
+
+func (m manifest) bytes() []byte {
+	return append(append([]byte{}, manifestPrefix...), []byte(m.generation+":"+strconv.Itoa(m.count))...)
+}
+
+func parseManifest(data []byte) (manifest, error) {
+	if !bytes.HasPrefix(data, manifestPrefix) {
+		return manifest{}, nil
+	}
+	generation, count, found := strings.Cut(string(data[len(manifestPrefix):]), ":")
+	if !found {
+		// the manifest written before the chunks had generations
+		generation, count = "", generation
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 0 {
+		return manifest{}, fmt.Errorf("%w: invalid number of chunks", corruptedManifestError)
+	}
+	return manifest{generation: generation, count: n}, nil
+}
+
+func newGeneration() (string, error) {
+	generation := make([]byte, generationLength)
+	if _, err := rand.Read(generation); err != nil {
+		return "", fmt.Errorf("failed to generate the generation of the chunks: %w", err)
+	}
+	return hex.EncodeToString(generation), nil
+}
+
+func chunkId(id secretstorage.SecretID, generation string, index int) secretstorage.SecretID {
+	name := id.Name + chunkIdSuffix
+	if generation != "" {
+		name += generation + "_"
+	}
+	return secretstorage.SecretID{
+		Name:      name + strconv.Itoa(index),
+		Namespace: id.Namespace,
+	}
+}
@@ -0,0 +1,211 @@
+//
+// Copyright (c) 2023 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chunking
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage"
+	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage/memorystorage"
+	"github.com/stretchr/testify/assert"
+)
+
+var testId = secretstorage.SecretID{Name: "rs", Namespace: "ns"}
+
+func TestStoreAndGet(t *testing.T) {
+	backend := &memorystorage.MemoryStorage{MaxDataSize: 4}
+	storage := &ChunkingSecretStorage{SecretStorage: backend}
+	assert.NoError(t, storage.Initialize(context.TODO()))
+	assert.Equal(t, 0, storage.Capabilities().MaxDataSize)
+
+	t.Run("small data stored as is", func(t *testing.T) {
+		assert.NoError(t, storage.Store(context.TODO(), testId, []byte("abc")))
+		assert.Equal(t, 1, backend.Len())
+		assert.Equal(t, []byte("abc"), backend.Data[testId])
+
+		data, err := storage.Get(context.TODO(), testId)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("abc"), data)
+	})
+
+	t.Run("large data stored in chunks", func(t *testing.T) {
+		assert.NoError(t, storage.Store(context.TODO(), testId, []byte("abcdefghij")))
+		assert.Equal(t, 4, backend.Len())
+		m, err := parseManifest(backend.Data[testId])
+		assert.NoError(t, err)
+		assert.Equal(t, 3, m.count)
+		assert.NotEmpty(t, m.generation)
+		assert.Equal(t, []byte("abcd"), backend.Data[chunkId(testId, m.generation, 0)])
+		assert.Equal(t, []byte("ij"), backend.Data[chunkId(testId, m.generation, 2)])
+
+		data, err := storage.Get(context.TODO(), testId)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("abcdefghij"), data)
+	})
+
+	t.Run("new chunks written under new generation", func(t *testing.T) {
+		old, err := parseManifest(backend.Data[testId])
+		assert.NoError(t, err)
+
+		assert.NoError(t, storage.Store(context.TODO(), testId, []byte("klmnopqrst")))
+		assert.Equal(t, 4, backend.Len())
+
+		m, err := parseManifest(backend.Data[testId])
+		assert.NoError(t, err)
+		assert.NotEqual(t, old.generation, m.generation)
+		assert.NotContains(t, backend.Data, chunkId(testId, old.generation, 0))
+
+		data, err := storage.Get(context.TODO(), testId)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("klmnopqrst"), data)
+	})
+
+	t.Run("superfluous chunks deleted", func(t *testing.T) {
+		assert.NoError(t, storage.Store(context.TODO(), testId, []byte("abcdef")))
+		assert.Equal(t, 3, backend.Len())
+
+		data, err := storage.Get(context.TODO(), testId)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("abcdef"), data)
+
+		assert.NoError(t, storage.Store(context.TODO(), testId, []byte("ab")))
+		assert.Equal(t, 1, backend.Len())
+	})
+
+	t.Run("data looking like manifest", func(t *testing.T) {
+		storage := &ChunkingSecretStorage{SecretStorage: backend, ChunkSize: 100}
+		fake := append(append([]byte{}, manifestPrefix...), '1')
+		assert.NoError(t, storage.Store(context.TODO(), testId, fake))
+
+		data, err := storage.Get(context.TODO(), testId)
+		assert.NoError(t, err)
+		assert.Equal(t, fake, data)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	backend := &memorystorage.MemoryStorage{MaxDataSize: 4}
+	storage := &ChunkingSecretStorage{SecretStorage: backend}
+
+	assert.NoError(t, storage.Store(context.TODO(), testId, []byte("abcdefghij")))
+	assert.NoError(t, storage.Store(context.TODO(), secretstorage.SecretID{Name: "other", Namespace: "ns"}, []byte("abc")))
+	assert.Equal(t, 5, backend.Len())
+
+	assert.NoError(t, storage.Delete(context.TODO(), testId))
+	assert.Equal(t, 1, backend.Len())
+
+	_, err := storage.Get(context.TODO(), testId)
+	assert.ErrorIs(t, err, secretstorage.NotFoundError)
+}
+
+func TestGetMissingChunk(t *testing.T) {
+	backend := &memorystorage.MemoryStorage{MaxDataSize: 4}
+	storage := &ChunkingSecretStorage{SecretStorage: backend}
+
+	assert.NoError(t, storage.Store(context.TODO(), testId, []byte("abcdefghij")))
+	m, err := parseManifest(backend.Data[testId])
+	assert.NoError(t, err)
+	delete(backend.Data, chunkId(testId, m.generation, 1))
+
+	_, err = storage.Get(context.TODO(), testId)
+	assert.ErrorIs(t, err, missingChunkError)
+	assert.NotErrorIs(t, err, secretstorage.NotFoundError)
+}
+
+func TestGetLegacyManifest(t *testing.T) {
+	backend := &memorystorage.MemoryStorage{MaxDataSize: 4}
+	storage := &ChunkingSecretStorage{SecretStorage: backend}
+
+	assert.NoError(t, backend.Store(context.TODO(), testId, append(append([]byte{}, manifestPrefix...), '2')))
+	assert.NoError(t, backend.Store(context.TODO(), secretstorage.SecretID{Name: "rs_chunk_0", Namespace: "ns"}, []byte("abcd")))
+	assert.NoError(t, backend.Store(context.TODO(), secretstorage.SecretID{Name: "rs_chunk_1", Namespace: "ns"}, []byte("ef")))
+
+	data, err := storage.Get(context.TODO(), testId)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("abcdef"), data)
+
+	assert.NoError(t, storage.Store(context.TODO(), testId, []byte("ghijkl")))
+	assert.Equal(t, 3, backend.Len())
+	assert.NotContains(t, backend.Data, secretstorage.SecretID{Name: "rs_chunk_0", Namespace: "ns"})
+}
+
+func TestStoreFailure(t *testing.T) {
+	backend := &failingStorage{SecretStorage: &memorystorage.MemoryStorage{MaxDataSize: 4}}
+	storage := &ChunkingSecretStorage{SecretStorage: backend}
+
+	assert.NoError(t, storage.Store(context.TODO(), testId, []byte("abcdefghij")))
+
+	backend.failOn = &testId
+	assert.Error(t, storage.Store(context.TODO(), testId, []byte("klmnopqrst")))
+	backend.failOn = nil
+
+	data, err := storage.Get(context.TODO(), testId)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("abcdefghij"), data)
+	assert.Equal(t, 4, backend.SecretStorage.(*memorystorage.MemoryStorage).Len())
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	backend := &memorystorage.MemoryStorage{MaxDataSize: 4}
+	storage := &ChunkingSecretStorage{SecretStorage: backend}
+
+	version, err := storage.CompareAndSwap(context.TODO(), testId, []byte("abcdefghij"), "")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, version)
+
+	data, currentVersion, err := storage.GetVersioned(context.TODO(), testId)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("abcdefghij"), data)
+	assert.Equal(t, version, currentVersion)
+
+	t.Run("swaps the data of the expected version", func(t *testing.T) {
+		newVersion, err := storage.CompareAndSwap(context.TODO(), testId, []byte("klmnop"), version)
+		assert.NoError(t, err)
+		assert.NotEqual(t, version, newVersion)
+		assert.Equal(t, 3, backend.Len())
+
+		data, currentVersion, err := storage.GetVersioned(context.TODO(), testId)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("klmnop"), data)
+		assert.Equal(t, newVersion, currentVersion)
+	})
+
+	t.Run("rejects the data of other version", func(t *testing.T) {
+		_, err := storage.CompareAndSwap(context.TODO(), testId, []byte("qrstuvwxyz"), version)
+		assert.ErrorIs(t, err, secretstorage.ConflictError)
+		assert.Equal(t, 3, backend.Len())
+
+		data, err := storage.Get(context.TODO(), testId)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("klmnop"), data)
+	})
+}
+
+// failingStorage fails to store the data under the configured id.
+type failingStorage struct {
+	secretstorage.SecretStorage
+	failOn *secretstorage.SecretID
+}
+
+var errStoreFailed = errors.New("store failed")
+
+func (f *failingStorage) Store(ctx context.Context, id secretstorage.SecretID, data []byte) error {
+	if f.failOn != nil && *f.failOn == id {
+		return errStoreFailed
+	}
+	return f.SecretStorage.Store(ctx, id, data) //nolint:wrapcheck // test wrapper
+}
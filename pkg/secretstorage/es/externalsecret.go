@@ -31,6 +31,8 @@ import (
 
 	es "github.com/external-secrets/external-secrets/apis/externalsecrets/v1beta1"
 	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage"
+	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage/awsstorage"
+	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage/vaultstorage"
 )
 
 var _ secretstorage.SecretStorage = (*ExternalSecretStorage)(nil)
@@ -173,6 +175,32 @@ func (p *ExternalSecretStorage) Store(ctx context.Context, id secretstorage.Secr
 	return nil
 }
 
+// GetVersioned implements secretstorage.SecretStorage. The versions of the data are not exposed by the external secrets providers,
+// so the version is always empty.
+func (p *ExternalSecretStorage) GetVersioned(ctx context.Context, id secretstorage.SecretID) ([]byte, string, error) {
+	data, err := p.Get(ctx, id)
+	return data, "", err
+}
+
+// CompareAndSwap implements secretstorage.SecretStorage. It is not supported by the external secrets providers.
+func (p *ExternalSecretStorage) CompareAndSwap(ctx context.Context, id secretstorage.SecretID, data []byte, expectedVersion string) (string, error) {
+	return "", secretstorage.CompareAndSwapNotSupportedError
+}
+
+// Capabilities implements secretstorage.SecretStorage. The limits depend on the configured provider.
+func (p *ExternalSecretStorage) Capabilities() secretstorage.Capabilities {
+	switch {
+	case p.ProviderConfig == nil:
+		return secretstorage.Capabilities{}
+	case p.ProviderConfig.AWS != nil:
+		return secretstorage.Capabilities{MaxDataSize: awsstorage.MaxDataSize}
+	case p.ProviderConfig.Vault != nil:
+		return secretstorage.Capabilities{MaxDataSize: vaultstorage.MaxDataSize}
+	default:
+		return secretstorage.Capabilities{}
+	}
+}
+
 func (p *ExternalSecretStorage) initSecretNameFormat() string {
 	if p.InstanceId == "" {
 		return "%s/%s"
@@ -17,6 +17,7 @@ package memorystorage
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"sync"
 
 	"github.com/redhat-appstudio/remote-secret/pkg/logs"
@@ -36,8 +37,14 @@ type MemoryStorage struct {
 	ErrorOnGet error
 	// ErrorOnDelete if not nil, the error is thrown when the Delete method is called.
 	ErrorOnDelete error
-
-	lock sync.RWMutex
+	// MaxDataSize is reported as the maximum data size in the capabilities of the storage. It is not enforced by the storage itself.
+	MaxDataSize int
+
+	// versions are the versions of the stored data. The versions are taken from a single counter, so that the data deleted
+	// and stored again never gets the same version as before.
+	versions    map[secretstorage.SecretID]uint64
+	lastVersion uint64
+	lock        sync.RWMutex
 }
 
 // Delete implements secretstorage.SecretStorage
@@ -54,6 +61,7 @@ func (m *MemoryStorage) Delete(ctx context.Context, id secretstorage.SecretID) e
 	m.ensureTokens()
 
 	delete(m.Data, id)
+	delete(m.versions, id)
 	return nil
 }
 
@@ -66,11 +74,10 @@ func (m *MemoryStorage) Get(ctx context.Context, id secretstorage.SecretID) ([]b
 		return nil, m.ErrorOnGet
 	}
 
+	// the maps are not initialized here, because that would need the write lock. Reading from the nil maps is fine.
 	m.lock.RLock()
 	defer m.lock.RUnlock()
 
-	m.ensureTokens()
-
 	data, ok := m.Data[id]
 	if !ok {
 		return nil, fmt.Errorf("%w", secretstorage.NotFoundError)
@@ -89,6 +96,7 @@ func (m *MemoryStorage) Initialize(ctx context.Context) error {
 	defer m.lock.Unlock()
 
 	m.Data = map[secretstorage.SecretID][]byte{}
+	m.versions = map[secretstorage.SecretID]uint64{}
 	return nil
 }
 
@@ -105,15 +113,86 @@ func (m *MemoryStorage) Store(ctx context.Context, id secretstorage.SecretID, da
 
 	m.ensureTokens()
 
-	m.Data[id] = data
+	m.store(id, data)
 	return nil
 }
-func (m *MemoryStorage) Len() int {
+
+// GetVersioned implements secretstorage.SecretStorage
+func (m *MemoryStorage) GetVersioned(ctx context.Context, id secretstorage.SecretID) ([]byte, string, error) {
+	lg := log.FromContext(ctx)
+	lg.V(logs.DebugLevel).Info("get versioned", "id", id)
+
+	if m.ErrorOnGet != nil {
+		return nil, "", m.ErrorOnGet
+	}
+
+	// the maps are not initialized here, because that would need the write lock. Reading from the nil maps is fine.
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	data, ok := m.Data[id]
+	if !ok {
+		return nil, "", fmt.Errorf("%w", secretstorage.NotFoundError)
+	}
+
+	return data, m.version(id), nil
+}
+
+// CompareAndSwap implements secretstorage.SecretStorage
+func (m *MemoryStorage) CompareAndSwap(ctx context.Context, id secretstorage.SecretID, data []byte, expectedVersion string) (string, error) {
+	lg := log.FromContext(ctx)
+	lg.V(logs.DebugLevel).Info("compare and swap", "id", id, "expectedVersion", expectedVersion)
+	if m.ErrorOnStore != nil {
+		return "", m.ErrorOnStore
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
 	m.ensureTokens()
+
+	currentVersion := ""
+	if _, ok := m.Data[id]; ok {
+		currentVersion = m.version(id)
+	}
+	if currentVersion != expectedVersion {
+		return "", fmt.Errorf("%w: expected version '%s' but found '%s'", secretstorage.ConflictError, expectedVersion, currentVersion)
+	}
+
+	m.store(id, data)
+	return m.version(id), nil
+}
+
+// store stores the data with a new version. The lock must be held by the caller.
+func (m *MemoryStorage) store(id secretstorage.SecretID, data []byte) {
+	m.lastVersion++
+	m.Data[id] = data
+	m.versions[id] = m.lastVersion
+}
+
+// version returns the version of the stored data. The data put directly into the Data map has version 0. The lock must be
+// held by the caller.
+func (m *MemoryStorage) version(id secretstorage.SecretID) string {
+	return strconv.FormatUint(m.versions[id], 10)
+}
+
+func (m *MemoryStorage) Len() int {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
 	return len(m.Data)
 }
 func (m *MemoryStorage) Reset() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
 	m.Data = map[secretstorage.SecretID][]byte{}
+	m.versions = map[secretstorage.SecretID]uint64{}
+}
+
+// Capabilities implements secretstorage.SecretStorage
+func (m *MemoryStorage) Capabilities() secretstorage.Capabilities {
+	return secretstorage.Capabilities{MaxDataSize: m.MaxDataSize}
 }
 
 func (m *MemoryStorage) Examine(ctx context.Context) error {
@@ -124,6 +203,9 @@ func (m *MemoryStorage) ensureTokens() {
 	if m.Data == nil {
 		m.Data = map[secretstorage.SecretID][]byte{}
 	}
+	if m.versions == nil {
+		m.versions = map[secretstorage.SecretID]uint64{}
+	}
 }
 
 var _ secretstorage.SecretStorage = (*MemoryStorage)(nil)
@@ -88,3 +88,27 @@ func (m *MeteredSecretStorage) Delete(ctx context.Context, id secretstorage.Secr
 	}
 	return nil
 }
+
+func (m *MeteredSecretStorage) GetVersioned(ctx context.Context, id secretstorage.SecretID) ([]byte, string, error) {
+	timer := prometheus.NewTimer(m.getMetric)
+	defer timer.ObserveDuration()
+	result, version, err := m.SecretStorage.GetVersioned(ctx, id)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read secret data: %w", err)
+	}
+	return result, version, nil
+}
+
+func (m *MeteredSecretStorage) CompareAndSwap(ctx context.Context, id secretstorage.SecretID, data []byte, expectedVersion string) (string, error) {
+	timer := prometheus.NewTimer(m.storeMetric)
+	defer timer.ObserveDuration()
+	version, err := m.SecretStorage.CompareAndSwap(ctx, id, data, expectedVersion)
+	if err != nil {
+		return "", fmt.Errorf("failed to store secret data: %w", err)
+	}
+	return version, nil
+}
+
+func (m *MeteredSecretStorage) Capabilities() secretstorage.Capabilities {
+	return m.SecretStorage.Capabilities()
+}
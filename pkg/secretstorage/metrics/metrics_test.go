@@ -213,6 +213,23 @@ func (m *DummySecretStorage) Delete(ctx context.Context, id secretstorage.Secret
 	return nil
 }
 
+// GetVersioned is a mocked implementation of the GetVersioned method
+func (m *DummySecretStorage) GetVersioned(ctx context.Context, id secretstorage.SecretID) ([]byte, string, error) {
+	m.GetCalled = true
+	return nil, "", nil
+}
+
+// CompareAndSwap is a mocked implementation of the CompareAndSwap method
+func (m *DummySecretStorage) CompareAndSwap(ctx context.Context, id secretstorage.SecretID, data []byte, expectedVersion string) (string, error) {
+	m.StoreCalled = true
+	return "", nil
+}
+
+// Capabilities is a mocked implementation of the Capabilities method
+func (m *DummySecretStorage) Capabilities() secretstorage.Capabilities {
+	return secretstorage.Capabilities{}
+}
+
 func AssertHistogramTotalCount(t *testing.T, g prometheus.Gatherer, name string, labelFilter map[string]string, wantCount int) {
 	metrics, err := g.Gather()
 	if err != nil {
@@ -39,6 +39,31 @@ func (s SecretID) String() string {
 
 var NotFoundError = errors.New("not found")
 
+// DataTooLargeError is returned (wrapped) when the data is larger than the secret storage can store.
+var DataTooLargeError = errors.New("the data is too large for the secret storage")
+
+// ConflictError is returned (wrapped) by CompareAndSwap when the stored data is not of the expected version, i.e. it was
+// changed since it was read.
+var ConflictError = errors.New("the data was changed concurrently")
+
+// CompareAndSwapNotSupportedError is returned (wrapped) by CompareAndSwap of the storages that cannot update the data atomically.
+var CompareAndSwapNotSupportedError = errors.New("the secret storage does not support the compare-and-swap")
+
+// Capabilities describes the limits of a secret storage.
+type Capabilities struct {
+	// MaxDataSize is the maximum size of the data in bytes that can be stored under a single id. 0 means no limit.
+	MaxDataSize int
+}
+
+// CheckDataSize returns an error wrapping the DataTooLargeError if data of the provided size cannot be stored
+// in the secret storage with these capabilities.
+func (c Capabilities) CheckDataSize(size int) error {
+	if c.MaxDataSize > 0 && size > c.MaxDataSize {
+		return fmt.Errorf("%w: the data has %d bytes but the maximum is %d bytes", DataTooLargeError, size, c.MaxDataSize)
+	}
+	return nil
+}
+
 // SecretStorage is a generic storage mechanism for storing secret data keyed by the SecretID.
 type SecretStorage interface {
 	// Initialize initializes the connection to the underlying data store, etc.
@@ -51,6 +76,35 @@ type SecretStorage interface {
 	Get(ctx context.Context, id SecretID) ([]byte, error)
 	// Delete deletes the data of given id. A NotFoundError is returned if there is no such data.
 	Delete(ctx context.Context, id SecretID) error
+	// GetVersioned retrieves the data under the given id together with its version. The version is opaque and is only
+	// meant to be passed to CompareAndSwap. The storages that don't support the compare-and-swap return an empty version.
+	// A NotFoundError is returned if the data is not found.
+	GetVersioned(ctx context.Context, id SecretID) ([]byte, string, error)
+	// CompareAndSwap stores the provided data under given id only if the currently stored data has the expected version.
+	// The empty expected version means that there must be no data stored under the id. An error wrapping the ConflictError
+	// is returned otherwise. The version of the stored data is returned on success.
+	CompareAndSwap(ctx context.Context, id SecretID, data []byte, expectedVersion string) (string, error)
+	// Capabilities returns the limits of the storage. The callers can use it to reject the data that cannot be stored
+	// before trying to store it.
+	Capabilities() Capabilities
+}
+
+// WithMaxDataSize returns a secret storage that advertises the provided maximum data size in its capabilities instead
+// of the one advertised by the provided storage. This is useful when the actual limit depends on the configuration
+// of the backend that the storage cannot find out.
+func WithMaxDataSize(storage SecretStorage, maxDataSize int) SecretStorage {
+	return &maxDataSizeSecretStorage{SecretStorage: storage, maxDataSize: maxDataSize}
+}
+
+type maxDataSizeSecretStorage struct {
+	SecretStorage
+	maxDataSize int
+}
+
+func (s *maxDataSizeSecretStorage) Capabilities() Capabilities {
+	caps := s.SecretStorage.Capabilities()
+	caps.MaxDataSize = s.maxDataSize
+	return caps
 }
 
 // TypedSecretStorage is a generic "companion" to the "raw" SecretStorage interface which uses
@@ -66,6 +120,14 @@ type TypedSecretStorage[ID any, D any] interface {
 	Get(ctx context.Context, id *ID) (*D, error)
 	// Delete deletes the data of given id. A NotFoundError is returned if there is no such data.
 	Delete(ctx context.Context, id *ID) error
+	// GetVersioned retrieves the data under the given id together with its version. A NotFoundError is returned if the data is not found.
+	GetVersioned(ctx context.Context, id *ID) (*D, string, error)
+	// CompareAndSwap stores the provided data under given id only if the currently stored data has the expected version.
+	// An error wrapping the ConflictError is returned otherwise. The version of the stored data is returned on success.
+	CompareAndSwap(ctx context.Context, id *ID, data *D, expectedVersion string) (string, error)
+	// CheckDataSize checks that the data is not too large to be stored. An error wrapping the DataTooLargeError is returned
+	// if it is.
+	CheckDataSize(data *D) error
 }
 
 // DefaultTypedSecretStorage is the default implementation of the TypedSecretStorage interface
@@ -150,6 +212,49 @@ func (s *DefaultTypedSecretStorage[ID, D]) Get(ctx context.Context, id *ID) (*D,
 	return &parsed, nil
 }
 
+// GetVersioned implements TypedSecretStorage
+func (s *DefaultTypedSecretStorage[ID, D]) GetVersioned(ctx context.Context, id *ID) (*D, string, error) {
+	realId, errId := s.ToID(id)
+	if errId != nil {
+		return nil, "", fmt.Errorf("failed to create object id during getting the secret: %w", errId)
+	}
+
+	d, version, err := s.SecretStorage.GetVersioned(ctx, *realId)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get the %s: %w", s.DataTypeName, err)
+	}
+
+	var parsed D
+	if err := s.Deserialize(d, &parsed); err != nil {
+		return nil, "", fmt.Errorf("failed to deserialize the data to %s: %w", s.DataTypeName, err)
+	}
+	return &parsed, version, nil
+}
+
+// CompareAndSwap implements TypedSecretStorage
+func (s *DefaultTypedSecretStorage[ID, D]) CompareAndSwap(ctx context.Context, id *ID, data *D, expectedVersion string) (string, error) {
+	secretId, errId := s.ToID(id)
+	if errId != nil {
+		return "", fmt.Errorf("failed to create object id during storing the secret: %w", errId)
+	}
+
+	bytes, err := s.Serialize(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize the %s for storage: %w", s.DataTypeName, err)
+	}
+
+	if err = s.SecretStorage.Capabilities().CheckDataSize(len(bytes)); err != nil {
+		return "", fmt.Errorf("failed to store %s: %w", s.DataTypeName, err)
+	}
+
+	version, err := s.SecretStorage.CompareAndSwap(ctx, *secretId, bytes, expectedVersion)
+	if err != nil {
+		return "", fmt.Errorf("failed to store %s: %w", s.DataTypeName, err)
+	}
+
+	return version, nil
+}
+
 // Initialize implements TypedSecretStorage. It is a noop.
 func (s *DefaultTypedSecretStorage[ID, D]) Initialize(ctx context.Context) error {
 	return nil
@@ -163,6 +268,19 @@ func (s *DefaultTypedSecretStorage[ID, D]) Examine(ctx context.Context) error {
 	return nil
 }
 
+// CheckDataSize implements TypedSecretStorage. It serializes the data to find out its size in the underlying storage.
+func (s *DefaultTypedSecretStorage[ID, D]) CheckDataSize(data *D) error {
+	bytes, err := s.Serialize(data)
+	if err != nil {
+		return fmt.Errorf("failed to serialize the %s to check its size: %w", s.DataTypeName, err)
+	}
+
+	if err := s.SecretStorage.Capabilities().CheckDataSize(len(bytes)); err != nil {
+		return fmt.Errorf("the %s cannot be stored: %w", s.DataTypeName, err)
+	}
+	return nil
+}
+
 // Store implements TypedSecretStorage
 func (s *DefaultTypedSecretStorage[ID, D]) Store(ctx context.Context, id *ID, data *D) error {
 	secretId, errId := s.ToID(id)
@@ -175,6 +293,10 @@ func (s *DefaultTypedSecretStorage[ID, D]) Store(ctx context.Context, id *ID, da
 		return fmt.Errorf("failed to serialize the %s for storage: %w", s.DataTypeName, err)
 	}
 
+	if err = s.SecretStorage.Capabilities().CheckDataSize(len(bytes)); err != nil {
+		return fmt.Errorf("failed to store %s: %w", s.DataTypeName, err)
+	}
+
 	if err = s.SecretStorage.Store(ctx, *secretId, bytes); err != nil {
 		return fmt.Errorf("failed to store %s: %w", s.DataTypeName, err)
 	}
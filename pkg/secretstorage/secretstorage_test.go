@@ -141,6 +141,47 @@ func TestDefaultTypedSecretStorage_Initialize(t *testing.T) {
 	assert.False(t, record.SerializeCalled)
 }
 
+func TestDefaultTypedSecretStorage_CheckDataSize(t *testing.T) {
+	stored := false
+	dtss := DefaultTypedSecretStorage[string, string]{
+		DataTypeName: "kachny",
+		SecretStorage: &TestSecretStorage{
+			StoreImpl: func(_ context.Context, _ SecretID, _ []byte) error {
+				stored = true
+				return nil
+			},
+			Caps: Capabilities{MaxDataSize: 5},
+		},
+		ToID: func(s *string) (*SecretID, error) {
+			return &SecretID{Name: *s}, nil
+		},
+		Serialize:   SerializeJSON[string],
+		Deserialize: DeserializeJSON[string],
+	}
+
+	// the size is checked on the serialized data, i.e. including the quotes
+	assert.NoError(t, dtss.CheckDataSize(ptr.To("abc")))
+
+	err := dtss.CheckDataSize(ptr.To("abcd"))
+	assert.ErrorIs(t, err, DataTooLargeError)
+	assert.ErrorContains(t, err, "the data has 6 bytes but the maximum is 5 bytes")
+
+	t.Run("store refuses too large data", func(t *testing.T) {
+		assert.ErrorIs(t, dtss.Store(context.TODO(), ptr.To("id"), ptr.To("abcd")), DataTooLargeError)
+		assert.False(t, stored)
+
+		assert.NoError(t, dtss.Store(context.TODO(), ptr.To("id"), ptr.To("abc")))
+		assert.True(t, stored)
+	})
+}
+
+func TestWithMaxDataSize(t *testing.T) {
+	storage := WithMaxDataSize(&TestSecretStorage{Caps: Capabilities{MaxDataSize: 5}}, 10)
+	assert.Equal(t, 10, storage.Capabilities().MaxDataSize)
+	assert.NoError(t, storage.Capabilities().CheckDataSize(10))
+	assert.ErrorIs(t, storage.Capabilities().CheckDataSize(11), DataTooLargeError)
+}
+
 func TestSerializeJSON(t *testing.T) {
 	data, err := SerializeJSON(ptr.To(true))
 	assert.NoError(t, err)
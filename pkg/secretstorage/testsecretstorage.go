@@ -25,6 +25,11 @@ type TestSecretStorage struct {
 	StoreImpl      func(ctx context.Context, key SecretID, data []byte) error
 	GetImpl        func(ctx context.Context, key SecretID) ([]byte, error)
 	DeleteImpl     func(ctx context.Context, key SecretID) error
+	// GetVersionedImpl defaults to GetImpl returning an empty version.
+	GetVersionedImpl func(ctx context.Context, key SecretID) ([]byte, string, error)
+	// CompareAndSwapImpl defaults to returning the CompareAndSwapNotSupportedError.
+	CompareAndSwapImpl func(ctx context.Context, key SecretID, data []byte, expectedVersion string) (string, error)
+	Caps               Capabilities
 }
 
 func (t TestSecretStorage) Examine(ctx context.Context) error {
@@ -63,4 +68,25 @@ func (t TestSecretStorage) Delete(ctx context.Context, key SecretID) error {
 	return t.DeleteImpl(ctx, key)
 }
 
+func (t TestSecretStorage) GetVersioned(ctx context.Context, key SecretID) ([]byte, string, error) {
+	if t.GetVersionedImpl == nil {
+		data, err := t.Get(ctx, key)
+		return data, "", err
+	}
+
+	return t.GetVersionedImpl(ctx, key)
+}
+
+func (t TestSecretStorage) CompareAndSwap(ctx context.Context, key SecretID, data []byte, expectedVersion string) (string, error) {
+	if t.CompareAndSwapImpl == nil {
+		return "", CompareAndSwapNotSupportedError
+	}
+
+	return t.CompareAndSwapImpl(ctx, key, data, expectedVersion)
+}
+
+func (t TestSecretStorage) Capabilities() Capabilities {
+	return t.Caps
+}
+
 var _ SecretStorage = (*TestSecretStorage)(nil)
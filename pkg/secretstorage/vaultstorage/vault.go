@@ -17,10 +17,12 @@ package vaultstorage
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	hclog "github.com/hashicorp/go-hclog"
 	vault "github.com/hashicorp/vault/api"
@@ -44,6 +46,10 @@ type VaultSecretStorage struct {
 
 const vaultDataPathFormat = "%s/data/%s/%s"
 
+// MaxDataSize is the maximum size of the data that can be safely stored in Vault. The data is stored base64 encoded and the default
+// maximum size of an entry in the Vault integrated storage is 1MiB, so we leave some room for the encoding and the metadata.
+const MaxDataSize = 768 * 1024
+
 var (
 	VaultError             = errors.New("error in Vault")
 	noAuthInfoInVaultError = errors.New("no auth info returned from Vault")
@@ -129,31 +135,126 @@ func (v *VaultSecretStorage) Examine(ctx context.Context) error {
 }
 
 func (v *VaultSecretStorage) Store(ctx context.Context, id secretstorage.SecretID, bytes []byte) error {
+	if _, err := v.write(ctx, id, bytes, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (v *VaultSecretStorage) Get(ctx context.Context, id secretstorage.SecretID) ([]byte, error) {
+	secret, err := v.read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !hasData(secret) {
+		return nil, secretstorage.NotFoundError
+	}
+
+	bytes, err := extractByteData(secret.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract the data from Vault response: %w", err)
+	}
+
+	return bytes, nil
+}
+
+// GetVersioned implements secretstorage.SecretStorage. The version is the version of the secret in the KV secrets engine.
+func (v *VaultSecretStorage) GetVersioned(ctx context.Context, id secretstorage.SecretID) ([]byte, string, error) {
+	secret, err := v.read(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if !hasData(secret) {
+		return nil, "", secretstorage.NotFoundError
+	}
+
+	bytes, err := extractByteData(secret.Data)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to extract the data from Vault response: %w", err)
+	}
+
+	version, err := extractMetadataVersion(secret.Data)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to extract the version from Vault response: %w", err)
+	}
+
+	return bytes, version, nil
+}
+
+// CompareAndSwap implements secretstorage.SecretStorage using the check-and-set of the KV secrets engine.
+func (v *VaultSecretStorage) CompareAndSwap(ctx context.Context, id secretstorage.SecretID, bytes []byte, expectedVersion string) (string, error) {
+	var cas uint64
+	if expectedVersion == "" {
+		// The KV secrets engine keeps the metadata of the deleted data, so the check-and-set needs the version of the deleted data.
+		secret, err := v.read(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		if hasData(secret) {
+			return "", fmt.Errorf("%w: the data already exists", secretstorage.ConflictError)
+		}
+		if secret != nil && secret.Data != nil && secret.Data["metadata"] != nil {
+			deletedVersion, err := extractMetadataVersion(secret.Data)
+			if err != nil {
+				return "", fmt.Errorf("failed to extract the version of the deleted data from Vault response: %w", err)
+			}
+			cas, _ = strconv.ParseUint(deletedVersion, 10, 64)
+		}
+	} else {
+		var err error
+		cas, err = strconv.ParseUint(expectedVersion, 10, 64)
+		if err != nil {
+			// such version can never be stored
+			return "", fmt.Errorf("%w: invalid version '%s'", secretstorage.ConflictError, expectedVersion)
+		}
+	}
+
+	s, err := v.write(ctx, id, bytes, map[string]interface{}{"cas": cas})
+	if err != nil {
+		if isCheckAndSetError(err) {
+			return "", fmt.Errorf("%w: %s", secretstorage.ConflictError, err.Error())
+		}
+		return "", err
+	}
+
+	version, err := versionString(s.Data["version"])
+	if err != nil {
+		return "", fmt.Errorf("failed to extract the version of the stored data from Vault response: %w", err)
+	}
+	return version, nil
+}
+
+// write writes the data to Vault using the provided options of the write request.
+func (v *VaultSecretStorage) write(ctx context.Context, id secretstorage.SecretID, bytes []byte, options map[string]interface{}) (*vault.Secret, error) {
 	data := map[string]interface{}{
 		// yes, the data HAS TO be a JSON object (or serializable thereto). Even if a string is a valid JSON value, it gives Vault fits :)
 		"data": map[string]interface{}{
 			"bytes": base64.StdEncoding.EncodeToString(bytes),
 		},
 	}
+	if options != nil {
+		data["options"] = options
+	}
 	lg := log.FromContext(ctx)
 	path := v.generateSecretName(id)
 
 	ctx = httptransport.ContextWithMetrics(ctx, &requestMetricConfig)
 	s, err := v.client.Logical().WriteWithContext(ctx, path, data)
 	if err != nil {
-		return fmt.Errorf("error writing the data to Vault: %w", err)
+		return nil, fmt.Errorf("error writing the data to Vault: %w", err)
 	}
 	if s == nil {
-		return unspecifiedStoreError
+		return nil, unspecifiedStoreError
 	}
 	for _, w := range s.Warnings {
 		lg.Info(w)
 	}
 
-	return nil
+	return s, nil
 }
 
-func (v *VaultSecretStorage) Get(ctx context.Context, id secretstorage.SecretID) ([]byte, error) {
+// read reads the secret from Vault. The returned secret may be nil or without data if there is no data stored under the id.
+func (v *VaultSecretStorage) read(ctx context.Context, id secretstorage.SecretID) (*vault.Secret, error) {
 	lg := log.FromContext(ctx)
 
 	ctx = httptransport.ContextWithMetrics(ctx, &requestMetricConfig)
@@ -163,20 +264,14 @@ func (v *VaultSecretStorage) Get(ctx context.Context, id secretstorage.SecretID)
 	if err != nil {
 		return nil, fmt.Errorf("error reading the data: %w", err)
 	}
-	if secret == nil || secret.Data == nil || len(secret.Data) == 0 || secret.Data["data"] == nil {
+	if !hasData(secret) {
 		lg.V(logs.DebugLevel).Info("no data found in vault at", "path", path)
-		return nil, secretstorage.NotFoundError
+		return secret, nil
 	}
 	for _, w := range secret.Warnings {
 		lg.Info(w)
 	}
-
-	bytes, err := extractByteData(secret.Data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to extract the data from Vault response: %w", err)
-	}
-
-	return bytes, nil
+	return secret, nil
 }
 
 func (v *VaultSecretStorage) Delete(ctx context.Context, id secretstorage.SecretID) error {
@@ -191,6 +286,10 @@ func (v *VaultSecretStorage) Delete(ctx context.Context, id secretstorage.Secret
 	return nil
 }
 
+func (v *VaultSecretStorage) Capabilities() secretstorage.Capabilities {
+	return secretstorage.Capabilities{MaxDataSize: MaxDataSize}
+}
+
 func (v *VaultSecretStorage) initFields() error {
 	// These fields are only non-nil at the point in time they're called
 	// from init if called from tests that pre-initialize these to work with
@@ -272,6 +371,44 @@ func (v *VaultSecretStorage) generateSecretName(id secretstorage.SecretID) strin
 	return fmt.Sprintf(vaultDataPathFormat, v.Config.DataPathPrefix, id.Namespace, id.Name)
 }
 
+func hasData(secret *vault.Secret) bool {
+	return secret != nil && len(secret.Data) > 0 && secret.Data["data"] != nil
+}
+
+// isCheckAndSetError tells whether the write to Vault failed because the check-and-set version didn't match.
+func isCheckAndSetError(err error) bool {
+	var respErr *vault.ResponseError
+	if !errors.As(err, &respErr) || respErr.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	return strings.Contains(strings.Join(respErr.Errors, " "), "check-and-set")
+}
+
+func extractMetadataVersion(responseData map[string]interface{}) (string, error) {
+	metadataField, ok := responseData["metadata"]
+	if !ok {
+		return "", fmt.Errorf("%w: metadata field not present in Vault response", UnexpectedDataError)
+	}
+	metadata, ok := metadataField.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("%w: metadata field not a map", UnexpectedDataError)
+	}
+	return versionString(metadata["version"])
+}
+
+func versionString(version interface{}) (string, error) {
+	switch v := version.(type) {
+	case json.Number:
+		return v.String(), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', 0, 64), nil
+	case int:
+		return strconv.Itoa(v), nil
+	default:
+		return "", fmt.Errorf("%w: version field not a number", UnexpectedDataError)
+	}
+}
+
 func extractByteData(responseData map[string]interface{}) ([]byte, error) {
 	dataField, ok := responseData["data"]
 	if !ok {
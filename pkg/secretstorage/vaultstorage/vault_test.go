@@ -16,8 +16,12 @@ package vaultstorage
 
 import (
 	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"testing"
 
+	vault "github.com/hashicorp/vault/api"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -115,3 +119,39 @@ func TestExtractByteData(t *testing.T) {
 		assert.Nil(t, extracted)
 	})
 }
+
+func TestExtractMetadataVersion(t *testing.T) {
+	t.Run("json number", func(t *testing.T) {
+		version, err := extractMetadataVersion(map[string]any{"metadata": map[string]any{"version": json.Number("3")}})
+		assert.NoError(t, err)
+		assert.Equal(t, "3", version)
+	})
+
+	t.Run("float", func(t *testing.T) {
+		version, err := extractMetadataVersion(map[string]any{"metadata": map[string]any{"version": float64(42)}})
+		assert.NoError(t, err)
+		assert.Equal(t, "42", version)
+	})
+
+	t.Run("missing metadata", func(t *testing.T) {
+		_, err := extractMetadataVersion(map[string]any{})
+		assert.ErrorIs(t, err, UnexpectedDataError)
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		_, err := extractMetadataVersion(map[string]any{"metadata": map[string]any{"version": "3"}})
+		assert.ErrorIs(t, err, UnexpectedDataError)
+	})
+}
+
+func TestIsCheckAndSetError(t *testing.T) {
+	assert.True(t, isCheckAndSetError(fmt.Errorf("failed: %w", &vault.ResponseError{
+		StatusCode: http.StatusBadRequest,
+		Errors:     []string{"check-and-set parameter did not match the current version"},
+	})))
+	assert.False(t, isCheckAndSetError(&vault.ResponseError{
+		StatusCode: http.StatusForbidden,
+		Errors:     []string{"permission denied"},
+	}))
+	assert.False(t, isCheckAndSetError(UnexpectedDataError))
+}
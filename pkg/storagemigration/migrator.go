@@ -0,0 +1,99 @@
+//
+// Copyright (c) 2021 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagemigration
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	apiv1 "github.com/redhat-appstudio/remote-secret/api/v1"
+	"github.com/redhat-appstudio/remote-secret/pkg/logs"
+)
+
+// listPageSize is the number of remote secrets read from the cluster at once during the migration.
+const listPageSize = 100
+
+// StorageVersionMigrator rewrites all the remote secrets in the cluster so that they are stored in the current storage version
+// of the CRD. The API server converts the objects to the storage version on every write, so it is enough to update each object
+// without changing it. Once the migration finishes, the old versions can be removed from the stored versions in the status
+// of the CRD.
+type StorageVersionMigrator struct {
+	// Reader is used to list the remote secrets. It should read directly from the cluster rather than from a cache so that
+	// the migration doesn't need to start an informer for the hub version of the remote secrets.
+	Reader client.Reader
+	// Client is used to get and update the individual remote secrets.
+	Client client.Client
+}
+
+// Start runs the migration in the background. Any failure is only logged, because the remote secrets stored in the previous
+// versions remain fully functional.
+func (m *StorageVersionMigrator) Start(ctx context.Context) error {
+	go func() {
+		lg := log.FromContext(ctx)
+		lg.Info("starting the storage version migration of the remote secrets")
+		count, err := m.Migrate(ctx)
+		if err != nil {
+			lg.Error(err, "the storage version migration of the remote secrets failed", "migratedCount", count)
+			return
+		}
+		lg.Info("storage version migration of the remote secrets finished", "migratedCount", count)
+	}()
+	return nil
+}
+
+// Migrate rewrites all the remote secrets in the cluster and returns the number of the rewritten remote secrets.
+func (m *StorageVersionMigrator) Migrate(ctx context.Context) (int, error) {
+	count := 0
+	list := &apiv1.RemoteSecretList{}
+	opts := &client.ListOptions{Limit: listPageSize}
+	for {
+		if err := m.Reader.List(ctx, list, opts); err != nil {
+			return count, fmt.Errorf("failed to list the remote secrets to migrate: %w", err)
+		}
+
+		for i := range list.Items {
+			key := client.ObjectKeyFromObject(&list.Items[i])
+			if err := m.rewrite(ctx, key); err != nil {
+				return count, fmt.Errorf("failed to migrate the remote secret %s: %w", key, err)
+			}
+			count++
+		}
+
+		if list.Continue == "" {
+			return count, nil
+		}
+		opts.Continue = list.Continue
+	}
+}
+
+func (m *StorageVersionMigrator) rewrite(ctx context.Context, key client.ObjectKey) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error { //nolint:wrapcheck // the error is wrapped by the caller
+		rs := &apiv1.RemoteSecret{}
+		if err := m.Reader.Get(ctx, key, rs); err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return err //nolint:wrapcheck // the error is wrapped by the caller
+		}
+
+		log.FromContext(ctx).V(logs.DebugLevel).Info("rewriting remote secret in the storage version", "remoteSecret", key)
+		return m.Client.Update(ctx, rs) //nolint:wrapcheck // the error is wrapped by the caller
+	})
+}
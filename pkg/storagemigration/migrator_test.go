@@ -0,0 +1,53 @@
+//
+// Copyright (c) 2021 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagemigration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/redhat-appstudio/remote-secret/api/v1"
+)
+
+func TestMigrate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, apiv1.AddToScheme(scheme))
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&apiv1.RemoteSecret{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns1"}},
+			&apiv1.RemoteSecret{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "ns2"}},
+		).
+		Build()
+
+	before := &apiv1.RemoteSecret{}
+	assert.NoError(t, cl.Get(context.TODO(), client.ObjectKey{Name: "a", Namespace: "ns1"}, before))
+
+	m := &StorageVersionMigrator{Reader: cl, Client: cl}
+	count, err := m.Migrate(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	after := &apiv1.RemoteSecret{}
+	assert.NoError(t, cl.Get(context.TODO(), client.ObjectKey{Name: "a", Namespace: "ns1"}, after))
+	assert.NotEqual(t, before.ResourceVersion, after.ResourceVersion)
+}
@@ -14,15 +14,25 @@ package sync
 
 import (
 	"context"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"sort"
 
 	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/csaupgrade"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+var blueprintWithoutNameError = stderrors.New("the object to apply must have a name")
+
 // Syncer synchronized K8s objects with the cluster
 type Syncer struct {
 	client client.Client
@@ -44,38 +54,191 @@ type LabelsAndAnnotationsSyncOptions struct {
 // Sync syncs the blueprint to the cluster in a generic (as much as Go allows) manner.
 // Returns true if the object was created or updated, false if there was no change detected.
 func (s *Syncer) Sync(ctx context.Context, owner client.Object, blueprint client.Object, diffOpts cmp.Option, laOpts LabelsAndAnnotationsSyncOptions) (bool, client.Object, error) {
+	actual, err := s.getActual(ctx, blueprint)
+	if err != nil {
+		return false, nil, err
+	}
+
+	if actual == nil {
+		actual, err = s.create(ctx, owner, blueprint)
+		if err != nil {
+			return false, actual, err
+		}
+
+		return true, actual, nil
+	}
+
+	return s.update(ctx, owner, actual, blueprint, diffOpts, laOpts)
+}
+
+// Plan computes what Sync would do with the blueprint without actually changing anything in the cluster. It returns the object
+// as it currently exists in the cluster (nil if it doesn't exist and Sync would create it) and the object that Sync would persist
+// in the cluster (nil if no change would be made).
+func (s *Syncer) Plan(ctx context.Context, blueprint client.Object, diffOpts cmp.Option, laOpts LabelsAndAnnotationsSyncOptions) (client.Object, client.Object, error) {
+	actual, err := s.getActual(ctx, blueprint)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if actual == nil {
+		return nil, blueprint, nil
+	}
+
+	if len(cmp.Diff(actual, blueprint, diffOpts)) == 0 {
+		return actual, nil, nil
+	}
+
+	desired := blueprint.DeepCopyObject().(client.Object)
+	mergeLabelsAndAnnotations(actual, desired, laOpts)
+
+	return actual, desired, nil
+}
+
+// Apply applies the blueprint to the cluster using the server-side apply with the provided field manager. Unlike Sync,
+// this doesn't need to compute the set of managed labels and annotations, because the cluster tracks the ownership of
+// the fields in the managed fields of the object. The fields that were previously applied by the field manager and are no
+// longer present in the blueprint are removed from the object by the cluster, while the fields owned by other field managers
+// are left intact. The ownership of the fields in conflict is forced to the provided field manager.
+//
+// The blueprint must have its name set, because the server-side apply is not able to generate names.
+func (s *Syncer) Apply(ctx context.Context, owner client.Object, blueprint client.Object, fieldManager string) (client.Object, error) {
 	lg := log.FromContext(ctx)
 
-	var actual client.Object
-	var err error
+	applied := blueprint.DeepCopyObject().(client.Object)
+	objectKey := client.ObjectKeyFromObject(applied)
 
-	if blueprint.GetName() != "" {
-		actual, err = s.newWithSameKind(blueprint)
-		if err != nil {
-			lg.Error(err, "failed to create an empty object with GVK", "GVK", blueprint.GetObjectKind().GroupVersionKind())
-			return false, nil, err
+	if applied.GetName() == "" {
+		return nil, fmt.Errorf("%w: %+v", blueprintWithoutNameError, objectKey)
+	}
+
+	// the apply configuration must not contain the managed fields
+	applied.SetManagedFields(nil)
+
+	if owner != nil {
+		if err := controllerutil.SetControllerReference(owner, applied, s.client.Scheme()); err != nil {
+			lg.Error(err, "failed to set owner reference", "Owner", client.ObjectKeyFromObject(owner), "Object", objectKey)
+			return nil, fmt.Errorf("error while setting the owner reference to %+v: %w", objectKey, err)
+		}
+	}
+
+	if err := s.client.Patch(ctx, applied, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership); err != nil {
+		lg.Error(err, "failed to apply object", "Object", objectKey)
+		return nil, fmt.Errorf("error while applying the object %+v with GVK %+v: %w", objectKey, blueprint.GetObjectKind().GroupVersionKind(), err)
+	}
+
+	// set the type meta again, because it disappears after client patches the object
+	applied.GetObjectKind().SetGroupVersionKind(blueprint.GetObjectKind().GroupVersionKind())
+
+	return applied, nil
+}
+
+// UpgradeManagedFields makes the fieldManager own the fields of the object that were previously set by the client-side updates
+// of the csaManagers, so that the later server-side applies of the fieldManager remove these fields once they are no longer
+// present in the applied blueprint. Without this, the fields set by the updates would stay on the object forever. The object
+// must have been read from the cluster. Nothing is changed if there are no fields set by the csaManagers.
+//
+// The ownership of the top-level fields listed in keptFields is not transferred, which is needed for the fields the applies of
+// the fieldManager never contain. Otherwise the next apply would remove them.
+func (s *Syncer) UpgradeManagedFields(ctx context.Context, obj client.Object, csaManagers sets.Set[string], fieldManager string, keptFields ...string) error {
+	managedFields, kept, err := splitManagedFields(obj.GetManagedFields(), csaManagers, keptFields)
+	if err != nil {
+		return fmt.Errorf("failed to process the managed fields of %s: %w", client.ObjectKeyFromObject(obj), err)
+	}
+
+	upgraded := obj.DeepCopyObject().(client.Object)
+	upgraded.SetManagedFields(managedFields)
+	if err = csaupgrade.UpgradeManagedFields(upgraded, csaManagers, fieldManager); err != nil {
+		return fmt.Errorf("failed to upgrade the managed fields of %s: %w", client.ObjectKeyFromObject(obj), err)
+	}
+	upgradedFields := append(upgraded.GetManagedFields(), kept...)
+
+	if sameManagedFields(obj.GetManagedFields(), upgradedFields) {
+		return nil
+	}
+
+	// the same patch as produced by csaupgrade.UpgradeManagedFieldsPatch, guarded by the resource version
+	patch, err := json.Marshal([]map[string]any{
+		{"op": "replace", "path": "/metadata/managedFields", "value": upgradedFields},
+		{"op": "replace", "path": "/metadata/resourceVersion", "value": obj.GetResourceVersion()},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to construct the patch of the managed fields of %s: %w", client.ObjectKeyFromObject(obj), err)
+	}
+
+	if err = s.client.Patch(ctx, obj, client.RawPatch(types.JSONPatchType, patch)); err != nil {
+		log.FromContext(ctx).Error(err, "failed to upgrade the managed fields", "Object", client.ObjectKeyFromObject(obj))
+		return fmt.Errorf("error while upgrading the managed fields of %s: %w", client.ObjectKeyFromObject(obj), err)
+	}
+
+	return nil
+}
+
+// splitManagedFields removes the keptFields from the managed fields entries of the client-side updates of the csaManagers. The removed
+// parts are returned as separate entries of the same managers.
+func splitManagedFields(entries []metav1.ManagedFieldsEntry, csaManagers sets.Set[string], keptFields []string) ([]metav1.ManagedFieldsEntry, []metav1.ManagedFieldsEntry, error) {
+	if len(keptFields) == 0 {
+		return entries, nil, nil
+	}
+
+	var split []metav1.ManagedFieldsEntry
+	var kept []metav1.ManagedFieldsEntry
+	for _, entry := range entries {
+		if entry.Operation != metav1.ManagedFieldsOperationUpdate || entry.Subresource != "" || !csaManagers.Has(entry.Manager) || entry.FieldsV1 == nil {
+			split = append(split, entry)
+			continue
 		}
 
-		key := client.ObjectKeyFromObject(blueprint)
-		if err = s.client.Get(ctx, key, actual); err != nil {
-			if !errors.IsNotFound(err) {
-				lg.Error(err, "failed to read object to be synced", "ObjectKey", key)
-				return false, nil, fmt.Errorf("error getting the object %+v: %w", key, err)
+		fields := map[string]json.RawMessage{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse the managed fields of %s: %w", entry.Manager, err)
+		}
+		keptPart := map[string]json.RawMessage{}
+		for _, f := range keptFields {
+			if v, ok := fields["f:"+f]; ok {
+				keptPart["f:"+f] = v
+				delete(fields, "f:"+f)
 			}
-			actual = nil
 		}
-	}
+		if len(keptPart) == 0 {
+			split = append(split, entry)
+			continue
+		}
 
-	if actual == nil {
-		actual, err = s.create(ctx, owner, blueprint)
+		rest, err := json.Marshal(fields)
 		if err != nil {
-			return false, actual, err
+			return nil, nil, fmt.Errorf("failed to serialize the managed fields of %s: %w", entry.Manager, err)
+		}
+		keptRaw, err := json.Marshal(keptPart)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to serialize the managed fields of %s: %w", entry.Manager, err)
 		}
 
-		return true, actual, nil
+		restEntry := *entry.DeepCopy()
+		restEntry.FieldsV1 = &metav1.FieldsV1{Raw: rest}
+		split = append(split, restEntry)
+
+		keptEntry := *entry.DeepCopy()
+		keptEntry.FieldsV1 = &metav1.FieldsV1{Raw: keptRaw}
+		kept = append(kept, keptEntry)
 	}
 
-	return s.update(ctx, owner, actual, blueprint, diffOpts, laOpts)
+	return split, kept, nil
+}
+
+// sameManagedFields compares the managed fields entries regardless of their order, because splitManagedFields may reorder them.
+func sameManagedFields(a, b []metav1.ManagedFieldsEntry) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sorted := func(entries []metav1.ManagedFieldsEntry) []metav1.ManagedFieldsEntry {
+		c := make([]metav1.ManagedFieldsEntry, len(entries))
+		copy(c, entries)
+		sort.SliceStable(c, func(i, j int) bool {
+			return fmt.Sprint(c[i].Manager, c[i].Operation, c[i].Subresource, c[i].APIVersion) < fmt.Sprint(c[j].Manager, c[j].Operation, c[j].Subresource, c[j].APIVersion)
+		})
+		return c
+	}
+	return equality.Semantic.DeepEqual(sorted(a), sorted(b))
 }
 
 // Delete deletes the supplied object from the cluster.
@@ -102,6 +265,33 @@ func (s *Syncer) Delete(ctx context.Context, object client.Object) error {
 	return nil
 }
 
+// getActual reads the object corresponding to the blueprint from the cluster. Returns nil if the blueprint doesn't have
+// a name or if the object doesn't exist in the cluster.
+func (s *Syncer) getActual(ctx context.Context, blueprint client.Object) (client.Object, error) {
+	if blueprint.GetName() == "" {
+		return nil, nil
+	}
+
+	lg := log.FromContext(ctx)
+
+	actual, err := s.newWithSameKind(blueprint)
+	if err != nil {
+		lg.Error(err, "failed to create an empty object with GVK", "GVK", blueprint.GetObjectKind().GroupVersionKind())
+		return nil, err
+	}
+
+	key := client.ObjectKeyFromObject(blueprint)
+	if err = s.client.Get(ctx, key, actual); err != nil {
+		if !errors.IsNotFound(err) {
+			lg.Error(err, "failed to read object to be synced", "ObjectKey", key)
+			return nil, fmt.Errorf("error getting the object %+v: %w", key, err)
+		}
+		return nil, nil
+	}
+
+	return actual, nil
+}
+
 func (s *Syncer) newWithSameKind(blueprint client.Object) (client.Object, error) {
 	gvk := blueprint.GetObjectKind().GroupVersionKind()
 	o, err := s.client.Scheme().New(gvk)
@@ -155,50 +345,7 @@ func (s *Syncer) update(ctx context.Context, owner client.Object, actual client.
 	lg := log.FromContext(ctx)
 	diff := cmp.Diff(actual, blueprint, diffOpts)
 	if len(diff) > 0 {
-		// we need to handle labels and annotations specially in case the cluster admin has modified them.
-		// if the current object in the cluster has the same annos/labels, they get overwritten with what's
-		// in the blueprint. If there are any managed labels/annos, they are deleted from the current object if not in the blueprint.
-		// Any additional labels/annos on the object are kept though.
-		targetLabels := map[string]string{}
-		targetAnnos := map[string]string{}
-
-		for k, v := range actual.GetAnnotations() {
-			targetAnnos[k] = v
-		}
-		for k, v := range actual.GetLabels() {
-			targetLabels[k] = v
-		}
-
-		for k, v := range blueprint.GetAnnotations() {
-			targetAnnos[k] = v
-		}
-		for k, v := range blueprint.GetLabels() {
-			targetLabels[k] = v
-		}
-
-		// now go through the labels and annos and remove all that are managed and not in the blueprint
-		for k := range targetLabels {
-			for _, mlk := range laOpts.ManagedLabelKeys {
-				if mlk == k {
-					if _, ok := blueprint.GetLabels()[k]; !ok {
-						delete(targetLabels, k)
-					}
-				}
-			}
-		}
-
-		for k := range targetAnnos {
-			for _, mak := range laOpts.ManagedAnnotationKeys {
-				if mak == k {
-					if _, ok := blueprint.GetAnnotations()[k]; !ok {
-						delete(targetAnnos, k)
-					}
-				}
-			}
-		}
-
-		blueprint.SetAnnotations(targetAnnos)
-		blueprint.SetLabels(targetLabels)
+		mergeLabelsAndAnnotations(actual, blueprint, laOpts)
 
 		actualKey := client.ObjectKeyFromObject(actual)
 
@@ -239,6 +386,55 @@ func (s *Syncer) update(ctx context.Context, owner client.Object, actual client.
 	return false, actual, nil
 }
 
+// mergeLabelsAndAnnotations sets the labels and annotations of the blueprint to the result of merging the labels and annotations
+// of the actual object with the ones of the blueprint, taking into account the managed labels and annotations.
+func mergeLabelsAndAnnotations(actual client.Object, blueprint client.Object, laOpts LabelsAndAnnotationsSyncOptions) {
+	// we need to handle labels and annotations specially in case the cluster admin has modified them.
+	// if the current object in the cluster has the same annos/labels, they get overwritten with what's
+	// in the blueprint. If there are any managed labels/annos, they are deleted from the current object if not in the blueprint.
+	// Any additional labels/annos on the object are kept though.
+	targetLabels := map[string]string{}
+	targetAnnos := map[string]string{}
+
+	for k, v := range actual.GetAnnotations() {
+		targetAnnos[k] = v
+	}
+	for k, v := range actual.GetLabels() {
+		targetLabels[k] = v
+	}
+
+	for k, v := range blueprint.GetAnnotations() {
+		targetAnnos[k] = v
+	}
+	for k, v := range blueprint.GetLabels() {
+		targetLabels[k] = v
+	}
+
+	// now go through the labels and annos and remove all that are managed and not in the blueprint
+	for k := range targetLabels {
+		for _, mlk := range laOpts.ManagedLabelKeys {
+			if mlk == k {
+				if _, ok := blueprint.GetLabels()[k]; !ok {
+					delete(targetLabels, k)
+				}
+			}
+		}
+	}
+
+	for k := range targetAnnos {
+		for _, mak := range laOpts.ManagedAnnotationKeys {
+			if mak == k {
+				if _, ok := blueprint.GetAnnotations()[k]; !ok {
+					delete(targetAnnos, k)
+				}
+			}
+		}
+	}
+
+	blueprint.SetAnnotations(targetAnnos)
+	blueprint.SetLabels(targetLabels)
+}
+
 func isUpdateUsingDeleteCreate(kind string) bool {
 	// Routes are not able to update the host, so we just need to re-create them...
 	// ingresses and services have been identified to needs this, too, for reasons that I don't know..
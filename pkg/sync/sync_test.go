@@ -16,18 +16,23 @@ package sync
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/redhat-appstudio/remote-secret/pkg/infrastructure"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 var scheme = runtime.NewScheme()
@@ -293,3 +298,209 @@ func TestSyncKeepsAdditionalAnnosAndLabels(t *testing.T) {
 		assert.Equal(t, expectedValues, synced.Annotations, "Unexpected annotations on the synced object")
 	})
 }
+
+func TestApply(t *testing.T) {
+	blueprint := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "applied",
+			Namespace: "default",
+			Labels: map[string]string{
+				"a": "b",
+			},
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager: "someone-else",
+				},
+			},
+		},
+	}
+
+	var patchType types.PatchType
+	var patchOpts client.PatchOptions
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, cl client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			patchType = patch.Type()
+			patchOpts.ApplyOptions(opts)
+			assert.Empty(t, obj.GetManagedFields())
+			return nil
+		},
+	}).Build()
+
+	syncer := Syncer{client: cl}
+
+	obj, err := syncer.Apply(context.TODO(), nil, blueprint, "test-manager")
+	assert.NoError(t, err)
+
+	assert.Equal(t, types.ApplyPatchType, patchType)
+	assert.Equal(t, "test-manager", patchOpts.FieldManager)
+	assert.True(t, *patchOpts.Force)
+	assert.Equal(t, "applied", obj.GetName())
+	assert.Equal(t, "b", obj.GetLabels()["a"])
+	assert.Equal(t, "Secret", obj.GetObjectKind().GroupVersionKind().Kind)
+	// the blueprint must not be modified
+	assert.Len(t, blueprint.ManagedFields, 1)
+}
+
+func TestUpgradeManagedFields(t *testing.T) {
+	obj := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "sa",
+			Namespace:       "default",
+			ResourceVersion: "42",
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:    "manager",
+					Operation:  metav1.ManagedFieldsOperationUpdate,
+					APIVersion: "v1",
+					FieldsType: "FieldsV1",
+					FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{"f:a":{}}},"f:secrets":{"k:{\"name\":\"s\"}":{}}}`)},
+				},
+				{
+					Manager:    "someone-else",
+					Operation:  metav1.ManagedFieldsOperationUpdate,
+					APIVersion: "v1",
+					FieldsType: "FieldsV1",
+					FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{"f:b":{}}}}`)},
+				},
+			},
+		},
+	}
+
+	var patches [][]byte
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, cl client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			assert.Equal(t, types.JSONPatchType, patch.Type())
+			data, err := patch.Data(obj)
+			assert.NoError(t, err)
+			patches = append(patches, data)
+			return nil
+		},
+	}).Build()
+
+	syncer := Syncer{client: cl}
+
+	t.Run("transfers the ownership", func(t *testing.T) {
+		patches = nil
+		assert.NoError(t, syncer.UpgradeManagedFields(context.TODO(), obj, sets.New("manager"), "ssa", "secrets"))
+		assert.Len(t, patches, 1)
+
+		var patch []struct {
+			Path  string          `json:"path"`
+			Value json.RawMessage `json:"value"`
+		}
+		assert.NoError(t, json.Unmarshal(patches[0], &patch))
+		assert.Equal(t, "/metadata/managedFields", patch[0].Path)
+		assert.Equal(t, "/metadata/resourceVersion", patch[1].Path)
+		assert.JSONEq(t, `"42"`, string(patch[1].Value))
+
+		var entries []metav1.ManagedFieldsEntry
+		assert.NoError(t, json.Unmarshal(patch[0].Value, &entries))
+		assert.Len(t, entries, 3)
+		byManager := map[string]metav1.ManagedFieldsEntry{}
+		for _, e := range entries {
+			byManager[e.Manager] = e
+		}
+		assert.Equal(t, metav1.ManagedFieldsOperationApply, byManager["ssa"].Operation)
+		assert.JSONEq(t, `{"f:metadata":{"f:labels":{"f:a":{}}}}`, string(byManager["ssa"].FieldsV1.Raw))
+		// the kept fields stay with the original manager
+		assert.Equal(t, metav1.ManagedFieldsOperationUpdate, byManager["manager"].Operation)
+		assert.JSONEq(t, `{"f:secrets":{"k:{\"name\":\"s\"}":{}}}`, string(byManager["manager"].FieldsV1.Raw))
+		assert.JSONEq(t, `{"f:metadata":{"f:labels":{"f:b":{}}}}`, string(byManager["someone-else"].FieldsV1.Raw))
+	})
+
+	t.Run("does nothing without client-side changes", func(t *testing.T) {
+		patches = nil
+		assert.NoError(t, syncer.UpgradeManagedFields(context.TODO(), obj, sets.New("nobody"), "ssa"))
+		assert.Empty(t, patches)
+	})
+}
+
+func TestApplyRequiresName(t *testing.T) {
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	syncer := Syncer{client: cl}
+
+	_, err := syncer.Apply(context.TODO(), nil, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "applied-",
+			Namespace:    "default",
+		},
+	}, "test-manager")
+	assert.ErrorIs(t, err, blueprintWithoutNameError)
+}
+
+func TestPlan(t *testing.T) {
+	preexisting := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "preexisting",
+			Namespace: "default",
+			Labels: map[string]string{
+				"managed":   "label",
+				"unmanaged": "label",
+			},
+		},
+	}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(preexisting).Build()
+
+	syncer := Syncer{client: cl}
+
+	t.Run("create", func(t *testing.T) {
+		blueprint := &corev1.Secret{
+			TypeMeta: preexisting.TypeMeta,
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "new",
+				Namespace: "default",
+			},
+		}
+
+		actual, desired, err := syncer.Plan(context.TODO(), blueprint, cmp.Options{}, LabelsAndAnnotationsSyncOptions{})
+		assert.NoError(t, err)
+		assert.Nil(t, actual)
+		assert.Same(t, blueprint, desired)
+		assert.Error(t, cl.Get(context.TODO(), client.ObjectKeyFromObject(blueprint), &corev1.Secret{}))
+	})
+
+	t.Run("update", func(t *testing.T) {
+		blueprint := &corev1.Secret{
+			TypeMeta: preexisting.TypeMeta,
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "preexisting",
+				Namespace: "default",
+				Labels: map[string]string{
+					"new": "label",
+				},
+			},
+		}
+
+		actual, desired, err := syncer.Plan(context.TODO(), blueprint, secretIgnoredMetadata, LabelsAndAnnotationsSyncOptions{
+			ManagedLabelKeys: []string{"managed"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "preexisting", actual.GetName())
+		assert.Equal(t, map[string]string{"new": "label", "unmanaged": "label"}, desired.GetLabels())
+		// the blueprint must not be modified
+		assert.Equal(t, map[string]string{"new": "label"}, blueprint.Labels)
+
+		inCluster := &corev1.Secret{}
+		assert.NoError(t, cl.Get(context.TODO(), client.ObjectKeyFromObject(blueprint), inCluster))
+		assert.Equal(t, preexisting.Labels, inCluster.Labels)
+	})
+
+	t.Run("no change", func(t *testing.T) {
+		actual, desired, err := syncer.Plan(context.TODO(), preexisting.DeepCopy(), secretIgnoredMetadata, LabelsAndAnnotationsSyncOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, "preexisting", actual.GetName())
+		assert.Nil(t, desired)
+	})
+}
+
+var secretIgnoredMetadata = cmpopts.IgnoreFields(corev1.Secret{}, "TypeMeta", "ObjectMeta.ResourceVersion")
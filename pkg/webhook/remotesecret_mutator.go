@@ -18,40 +18,69 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/redhat-appstudio/remote-secret/pkg/metrics"
 	authv1 "k8s.io/api/authentication/v1"
 	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/redhat-appstudio/remote-secret/controllers/remotesecrets"
 	"github.com/redhat-appstudio/remote-secret/controllers/remotesecretstorage"
+	"github.com/redhat-appstudio/remote-secret/pkg/commaseparated"
 	"github.com/redhat-appstudio/remote-secret/pkg/logs"
+	"github.com/redhat-appstudio/remote-secret/pkg/quota"
+	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage"
 )
 
 var errorCopyNotAllowed = errors.New("user cannot copy the data of the specified remote secret")
+var errorImportNotAllowed = errors.New("user cannot import the data of the secret in the target")
+var errorFollowedSourceFollows = errors.New("the source remote secret follows another remote secret itself")
+var errorSourceKeysMissing = errors.New("not found in the data")
+var errorSecretImportNotAllowed = errors.New("user cannot import the data of the specified secret")
 
 var metricUploadDataOperationLabel = "webhook_data_upload"
 var metricCopyDataDataOperationLabel = "copy_data_from"
+var metricImportDataOperationLabel = "import_data"
+var metricFollowDataOperationLabel = "follow_data_from"
 
 // +kubebuilder:rbac:groups="authorization.k8s.io",resources=subjectaccessreviews,verbs=create
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;delete
 
 // WebhookMutator defines the contract between the RemoteSecretWebhook and the "thing" that
 // mutates the remote secret passed to its methods. This interface mainly exists to ease the testing
 // because it will only ever have one implementation in the production code - the RemoteSecretMutator.
 type WebhookMutator interface {
 	StoreUploadData(context.Context, *api.RemoteSecret) error
-	CopyDataFrom(context.Context, authv1.UserInfo, *api.RemoteSecret) error
+	// CheckDataFrom checks that the user can read the sources specified in the dataFrom of the new remote secret. The old remote secret
+	// is nil when the new one is being created.
+	CheckDataFrom(ctx context.Context, user authv1.UserInfo, old *api.RemoteSecret, new *api.RemoteSecret) error
+	// CopyDataFrom copies the data from the sources specified in the dataFrom of the remote secret, unless the remote secret follows
+	// them. The permissions to the sources must have been checked using CheckDataFrom before.
+	CopyDataFrom(context.Context, *api.RemoteSecret) error
+	// CheckDataImport checks that the user can read the secrets that the data of the new remote secret is imported from and records
+	// them in the remote secret for the controller. The old remote secret is nil when the new one is being created.
+	CheckDataImport(ctx context.Context, user authv1.UserInfo, old *api.RemoteSecret, new *api.RemoteSecret) error
 }
 
 type RemoteSecretMutator struct {
 	Client  client.Client
 	Storage remotesecretstorage.RemoteSecretStorage
+	// QuotaChecker checks the size of the stored data against the quota. If nil, the quota is not checked.
+	QuotaChecker *quota.Checker
 }
 
 var _ WebhookMutator = (*RemoteSecretMutator)(nil)
 
+// StoreUploadData stores the data from the data and stringData of the remote secret. If the remote secret has the partial update annotation
+// and already has some data, the data only updates the stored keys and the keys from the deleted keys annotation are deleted, just like with
+// the partial upload secrets.
 func (m *RemoteSecretMutator) StoreUploadData(ctx context.Context, rs *api.RemoteSecret) error {
+	// the pending data update is only ever set by this request, the users must not be able to forge it
+	delete(rs.Annotations, api.LastDataUpdateAnnotation)
+
 	binData := rs.UploadData
 
 	if len(rs.StringUploadData) > 0 {
@@ -63,100 +92,553 @@ func (m *RemoteSecretMutator) StoreUploadData(ctx context.Context, rs *api.Remot
 		}
 	}
 
-	if len(binData) > 0 {
-		auditLog := logs.AuditLog(ctx).WithValues("remoteSecret", client.ObjectKeyFromObject(rs))
-		auditLog.Info("webhook data upload initiated")
+	_, partialUpdate := rs.Annotations[api.RemoteSecretPartialUpdateAnnotation]
+	var deletedKeys []string
+	if partialUpdate {
+		deletedKeys = commaseparated.Value(rs.Annotations[api.RemoteSecretDeletedKeysAnnotation]).Values()
+	}
+
+	if len(binData) > 0 || len(deletedKeys) > 0 {
+		auditLog := logs.AuditLog(ctx).WithValues("remoteSecret", client.ObjectKeyFromObject(rs), "partialUpdate", partialUpdate)
 
-		err := m.Storage.Store(ctx, rs, &binData)
+		var current *remotesecretstorage.SecretData
+		if partialUpdate {
+			var err error
+			if current, err = m.currentData(ctx, rs); err != nil {
+				metrics.UploadRejectionsCounter.WithLabelValues(metricUploadDataOperationLabel, "storage_read_failed").Inc()
+				auditLog.Error(err, "webhook data partial update failed")
+				return err
+			}
+		}
+
+		var err error
+		stored := false
+		if current != nil {
+			err = m.storePartialUpdate(ctx, rs, *current, binData, deletedKeys)
+			stored = true
+		} else if len(binData) > 0 {
+			// there's nothing to partially update yet, so the data is uploaded in full
+			binData, err = m.storeData(ctx, rs, binData)
+			deletedKeys = nil
+			stored = true
+		}
 		if err != nil {
-			metrics.UploadRejectionsCounter.WithLabelValues(metricUploadDataOperationLabel, "storage_write_failed").Inc()
-			err = fmt.Errorf("storage error on data save: %w", err)
-			auditLog.Error(err, "webhook data upload failed")
 			return err
 		}
 
-		auditLog.Info("webhook data upload completed")
+		// the event is emitted by the controller once the remote secret with the pending data update is persisted
+		if stored {
+			if err := remotesecrets.SetPendingDataUpdate(rs, remotesecrets.NewDataUpdate(api.DataUpdateSourceWebhook, "", binData, deletedKeys, "", nil)); err != nil {
+				return err
+			}
+		}
 	}
 
 	// clean upload data
 	rs.UploadData = nil
 	rs.StringUploadData = nil
+	delete(rs.Annotations, api.ExpectedDataVersionAnnotation)
+	delete(rs.Annotations, api.RemoteSecretDeletedKeysAnnotation)
 
 	return nil
 }
 
-func (m *RemoteSecretMutator) CopyDataFrom(ctx context.Context, user authv1.UserInfo, rs *api.RemoteSecret) error {
-	if rs.DataFrom.Name == "" {
+// storeData validates the uploaded data and stores it as the whole data of the remote secret. The stored data with the defaults
+// applied is returned.
+func (m *RemoteSecretMutator) storeData(ctx context.Context, rs *api.RemoteSecret, binData map[string][]byte) (map[string][]byte, error) {
+	auditLog := logs.AuditLog(ctx).WithValues("remoteSecret", client.ObjectKeyFromObject(rs))
+
+	binData = rs.ApplySecretDataDefaults(binData)
+	if err := rs.ValidateSecretData(binData); err != nil {
+		metrics.UploadRejectionsCounter.WithLabelValues(metricUploadDataOperationLabel, "invalid_data").Inc()
+		auditLog.Info("webhook data upload not started because of invalid data")
+		return nil, fmt.Errorf("the uploaded data is not valid: %w", err)
+	}
+	if err := m.checkDataQuota(ctx, rs, binData); err != nil {
+		metrics.UploadRejectionsCounter.WithLabelValues(metricUploadDataOperationLabel, quotaRejectionReason(err)).Inc()
+		auditLog.Info("webhook data upload not started because of the quota")
+		return nil, err
+	}
+	if err := m.Storage.CheckDataSize(&binData); err != nil {
+		metrics.UploadRejectionsCounter.WithLabelValues(metricUploadDataOperationLabel, dataSizeRejectionReason(err)).Inc()
+		auditLog.Info("webhook data upload not started because the data is too large for the secret storage")
+		return nil, fmt.Errorf("the uploaded data cannot be stored: %w", err)
+	}
+
+	auditLog.Info("webhook data upload initiated")
+
+	var err error
+	if expectedVersion, versioned := rs.Annotations[api.ExpectedDataVersionAnnotation]; versioned {
+		_, err = m.Storage.CompareAndSwap(ctx, rs, &binData, expectedVersion)
+	} else {
+		err = m.Storage.Store(ctx, rs, &binData)
+	}
+	if err != nil {
+		metrics.UploadRejectionsCounter.WithLabelValues(metricUploadDataOperationLabel, remotesecretstorage.StorageWriteRejectionReason(err)).Inc()
+		err = fmt.Errorf("storage error on data save: %w", err)
+		auditLog.Error(err, "webhook data upload failed")
+		return nil, err
+	}
+
+	auditLog.Info("webhook data upload completed")
+	return binData, nil
+}
+
+// storePartialUpdate updates the keys of the current data of the remote secret with the uploaded data and deletes the deleted keys from it.
+// As with the partial upload secrets, only the values of the uploaded keys are validated.
+func (m *RemoteSecretMutator) storePartialUpdate(ctx context.Context, rs *api.RemoteSecret, current remotesecretstorage.SecretData, binData map[string][]byte, deletedKeys []string) error {
+	auditLog := logs.AuditLog(ctx).WithValues("remoteSecret", client.ObjectKeyFromObject(rs))
+
+	if err := rs.ValidateSecretDataValues(binData); err != nil {
+		metrics.UploadRejectionsCounter.WithLabelValues(metricUploadDataOperationLabel, "invalid_data").Inc()
+		auditLog.Info("webhook data partial update not started because of invalid data")
+		return fmt.Errorf("the uploaded data is not valid: %w", err)
+	}
+
+	// the same order as in the PartialUpdate of the storage
+	merged := remotesecretstorage.SecretData{}
+	for k, v := range current {
+		merged[k] = v
+	}
+	for k, v := range binData {
+		merged[k] = v
+	}
+	for _, k := range deletedKeys {
+		delete(merged, k)
+	}
+
+	if err := m.checkDataQuota(ctx, rs, merged); err != nil {
+		metrics.UploadRejectionsCounter.WithLabelValues(metricUploadDataOperationLabel, quotaRejectionReason(err)).Inc()
+		auditLog.Info("webhook data partial update not started because of the quota")
+		return err
+	}
+	if err := m.Storage.CheckDataSize(&merged); err != nil {
+		metrics.UploadRejectionsCounter.WithLabelValues(metricUploadDataOperationLabel, dataSizeRejectionReason(err)).Inc()
+		auditLog.Info("webhook data partial update not started because the data is too large for the secret storage")
+		return fmt.Errorf("the partial update cannot be stored: %w", err)
+	}
+
+	auditLog.Info("webhook data partial update initiated")
+
+	updates := remotesecretstorage.SecretData(binData)
+	var err error
+	if expectedVersion, versioned := rs.Annotations[api.ExpectedDataVersionAnnotation]; versioned {
+		err = m.Storage.PartialUpdateIfVersion(ctx, rs, &updates, deletedKeys, expectedVersion)
+	} else {
+		err = m.Storage.PartialUpdate(ctx, rs, &updates, deletedKeys)
+	}
+	if err != nil {
+		metrics.UploadRejectionsCounter.WithLabelValues(metricUploadDataOperationLabel, remotesecretstorage.StorageWriteRejectionReason(err)).Inc()
+		err = fmt.Errorf("storage error on data partial update: %w", err)
+		auditLog.Error(err, "webhook data partial update failed")
+		return err
+	}
+
+	auditLog.Info("webhook data partial update completed")
+	return nil
+}
+
+// currentData returns the data stored for the remote secret or nil if there's no data yet.
+func (m *RemoteSecretMutator) currentData(ctx context.Context, rs *api.RemoteSecret) (*remotesecretstorage.SecretData, error) {
+	data, err := m.Storage.Get(ctx, rs)
+	if errors.Is(err, secretstorage.NotFoundError) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the current data for the partial update: %w", err)
+	}
+	return data, nil
+}
+
+// checkDataQuota checks that storing the data in the remote secret doesn't exceed the quota.
+func (m *RemoteSecretMutator) checkDataQuota(ctx context.Context, rs *api.RemoteSecret, data map[string][]byte) error {
+	if m.QuotaChecker == nil {
+		return nil
+	}
+	if err := m.QuotaChecker.CheckDataSize(ctx, rs, data); err != nil {
+		return fmt.Errorf("the data cannot be stored: %w", err)
+	}
+	return nil
+}
+
+// quotaRejectionReason returns the reason of the rejection metric for the error returned from the quota check.
+func quotaRejectionReason(err error) string {
+	if errors.Is(err, quota.QuotaExceededError) {
+		return "quota_exceeded"
+	}
+	return "quota_check_failed"
+}
+
+// dataSizeRejectionReason returns the reason of the rejection metric for the error returned from the check of the data size
+// against the capabilities of the secret storage.
+func dataSizeRejectionReason(err error) string {
+	if errors.Is(err, secretstorage.DataTooLargeError) {
+		return "data_too_large"
+	}
+	return "data_size_check_failed"
+}
+
+func (m *RemoteSecretMutator) CheckDataFrom(ctx context.Context, user authv1.UserInfo, old *api.RemoteSecret, rs *api.RemoteSecret) error {
+	// the secrets to delete are only ever added by CopyDataFrom, so the value from the request is replaced by the one of the old object.
+	// The request can remove the annotation though, which is what the controller does once it deleted the secrets. The secrets that
+	// the controller didn't delete yet are only kept if the request doesn't remove them or if it adds new ones.
+	_, keep := rs.Annotations[api.DeleteImportedSecretsAnnotation]
+	delete(rs.Annotations, api.DeleteImportedSecretsAnnotation)
+	if old != nil && (keep || deletesAfterImport(rs)) {
+		if toDelete, ok := old.Annotations[api.DeleteImportedSecretsAnnotation]; ok {
+			if rs.Annotations == nil {
+				rs.Annotations = map[string]string{}
+			}
+			rs.Annotations[api.DeleteImportedSecretsAnnotation] = toDelete
+		}
+	}
+
+	sources := rs.DataSources()
+	if len(sources) == 0 {
 		return nil
 	}
 
-	sourceName := rs.DataFrom.Name
-	sourceNamespace := rs.DataFrom.Namespace
-	if sourceNamespace == "" {
-		sourceNamespace = rs.Namespace
+	if rs.DataFrom.Follow {
+		return m.checkFollow(ctx, user, old, rs)
+	}
+
+	for i := range sources {
+		var err error
+		if sources[i].IsSecret() {
+			err = m.checkSecretSource(ctx, user, &sources[i])
+		} else {
+			err = m.checkRemoteSecretSource(ctx, user, &sources[i])
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deletesAfterImport tells whether the remote secret copies the data from any secret that should be deleted after the import.
+func deletesAfterImport(rs *api.RemoteSecret) bool {
+	if rs.DataFrom.Follow {
+		return false
+	}
+	sources := rs.DataSources()
+	for i := range sources {
+		if sources[i].IsSecret() && sources[i].DeleteAfterImport {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *RemoteSecretMutator) CopyDataFrom(ctx context.Context, rs *api.RemoteSecret) error {
+	sources := rs.DataSources()
+	if len(sources) == 0 || rs.DataFrom.Follow {
+		return nil
 	}
 
-	if err := m.checkHasPermissions(ctx, user, sourceName, sourceNamespace); err != nil {
+	auditLog := logs.AuditLog(ctx).WithValues("target-remote-secret", client.ObjectKeyFromObject(rs))
+
+	// the data of the sources is merged in the order of their precedence. We copy the data, so that the merging and the defaults
+	// don't leak into the data returned by the storage.
+	copied := map[string][]byte{}
+	sourceKeys := make([]string, len(sources))
+	for i := range sources {
+		sourceKey := sources[i].Key()
+		sourceKeys[i] = sourceKey.String()
+
+		var data map[string][]byte
+		var err error
+		if sources[i].IsSecret() {
+			data, err = m.readSecretSource(ctx, rs, &sources[i])
+		} else {
+			data, err = m.readRemoteSecretSource(ctx, &sources[i])
+		}
+		if err != nil {
+			return err
+		}
+
+		if missing := sources[i].SelectData(copied, data); len(missing) > 0 {
+			metrics.UploadRejectionsCounter.WithLabelValues(metricCopyDataDataOperationLabel, "source_keys_missing").Inc()
+			auditLog.Info("the data of the source doesn't contain the selected keys", "source", sourceKey)
+			return fmt.Errorf("the keys %s of the source %s: %w", strings.Join(missing, ", "), sourceKey, errorSourceKeysMissing)
+		}
+	}
+
+	auditLog = auditLog.WithValues("sources", sourceKeys)
+
+	// the data is validated against the spec of the target remote secret, not the sources, because the target might
+	// put stricter requirements on the data than the sources.
+	copied = rs.ApplySecretDataDefaults(copied)
+	if err := rs.ValidateSecretData(copied); err != nil {
+		metrics.UploadRejectionsCounter.WithLabelValues(metricCopyDataDataOperationLabel, "invalid_data").Inc()
+		auditLog.Info("the data of the sources is not valid for the target remote secret")
+		return fmt.Errorf("the data of the sources %s is not valid for this remote secret: %w", strings.Join(sourceKeys, ", "), err)
+	}
+	if err := m.checkDataQuota(ctx, rs, copied); err != nil {
+		metrics.UploadRejectionsCounter.WithLabelValues(metricCopyDataDataOperationLabel, quotaRejectionReason(err)).Inc()
+		auditLog.Info("the data of the sources cannot be copied because of the quota")
+		return err
+	}
+	if err := m.Storage.CheckDataSize(&copied); err != nil {
+		metrics.UploadRejectionsCounter.WithLabelValues(metricCopyDataDataOperationLabel, dataSizeRejectionReason(err)).Inc()
+		auditLog.Info("the data of the sources cannot be copied because it is too large for the secret storage")
+		return fmt.Errorf("the data of the sources %s cannot be stored: %w", strings.Join(sourceKeys, ", "), err)
+	}
+	data := remotesecretstorage.SecretData(copied)
+
+	auditLog.Info("about to copy data from one remote secret to another")
+	if err := m.Storage.Store(ctx, rs, &data); err != nil {
+		metrics.UploadRejectionsCounter.WithLabelValues(metricUploadDataOperationLabel, remotesecretstorage.StorageWriteRejectionReason(err)).Inc()
+		auditLog.Error(err, "failed to copy data from one remote secret to another")
+		return fmt.Errorf("failed to store the data copied from the sources: %w", err)
+	}
+	auditLog.Info("successfully copied the data from the sources to target remote secret")
+	if err := remotesecrets.SetPendingDataUpdate(rs, remotesecrets.NewDataUpdate(api.DataUpdateSourceDataFrom, strings.Join(sourceKeys, ", "), copied, nil, "", nil)); err != nil {
+		return err
+	}
+
+	// the secrets are deleted by the controller once the remote secret is persisted, so that they are not deleted if the admission
+	// of the remote secret fails after this point
+	if deletesAfterImport(rs) {
+		toDelete := commaseparated.Value(rs.Annotations[api.DeleteImportedSecretsAnnotation])
+		for i := range sources {
+			if sources[i].IsSecret() && sources[i].DeleteAfterImport {
+				toDelete.Add(sources[i].Key().String())
+			}
+		}
+		rs.Annotations[api.DeleteImportedSecretsAnnotation] = toDelete.String()
+	}
+
+	rs.DataFrom = api.RemoteSecretDataFrom{}
+
+	return nil
+}
+
+// checkRemoteSecretSource checks that the user can read the source remote secret.
+func (m *RemoteSecretMutator) checkRemoteSecretSource(ctx context.Context, user authv1.UserInfo, ds *api.RemoteSecretDataSource) error {
+	if err := m.checkHasPermissions(ctx, user, ds.Name, ds.Namespace); err != nil {
 		if errors.Is(err, errorCopyNotAllowed) {
 			metrics.UploadRejectionsCounter.WithLabelValues(metricCopyDataDataOperationLabel, "source_permissions_insufficient").Inc()
 			return fmt.Errorf("%w", err)
 		}
 		metrics.UploadRejectionsCounter.WithLabelValues(metricCopyDataDataOperationLabel, "permissions_check_failed").Inc()
-		return fmt.Errorf("failed to check the permissions of remote secret %s in namespace %s for user %s: %w", sourceName, sourceNamespace, user.Username, err)
+		return fmt.Errorf("failed to check the permissions of remote secret %s in namespace %s for user %s: %w", ds.Name, ds.Namespace, user.Username, err)
 	}
+	return nil
+}
 
+// readRemoteSecretSource reads the data of the source remote secret.
+func (m *RemoteSecretMutator) readRemoteSecretSource(ctx context.Context, ds *api.RemoteSecretDataSource) (map[string][]byte, error) {
 	source := &api.RemoteSecret{}
-	if err := m.Client.Get(ctx, client.ObjectKey{Name: sourceName, Namespace: sourceNamespace}, source); err != nil {
+	if err := m.Client.Get(ctx, ds.Key(), source); err != nil {
 		metrics.UploadRejectionsCounter.WithLabelValues(metricUploadDataOperationLabel, "source_not_found").Inc()
-		return fmt.Errorf("failed to get the source remote secret for copying the data: %w", err)
+		return nil, fmt.Errorf("failed to get the source remote secret for copying the data: %w", err)
 	}
 
 	data, err := m.Storage.Get(ctx, source)
 	if err != nil {
 		metrics.UploadRejectionsCounter.WithLabelValues(metricUploadDataOperationLabel, "storage_read_failed").Inc()
-		return fmt.Errorf("failed to obtain the data of the source remote secret when copying the data: %w", err)
+		return nil, fmt.Errorf("failed to obtain the data of the source remote secret when copying the data: %w", err)
 	}
+	return *data, nil
+}
 
-	auditLog := logs.AuditLog(ctx).WithValues("source-remote-secret", client.ObjectKeyFromObject(source), "target-remote-secret", client.ObjectKeyFromObject(rs))
-	auditLog.Info("about to copy data from one remote secret to another")
-	if err := m.Storage.Store(ctx, rs, data); err != nil {
-		metrics.UploadRejectionsCounter.WithLabelValues(metricUploadDataOperationLabel, "storage_write_failed").Inc()
-		auditLog.Error(err, "failed to copy data from one remote secret to another")
-		return fmt.Errorf("failed to store the data copied from the source remote secret: %w", err)
+// checkSecretSource checks that the user can read the source secret and, if it is to be deleted after the import, also delete it.
+func (m *RemoteSecretMutator) checkSecretSource(ctx context.Context, user authv1.UserInfo, ds *api.RemoteSecretDataSource) error {
+	verbs := []string{"get"}
+	if ds.DeleteAfterImport {
+		verbs = append(verbs, "delete")
+	}
+	for _, verb := range verbs {
+		attrs := &authzv1.ResourceAttributes{
+			Name:      ds.Name,
+			Namespace: ds.Namespace,
+			Verb:      verb,
+			Version:   "v1",
+			Resource:  "secrets",
+		}
+		if err := checkAccess(ctx, m.Client, user, attrs, errorSecretImportNotAllowed); err != nil {
+			if errors.Is(err, errorSecretImportNotAllowed) {
+				metrics.UploadRejectionsCounter.WithLabelValues(metricCopyDataDataOperationLabel, "secret_permissions_insufficient").Inc()
+				return fmt.Errorf("cannot %s secret %s in namespace %s: %w", verb, ds.Name, ds.Namespace, err)
+			}
+			metrics.UploadRejectionsCounter.WithLabelValues(metricCopyDataDataOperationLabel, "permissions_check_failed").Inc()
+			return fmt.Errorf("failed to check the permissions of secret %s in namespace %s for user %s: %w", ds.Name, ds.Namespace, user.Username, err)
+		}
 	}
-	auditLog.Info("successfully copied the data from source remote secret to target remote secret")
+	return nil
+}
 
-	rs.DataFrom = api.RemoteSecretDataFrom{}
+// readSecretSource reads the data of the source secret. The type of the secret must match the type of the remote secret.
+func (m *RemoteSecretMutator) readSecretSource(ctx context.Context, rs *api.RemoteSecret, ds *api.RemoteSecretDataSource) (map[string][]byte, error) {
+	secret := &corev1.Secret{}
+	if err := m.Client.Get(ctx, ds.Key(), secret); err != nil {
+		metrics.UploadRejectionsCounter.WithLabelValues(metricCopyDataDataOperationLabel, "secret_not_found").Inc()
+		return nil, fmt.Errorf("failed to get the secret to import the data from: %w", err)
+	}
+
+	if err := rs.ValidateSecretType(secret.Type); err != nil {
+		metrics.UploadRejectionsCounter.WithLabelValues(metricCopyDataDataOperationLabel, "secret_type_mismatch").Inc()
+		return nil, fmt.Errorf("the data of the secret %s cannot be imported: %w", ds.Key(), err)
+	}
+
+	logs.AuditLog(ctx).Info("importing the data of a secret", "source-secret", ds.Key(), "target-remote-secret", client.ObjectKeyFromObject(rs))
+
+	return secret.Data, nil
+}
+
+// CheckDataImport makes sure that the user can read the secrets in the targets in the local cluster, if the data of the remote
+// secret is to be imported from them. Otherwise, the user could use the remote secret to read any secret in the cluster.
+// The targets in the remote clusters are accessed using the credentials provided by the user and therefore are not checked.
+// The checked secrets are recorded in the ImportAuthorizedSecretsAnnotation and the controller only imports the data from those.
+// The annotation is always computed from the old remote secret and the checks, never taken from the request. Whether the data
+// still needs to be imported is decided by the storage, because the status of the remote secret is under the control of the user.
+// This doesn't mutate the remote secret but it needs the information about the user which the validator doesn't have.
+func (m *RemoteSecretMutator) CheckDataImport(ctx context.Context, user authv1.UserInfo, old *api.RemoteSecret, rs *api.RemoteSecret) error {
+	authorized := commaseparated.Empty()
+	if old != nil {
+		authorized = commaseparated.Value(old.Annotations[api.ImportAuthorizedSecretsAnnotation])
+	}
+	delete(rs.Annotations, api.ImportAuthorizedSecretsAnnotation)
+
+	if !rs.Spec.Adoption.ImportData {
+		return nil
+	}
+
+	if _, err := m.Storage.Get(ctx, rs); err == nil {
+		// the data is not imported if there already is some
+		setImportAuthorizedSecrets(rs, authorized)
+		return nil
+	} else if !errors.Is(err, secretstorage.NotFoundError) {
+		metrics.UploadRejectionsCounter.WithLabelValues(metricImportDataOperationLabel, "storage_read_failed").Inc()
+		return fmt.Errorf("failed to find out whether the data of the remote secret needs to be imported: %w", err)
+	}
+
+	for _, t := range rs.Spec.Targets {
+		if t.ApiUrl != "" {
+			continue
+		}
+
+		name := rs.Spec.Secret.Name
+		if t.Secret != nil && t.Secret.Name != "" {
+			name = t.Secret.Name
+		}
+		if name == "" {
+			// a secret with a generated name cannot be adopted
+			continue
+		}
+
+		key := client.ObjectKey{Name: name, Namespace: t.Namespace}.String()
+		if authorized.Contains(key) {
+			continue
+		}
+
+		attrs := &authzv1.ResourceAttributes{
+			Name:      name,
+			Namespace: t.Namespace,
+			Verb:      "get",
+			Version:   "v1",
+			Resource:  "secrets",
+		}
+		if err := checkAccess(ctx, m.Client, user, attrs, errorImportNotAllowed); err != nil {
+			if errors.Is(err, errorImportNotAllowed) {
+				metrics.UploadRejectionsCounter.WithLabelValues(metricImportDataOperationLabel, "target_permissions_insufficient").Inc()
+				return fmt.Errorf("secret %s in namespace %s: %w", name, t.Namespace, err)
+			}
+			metrics.UploadRejectionsCounter.WithLabelValues(metricImportDataOperationLabel, "permissions_check_failed").Inc()
+			return fmt.Errorf("failed to check the permissions of secret %s in namespace %s for user %s: %w", name, t.Namespace, user.Username, err)
+		}
+		authorized.Add(key)
+	}
+
+	setImportAuthorizedSecrets(rs, authorized)
+	return nil
+}
+
+func setImportAuthorizedSecrets(rs *api.RemoteSecret, authorized *commaseparated.CommaSeparated) {
+	if authorized.Len() == 0 {
+		return
+	}
+	if rs.Annotations == nil {
+		rs.Annotations = map[string]string{}
+	}
+	rs.Annotations[api.ImportAuthorizedSecretsAnnotation] = authorized.String()
+}
+
+// checkFollow checks that the user can read the remote secrets that the new remote secret follows. This is only checked for the
+// sources that the remote secret starts following so that the followers can be updated by users without access to the sources.
+func (m *RemoteSecretMutator) checkFollow(ctx context.Context, user authv1.UserInfo, old *api.RemoteSecret, rs *api.RemoteSecret) error {
+	alreadyFollowed := map[client.ObjectKey]struct{}{}
+	if old != nil {
+		for _, key := range old.FollowedRemoteSecrets() {
+			alreadyFollowed[key] = struct{}{}
+		}
+	}
+
+	for _, sourceKey := range rs.FollowedRemoteSecrets() {
+		if _, ok := alreadyFollowed[sourceKey]; ok {
+			continue
+		}
+
+		if err := m.checkHasPermissions(ctx, user, sourceKey.Name, sourceKey.Namespace); err != nil {
+			if errors.Is(err, errorCopyNotAllowed) {
+				metrics.UploadRejectionsCounter.WithLabelValues(metricFollowDataOperationLabel, "source_permissions_insufficient").Inc()
+				return fmt.Errorf("%w", err)
+			}
+			metrics.UploadRejectionsCounter.WithLabelValues(metricFollowDataOperationLabel, "permissions_check_failed").Inc()
+			return fmt.Errorf("failed to check the permissions of remote secret %s in namespace %s for user %s: %w", sourceKey.Name, sourceKey.Namespace, user.Username, err)
+		}
+
+		// the source doesn't have to exist yet, the remote secret just waits for its data in that case
+		source := &api.RemoteSecret{}
+		if err := m.Client.Get(ctx, sourceKey, source); client.IgnoreNotFound(err) != nil {
+			metrics.UploadRejectionsCounter.WithLabelValues(metricFollowDataOperationLabel, "source_read_failed").Inc()
+			return fmt.Errorf("failed to get the source remote secret %s to follow: %w", sourceKey, err)
+		}
+		if len(source.FollowedRemoteSecrets()) > 0 {
+			metrics.UploadRejectionsCounter.WithLabelValues(metricFollowDataOperationLabel, "source_follows").Inc()
+			return fmt.Errorf("cannot follow the remote secret %s: %w", sourceKey, errorFollowedSourceFollows)
+		}
+
+		logs.AuditLog(ctx).Info("the remote secret follows the data of another remote secret", "source-remote-secret", sourceKey, "target-remote-secret", client.ObjectKeyFromObject(rs))
+	}
 
 	return nil
 }
 
 func (m *RemoteSecretMutator) checkHasPermissions(ctx context.Context, user authv1.UserInfo, sourceName, sourceNamespace string) error {
+	return checkAccess(ctx, m.Client, user, &authzv1.ResourceAttributes{
+		Name:      sourceName,
+		Namespace: sourceNamespace,
+		Verb:      "get",
+		Group:     api.GroupVersion.Group,
+		Version:   api.GroupVersion.Version,
+		Resource:  "remotesecrets",
+	}, errorCopyNotAllowed)
+}
+
+// checkAccess creates a subject access review for the provided user and resource attributes and returns the notAllowedErr
+// if the access is not allowed.
+func checkAccess(ctx context.Context, cl client.Client, user authv1.UserInfo, attrs *authzv1.ResourceAttributes, notAllowedErr error) error {
 	sar := &authzv1.SubjectAccessReview{
 		Spec: authzv1.SubjectAccessReviewSpec{
-			ResourceAttributes: &authzv1.ResourceAttributes{
-				Name:      sourceName,
-				Namespace: sourceNamespace,
-				Verb:      "get",
-				Group:     api.GroupVersion.Group,
-				Version:   api.GroupVersion.Version,
-				Resource:  "remotesecrets",
-			},
-			UID:    user.UID,
-			User:   user.Username,
-			Groups: user.Groups,
+			ResourceAttributes: attrs,
+			UID:                user.UID,
+			User:               user.Username,
+			Groups:             user.Groups,
 		},
 	}
 
-	if err := m.Client.Create(ctx, sar); err != nil {
-		return fmt.Errorf("failed to create a subject access review to check if the user can copy data of remote secret: %w", err)
+	if err := cl.Create(ctx, sar); err != nil {
+		return fmt.Errorf("failed to create a subject access review to check if the user can %s %s: %w", attrs.Verb, attrs.Resource, err)
 	}
 
 	if !sar.Status.Allowed {
 		if sar.Status.Reason != "" {
-			return fmt.Errorf("%w: %s", errorCopyNotAllowed, sar.Status.Reason)
+			return fmt.Errorf("%w: %s", notAllowedErr, sar.Status.Reason)
 		} else {
-			return errorCopyNotAllowed
+			return notAllowedErr
 		}
 	}
 
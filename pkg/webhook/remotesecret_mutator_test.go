@@ -16,13 +16,26 @@ package webhook
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	authv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/redhat-appstudio/remote-secret/controllers/remotesecrets"
 	"github.com/redhat-appstudio/remote-secret/controllers/remotesecretstorage"
+	"github.com/redhat-appstudio/remote-secret/pkg/quota"
+	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage"
 	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage/memorystorage"
 )
 
@@ -58,6 +71,7 @@ func TestStoreUploadData(t *testing.T) {
 			}
 
 			assert.NoError(t, m.StoreUploadData(context.TODO(), rs))
+			assert.Contains(t, rs.Annotations, api.LastDataUpdateAnnotation)
 
 			data, err := storage.Get(context.TODO(), rs)
 			assert.NoError(t, err)
@@ -66,10 +80,791 @@ func TestStoreUploadData(t *testing.T) {
 			assert.Equal(t, []byte("b"), (*data)["a"])
 			assert.Nil(t, rs.UploadData)
 			assert.Nil(t, rs.StringUploadData)
+
+			update, err := remotesecrets.PendingDataUpdate(rs)
+			assert.NoError(t, err)
+			assert.Equal(t, api.DataUpdateSourceWebhook, update.Source)
+			assert.Equal(t, api.DataUpdateResultSucceeded, update.Result)
+			assert.Equal(t, []string{"a"}, update.Keys)
 		})
 	}
 }
 
+func TestStoreUploadDataDropsForgedUpdate(t *testing.T) {
+	storage := remotesecretstorage.NewJSONSerializingRemoteSecretStorage(&memorystorage.MemoryStorage{})
+	m := RemoteSecretMutator{Storage: storage}
+
+	forged, err := json.Marshal(remotesecrets.NewDataUpdate(api.DataUpdateSourceDataFrom, "ns/other", map[string][]byte{"a": nil}, nil, "", nil))
+	assert.NoError(t, err)
+
+	rs := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "rs",
+			Namespace:   "ns",
+			Annotations: map[string]string{api.LastDataUpdateAnnotation: string(forged)},
+		},
+	}
+
+	t.Run("without upload", func(t *testing.T) {
+		assert.NoError(t, m.StoreUploadData(context.TODO(), rs))
+		assert.NotContains(t, rs.Annotations, api.LastDataUpdateAnnotation)
+	})
+
+	t.Run("with upload", func(t *testing.T) {
+		rs.Annotations[api.LastDataUpdateAnnotation] = string(forged)
+		rs.StringUploadData = map[string]string{"b": "c"}
+		assert.NoError(t, m.StoreUploadData(context.TODO(), rs))
+
+		update, err := remotesecrets.PendingDataUpdate(rs)
+		assert.NoError(t, err)
+		assert.Equal(t, api.DataUpdateSourceWebhook, update.Source)
+		assert.Equal(t, []string{"b"}, update.Keys)
+	})
+}
+
+func TestStoreUploadDataWithKeyRules(t *testing.T) {
+	rs := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rs",
+			Namespace: "ns",
+		},
+		Spec: api.RemoteSecretSpec{
+			Secret: api.LinkableSecretSpec{
+				RequiredKeys: []api.SecretKey{
+					{Name: "url", Format: api.SecretKeyFormatUrl},
+					{Name: "config", DefaultValue: ptr.To("{}"), Format: api.SecretKeyFormatJson},
+				},
+			},
+		},
+	}
+
+	t.Run("applies defaults", func(t *testing.T) {
+		storage := remotesecretstorage.NewJSONSerializingRemoteSecretStorage(&memorystorage.MemoryStorage{})
+		m := RemoteSecretMutator{
+			Storage: storage,
+		}
+		rs := rs.DeepCopy()
+		rs.StringUploadData = map[string]string{"url": "https://example.com"}
+
+		assert.NoError(t, m.StoreUploadData(context.TODO(), rs))
+
+		data, err := storage.Get(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]byte{"url": []byte("https://example.com"), "config": []byte("{}")}, *data)
+	})
+
+	t.Run("rejects invalid data", func(t *testing.T) {
+		storage := remotesecretstorage.NewJSONSerializingRemoteSecretStorage(&memorystorage.MemoryStorage{})
+		m := RemoteSecretMutator{
+			Storage: storage,
+		}
+		rs := rs.DeepCopy()
+		rs.StringUploadData = map[string]string{"url": "example.com", "config": "{"}
+
+		err := m.StoreUploadData(context.TODO(), rs)
+		assert.ErrorContains(t, err, "key 'url' does not have the required format 'url': not an absolute URL")
+		assert.ErrorContains(t, err, "key 'config' does not have the required format 'json'")
+		assert.NotContains(t, rs.Annotations, api.LastDataUpdateAnnotation)
+
+		_, err = storage.Get(context.TODO(), rs)
+		assert.Error(t, err)
+	})
+}
+
+func TestStoreUploadDataWithQuota(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, api.AddToScheme(scheme))
+
+	other := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other",
+			Namespace: "ns",
+		},
+		Status: api.RemoteSecretStatus{
+			SecretStatus: api.SecretStatus{Size: 5},
+		},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(other).Build()
+
+	storage := remotesecretstorage.NewJSONSerializingRemoteSecretStorage(&memorystorage.MemoryStorage{})
+	m := RemoteSecretMutator{
+		Client:       cl,
+		Storage:      storage,
+		QuotaChecker: &quota.Checker{Client: cl, Quota: &api.RemoteSecretQuota{MaxStoredBytes: 10}},
+	}
+
+	rs := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rs",
+			Namespace: "ns",
+		},
+		StringUploadData: map[string]string{"a": "bcdef"},
+	}
+
+	err := m.StoreUploadData(context.TODO(), rs)
+	assert.ErrorIs(t, err, quota.QuotaExceededError)
+	assert.ErrorContains(t, err, "the data cannot be stored")
+	assert.NotContains(t, rs.Annotations, api.LastDataUpdateAnnotation)
+
+	_, err = storage.Get(context.TODO(), rs)
+	assert.Error(t, err)
+
+	rs.StringUploadData = map[string]string{"a": "bcd"}
+	assert.NoError(t, m.StoreUploadData(context.TODO(), rs))
+}
+
+func TestStoreUploadDataTooLarge(t *testing.T) {
+	storage := remotesecretstorage.NewJSONSerializingRemoteSecretStorage(&memorystorage.MemoryStorage{MaxDataSize: 20})
+	m := RemoteSecretMutator{
+		Storage: storage,
+	}
+
+	rs := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rs",
+			Namespace: "ns",
+		},
+		StringUploadData: map[string]string{"a": "bcdefghijklmnopqrstuvwxyz"},
+	}
+
+	err := m.StoreUploadData(context.TODO(), rs)
+	assert.ErrorIs(t, err, secretstorage.DataTooLargeError)
+	assert.ErrorContains(t, err, "the uploaded data cannot be stored")
+	assert.NotContains(t, rs.Annotations, api.LastDataUpdateAnnotation)
+
+	_, err = storage.Get(context.TODO(), rs)
+	assert.Error(t, err)
+
+	rs.StringUploadData = map[string]string{"a": "b"}
+	assert.NoError(t, m.StoreUploadData(context.TODO(), rs))
+}
+
+func TestStoreUploadDataWithExpectedVersion(t *testing.T) {
+	storage := remotesecretstorage.NewJSONSerializingRemoteSecretStorage(&memorystorage.MemoryStorage{})
+	m := RemoteSecretMutator{
+		Storage: storage,
+	}
+
+	rs := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rs",
+			Namespace: "ns",
+			Annotations: map[string]string{
+				api.ExpectedDataVersionAnnotation: "",
+			},
+		},
+		StringUploadData: map[string]string{"a": "b"},
+	}
+
+	assert.NoError(t, m.StoreUploadData(context.TODO(), rs))
+	assert.NotContains(t, rs.Annotations, api.ExpectedDataVersionAnnotation)
+	_, version, err := storage.GetVersioned(context.TODO(), rs)
+	assert.NoError(t, err)
+
+	t.Run("fails on stale version", func(t *testing.T) {
+		rs.Annotations[api.ExpectedDataVersionAnnotation] = ""
+		rs.StringUploadData = map[string]string{"a": "c"}
+
+		err := m.StoreUploadData(context.TODO(), rs)
+		assert.ErrorIs(t, err, secretstorage.ConflictError)
+
+		data, err := storage.Get(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("b"), (*data)["a"])
+	})
+
+	t.Run("stores with current version", func(t *testing.T) {
+		rs.Annotations[api.ExpectedDataVersionAnnotation] = version
+		rs.StringUploadData = map[string]string{"a": "c"}
+
+		assert.NoError(t, m.StoreUploadData(context.TODO(), rs))
+
+		data, err := storage.Get(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("c"), (*data)["a"])
+	})
+}
+
+func TestStoreUploadDataPartialUpdate(t *testing.T) {
+	storage := remotesecretstorage.NewJSONSerializingRemoteSecretStorage(&memorystorage.MemoryStorage{})
+	m := RemoteSecretMutator{
+		Storage: storage,
+	}
+
+	rs := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rs",
+			Namespace: "ns",
+			Annotations: map[string]string{
+				api.RemoteSecretPartialUpdateAnnotation: "true",
+			},
+		},
+		Spec: api.RemoteSecretSpec{
+			Secret: api.LinkableSecretSpec{RequiredKeys: []api.SecretKey{{Name: "a"}}},
+		},
+	}
+
+	t.Run("uploads the whole data when there is none yet", func(t *testing.T) {
+		rs.StringUploadData = map[string]string{"b": "c"}
+		assert.Error(t, m.StoreUploadData(context.TODO(), rs), "the required keys are validated")
+
+		rs.StringUploadData = map[string]string{"a": "b", "b": "c"}
+		assert.NoError(t, m.StoreUploadData(context.TODO(), rs))
+		assert.Contains(t, rs.Annotations, api.LastDataUpdateAnnotation)
+
+		data, err := storage.Get(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Len(t, *data, 2)
+	})
+
+	t.Run("updates the keys", func(t *testing.T) {
+		rs.StringUploadData = map[string]string{"c": "d"}
+		assert.NoError(t, m.StoreUploadData(context.TODO(), rs))
+		assert.Contains(t, rs.Annotations, api.LastDataUpdateAnnotation)
+
+		data, err := storage.Get(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Len(t, *data, 3)
+		assert.Equal(t, []byte("b"), (*data)["a"])
+		assert.Equal(t, []byte("d"), (*data)["c"])
+		assert.Nil(t, rs.StringUploadData)
+	})
+
+	t.Run("deletes the keys", func(t *testing.T) {
+		rs.Annotations[api.RemoteSecretDeletedKeysAnnotation] = "b, c"
+		rs.StringUploadData = map[string]string{"c": "e", "d": "f"}
+		assert.NoError(t, m.StoreUploadData(context.TODO(), rs))
+		assert.Contains(t, rs.Annotations, api.LastDataUpdateAnnotation)
+
+		data, err := storage.Get(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Len(t, *data, 2)
+		assert.Equal(t, []byte("b"), (*data)["a"])
+		assert.Equal(t, []byte("f"), (*data)["d"])
+		assert.NotContains(t, rs.Annotations, api.RemoteSecretDeletedKeysAnnotation)
+		assert.Contains(t, rs.Annotations, api.RemoteSecretPartialUpdateAnnotation)
+
+		update, err := remotesecrets.PendingDataUpdate(rs)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"c", "d"}, update.Keys)
+		assert.Equal(t, []string{"b", "c"}, update.DeletedKeys)
+	})
+
+	t.Run("deletes the keys without data", func(t *testing.T) {
+		rs.Annotations[api.RemoteSecretDeletedKeysAnnotation] = "d"
+		assert.NoError(t, m.StoreUploadData(context.TODO(), rs))
+
+		data, err := storage.Get(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Len(t, *data, 1)
+	})
+
+	t.Run("ignores the deleted keys without the partial update", func(t *testing.T) {
+		other := &api.RemoteSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "other",
+				Namespace: "ns",
+				Annotations: map[string]string{
+					api.RemoteSecretDeletedKeysAnnotation: "a",
+				},
+			},
+			StringUploadData: map[string]string{"a": "b", "b": "c"},
+		}
+		assert.NoError(t, m.StoreUploadData(context.TODO(), other))
+
+		other.StringUploadData = map[string]string{"c": "d"}
+		assert.NoError(t, m.StoreUploadData(context.TODO(), other))
+
+		data, err := storage.Get(context.TODO(), other)
+		assert.NoError(t, err)
+		assert.Len(t, *data, 1)
+		assert.Equal(t, []byte("d"), (*data)["c"])
+	})
+
+	t.Run("fails on stale version", func(t *testing.T) {
+		rs.Annotations[api.ExpectedDataVersionAnnotation] = "0"
+		rs.StringUploadData = map[string]string{"e": "f"}
+
+		err := m.StoreUploadData(context.TODO(), rs)
+		assert.ErrorIs(t, err, secretstorage.ConflictError)
+
+		data, err := storage.Get(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.NotContains(t, *data, "e")
+	})
+}
+
 func TestStoreCopyDataFrom(t *testing.T) {
-	t.Skip("not testable until we use controller-runtime >= 0.15.x because we need to fake SubjectAccessReview using interceptors")
+	scheme := runtime.NewScheme()
+	assert.NoError(t, authzv1.AddToScheme(scheme))
+	assert.NoError(t, api.AddToScheme(scheme))
+
+	creds := &api.RemoteSecret{ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "ns"}}
+	ca := &api.RemoteSecret{ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "shared"}}
+	forbidden := &api.RemoteSecret{ObjectMeta: metav1.ObjectMeta{Name: "forbidden", Namespace: "ns"}}
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(creds, ca, forbidden).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				sar, ok := obj.(*authzv1.SubjectAccessReview)
+				if !ok {
+					return cl.Create(ctx, obj, opts...)
+				}
+				sar.Status.Allowed = sar.Spec.ResourceAttributes.Name != "forbidden"
+				return nil
+			},
+		}).
+		Build()
+
+	storage := remotesecretstorage.NewJSONSerializingRemoteSecretStorage(&memorystorage.MemoryStorage{})
+	assert.NoError(t, storage.Initialize(context.TODO()))
+	assert.NoError(t, storage.Store(context.TODO(), creds, &remotesecretstorage.SecretData{"username": []byte("user"), "password": []byte("pass"), "ca": []byte("old-ca")}))
+	assert.NoError(t, storage.Store(context.TODO(), ca, &remotesecretstorage.SecretData{"ca.crt": []byte("ca"), "ca.key": []byte("key")}))
+	assert.NoError(t, storage.Store(context.TODO(), forbidden, &remotesecretstorage.SecretData{"a": []byte("b")}))
+
+	m := RemoteSecretMutator{
+		Client:  cl,
+		Storage: storage,
+	}
+
+	newRs := func(name string) *api.RemoteSecret {
+		return &api.RemoteSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			DataFrom: api.RemoteSecretDataFrom{
+				Name: "creds",
+				Sources: []api.RemoteSecretDataSource{
+					{Name: "ca", Namespace: "shared", Keys: []api.RemoteSecretDataSourceKey{{Name: "ca.crt", As: "ca"}}},
+				},
+			},
+		}
+	}
+
+	t.Run("merges the sources", func(t *testing.T) {
+		rs := newRs("merged")
+		assert.NoError(t, m.CheckDataFrom(context.TODO(), authv1.UserInfo{Username: "user"}, nil, rs))
+		assert.NoError(t, m.CopyDataFrom(context.TODO(), rs))
+		assert.True(t, rs.DataFrom.IsEmpty())
+
+		data, err := storage.Get(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Equal(t, remotesecretstorage.SecretData{"username": []byte("user"), "password": []byte("pass"), "ca": []byte("ca")}, *data)
+
+		update, err := remotesecrets.PendingDataUpdate(rs)
+		assert.NoError(t, err)
+		assert.Equal(t, api.DataUpdateSourceDataFrom, update.Source)
+		assert.Equal(t, "ns/creds, shared/ca", update.SourceName)
+		assert.Equal(t, []string{"ca", "password", "username"}, update.Keys)
+	})
+
+	t.Run("selects the keys", func(t *testing.T) {
+		rs := newRs("selected")
+		rs.DataFrom.Name = ""
+		rs.DataFrom.Sources = append(rs.DataFrom.Sources, api.RemoteSecretDataSource{Name: "creds", Keys: []api.RemoteSecretDataSourceKey{{Name: "password"}}})
+		assert.NoError(t, m.CopyDataFrom(context.TODO(), rs))
+
+		data, err := storage.Get(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Equal(t, remotesecretstorage.SecretData{"password": []byte("pass"), "ca": []byte("ca")}, *data)
+	})
+
+	t.Run("fails on missing keys", func(t *testing.T) {
+		rs := newRs("missing")
+		rs.DataFrom.Sources[0].Keys[0].Name = "tls.crt"
+		assert.ErrorIs(t, m.CopyDataFrom(context.TODO(), rs), errorSourceKeysMissing)
+
+		_, err := storage.Get(context.TODO(), rs)
+		assert.ErrorIs(t, err, secretstorage.NotFoundError)
+	})
+
+	t.Run("checks the permissions of all sources", func(t *testing.T) {
+		rs := newRs("forbidden-copy")
+		rs.DataFrom.Sources = append(rs.DataFrom.Sources, api.RemoteSecretDataSource{Name: "forbidden"})
+		assert.ErrorIs(t, m.CheckDataFrom(context.TODO(), authv1.UserInfo{Username: "user"}, nil, rs), errorCopyNotAllowed)
+
+		_, err := storage.Get(context.TODO(), rs)
+		assert.ErrorIs(t, err, secretstorage.NotFoundError)
+	})
+}
+
+func TestStoreCopyDataFromSecret(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, authzv1.AddToScheme(scheme))
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	assert.NoError(t, api.AddToScheme(scheme))
+
+	secret := func(name string, secretType corev1.SecretType) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "legacy"},
+			Type:       secretType,
+			Data:       map[string][]byte{"username": []byte("user"), "password": []byte("pass")},
+		}
+	}
+
+	var reviewed []authzv1.ResourceAttributes
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(secret("creds", corev1.SecretTypeOpaque), secret("kept", corev1.SecretTypeOpaque), secret("basic", corev1.SecretTypeBasicAuth)).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				sar, ok := obj.(*authzv1.SubjectAccessReview)
+				if !ok {
+					return cl.Create(ctx, obj, opts...)
+				}
+				reviewed = append(reviewed, *sar.Spec.ResourceAttributes)
+				sar.Status.Allowed = sar.Spec.ResourceAttributes.Name != "kept" || sar.Spec.ResourceAttributes.Verb != "delete"
+				return nil
+			},
+		}).
+		Build()
+
+	storage := remotesecretstorage.NewJSONSerializingRemoteSecretStorage(&memorystorage.MemoryStorage{})
+	assert.NoError(t, storage.Initialize(context.TODO()))
+
+	m := RemoteSecretMutator{
+		Client:  cl,
+		Storage: storage,
+	}
+
+	newRs := func(name, secretName string, deleteAfterImport bool) *api.RemoteSecret {
+		return &api.RemoteSecret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+			DataFrom: api.RemoteSecretDataFrom{
+				Sources: []api.RemoteSecretDataSource{
+					{Kind: api.RemoteSecretDataSourceKindSecret, Name: secretName, Namespace: "legacy", DeleteAfterImport: deleteAfterImport},
+				},
+			},
+		}
+	}
+
+	t.Run("imports the data", func(t *testing.T) {
+		reviewed = nil
+		rs := newRs("imported", "kept", false)
+		assert.NoError(t, m.CheckDataFrom(context.TODO(), authv1.UserInfo{Username: "user"}, nil, rs))
+		assert.NoError(t, m.CopyDataFrom(context.TODO(), rs))
+		assert.Equal(t, []authzv1.ResourceAttributes{{
+			Namespace: "legacy",
+			Name:      "kept",
+			Verb:      "get",
+			Version:   "v1",
+			Resource:  "secrets",
+		}}, reviewed)
+
+		data, err := storage.Get(context.TODO(), rs)
+		assert.NoError(t, err)
+		assert.Equal(t, remotesecretstorage.SecretData{"username": []byte("user"), "password": []byte("pass")}, *data)
+		assert.NoError(t, cl.Get(context.TODO(), client.ObjectKey{Name: "kept", Namespace: "legacy"}, &corev1.Secret{}))
+	})
+
+	t.Run("marks the imported secret for deletion", func(t *testing.T) {
+		rs := newRs("deleted", "creds", true)
+		assert.NoError(t, m.CheckDataFrom(context.TODO(), authv1.UserInfo{Username: "user"}, nil, rs))
+		assert.NoError(t, m.CopyDataFrom(context.TODO(), rs))
+
+		_, err := storage.Get(context.TODO(), rs)
+		assert.NoError(t, err)
+		// the controller deletes the secret once the remote secret is persisted
+		assert.NoError(t, cl.Get(context.TODO(), client.ObjectKey{Name: "creds", Namespace: "legacy"}, &corev1.Secret{}))
+		assert.Equal(t, "legacy/creds", rs.Annotations[api.DeleteImportedSecretsAnnotation])
+	})
+
+	t.Run("ignores the secrets to delete from the request", func(t *testing.T) {
+		old := newRs("forged", "kept", false)
+		old.Annotations = map[string]string{api.DeleteImportedSecretsAnnotation: "legacy/pending"}
+		rs := newRs("forged", "kept", false)
+		rs.Annotations = map[string]string{api.DeleteImportedSecretsAnnotation: "legacy/basic"}
+
+		assert.NoError(t, m.CheckDataFrom(context.TODO(), authv1.UserInfo{Username: "user"}, old, rs))
+		assert.Equal(t, "legacy/pending", rs.Annotations[api.DeleteImportedSecretsAnnotation])
+
+		assert.NoError(t, m.CheckDataFrom(context.TODO(), authv1.UserInfo{Username: "user"}, nil, rs))
+		assert.NotContains(t, rs.Annotations, api.DeleteImportedSecretsAnnotation)
+	})
+
+	t.Run("lets the controller remove the secrets to delete", func(t *testing.T) {
+		old := newRs("removed", "kept", false)
+		old.DataFrom = api.RemoteSecretDataFrom{}
+		old.Annotations = map[string]string{api.DeleteImportedSecretsAnnotation: "legacy/pending"}
+		// the update of the controller after it deleted the secrets
+		rs := old.DeepCopy()
+		delete(rs.Annotations, api.DeleteImportedSecretsAnnotation)
+
+		assert.NoError(t, m.CheckDataFrom(context.TODO(), authv1.UserInfo{Username: "controller"}, old, rs))
+		assert.NoError(t, m.CopyDataFrom(context.TODO(), rs))
+		assert.NotContains(t, rs.Annotations, api.DeleteImportedSecretsAnnotation)
+	})
+
+	t.Run("keeps the pending secrets to delete when adding new ones", func(t *testing.T) {
+		old := newRs("added", "kept", false)
+		old.DataFrom = api.RemoteSecretDataFrom{}
+		old.Annotations = map[string]string{api.DeleteImportedSecretsAnnotation: "legacy/pending"}
+		rs := newRs("added", "creds", true)
+
+		assert.NoError(t, m.CheckDataFrom(context.TODO(), authv1.UserInfo{Username: "user"}, old, rs))
+		assert.NoError(t, m.CopyDataFrom(context.TODO(), rs))
+		assert.Equal(t, "legacy/pending,legacy/creds", rs.Annotations[api.DeleteImportedSecretsAnnotation])
+	})
+
+	t.Run("requires the permission to delete", func(t *testing.T) {
+		rs := newRs("not-deleted", "kept", true)
+		assert.ErrorIs(t, m.CheckDataFrom(context.TODO(), authv1.UserInfo{Username: "user"}, nil, rs), errorSecretImportNotAllowed)
+
+		_, err := storage.Get(context.TODO(), rs)
+		assert.ErrorIs(t, err, secretstorage.NotFoundError)
+		assert.NoError(t, cl.Get(context.TODO(), client.ObjectKey{Name: "kept", Namespace: "legacy"}, &corev1.Secret{}))
+	})
+
+	t.Run("checks the secret type", func(t *testing.T) {
+		rs := newRs("type-mismatch", "basic", false)
+		assert.Error(t, m.CopyDataFrom(context.TODO(), rs))
+
+		rs.Spec.Secret.Type = corev1.SecretTypeBasicAuth
+		assert.NoError(t, m.CopyDataFrom(context.TODO(), rs))
+	})
+
+	t.Run("validates the data", func(t *testing.T) {
+		rs := newRs("invalid", "kept", false)
+		rs.Spec.Secret.RequiredKeys = []api.SecretKey{{Name: "token"}}
+		assert.Error(t, m.CopyDataFrom(context.TODO(), rs))
+
+		_, err := storage.Get(context.TODO(), rs)
+		assert.ErrorIs(t, err, secretstorage.NotFoundError)
+	})
+}
+
+func TestCopyDataFromFollow(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, authzv1.AddToScheme(scheme))
+	assert.NoError(t, api.AddToScheme(scheme))
+
+	var reviewed []authzv1.ResourceAttributes
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&api.RemoteSecret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "source",
+					Namespace: "allowed",
+				},
+			},
+			&api.RemoteSecret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "follower",
+					Namespace: "allowed",
+				},
+				DataFrom: api.RemoteSecretDataFrom{
+					Name:   "source",
+					Follow: true,
+				},
+			},
+		).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				sar, ok := obj.(*authzv1.SubjectAccessReview)
+				if !ok {
+					return cl.Create(ctx, obj, opts...)
+				}
+				reviewed = append(reviewed, *sar.Spec.ResourceAttributes)
+				sar.Status.Allowed = sar.Spec.ResourceAttributes.Namespace == "allowed"
+				return nil
+			},
+		}).
+		Build()
+
+	storage := remotesecretstorage.NewJSONSerializingRemoteSecretStorage(&memorystorage.MemoryStorage{})
+	assert.NoError(t, storage.Initialize(context.TODO()))
+
+	m := RemoteSecretMutator{
+		Client:  cl,
+		Storage: storage,
+	}
+
+	rs := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rs",
+			Namespace: "ns",
+		},
+		DataFrom: api.RemoteSecretDataFrom{
+			Name:      "source",
+			Namespace: "allowed",
+			Follow:    true,
+		},
+	}
+
+	t.Run("checks the permissions to the source", func(t *testing.T) {
+		reviewed = nil
+		rs := rs.DeepCopy()
+		assert.NoError(t, m.CheckDataFrom(context.TODO(), authv1.UserInfo{Username: "user"}, nil, rs))
+		assert.Equal(t, []authzv1.ResourceAttributes{{
+			Namespace: "allowed",
+			Name:      "source",
+			Verb:      "get",
+			Group:     api.GroupVersion.Group,
+			Version:   api.GroupVersion.Version,
+			Resource:  "remotesecrets",
+		}}, reviewed)
+		// the data is not copied and the remote secret keeps following the source
+		assert.NoError(t, m.CopyDataFrom(context.TODO(), rs))
+		assert.Equal(t, "source", rs.DataFrom.Name)
+		_, err := storage.Get(context.TODO(), rs)
+		assert.ErrorIs(t, err, secretstorage.NotFoundError)
+	})
+
+	t.Run("fails when not allowed", func(t *testing.T) {
+		rs := rs.DeepCopy()
+		rs.DataFrom.Namespace = "forbidden"
+		assert.ErrorIs(t, m.CheckDataFrom(context.TODO(), authv1.UserInfo{Username: "user"}, nil, rs), errorCopyNotAllowed)
+	})
+
+	t.Run("allows non-existent source", func(t *testing.T) {
+		rs := rs.DeepCopy()
+		rs.DataFrom.Name = "non-existent"
+		assert.NoError(t, m.CheckDataFrom(context.TODO(), authv1.UserInfo{Username: "user"}, nil, rs))
+	})
+
+	t.Run("fails when the source follows", func(t *testing.T) {
+		rs := rs.DeepCopy()
+		rs.DataFrom.Name = "follower"
+		assert.ErrorIs(t, m.CheckDataFrom(context.TODO(), authv1.UserInfo{Username: "user"}, nil, rs), errorFollowedSourceFollows)
+	})
+
+	t.Run("doesn't check unchanged source", func(t *testing.T) {
+		reviewed = nil
+		old := rs.DeepCopy()
+		old.DataFrom.Namespace = ""
+		old.Namespace = "allowed"
+		rs := old.DeepCopy()
+		rs.DataFrom.Namespace = "allowed"
+		assert.NoError(t, m.CheckDataFrom(context.TODO(), authv1.UserInfo{Username: "user"}, old, rs))
+		assert.Empty(t, reviewed)
+	})
+}
+
+func TestCheckDataImport(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, authzv1.AddToScheme(scheme))
+
+	var reviewed []authzv1.ResourceAttributes
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				sar, ok := obj.(*authzv1.SubjectAccessReview)
+				if !ok {
+					return cl.Create(ctx, obj, opts...)
+				}
+				reviewed = append(reviewed, *sar.Spec.ResourceAttributes)
+				sar.Status.Allowed = sar.Spec.ResourceAttributes.Namespace == "allowed"
+				return nil
+			},
+		}).
+		Build()
+
+	storage := remotesecretstorage.NewJSONSerializingRemoteSecretStorage(&memorystorage.MemoryStorage{})
+	assert.NoError(t, storage.Initialize(context.TODO()))
+
+	m := RemoteSecretMutator{
+		Client:  cl,
+		Storage: storage,
+	}
+
+	rs := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rs",
+			Namespace: "ns",
+		},
+		Spec: api.RemoteSecretSpec{
+			Secret: api.LinkableSecretSpec{
+				Name: "secret",
+			},
+			Targets: []api.RemoteSecretTarget{
+				{
+					Namespace: "allowed",
+					Secret: &api.SecretOverride{
+						Name: "overridden",
+					},
+				},
+				{
+					Namespace: "forbidden",
+					ApiUrl:    "https://remote.cluster",
+				},
+			},
+			Adoption: api.SecretAdoption{
+				ImportData: true,
+			},
+		},
+	}
+
+	t.Run("checks the local targets", func(t *testing.T) {
+		reviewed = nil
+		rs := rs.DeepCopy()
+		assert.NoError(t, m.CheckDataImport(context.TODO(), authv1.UserInfo{Username: "user"}, nil, rs))
+		assert.Equal(t, []authzv1.ResourceAttributes{{
+			Namespace: "allowed",
+			Name:      "overridden",
+			Verb:      "get",
+			Version:   "v1",
+			Resource:  "secrets",
+		}}, reviewed)
+		assert.Equal(t, "allowed/overridden", rs.Annotations[api.ImportAuthorizedSecretsAnnotation])
+	})
+
+	t.Run("fails when not allowed", func(t *testing.T) {
+		rs := rs.DeepCopy()
+		rs.Spec.Targets = append(rs.Spec.Targets, api.RemoteSecretTarget{Namespace: "forbidden"})
+		err := m.CheckDataImport(context.TODO(), authv1.UserInfo{Username: "user"}, nil, rs)
+		assert.ErrorIs(t, err, errorImportNotAllowed)
+		assert.ErrorContains(t, err, "secret secret in namespace forbidden")
+	})
+
+	t.Run("doesn't check when not importing", func(t *testing.T) {
+		reviewed = nil
+		rs := rs.DeepCopy()
+		rs.Spec.Adoption.ImportData = false
+		rs.Spec.Targets = append(rs.Spec.Targets, api.RemoteSecretTarget{Namespace: "forbidden"})
+		assert.NoError(t, m.CheckDataImport(context.TODO(), authv1.UserInfo{Username: "user"}, nil, rs))
+		assert.Empty(t, reviewed)
+		assert.NotContains(t, rs.Annotations, api.ImportAuthorizedSecretsAnnotation)
+	})
+
+	t.Run("doesn't check the secrets authorized before", func(t *testing.T) {
+		reviewed = nil
+		old := rs.DeepCopy()
+		old.Spec.Targets = append(old.Spec.Targets, api.RemoteSecretTarget{Namespace: "forbidden"})
+		old.Annotations = map[string]string{api.ImportAuthorizedSecretsAnnotation: "allowed/overridden,forbidden/secret"}
+		rs := old.DeepCopy()
+		assert.NoError(t, m.CheckDataImport(context.TODO(), authv1.UserInfo{Username: "user"}, old, rs))
+		assert.Empty(t, reviewed)
+		assert.Equal(t, "allowed/overridden,forbidden/secret", rs.Annotations[api.ImportAuthorizedSecretsAnnotation])
+	})
+
+	t.Run("ignores the authorization from the request", func(t *testing.T) {
+		rs := rs.DeepCopy()
+		rs.Spec.Targets = append(rs.Spec.Targets, api.RemoteSecretTarget{Namespace: "forbidden"})
+		rs.Annotations = map[string]string{api.ImportAuthorizedSecretsAnnotation: "forbidden/secret"}
+		assert.ErrorIs(t, m.CheckDataImport(context.TODO(), authv1.UserInfo{Username: "user"}, nil, rs), errorImportNotAllowed)
+	})
+
+	t.Run("ignores the status from the request", func(t *testing.T) {
+		rs := rs.DeepCopy()
+		rs.Spec.Targets = append(rs.Spec.Targets, api.RemoteSecretTarget{Namespace: "forbidden"})
+		meta.SetStatusCondition(&rs.Status.Conditions, metav1.Condition{
+			Type:   string(api.RemoteSecretConditionTypeDataObtained),
+			Status: metav1.ConditionTrue,
+			Reason: string(api.RemoteSecretReasonDataFound),
+		})
+		assert.ErrorIs(t, m.CheckDataImport(context.TODO(), authv1.UserInfo{Username: "user"}, nil, rs), errorImportNotAllowed)
+	})
+
+	t.Run("doesn't check when the data is already stored", func(t *testing.T) {
+		reviewed = nil
+		rs := rs.DeepCopy()
+		rs.Name = "stored"
+		rs.Spec.Targets = append(rs.Spec.Targets, api.RemoteSecretTarget{Namespace: "forbidden"})
+		assert.NoError(t, storage.Store(context.TODO(), rs, &remotesecretstorage.SecretData{"a": []byte("b")}))
+		assert.NoError(t, m.CheckDataImport(context.TODO(), authv1.UserInfo{Username: "user"}, nil, rs))
+		assert.Empty(t, reviewed)
+		assert.NotContains(t, rs.Annotations, api.ImportAuthorizedSecretsAnnotation)
+	})
 }
@@ -18,20 +18,56 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
 
+	"github.com/redhat-appstudio/remote-secret/pkg/commaseparated"
+	"github.com/redhat-appstudio/remote-secret/pkg/externalsource"
 	"github.com/redhat-appstudio/remote-secret/pkg/metrics"
-
+	"github.com/redhat-appstudio/remote-secret/pkg/policy"
+	"github.com/redhat-appstudio/remote-secret/pkg/quota"
+	authv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/redhat-appstudio/remote-secret/pkg/config"
+	"github.com/redhat-appstudio/remote-secret/pkg/logs"
 )
 
-type RemoteSecretValidator struct{}
+type RemoteSecretValidator struct {
+	// Client is used to check the permissions of the requesting user using the subject access reviews.
+	Client client.Client
+	// Configuration is the configuration of the operator. If nil, the checks that can be switched off in the configuration
+	// are switched off.
+	Configuration *config.OperatorConfiguration
+	// PolicyChecker checks the remote secrets against the remote secret policies. If nil, the policies are not checked.
+	PolicyChecker *policy.Checker
+	// QuotaChecker checks the number of the remote secrets and their targets against the quota. If nil, the quota is not checked.
+	QuotaChecker *quota.Checker
+}
 
 var (
 	errTargetsNotUnique                            = errors.New("targets are not unique in the remote secret")
 	errDataFromSpecifiedWhenDataAlreadyPresent     = errors.New("dataFrom is not supported if there is data already present in the remote secret")
 	errOnlyOneOfDataFromOrUploadDataCanBeSpecified = errors.New("only one of dataFrom or data can be specified")
+	errDataUpdateOfSuspendedRemoteSecret           = errors.New("the data of a suspended remote secret cannot be changed")
+	errReplicationTargetsNotUnique                 = errors.New("replication targets are not unique in the remote secret")
+	errReplicationToSelf                           = errors.New("the remote secret cannot be replicated to itself")
+	errFollowSelf                                  = errors.New("the remote secret cannot follow itself")
+	errDataUploadToFollowingRemoteSecret           = errors.New("data cannot be uploaded to a remote secret following another remote secret")
+	errDataFromKeysNotUnique                       = errors.New("the keys taken from a source remote secret are not unique")
+	errDeleteAfterImportOfRemoteSecret             = errors.New("only the secrets can be deleted after importing their data")
+	errFollowSecret                                = errors.New("only the remote secrets can be followed, not the secrets")
+	errDataOfRemoteSecretWithExternalSource        = errors.New("data cannot be uploaded or copied to a remote secret with an external source")
+	errExternalSourceEmpty                         = errors.New("the external source must specify at least one secret to read")
+	errExternalSourceKeysNotUnique                 = errors.New("the secret keys of the external source are not unique")
+	errExternalSourceRefreshIntervalTooShort       = errors.New("the refresh interval of the external source is too short")
+	errTargetNotAllowed                            = errors.New("user cannot create secrets in the target namespace")
+	errClusterCredentialsNotAllowed                = errors.New("user cannot get the cluster credentials secret")
+	errClusterCredentialsUseRestricted             = errors.New("the cluster credentials secret does not allow its use by the remote secret")
 	metricValidateOperationLabel                   = "webhook_validate"
 )
 
@@ -42,31 +78,283 @@ type WebhookValidator interface {
 	ValidateCreate(context.Context, *api.RemoteSecret) error
 	ValidateUpdate(context.Context, *api.RemoteSecret, *api.RemoteSecret) error
 	ValidateDelete(context.Context, *api.RemoteSecret) error
+	// CheckTargetPermissions checks that the user is allowed to deploy to the targets of the new remote secret that are not present
+	// in the old one, including the use of their cluster credentials secrets. The old remote secret is nil when the new one is being created.
+	CheckTargetPermissions(ctx context.Context, user authv1.UserInfo, old *api.RemoteSecret, new *api.RemoteSecret) error
 }
 
 var _ WebhookValidator = (*RemoteSecretValidator)(nil)
 
-func (a *RemoteSecretValidator) ValidateCreate(_ context.Context, rs *api.RemoteSecret) error {
+func (a *RemoteSecretValidator) ValidateCreate(ctx context.Context, rs *api.RemoteSecret) error {
 	if err := validateUploadDataAndDataFrom(rs); err != nil {
 		return err
 	}
-	return validateUniqueTargets(rs)
+	if err := validateDataSources(rs); err != nil {
+		return err
+	}
+	if err := validateFollow(rs); err != nil {
+		return err
+	}
+	if err := validateExternalSource(rs); err != nil {
+		return err
+	}
+	if err := validateReplicationTargets(rs); err != nil {
+		return err
+	}
+	if err := validateSecretKeys(rs); err != nil {
+		return err
+	}
+	if err := validateUniqueTargets(rs); err != nil {
+		return err
+	}
+	if err := a.checkQuota(ctx, nil, rs); err != nil {
+		return err
+	}
+	return a.checkPolicies(ctx, nil, rs)
 }
 
-func (a *RemoteSecretValidator) ValidateUpdate(_ context.Context, _, new *api.RemoteSecret) error {
+func (a *RemoteSecretValidator) ValidateUpdate(ctx context.Context, old, new *api.RemoteSecret) error {
 	if err := validateUploadDataAndDataFrom(new); err != nil {
 		return err
 	}
+	if err := validateDataSources(new); err != nil {
+		return err
+	}
+	if err := validateFollow(new); err != nil {
+		return err
+	}
+	if err := validateExternalSource(new); err != nil {
+		return err
+	}
 	if err := validateDataFrom(new); err != nil {
 		return err
 	}
-	return validateUniqueTargets(new)
+	if err := validateNotSuspended(old, new); err != nil {
+		return err
+	}
+	if err := validateReplicationTargets(new); err != nil {
+		return err
+	}
+	if err := validateSecretKeys(new); err != nil {
+		return err
+	}
+	if err := validateUniqueTargets(new); err != nil {
+		return err
+	}
+	if err := a.checkQuota(ctx, old, new); err != nil {
+		return err
+	}
+	return a.checkPolicies(ctx, old, new)
 }
 
 func (a *RemoteSecretValidator) ValidateDelete(_ context.Context, _ *api.RemoteSecret) error {
 	return nil
 }
 
+// CheckTargetPermissions makes sure that the user can create secrets in the namespaces of the newly added targets and replication targets
+// in the local cluster.
+// Otherwise, anyone who can edit a remote secret could deploy its data to any namespace the remote secret auth service account can reach.
+// This mirrors how the permissions of the user are checked when copying the data using dataFrom.
+//
+// Additionally, the user must be able to read the cluster credentials secrets used by the newly added or changed targets, and the secrets
+// must not restrict their use to other remote secrets. Otherwise, the user could deploy anywhere the kubeconfig in the secret allows without
+// ever seeing it. Unlike the check of the target namespaces, this check cannot be switched off in the configuration.
+func (a *RemoteSecretValidator) CheckTargetPermissions(ctx context.Context, user authv1.UserInfo, old *api.RemoteSecret, new *api.RemoteSecret) error {
+	if err := a.checkClusterCredentialsPermissions(ctx, user, old, new); err != nil {
+		return err
+	}
+
+	if a.Configuration == nil || a.Configuration.TargetAuthorization == "" || a.Configuration.TargetAuthorization == config.TargetAuthorizationDisabled {
+		return nil
+	}
+
+	return a.checkLocalTargetPermissions(ctx, user, old, new)
+}
+
+// checkLocalTargetPermissions checks that the user can create secrets in the namespaces of the targets and replication targets in the local
+// cluster that were not present in the old remote secret. The replication creates the transfer secrets in the namespaces of the replicas, so
+// it requires the same permission as the deployment to the targets.
+func (a *RemoteSecretValidator) checkLocalTargetPermissions(ctx context.Context, user authv1.UserInfo, old *api.RemoteSecret, new *api.RemoteSecret) error {
+	authorized := map[string]bool{}
+	if old != nil {
+		for _, ns := range localTargetNamespaces(old) {
+			authorized[ns] = true
+		}
+	}
+
+	for _, ns := range localTargetNamespaces(new) {
+		if authorized[ns] {
+			continue
+		}
+
+		attrs := &authzv1.ResourceAttributes{
+			Namespace: ns,
+			Verb:      "create",
+			Version:   "v1",
+			Resource:  "secrets",
+		}
+		err := checkAccess(ctx, a.Client, user, attrs, errTargetNotAllowed)
+		if errors.Is(err, errTargetNotAllowed) && a.Configuration.TargetAuthorization == config.TargetAuthorizationAudit {
+			logs.AuditLog(ctx).Info("user not allowed to create secrets in the target namespace, allowed because of the audit mode",
+				"remoteSecret", client.ObjectKeyFromObject(new), "user", user.Username, "targetNamespace", ns)
+			err = nil
+		}
+		if err != nil {
+			if errors.Is(err, errTargetNotAllowed) {
+				metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "target_permissions_insufficient").Inc()
+				return fmt.Errorf("namespace %s: %w", ns, err)
+			}
+			metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "permissions_check_failed").Inc()
+			return fmt.Errorf("failed to check the permissions in target namespace %s for user %s: %w", ns, user.Username, err)
+		}
+
+		// each namespace needs to be checked only once
+		authorized[ns] = true
+	}
+
+	return nil
+}
+
+// localTargetNamespaces returns the namespaces of the targets and the replication targets of the remote secret in the local cluster.
+func localTargetNamespaces(rs *api.RemoteSecret) []string {
+	namespaces := make([]string, 0, len(rs.Spec.Targets)+len(rs.Spec.ReplicationTargets))
+	for _, t := range rs.Spec.Targets {
+		if t.ApiUrl == "" {
+			namespaces = append(namespaces, t.Namespace)
+		}
+	}
+	for _, t := range rs.Spec.ReplicationTargets {
+		if t.ApiUrl == "" {
+			namespaces = append(namespaces, t.Namespace)
+		}
+	}
+	return namespaces
+}
+
+// credentialsUse describes the use of the cluster credentials secret by a target or a replication target.
+type credentialsUse struct {
+	apiUrl, namespace, secretName string
+}
+
+// credentialsUses returns how the targets and the replication targets of the remote secret use the cluster credentials secrets.
+func credentialsUses(rs *api.RemoteSecret) []credentialsUse {
+	uses := make([]credentialsUse, 0, len(rs.Spec.Targets)+len(rs.Spec.ReplicationTargets))
+	for _, t := range rs.Spec.Targets {
+		uses = append(uses, credentialsUse{apiUrl: t.ApiUrl, namespace: t.Namespace, secretName: t.ClusterCredentialsSecret})
+	}
+	for _, t := range rs.Spec.ReplicationTargets {
+		uses = append(uses, credentialsUse{apiUrl: t.ApiUrl, namespace: t.Namespace, secretName: t.ClusterCredentialsSecret})
+	}
+	return uses
+}
+
+// checkClusterCredentialsPermissions checks that the user can get the cluster credentials secrets of the targets and the replication targets
+// that are new or that changed the cluster they point to. The targets that were already present in the old remote secret were checked when
+// they were added.
+func (a *RemoteSecretValidator) checkClusterCredentialsPermissions(ctx context.Context, user authv1.UserInfo, old *api.RemoteSecret, new *api.RemoteSecret) error {
+	existing := map[credentialsUse]bool{}
+	if old != nil {
+		for _, use := range credentialsUses(old) {
+			existing[use] = true
+		}
+	}
+
+	checked := map[string]bool{}
+	for _, use := range credentialsUses(new) {
+		if use.secretName == "" || checked[use.secretName] || existing[use] {
+			continue
+		}
+
+		attrs := &authzv1.ResourceAttributes{
+			Namespace: new.Namespace,
+			Verb:      "get",
+			Version:   "v1",
+			Resource:  "secrets",
+			Name:      use.secretName,
+		}
+		if err := checkAccess(ctx, a.Client, user, attrs, errClusterCredentialsNotAllowed); err != nil {
+			if errors.Is(err, errClusterCredentialsNotAllowed) {
+				metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "cluster_credentials_permissions_insufficient").Inc()
+				return fmt.Errorf("secret %s: %w", use.secretName, err)
+			}
+			metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "permissions_check_failed").Inc()
+			return fmt.Errorf("failed to check the permissions to get the cluster credentials secret %s for user %s: %w", use.secretName, user.Username, err)
+		}
+
+		if err := a.checkClusterCredentialsRestrictions(ctx, new, use.secretName); err != nil {
+			return err
+		}
+
+		// each secret needs to be checked only once
+		checked[use.secretName] = true
+	}
+
+	return nil
+}
+
+// checkClusterCredentialsRestrictions makes sure that the cluster credentials secret doesn't restrict its use to other remote secrets. A missing
+// secret is not an error, because the remote secret can be created before the secret. The restriction is enforced also during the deployment.
+func (a *RemoteSecretValidator) checkClusterCredentialsRestrictions(ctx context.Context, rs *api.RemoteSecret, secretName string) error {
+	secret := &corev1.Secret{}
+	if err := a.Client.Get(ctx, client.ObjectKey{Name: secretName, Namespace: rs.Namespace}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "permissions_check_failed").Inc()
+		return fmt.Errorf("failed to get the cluster credentials secret %s: %w", secretName, err)
+	}
+
+	if allowed, restricted := secret.Annotations[api.ClusterCredentialsAllowedRemoteSecretsAnnotation]; restricted && !commaseparated.Value(allowed).Contains(rs.Name) {
+		metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "cluster_credentials_use_restricted").Inc()
+		return fmt.Errorf("secret %s: %w", secretName, errClusterCredentialsUseRestricted)
+	}
+
+	return nil
+}
+
+// checkQuota checks that the number of the remote secrets in the namespace and the number of targets of the new remote secret
+// are within the quota. The old remote secret is nil when the new one is being created.
+func (a *RemoteSecretValidator) checkQuota(ctx context.Context, old, new *api.RemoteSecret) error {
+	if a.QuotaChecker == nil {
+		return nil
+	}
+
+	var err error
+	if old == nil {
+		err = a.QuotaChecker.CheckRemoteSecretCount(ctx, new.Namespace)
+	}
+	if err == nil {
+		err = a.QuotaChecker.CheckTargetCount(old, new)
+	}
+	if err != nil {
+		if errors.Is(err, quota.QuotaExceededError) {
+			metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "quota_exceeded").Inc()
+			return fmt.Errorf("the remote secret is not allowed: %w", err)
+		}
+		metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "quota_check_failed").Inc()
+		return fmt.Errorf("failed to check the quota of the remote secret %s: %w", new.Name, err)
+	}
+	return nil
+}
+
+// checkPolicies checks the new remote secret against the remote secret policies. Only the parts of the remote secret that changed
+// compared to the old one are checked, the rest is enforced during the deployment.
+func (a *RemoteSecretValidator) checkPolicies(ctx context.Context, old, new *api.RemoteSecret) error {
+	if a.PolicyChecker == nil {
+		return nil
+	}
+
+	if err := a.PolicyChecker.Check(ctx, old, new); err != nil {
+		if errors.Is(err, policy.PolicyViolationError) {
+			metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "policy_violation").Inc()
+			return fmt.Errorf("remote secret %s: %w", new.Name, err)
+		}
+		metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "policy_check_failed").Inc()
+		return fmt.Errorf("failed to check the remote secret %s against the policies: %w", new.Name, err)
+	}
+	return nil
+}
+
 func validateUniqueTargets(rs *api.RemoteSecret) error {
 	targets := make(map[api.TargetKey]struct{}, len(rs.Spec.Targets))
 	for _, t := range rs.Spec.Targets {
@@ -81,19 +369,164 @@ func validateUniqueTargets(rs *api.RemoteSecret) error {
 	return nil
 }
 
+// validateReplicationTargets checks that each replica is specified only once and that the remote secret is not its own replica.
+func validateReplicationTargets(rs *api.RemoteSecret) error {
+	type replicaKey struct {
+		apiUrl, namespace, name string
+	}
+
+	replicas := make(map[replicaKey]struct{}, len(rs.Spec.ReplicationTargets))
+	for _, t := range rs.Spec.ReplicationTargets {
+		name := t.RemoteSecretName
+		if name == "" {
+			name = rs.Name
+		}
+
+		if t.ApiUrl == "" && t.Namespace == rs.Namespace && name == rs.Name {
+			metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "replication_to_self").Inc()
+			return fmt.Errorf("%w: %s", errReplicationToSelf, rs.Name)
+		}
+
+		rk := replicaKey{apiUrl: t.ApiUrl, namespace: t.Namespace, name: name}
+		if _, present := replicas[rk]; present {
+			metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "unique_replication_targets_check_failed").Inc()
+			return fmt.Errorf("%w %s: %#v", errReplicationTargetsNotUnique, rs.Name, rs.Spec.ReplicationTargets)
+		}
+		replicas[rk] = struct{}{}
+	}
+	return nil
+}
+
+// validateSecretKeys checks that the rules specified for the keys of the secret are consistent, so that the problems
+// with the spec are reported at the time it is written and not only when some data is uploaded.
+func validateSecretKeys(rs *api.RemoteSecret) error {
+	for i := range rs.Spec.Secret.RequiredKeys {
+		if err := rs.Spec.Secret.RequiredKeys[i].ValidateRules(); err != nil {
+			metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "invalid_key_rules").Inc()
+			return fmt.Errorf("invalid spec of the remote secret %s: %w", rs.Name, err)
+		}
+	}
+	return nil
+}
+
 func validateDataFrom(rs *api.RemoteSecret) error {
-	var empty api.RemoteSecretDataFrom
-	if rs.DataFrom != empty && meta.IsStatusConditionTrue(rs.Status.Conditions, string(api.RemoteSecretConditionTypeDataObtained)) {
+	// a remote secret following another one replaces its data each time the source changes, so it doesn't matter it has data already
+	if !rs.DataFrom.IsEmpty() && !rs.DataFrom.Follow && meta.IsStatusConditionTrue(rs.Status.Conditions, string(api.RemoteSecretConditionTypeDataObtained)) {
 		metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "data_already_exists").Inc()
 		return errDataFromSpecifiedWhenDataAlreadyPresent
 	}
 	return nil
 }
 
-func validateUploadDataAndDataFrom(rs *api.RemoteSecret) error {
-	var emptyDataFrom api.RemoteSecretDataFrom
+// validateNotSuspended checks that there is no attempt to change the data of a suspended remote secret. This is only checked on updates,
+// because a newly created remote secret doesn't have any data that could be overwritten. A remote secret can keep following the same
+// source while suspended, because that doesn't change its data until it is resumed.
+func validateNotSuspended(old, rs *api.RemoteSecret) error {
+	dataFromChanged := !rs.DataFrom.IsEmpty() && (!rs.DataFrom.Follow || old == nil || !reflect.DeepEqual(rs.DataFrom, old.DataFrom))
+
+	if rs.Spec.Suspend && (len(rs.UploadData) > 0 || len(rs.StringUploadData) > 0 || dataFromChanged) {
+		metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "remote_secret_suspended").Inc()
+		return errDataUpdateOfSuspendedRemoteSecret
+	}
+	return nil
+}
+
+// validateDataSources checks that only the secrets are requested to be deleted after the import and that the keys taken from each
+// of the sources in the dataFrom end up under unique names. The sources can provide the same keys though, in which case the later
+// sources take precedence.
+func validateDataSources(rs *api.RemoteSecret) error {
+	for _, ds := range rs.DataFrom.Sources {
+		if ds.DeleteAfterImport && !ds.IsSecret() {
+			metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "delete_after_import_of_remote_secret").Inc()
+			return fmt.Errorf("%w: remote secret %s", errDeleteAfterImportOfRemoteSecret, ds.Name)
+		}
+		keys := make(map[string]struct{}, len(ds.Keys))
+		for _, k := range ds.Keys {
+			as := k.As
+			if as == "" {
+				as = k.Name
+			}
+			if _, present := keys[as]; present {
+				metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "data_from_keys_not_unique").Inc()
+				return fmt.Errorf("%w: key %s of the remote secret %s", errDataFromKeysNotUnique, as, ds.Name)
+			}
+			keys[as] = struct{}{}
+		}
+	}
+	return nil
+}
 
-	if rs.DataFrom != emptyDataFrom && len(rs.UploadData) > 0 {
+func validateFollow(rs *api.RemoteSecret) error {
+	if rs.DataFrom.Follow {
+		for _, ds := range rs.DataFrom.Sources {
+			if ds.IsSecret() {
+				metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "follow_secret").Inc()
+				return fmt.Errorf("%w: secret %s", errFollowSecret, ds.Name)
+			}
+		}
+	}
+
+	sources := rs.FollowedRemoteSecrets()
+	if len(sources) == 0 {
+		return nil
+	}
+	for _, source := range sources {
+		if source == client.ObjectKeyFromObject(rs) {
+			metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "follow_self").Inc()
+			return errFollowSelf
+		}
+	}
+	if len(rs.UploadData) > 0 || len(rs.StringUploadData) > 0 {
+		metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "data_upload_to_follower").Inc()
+		return errDataUploadToFollowingRemoteSecret
+	}
+	return nil
+}
+
+// validateExternalSource checks that the remote secret with an external source doesn't get its data from anywhere else and that
+// the external source can be read without escaping the key prefix configured in the operator.
+func validateExternalSource(rs *api.RemoteSecret) error {
+	es := rs.Spec.ExternalSource
+	if es == nil {
+		return nil
+	}
+	if !rs.DataFrom.IsEmpty() || len(rs.UploadData) > 0 || len(rs.StringUploadData) > 0 {
+		metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "data_with_external_source").Inc()
+		return errDataOfRemoteSecretWithExternalSource
+	}
+	if len(es.Data) == 0 && len(es.DataFrom) == 0 {
+		metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "invalid_external_source").Inc()
+		return errExternalSourceEmpty
+	}
+	if es.RefreshInterval != nil && es.RefreshInterval.Duration < externalsource.MinimumRefreshInterval {
+		metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "invalid_external_source").Inc()
+		return fmt.Errorf("%w: %s is shorter than %s", errExternalSourceRefreshIntervalTooShort, es.RefreshInterval.Duration, externalsource.MinimumRefreshInterval)
+	}
+
+	keys := make([]string, 0, len(es.Data)+len(es.DataFrom))
+	secretKeys := make(map[string]struct{}, len(es.Data))
+	for _, d := range es.Data {
+		if _, present := secretKeys[d.SecretKey]; present {
+			metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "invalid_external_source").Inc()
+			return fmt.Errorf("%w: %s", errExternalSourceKeysNotUnique, d.SecretKey)
+		}
+		secretKeys[d.SecretKey] = struct{}{}
+		keys = append(keys, d.RemoteRef.Key)
+	}
+	for _, ref := range es.DataFrom {
+		keys = append(keys, ref.Key)
+	}
+	for _, k := range keys {
+		if err := externalsource.ValidateKey(k); err != nil {
+			metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "invalid_external_source").Inc()
+			return fmt.Errorf("invalid external source of the remote secret %s: %w", rs.Name, err)
+		}
+	}
+	return nil
+}
+
+func validateUploadDataAndDataFrom(rs *api.RemoteSecret) error {
+	if !rs.DataFrom.IsEmpty() && len(rs.UploadData) > 0 {
 		metrics.UploadRejectionsCounter.WithLabelValues(metricValidateOperationLabel, "data_field_not_unique").Inc()
 		return errOnlyOneOfDataFromOrUploadDataCanBeSpecified
 	}
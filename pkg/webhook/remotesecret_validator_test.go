@@ -17,12 +17,24 @@ package webhook
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	authv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/redhat-appstudio/remote-secret/pkg/config"
+	"github.com/redhat-appstudio/remote-secret/pkg/policy"
+	"github.com/redhat-appstudio/remote-secret/pkg/quota"
 )
 
 func TestValidateCreate(t *testing.T) {
@@ -37,6 +49,12 @@ func TestValidateCreate(t *testing.T) {
 	testDataFrom(t, false, runner)
 
 	testUniqueTargets(t, runner)
+
+	testReplicationTargets(t, runner)
+
+	testSecretKeys(t, runner)
+
+	testExternalSource(t, runner)
 }
 
 func TestValidateUpdate(t *testing.T) {
@@ -51,6 +69,56 @@ func TestValidateUpdate(t *testing.T) {
 	testDataFrom(t, true, runner)
 
 	testUniqueTargets(t, runner)
+
+	testReplicationTargets(t, runner)
+
+	testSecretKeys(t, runner)
+
+	t.Run("suspended", func(t *testing.T) {
+		rs := &api.RemoteSecret{
+			Spec: api.RemoteSecretSpec{
+				Suspend: true,
+			},
+		}
+		assert.NoError(t, runner(rs))
+
+		t.Run("with UploadData", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.UploadData = map[string][]byte{
+				"a": []byte("b"),
+			}
+			assert.Error(t, runner(rs))
+		})
+
+		t.Run("with StringUploadData", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.StringUploadData = map[string]string{
+				"a": "b",
+			}
+			assert.Error(t, runner(rs))
+		})
+
+		t.Run("with DataFrom", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.DataFrom = api.RemoteSecretDataFrom{
+				Name: "somename",
+			}
+			assert.Error(t, runner(rs))
+		})
+
+		t.Run("with followed DataFrom", func(t *testing.T) {
+			old := rs.DeepCopy()
+			old.DataFrom = api.RemoteSecretDataFrom{
+				Name:   "somename",
+				Follow: true,
+			}
+			rs := old.DeepCopy()
+			assert.NoError(t, v.ValidateUpdate(context.TODO(), old, rs))
+
+			rs.DataFrom.Name = "othername"
+			assert.ErrorIs(t, v.ValidateUpdate(context.TODO(), old, rs), errDataUpdateOfSuspendedRemoteSecret)
+		})
+	})
 }
 
 func TestValidateDelete(t *testing.T) {
@@ -87,6 +155,469 @@ func testDataFrom(t *testing.T, testDataPresent bool, op func(*api.RemoteSecret)
 			})
 		}
 	})
+
+	t.Run("DataFrom following", func(t *testing.T) {
+		rs := &api.RemoteSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rs",
+				Namespace: "ns",
+			},
+			DataFrom: api.RemoteSecretDataFrom{
+				Name:   "somename",
+				Follow: true,
+			},
+		}
+		assert.NoError(t, op(rs))
+
+		t.Run("with UploadData", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.UploadData = map[string][]byte{
+				"a": []byte("b"),
+			}
+			assert.Error(t, op(rs))
+		})
+
+		t.Run("with StringUploadData", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.StringUploadData = map[string]string{
+				"a": "b",
+			}
+			assert.ErrorIs(t, op(rs), errDataUploadToFollowingRemoteSecret)
+		})
+
+		t.Run("itself", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.DataFrom.Name = "rs"
+			assert.ErrorIs(t, op(rs), errFollowSelf)
+
+			rs.DataFrom.Namespace = "other-ns"
+			assert.NoError(t, op(rs))
+		})
+
+		if testDataPresent {
+			t.Run("with data present", func(t *testing.T) {
+				rs := rs.DeepCopy()
+				rs.Status.Conditions = []metav1.Condition{}
+				meta.SetStatusCondition(&rs.Status.Conditions, metav1.Condition{
+					Type:   string(api.RemoteSecretConditionTypeDataObtained),
+					Status: metav1.ConditionTrue,
+				})
+				assert.NoError(t, op(rs))
+			})
+		}
+	})
+
+	t.Run("DataFrom sources", func(t *testing.T) {
+		rs := &api.RemoteSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rs",
+				Namespace: "ns",
+			},
+			DataFrom: api.RemoteSecretDataFrom{
+				Sources: []api.RemoteSecretDataSource{
+					{Name: "a", Keys: []api.RemoteSecretDataSourceKey{{Name: "username"}, {Name: "password", As: "token"}}},
+					// the same keys can come from several sources
+					{Name: "b", Keys: []api.RemoteSecretDataSourceKey{{Name: "username"}}},
+				},
+			},
+		}
+		assert.NoError(t, op(rs))
+
+		t.Run("with duplicate keys in a source", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.DataFrom.Sources[0].Keys[1].As = "username"
+			assert.ErrorIs(t, op(rs), errDataFromKeysNotUnique)
+		})
+
+		t.Run("with UploadData", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.UploadData = map[string][]byte{
+				"a": []byte("b"),
+			}
+			assert.Error(t, op(rs))
+		})
+
+		t.Run("following itself", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.DataFrom.Follow = true
+			assert.NoError(t, op(rs))
+
+			rs.DataFrom.Sources[1].Name = "rs"
+			assert.ErrorIs(t, op(rs), errFollowSelf)
+		})
+
+		t.Run("with secrets", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.DataFrom.Sources[0].Kind = api.RemoteSecretDataSourceKindSecret
+			rs.DataFrom.Sources[0].DeleteAfterImport = true
+			assert.NoError(t, op(rs))
+
+			rs.DataFrom.Sources[1].DeleteAfterImport = true
+			assert.ErrorIs(t, op(rs), errDeleteAfterImportOfRemoteSecret)
+		})
+
+		t.Run("following secrets", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.DataFrom.Follow = true
+			rs.DataFrom.Sources[0].Kind = api.RemoteSecretDataSourceKindSecret
+			assert.ErrorIs(t, op(rs), errFollowSecret)
+		})
+	})
+}
+
+func TestCheckTargetPermissions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, authzv1.AddToScheme(scheme))
+
+	var reviewed []authzv1.ResourceAttributes
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				sar, ok := obj.(*authzv1.SubjectAccessReview)
+				if !ok {
+					return cl.Create(ctx, obj, opts...)
+				}
+				reviewed = append(reviewed, *sar.Spec.ResourceAttributes)
+				sar.Status.Allowed = sar.Spec.ResourceAttributes.Namespace != "forbidden"
+				return nil
+			},
+		}).
+		Build()
+
+	user := authv1.UserInfo{Username: "user"}
+	old := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rs",
+			Namespace: "ns",
+		},
+		Spec: api.RemoteSecretSpec{
+			Targets: []api.RemoteSecretTarget{
+				{Namespace: "existing"},
+			},
+		},
+	}
+	new := old.DeepCopy()
+	new.Spec.Targets = append(new.Spec.Targets,
+		api.RemoteSecretTarget{Namespace: "added"},
+		api.RemoteSecretTarget{Namespace: "added", Secret: &api.SecretOverride{Name: "other"}},
+		api.RemoteSecretTarget{Namespace: "forbidden", ApiUrl: "https://remote.cluster"},
+	)
+
+	validator := func(mode config.TargetAuthorizationMode) *RemoteSecretValidator {
+		return &RemoteSecretValidator{Client: cl, Configuration: &config.OperatorConfiguration{TargetAuthorization: mode}}
+	}
+
+	t.Run("checks the newly added local targets once", func(t *testing.T) {
+		reviewed = nil
+		assert.NoError(t, validator(config.TargetAuthorizationEnforce).CheckTargetPermissions(context.TODO(), user, old, new))
+		assert.Equal(t, []authzv1.ResourceAttributes{{
+			Namespace: "added",
+			Verb:      "create",
+			Version:   "v1",
+			Resource:  "secrets",
+		}}, reviewed)
+	})
+
+	t.Run("checks all local targets on create", func(t *testing.T) {
+		reviewed = nil
+		assert.NoError(t, validator(config.TargetAuthorizationEnforce).CheckTargetPermissions(context.TODO(), user, nil, new))
+		assert.Len(t, reviewed, 2)
+	})
+
+	t.Run("rejects forbidden namespace", func(t *testing.T) {
+		new := new.DeepCopy()
+		new.Spec.Targets = append(new.Spec.Targets, api.RemoteSecretTarget{Namespace: "forbidden"})
+		err := validator(config.TargetAuthorizationEnforce).CheckTargetPermissions(context.TODO(), user, old, new)
+		assert.ErrorIs(t, err, errTargetNotAllowed)
+		assert.ErrorContains(t, err, "namespace forbidden")
+	})
+
+	t.Run("allows forbidden namespace in audit mode", func(t *testing.T) {
+		new := new.DeepCopy()
+		new.Spec.Targets = append(new.Spec.Targets, api.RemoteSecretTarget{Namespace: "forbidden"})
+		assert.NoError(t, validator(config.TargetAuthorizationAudit).CheckTargetPermissions(context.TODO(), user, old, new))
+	})
+
+	t.Run("checks the newly added local replication targets", func(t *testing.T) {
+		reviewed = nil
+		new := new.DeepCopy()
+		new.Spec.ReplicationTargets = []api.ReplicationTarget{
+			{Namespace: "existing"},
+			{Namespace: "replica"},
+			{Namespace: "forbidden", ApiUrl: "https://remote.cluster"},
+		}
+		assert.NoError(t, validator(config.TargetAuthorizationEnforce).CheckTargetPermissions(context.TODO(), user, old, new))
+		namespaces := []string{}
+		for _, attrs := range reviewed {
+			namespaces = append(namespaces, attrs.Namespace)
+		}
+		assert.Equal(t, []string{"added", "replica"}, namespaces)
+
+		reviewed = nil
+		assert.NoError(t, validator(config.TargetAuthorizationEnforce).CheckTargetPermissions(context.TODO(), user, new, new))
+		assert.Empty(t, reviewed)
+
+		new.Spec.ReplicationTargets = append(new.Spec.ReplicationTargets, api.ReplicationTarget{Namespace: "forbidden"})
+		err := validator(config.TargetAuthorizationEnforce).CheckTargetPermissions(context.TODO(), user, old, new)
+		assert.ErrorIs(t, err, errTargetNotAllowed)
+		assert.ErrorContains(t, err, "namespace forbidden")
+	})
+
+	t.Run("doesn't check when disabled", func(t *testing.T) {
+		reviewed = nil
+		new := new.DeepCopy()
+		new.Spec.Targets = append(new.Spec.Targets, api.RemoteSecretTarget{Namespace: "forbidden"})
+		assert.NoError(t, validator(config.TargetAuthorizationDisabled).CheckTargetPermissions(context.TODO(), user, old, new))
+		assert.NoError(t, (&RemoteSecretValidator{Client: cl}).CheckTargetPermissions(context.TODO(), user, old, new))
+		assert.Empty(t, reviewed)
+	})
+}
+
+func TestCheckClusterCredentialsPermissions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, authzv1.AddToScheme(scheme))
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	var reviewed []authzv1.ResourceAttributes
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "restricted-kubeconfig",
+				Namespace: "ns",
+				Annotations: map[string]string{
+					api.ClusterCredentialsAllowedRemoteSecretsAnnotation: "other-rs",
+				},
+			},
+		}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				sar, ok := obj.(*authzv1.SubjectAccessReview)
+				if !ok {
+					return cl.Create(ctx, obj, opts...)
+				}
+				reviewed = append(reviewed, *sar.Spec.ResourceAttributes)
+				sar.Status.Allowed = sar.Spec.ResourceAttributes.Name != "forbidden-kubeconfig"
+				return nil
+			},
+		}).
+		Build()
+
+	user := authv1.UserInfo{Username: "user"}
+	old := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rs",
+			Namespace: "ns",
+		},
+		Spec: api.RemoteSecretSpec{
+			Targets: []api.RemoteSecretTarget{
+				{Namespace: "existing", ApiUrl: "https://remote.cluster", ClusterCredentialsSecret: "forbidden-kubeconfig"},
+			},
+		},
+	}
+
+	// the cluster credentials are checked even if the target authorization is disabled
+	validator := &RemoteSecretValidator{Client: cl}
+
+	t.Run("doesn't check unchanged targets", func(t *testing.T) {
+		reviewed = nil
+		assert.NoError(t, validator.CheckTargetPermissions(context.TODO(), user, old, old.DeepCopy()))
+		assert.Empty(t, reviewed)
+	})
+
+	t.Run("checks the new targets once per secret", func(t *testing.T) {
+		reviewed = nil
+		new := old.DeepCopy()
+		new.Spec.Targets = append(new.Spec.Targets,
+			api.RemoteSecretTarget{Namespace: "a", ApiUrl: "https://remote.cluster", ClusterCredentialsSecret: "kubeconfig"},
+			api.RemoteSecretTarget{Namespace: "b", ApiUrl: "https://remote.cluster", ClusterCredentialsSecret: "kubeconfig"},
+			api.RemoteSecretTarget{Namespace: "c"},
+		)
+		assert.NoError(t, validator.CheckTargetPermissions(context.TODO(), user, old, new))
+		assert.Equal(t, []authzv1.ResourceAttributes{{
+			Namespace: "ns",
+			Verb:      "get",
+			Version:   "v1",
+			Resource:  "secrets",
+			Name:      "kubeconfig",
+		}}, reviewed)
+	})
+
+	t.Run("rejects forbidden secret in changed target", func(t *testing.T) {
+		new := old.DeepCopy()
+		new.Spec.Targets[0].ApiUrl = "https://other.cluster"
+		err := validator.CheckTargetPermissions(context.TODO(), user, old, new)
+		assert.ErrorIs(t, err, errClusterCredentialsNotAllowed)
+		assert.ErrorContains(t, err, "secret forbidden-kubeconfig")
+	})
+
+	t.Run("rejects forbidden secret on create", func(t *testing.T) {
+		err := validator.CheckTargetPermissions(context.TODO(), user, nil, old)
+		assert.ErrorIs(t, err, errClusterCredentialsNotAllowed)
+	})
+
+	t.Run("checks the replication targets", func(t *testing.T) {
+		reviewed = nil
+		new := old.DeepCopy()
+		new.Spec.ReplicationTargets = []api.ReplicationTarget{
+			{Namespace: "replica", ApiUrl: "https://remote.cluster", ClusterCredentialsSecret: "kubeconfig"},
+		}
+		assert.NoError(t, validator.CheckTargetPermissions(context.TODO(), user, old, new))
+		assert.Len(t, reviewed, 1)
+
+		reviewed = nil
+		assert.NoError(t, validator.CheckTargetPermissions(context.TODO(), user, new, new))
+		assert.Empty(t, reviewed)
+
+		new.Spec.ReplicationTargets[0].ClusterCredentialsSecret = "forbidden-kubeconfig"
+		err := validator.CheckTargetPermissions(context.TODO(), user, old, new)
+		assert.ErrorIs(t, err, errClusterCredentialsNotAllowed)
+
+		new.Spec.ReplicationTargets[0].ClusterCredentialsSecret = "restricted-kubeconfig"
+		err = validator.CheckTargetPermissions(context.TODO(), user, old, new)
+		assert.ErrorIs(t, err, errClusterCredentialsUseRestricted)
+	})
+
+	t.Run("rejects secret restricted to other remote secrets", func(t *testing.T) {
+		new := old.DeepCopy()
+		new.Spec.Targets[0].ClusterCredentialsSecret = "restricted-kubeconfig"
+		err := validator.CheckTargetPermissions(context.TODO(), user, old, new)
+		assert.ErrorIs(t, err, errClusterCredentialsUseRestricted)
+
+		new.Name = "other-rs"
+		assert.NoError(t, validator.CheckTargetPermissions(context.TODO(), user, old, new))
+	})
+}
+
+func TestValidatePolicies(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, api.AddToScheme(scheme))
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(&api.RemoteSecretPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+			Spec: api.RemoteSecretPolicySpec{
+				TargetNamespaces: &api.TargetNamespacesPolicy{Names: []string{"allowed"}},
+			},
+		}).
+		Build()
+
+	validator := &RemoteSecretValidator{PolicyChecker: &policy.Checker{Client: cl}}
+
+	rs := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rs",
+			Namespace: "ns",
+		},
+		Spec: api.RemoteSecretSpec{
+			Targets: []api.RemoteSecretTarget{{Namespace: "allowed"}},
+		},
+	}
+	assert.NoError(t, validator.ValidateCreate(context.TODO(), rs))
+
+	disallowed := rs.DeepCopy()
+	disallowed.Spec.Targets = append(disallowed.Spec.Targets, api.RemoteSecretTarget{Namespace: "disallowed"})
+	assert.ErrorIs(t, validator.ValidateCreate(context.TODO(), disallowed), policy.PolicyViolationError)
+	assert.ErrorIs(t, validator.ValidateUpdate(context.TODO(), rs, disallowed), policy.PolicyViolationError)
+
+	// the targets that were already present are not checked again
+	assert.NoError(t, validator.ValidateUpdate(context.TODO(), disallowed, disallowed))
+}
+
+func TestValidateQuota(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, api.AddToScheme(scheme))
+
+	existing := &api.RemoteSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "existing",
+			Namespace: "ns",
+		},
+		Spec: api.RemoteSecretSpec{
+			Targets: []api.RemoteSecretTarget{{Namespace: "a"}, {Namespace: "b"}},
+		},
+	}
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(existing).
+		Build()
+
+	validator := &RemoteSecretValidator{QuotaChecker: &quota.Checker{
+		Client: cl,
+		Quota:  &api.RemoteSecretQuota{MaxRemoteSecrets: 1, MaxTargetsPerRemoteSecret: 1},
+	}}
+
+	t.Run("remote secret count", func(t *testing.T) {
+		err := validator.ValidateCreate(context.TODO(), &api.RemoteSecret{ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "ns"}})
+		assert.ErrorIs(t, err, quota.QuotaExceededError)
+		assert.ErrorContains(t, err, "the remote secret is not allowed")
+
+		assert.NoError(t, validator.ValidateCreate(context.TODO(), &api.RemoteSecret{ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "other-ns"}}))
+	})
+
+	t.Run("target count", func(t *testing.T) {
+		updated := existing.DeepCopy()
+		updated.Spec.Targets = append(updated.Spec.Targets, api.RemoteSecretTarget{Namespace: "c"})
+		assert.ErrorIs(t, validator.ValidateUpdate(context.TODO(), existing, updated), quota.QuotaExceededError)
+
+		// the remote secrets exceeding the quota can still be updated if they don't add targets
+		assert.NoError(t, validator.ValidateUpdate(context.TODO(), existing, existing.DeepCopy()))
+	})
+}
+
+func testExternalSource(t *testing.T, op func(*api.RemoteSecret) error) {
+	t.Run("ExternalSource", func(t *testing.T) {
+		rs := &api.RemoteSecret{
+			Spec: api.RemoteSecretSpec{
+				ExternalSource: &api.ExternalSource{
+					Data:            []api.ExternalSourceData{{SecretKey: "token", RemoteRef: api.ExternalSourceRef{Key: "app/token"}}},
+					DataFrom:        []api.ExternalSourceRef{{Key: "app/db"}},
+					RefreshInterval: &metav1.Duration{Duration: time.Minute},
+				},
+			},
+		}
+		assert.NoError(t, op(rs))
+
+		t.Run("with UploadData", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.StringUploadData = map[string]string{"a": "b"}
+			assert.ErrorIs(t, op(rs), errDataOfRemoteSecretWithExternalSource)
+		})
+
+		t.Run("with DataFrom", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.DataFrom = api.RemoteSecretDataFrom{Name: "source"}
+			assert.ErrorIs(t, op(rs), errDataOfRemoteSecretWithExternalSource)
+		})
+
+		t.Run("empty", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.Spec.ExternalSource = &api.ExternalSource{}
+			assert.ErrorIs(t, op(rs), errExternalSourceEmpty)
+		})
+
+		t.Run("too short refresh interval", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.Spec.ExternalSource.RefreshInterval.Duration = time.Second
+			assert.ErrorIs(t, op(rs), errExternalSourceRefreshIntervalTooShort)
+		})
+
+		t.Run("duplicate secret keys", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.Spec.ExternalSource.Data = append(rs.Spec.ExternalSource.Data, api.ExternalSourceData{SecretKey: "token", RemoteRef: api.ExternalSourceRef{Key: "other"}})
+			assert.ErrorIs(t, op(rs), errExternalSourceKeysNotUnique)
+		})
+
+		t.Run("escaping the key prefix", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.Spec.ExternalSource.DataFrom[0].Key = "../other-ns/db"
+			assert.Error(t, op(rs))
+		})
+	})
 }
 
 func testUploadData(t *testing.T, op func(*api.RemoteSecret) error) {
@@ -143,3 +674,82 @@ func testUniqueTargets(t *testing.T, op func(*api.RemoteSecret) error) {
 		})
 	})
 }
+
+func testReplicationTargets(t *testing.T, op func(*api.RemoteSecret) error) {
+	t.Run("replication targets", func(t *testing.T) {
+		rs := &api.RemoteSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rs",
+				Namespace: "ns",
+			},
+		}
+		t.Run("valid", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.Spec.ReplicationTargets = []api.ReplicationTarget{
+				{
+					Namespace: "ns",
+					ApiUrl:    "over-there",
+				},
+				{
+					Namespace:        "ns",
+					RemoteSecretName: "other",
+				},
+				{
+					Namespace: "a",
+				},
+			}
+			assert.NoError(t, op(rs))
+		})
+		t.Run("not unique", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.Spec.ReplicationTargets = []api.ReplicationTarget{
+				{
+					Namespace: "a",
+				},
+				{
+					Namespace:        "a",
+					RemoteSecretName: "rs",
+				},
+			}
+			assert.ErrorIs(t, op(rs), errReplicationTargetsNotUnique)
+		})
+		t.Run("to itself", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.Spec.ReplicationTargets = []api.ReplicationTarget{
+				{
+					Namespace: "ns",
+				},
+			}
+			assert.ErrorIs(t, op(rs), errReplicationToSelf)
+		})
+	})
+}
+
+func testSecretKeys(t *testing.T, op func(*api.RemoteSecret) error) {
+	t.Run("secret keys", func(t *testing.T) {
+		rs := &api.RemoteSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rs",
+				Namespace: "ns",
+			},
+		}
+		t.Run("valid", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.Spec.Secret.RequiredKeys = []api.SecretKey{
+				{Name: "a", Pattern: "^[a-z]+$", MinLength: ptr.To[int32](1), MaxLength: ptr.To[int32](10)},
+				{Name: "b", DefaultValue: ptr.To("{}"), Format: api.SecretKeyFormatJson},
+			}
+			assert.NoError(t, op(rs))
+		})
+		t.Run("invalid pattern", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.Spec.Secret.RequiredKeys = []api.SecretKey{{Name: "a", Pattern: "[a-z"}}
+			assert.ErrorContains(t, op(rs), "key 'a' has an invalid pattern")
+		})
+		t.Run("invalid default", func(t *testing.T) {
+			rs := rs.DeepCopy()
+			rs.Spec.Secret.RequiredKeys = []api.SecretKey{{Name: "a", DefaultValue: ptr.To("nope"), Format: api.SecretKeyFormatJson}}
+			assert.ErrorContains(t, op(rs), "the default value of key 'a' does not have the required format 'json'")
+		})
+	})
+}
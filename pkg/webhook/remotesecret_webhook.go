@@ -17,13 +17,16 @@ package webhook
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"reflect"
 
 	adm "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	wh "sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage"
 )
 
 // +kubebuilder:webhook:path=/mutate-appstudio-redhat-com-v1beta1-remotesecret,mutating=true,failurePolicy=fail,sideEffects=None,groups=appstudio.redhat.com,resources=remotesecrets,verbs=create;update,versions=v1beta1,name=mremotesecret.kb.io,admissionReviewVersions=v1
@@ -66,10 +69,20 @@ func (w *RemoteSecretWebhook) handleCreate(ctx context.Context, req wh.Request,
 	if err := w.Validator.ValidateCreate(ctx, rs); err != nil {
 		return wh.Denied(err.Error())
 	}
-	if err := w.Mutator.StoreUploadData(ctx, rs); err != nil {
+	if err := w.Validator.CheckTargetPermissions(ctx, req.UserInfo, nil, rs); err != nil {
+		return wh.Denied(err.Error())
+	}
+	if err := w.Mutator.CheckDataFrom(ctx, req.UserInfo, nil, rs); err != nil {
 		return wh.Denied(err.Error())
 	}
-	if err := w.Mutator.CopyDataFrom(ctx, req.UserInfo, rs); err != nil {
+	if err := w.Mutator.CheckDataImport(ctx, req.UserInfo, nil, rs); err != nil {
+		return wh.Denied(err.Error())
+	}
+	// all the authorization checks must be done before the data is stored
+	if err := w.Mutator.StoreUploadData(ctx, rs); err != nil {
+		return uploadDenied(err)
+	}
+	if err := w.Mutator.CopyDataFrom(ctx, rs); err != nil {
 		return wh.Denied(err.Error())
 	}
 	return patchedOrAllowed(orig, req.Object.Raw, rs)
@@ -80,10 +93,20 @@ func (w *RemoteSecretWebhook) handleUpdate(ctx context.Context, req wh.Request,
 	if err := w.Validator.ValidateUpdate(ctx, old, rs); err != nil {
 		return wh.Denied(err.Error())
 	}
-	if err := w.Mutator.StoreUploadData(ctx, rs); err != nil {
+	if err := w.Validator.CheckTargetPermissions(ctx, req.UserInfo, old, rs); err != nil {
 		return wh.Denied(err.Error())
 	}
-	if err := w.Mutator.CopyDataFrom(ctx, req.UserInfo, rs); err != nil {
+	if err := w.Mutator.CheckDataFrom(ctx, req.UserInfo, old, rs); err != nil {
+		return wh.Denied(err.Error())
+	}
+	if err := w.Mutator.CheckDataImport(ctx, req.UserInfo, old, rs); err != nil {
+		return wh.Denied(err.Error())
+	}
+	// all the authorization checks must be done before the data is stored
+	if err := w.Mutator.StoreUploadData(ctx, rs); err != nil {
+		return uploadDenied(err)
+	}
+	if err := w.Mutator.CopyDataFrom(ctx, rs); err != nil {
 		return wh.Denied(err.Error())
 	}
 	return patchedOrAllowed(orig, req.Object.Raw, rs)
@@ -93,6 +116,17 @@ func (w *RemoteSecretWebhook) handleDelete(ctx context.Context, req wh.Request,
 	return wh.Allowed("")
 }
 
+// uploadDenied denies the request that failed to store the upload data. The conflicts with the concurrent changes of the data
+// are reported with the conflict status code so that the clients can tell them apart and retry.
+func uploadDenied(err error) wh.Response {
+	resp := wh.Denied(err.Error())
+	if errors.Is(err, secretstorage.ConflictError) {
+		resp.Result.Code = http.StatusConflict
+		resp.Result.Reason = metav1.StatusReasonConflict
+	}
+	return resp
+}
+
 func patchedOrAllowed(orig any, origRaw []byte, obj any) wh.Response {
 	if !reflect.DeepEqual(orig, obj) {
 		json, err := json.Marshal(obj)
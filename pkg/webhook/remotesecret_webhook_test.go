@@ -16,16 +16,20 @@ package webhook
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	admissionv1 "k8s.io/api/admission/v1"
 	authv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	api "github.com/redhat-appstudio/remote-secret/api/v1beta1"
+	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage"
 )
 
 func TestHandle_Create(t *testing.T) {
@@ -54,15 +58,21 @@ func TestHandle_Create(t *testing.T) {
 	}
 
 	validator.On("ValidateCreate", mock.Anything, mock.Anything).Return(nil)
+	validator.On("CheckTargetPermissions", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mutator.On("CheckDataFrom", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mutator.On("CheckDataImport", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mutator.On("StoreUploadData", mock.Anything, mock.Anything).Return(nil)
-	mutator.On("CopyDataFrom", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mutator.On("CopyDataFrom", mock.Anything, mock.Anything).Return(nil)
 
 	res := w.Handle(context.TODO(), req)
 
 	assert.True(t, res.Allowed)
 	validator.AssertCalled(t, "ValidateCreate", mock.Anything, mock.Anything)
+	validator.AssertCalled(t, "CheckTargetPermissions", mock.Anything, mock.Anything, (*api.RemoteSecret)(nil), mock.Anything)
+	mutator.AssertCalled(t, "CheckDataFrom", mock.Anything, mock.Anything, (*api.RemoteSecret)(nil), mock.Anything)
+	mutator.AssertCalled(t, "CheckDataImport", mock.Anything, mock.Anything, (*api.RemoteSecret)(nil), mock.Anything)
 	mutator.AssertCalled(t, "StoreUploadData", mock.Anything, mock.Anything)
-	mutator.AssertCalled(t, "CopyDataFrom", mock.Anything, mock.Anything, mock.Anything)
+	mutator.AssertCalled(t, "CopyDataFrom", mock.Anything, mock.Anything)
 }
 
 func TestHandle_Update(t *testing.T) {
@@ -94,15 +104,58 @@ func TestHandle_Update(t *testing.T) {
 	}
 
 	validator.On("ValidateUpdate", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	validator.On("CheckTargetPermissions", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mutator.On("CheckDataFrom", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mutator.On("CheckDataImport", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mutator.On("StoreUploadData", mock.Anything, mock.Anything).Return(nil)
-	mutator.On("CopyDataFrom", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mutator.On("CopyDataFrom", mock.Anything, mock.Anything).Return(nil)
 
 	res := w.Handle(context.TODO(), req)
 
 	assert.True(t, res.Allowed)
 	validator.AssertCalled(t, "ValidateUpdate", mock.Anything, mock.Anything, mock.Anything)
+	validator.AssertCalled(t, "CheckTargetPermissions", mock.Anything, mock.Anything, mock.AnythingOfType("*v1beta1.RemoteSecret"), mock.Anything)
+	mutator.AssertCalled(t, "CheckDataFrom", mock.Anything, mock.Anything, mock.AnythingOfType("*v1beta1.RemoteSecret"), mock.Anything)
+	mutator.AssertCalled(t, "CheckDataImport", mock.Anything, mock.Anything, mock.AnythingOfType("*v1beta1.RemoteSecret"), mock.Anything)
 	mutator.AssertCalled(t, "StoreUploadData", mock.Anything, mock.Anything)
-	mutator.AssertCalled(t, "CopyDataFrom", mock.Anything, mock.Anything, mock.Anything)
+	mutator.AssertCalled(t, "CopyDataFrom", mock.Anything, mock.Anything)
+}
+
+func TestHandle_NoSideEffectsWhenNotAuthorized(t *testing.T) {
+	mutator := &TestMutator{}
+	validator := &TestValidator{}
+
+	scheme := runtime.NewScheme()
+	err := api.AddToScheme(scheme)
+	assert.NoError(t, err)
+
+	w := RemoteSecretWebhook{
+		Validator: validator,
+		Mutator:   mutator,
+		Decoder:   admission.NewDecoder(scheme),
+	}
+
+	req := admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Name:      "rs",
+			Namespace: "default",
+			Operation: admissionv1.Create,
+			Object: runtime.RawExtension{
+				Raw: []byte(`{"apiVersion": "appstudio.redhat.com/v1beta1", "kind": "RemoteSecret", "metadata": {"name": "rs", "namespace": "default"}}`),
+			},
+		},
+	}
+
+	validator.On("ValidateCreate", mock.Anything, mock.Anything).Return(nil)
+	validator.On("CheckTargetPermissions", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mutator.On("CheckDataFrom", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mutator.On("CheckDataImport", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errorImportNotAllowed)
+
+	res := w.Handle(context.TODO(), req)
+
+	assert.False(t, res.Allowed)
+	mutator.AssertNotCalled(t, "StoreUploadData", mock.Anything, mock.Anything)
+	mutator.AssertNotCalled(t, "CopyDataFrom", mock.Anything, mock.Anything)
 }
 
 func TestHandle_Delete(t *testing.T) {
@@ -127,6 +180,45 @@ func TestHandle_Delete(t *testing.T) {
 	assert.True(t, res.Allowed)
 }
 
+func TestHandle_UploadConflict(t *testing.T) {
+	mutator := &TestMutator{}
+	validator := &TestValidator{}
+
+	scheme := runtime.NewScheme()
+	err := api.AddToScheme(scheme)
+	assert.NoError(t, err)
+
+	w := RemoteSecretWebhook{
+		Validator: validator,
+		Mutator:   mutator,
+		Decoder:   admission.NewDecoder(scheme),
+	}
+
+	req := admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Name:      "rs",
+			Namespace: "default",
+			Operation: admissionv1.Create,
+			Object: runtime.RawExtension{
+				Raw: []byte(`{"apiVersion": "appstudio.redhat.com/v1beta1", "kind": "RemoteSecret", "metadata": {"name": "rs", "namespace": "default"}}`),
+			},
+		},
+	}
+
+	validator.On("ValidateCreate", mock.Anything, mock.Anything).Return(nil)
+	validator.On("CheckTargetPermissions", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mutator.On("CheckDataFrom", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mutator.On("CheckDataImport", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mutator.On("StoreUploadData", mock.Anything, mock.Anything).Return(fmt.Errorf("storage error on data save: %w", secretstorage.ConflictError))
+
+	res := w.Handle(context.TODO(), req)
+
+	assert.False(t, res.Allowed)
+	assert.Equal(t, int32(http.StatusConflict), res.Result.Code)
+	assert.Equal(t, metav1.StatusReasonConflict, res.Result.Reason)
+	mutator.AssertNotCalled(t, "CopyDataFrom", mock.Anything, mock.Anything)
+}
+
 type TestValidator struct {
 	mock.Mock
 }
@@ -153,9 +245,27 @@ func (v *TestValidator) ValidateUpdate(ctx context.Context, old *api.RemoteSecre
 	return args.Error(0) //nolint:wrapcheck // mock
 }
 
+// CheckTargetPermissions implements WebhookValidator.
+func (v *TestValidator) CheckTargetPermissions(ctx context.Context, user authv1.UserInfo, old *api.RemoteSecret, new *api.RemoteSecret) error {
+	args := v.Called(ctx, user, old, new)
+	return args.Error(0) //nolint:wrapcheck // mock
+}
+
+// CheckDataFrom implements WebhookMutator.
+func (m *TestMutator) CheckDataFrom(ctx context.Context, user authv1.UserInfo, old *api.RemoteSecret, rs *api.RemoteSecret) error {
+	args := m.Called(ctx, user, old, rs)
+	return args.Error(0) //nolint:wrapcheck // mock
+}
+
 // CopyDataFrom implements WebhookMutator.
-func (m *TestMutator) CopyDataFrom(ctx context.Context, user authv1.UserInfo, rs *api.RemoteSecret) error {
-	args := m.Called(ctx, user, rs)
+func (m *TestMutator) CopyDataFrom(ctx context.Context, rs *api.RemoteSecret) error {
+	args := m.Called(ctx, rs)
+	return args.Error(0) //nolint:wrapcheck // mock
+}
+
+// CheckDataImport implements WebhookMutator.
+func (m *TestMutator) CheckDataImport(ctx context.Context, user authv1.UserInfo, old *api.RemoteSecret, rs *api.RemoteSecret) error {
+	args := m.Called(ctx, user, old, rs)
 	return args.Error(0) //nolint:wrapcheck // mock
 }
 
@@ -17,24 +17,37 @@ package webhook
 import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	wh "sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
 
 	"github.com/redhat-appstudio/remote-secret/controllers/remotesecretstorage"
+	"github.com/redhat-appstudio/remote-secret/pkg/config"
+	"github.com/redhat-appstudio/remote-secret/pkg/policy"
+	"github.com/redhat-appstudio/remote-secret/pkg/quota"
 	"github.com/redhat-appstudio/remote-secret/pkg/secretstorage"
 )
 
-func SetupAllWebhooks(mgr ctrl.Manager, secretStorage secretstorage.SecretStorage) error {
+func SetupAllWebhooks(mgr ctrl.Manager, cfg *config.OperatorConfiguration, secretStorage secretstorage.SecretStorage) error {
 	remoteSecretStorage := remotesecretstorage.NewJSONSerializingRemoteSecretStorage(secretStorage)
+	quotaChecker := &quota.Checker{Client: mgr.GetClient(), Quota: &cfg.Quota}
 	w := &wh.Webhook{
 		Handler: &RemoteSecretWebhook{
 			Mutator: &RemoteSecretMutator{
-				Client:  mgr.GetClient(),
-				Storage: remoteSecretStorage,
+				Client:       mgr.GetClient(),
+				Storage:      remoteSecretStorage,
+				QuotaChecker: quotaChecker,
 			},
-			Validator: &RemoteSecretValidator{},
-			Decoder:   wh.NewDecoder(mgr.GetScheme()),
+			Validator: &RemoteSecretValidator{
+				Client:        mgr.GetClient(),
+				Configuration: cfg,
+				PolicyChecker: &policy.Checker{Client: mgr.GetClient()},
+				QuotaChecker:  quotaChecker,
+			},
+			Decoder: wh.NewDecoder(mgr.GetScheme()),
 		},
 		RecoverPanic: false,
 	}
 	mgr.GetWebhookServer().Register("/mutate-appstudio-redhat-com-v1beta1-remotesecret", w)
+	// converts the remote secrets between v1beta1 and v1 using the conversion functions of the API types in the scheme
+	mgr.GetWebhookServer().Register("/convert", conversion.NewWebhookHandler(mgr.GetScheme()))
 	return nil
 }